@@ -27,9 +27,11 @@ func main() {
 
 	// Dùng Fx để DI worker/handlers và auto start theo lifecycle
 	fxApp := fx.New(
+		app.TracingModule,
 		app.CoreModule,
 		app.RepoModule,
 		app.ServiceModule, // nếu cần tạo factory job cho nơi khác dùng
+		app.SchedulerModule,
 		app.QueueModule,   // module worker + handler + lifecycle
 		
 	)
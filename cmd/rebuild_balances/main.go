@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"core-ledger/pkg/database"
+	"core-ledger/pkg/repo"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Tính lại account_balances từ entries.
+// Usage:
+//   - Toàn bộ tài khoản: go run ./cmd/rebuild_balances
+//   - Một tài khoản:     go run ./cmd/rebuild_balances -account=123
+func main() {
+	accountID := flag.Uint64("account", 0, "chỉ rebuild một tài khoản (0 = toàn bộ)")
+	timeout := flag.Duration("timeout", 30*time.Minute, "thời gian tối đa")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var target *uint64
+	if *accountID > 0 {
+		target = accountID
+	}
+
+	start := time.Now()
+	affected, err := repo.NewAccountBalanceRepo(database.Instance()).Rebuild(ctx, target)
+	if err != nil {
+		log.Fatalf("rebuild account balances failed: %v", err)
+	}
+	fmt.Printf("Rebuilt %d account balances in %s\n", affected, time.Since(start))
+}
@@ -13,6 +13,7 @@ func main() {
 
 	seeders := []func(*gorm.DB) error{
 		seeder.SeederRuleCategories,
+		seeder.SeederLedgerCurrencies,
 	}
 
 	for _, s := range seeders {
@@ -0,0 +1,24 @@
+package config
+
+import "time"
+
+// ExportConfig cấu hình export dữ liệu (XLSX/CSV/JSONL)
+type ExportConfig struct {
+	// PageSize số bản ghi đọc mỗi lần khi stream dữ liệu ra file
+	PageSize int64
+	// MaxRows số dòng tối đa của một file export
+	MaxRows int64
+	// SyncMaxRows export trực tiếp qua HTTP (không qua queue) chỉ cho phép tới số dòng này
+	SyncMaxRows int64
+	// Retention thời gian giữ file export trước khi hết hạn tải
+	Retention time.Duration
+}
+
+func GetExportConfig() *ExportConfig {
+	return &ExportConfig{
+		PageSize:    int64(getEnvAsInt("EXPORT_PAGE_SIZE", 1000)),
+		MaxRows:     int64(getEnvAsInt("EXPORT_MAX_ROWS", 1000000)),
+		SyncMaxRows: int64(getEnvAsInt("EXPORT_SYNC_MAX_ROWS", 10000)),
+		Retention:   getEnvAsDuration("EXPORT_RETENTION", 7*24*time.Hour),
+	}
+}
@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+// HoldConfig cấu hình tạm giữ số dư (holds)
+type HoldConfig struct {
+	// DefaultTTL thời gian giữ mặc định khi request không truyền expires_at/ttl_seconds
+	DefaultTTL time.Duration
+	// ExpiryCron lịch chạy job chuyển hold quá hạn sang EXPIRED
+	ExpiryCron string
+	// ExpiryBatchSize số hold tối đa cập nhật trong một lần UPDATE
+	ExpiryBatchSize int
+}
+
+func GetHoldConfig() *HoldConfig {
+	return &HoldConfig{
+		DefaultTTL:      getEnvAsDuration("HOLD_DEFAULT_TTL", 7*24*time.Hour),
+		ExpiryCron:      getEnv("HOLD_EXPIRY_CRON", "*/5 * * * *"),
+		ExpiryBatchSize: getEnvAsInt("HOLD_EXPIRY_BATCH_SIZE", 500),
+	}
+}
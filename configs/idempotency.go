@@ -0,0 +1,35 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	IdempotencyStorePostgres = "postgres"
+	IdempotencyStoreRedis    = "redis"
+)
+
+// IdempotencyConfig cấu hình lớp idempotency cho các endpoint ghi sổ (header Idempotency-Key)
+type IdempotencyConfig struct {
+	// Store nơi lưu key: postgres (bảng idempotency_keys) hoặc redis
+	Store string
+	// TTL thời gian giữ response đã lưu để replay
+	TTL time.Duration
+	// LockTimeout request đang xử lý quá thời gian này thì request trùng key được phép chạy lại
+	LockTimeout time.Duration
+	// CleanupCron lịch xoá key hết hạn trong Postgres
+	CleanupCron string
+	// CleanupBatchSize số key tối đa xoá trong một lần DELETE
+	CleanupBatchSize int
+}
+
+func GetIdempotencyConfig() *IdempotencyConfig {
+	return &IdempotencyConfig{
+		Store:            strings.ToLower(getEnv("IDEMPOTENCY_STORE", IdempotencyStorePostgres)),
+		TTL:              getEnvAsDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		LockTimeout:      getEnvAsDuration("IDEMPOTENCY_LOCK_TIMEOUT", time.Minute),
+		CleanupCron:      getEnv("IDEMPOTENCY_CLEANUP_CRON", "15 * * * *"),
+		CleanupBatchSize: getEnvAsInt("IDEMPOTENCY_CLEANUP_BATCH_SIZE", 1000),
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import "strings"
+
+// defaultRedactFields tên field bị che khi ghi log (so khớp không phân biệt hoa thường, gồm cả dạng <prefix>_<name>)
+var defaultRedactFields = []string{
+	"password", "passwd", "secret", "token", "access_token", "refresh_token",
+	"api_key", "private_key", "authorization", "cookie", "credential",
+}
+
+// LoggingConfig cấu hình logger dùng chung (pkg/logging)
+type LoggingConfig struct {
+	// Level DEBUG | INFO | WARNING | ERROR | CRITICAL | ALERT | EMERGENCY, đổi được lúc chạy qua /admin/log-level
+	Level string
+	// Mode development: console có màu, còn lại JSON
+	Mode string
+	// RedactFields danh sách tên field bị che giá trị, LOG_REDACT_FIELDS phân tách bằng dấu phẩy
+	RedactFields []string
+}
+
+func GetLoggingConfig() *LoggingConfig {
+	return &LoggingConfig{
+		Level:        getEnv("LOG_LEVEL", "INFO"),
+		Mode:         getEnv("LOG_MODE", "production"),
+		RedactFields: getEnvAsList("LOG_REDACT_FIELDS", defaultRedactFields),
+	}
+}
+
+// getEnvAsList đọc danh sách phân tách bằng dấu phẩy, bỏ phần tử rỗng
+func getEnvAsList(key string, defaultValue []string) []string {
+	value := getEnv(key, "")
+	if value == "" {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"errors"
 	"log"
+	"strings"
 
 	"github.com/caarlos0/env/v10"
 	"github.com/joho/godotenv"
@@ -27,6 +29,15 @@ type CommonConfig struct {
 	LogTypes     string `env:"LOG_TYPES"`
 }
 
+// IsDevelopment MODE=development/dev/local
+func (c CommonConfig) IsDevelopment() bool {
+	switch strings.ToLower(strings.TrimSpace(c.Mode)) {
+	case "development", "dev", "local":
+		return true
+	}
+	return false
+}
+
 type VersionConfig struct {
 	Code int    `env:"VERSION_CODE"`
 	Name string `env:"VERSION_NAME"`
@@ -51,6 +62,17 @@ type JWTConfig struct {
 	ExpiresIn int    `env:"JWT_EXPIRES_IN"`
 }
 
+// ErrJWTSecretMissing JWT_SECRET chưa cấu hình khi MODE không phải development
+var ErrJWTSecretMissing = errors.New("JWT_SECRET is required when MODE is not development")
+
+// ValidateJWT chặn khởi động API khi thiếu JWT_SECRET ngoài môi trường dev
+func (c Config) ValidateJWT() error {
+	if strings.TrimSpace(c.JWT.Secret) == "" && !c.Common.IsDevelopment() {
+		return ErrJWTSecretMissing
+	}
+	return nil
+}
+
 var instance *Config
 
 func init() {
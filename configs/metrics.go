@@ -0,0 +1,22 @@
+package config
+
+// MetricsConfig cấu hình endpoint Prometheus
+type MetricsConfig struct {
+	Enabled bool
+	// Path đường dẫn endpoint trên HTTP server của API
+	Path string
+	// Token request scrape phải gửi "Authorization: Bearer <token>"; rỗng thì API không mở endpoint
+	// (chỉ còn listener riêng của worker)
+	Token string
+	// WorkerAddr địa chỉ listen của endpoint metrics trong queue worker, để trống thì không mở
+	WorkerAddr string
+}
+
+func GetMetricsConfig() *MetricsConfig {
+	return &MetricsConfig{
+		Enabled:    getEnvAsBool("METRICS_ENABLED", true),
+		Path:       getEnv("METRICS_PATH", "/metrics"),
+		Token:      getEnv("METRICS_TOKEN", ""),
+		WorkerAddr: getEnv("METRICS_WORKER_ADDR", ":9091"),
+	}
+}
@@ -8,6 +8,8 @@ import (
 
 // QueueConfig chứa cấu hình cho queue system
 type QueueConfig struct {
+	// Driver asynq (Redis + worker riêng), sync (chạy ngay trong lời gọi Dispatch) hoặc memory (goroutine trong process)
+	Driver        string
 	RedisAddr     string
 	RedisPassword string
 	RedisDB       int
@@ -18,6 +20,7 @@ type QueueConfig struct {
 
 // GetQueueConfig trả về cấu hình queue từ environment variables
 func GetQueueConfig() *QueueConfig {
+	driver := getEnv("QUEUE_DRIVER", "asynq")
 	redisAddr := Reader().Get("REDIS_ADDR")
 	redisPassword := Reader().Get("REDIS_PASSWORD")
 	redisDB := getEnvAsInt("REDIS_DB", 0)
@@ -32,6 +35,7 @@ func GetQueueConfig() *QueueConfig {
 	}
 
 	return &QueueConfig{
+		Driver:        driver,
 		RedisAddr:     redisAddr,
 		RedisPassword: redisPassword,
 		RedisDB:       redisDB,
@@ -61,8 +65,14 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 
 // ValidateQueueConfig kiểm tra cấu hình queue có hợp lệ không
 func ValidateQueueConfig(config *QueueConfig) error {
-	if config.RedisAddr == "" {
-		return fmt.Errorf("REDIS_ADDR is required")
+	switch config.Driver {
+	case "asynq":
+		if config.RedisAddr == "" {
+			return fmt.Errorf("REDIS_ADDR is required")
+		}
+	case "sync", "memory":
+	default:
+		return fmt.Errorf("QUEUE_DRIVER must be one of asynq, sync, memory")
 	}
 
 	if config.Concurrency <= 0 {
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	RateLimitGroupDefault = "default"
+	RateLimitGroupWrite   = "write"
+	RateLimitGroupReports = "reports"
+)
+
+// RateLimitRule token bucket: Limit request mỗi Window (tốc độ nạp lại), tối đa Burst request liên tiếp
+type RateLimitRule struct {
+	Limit int
+	Burst int
+}
+
+// RateLimitConfig giới hạn request theo principal (API key/nhân viên) hoặc tenant, lưu trạng thái trong Redis
+// để nhiều replica API dùng chung một quota
+type RateLimitConfig struct {
+	Enabled bool
+	Window  time.Duration
+	// ByTenant API key có tenant_id thì dùng chung quota theo tenant thay vì theo từng key
+	ByTenant bool
+	// ReportRoutes tiền tố route tính vào nhóm reports (export, đối soát...)
+	ReportRoutes []string
+	Groups       map[string]RateLimitRule
+}
+
+func GetRateLimitConfig() *RateLimitConfig {
+	return &RateLimitConfig{
+		Enabled:      getEnvAsBool("RATE_LIMIT_ENABLED", true),
+		Window:       getEnvAsDuration("RATE_LIMIT_WINDOW", time.Minute),
+		ByTenant:     getEnvAsBool("RATE_LIMIT_BY_TENANT", false),
+		ReportRoutes: splitList(getEnv("RATE_LIMIT_REPORT_ROUTES", "/api/v2/exports,/api/v2/reconciliations,/api/v2/coa-accounts/export")),
+		Groups: map[string]RateLimitRule{
+			RateLimitGroupDefault: {
+				Limit: getEnvAsInt("RATE_LIMIT_DEFAULT_LIMIT", 600),
+				Burst: getEnvAsInt("RATE_LIMIT_DEFAULT_BURST", 100),
+			},
+			RateLimitGroupWrite: {
+				Limit: getEnvAsInt("RATE_LIMIT_WRITE_LIMIT", 300),
+				Burst: getEnvAsInt("RATE_LIMIT_WRITE_BURST", 50),
+			},
+			RateLimitGroupReports: {
+				Limit: getEnvAsInt("RATE_LIMIT_REPORTS_LIMIT", 30),
+				Burst: getEnvAsInt("RATE_LIMIT_REPORTS_BURST", 5),
+			},
+		},
+	}
+}
+
+func splitList(value string) []string {
+	var out []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
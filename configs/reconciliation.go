@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ReconciliationConfig cấu hình job đối soát số dư nhà cung cấp thanh toán
+type ReconciliationConfig struct {
+	// Cron lịch chạy hằng ngày (mặc định 06:00 theo Timezone)
+	Cron     string
+	Timezone string
+	// Lookback khoảng thời gian trước cutoff dùng để tìm giao dịch chưa khớp
+	Lookback time.Duration
+	// Tolerance chênh lệch tối đa vẫn coi là khớp
+	Tolerance decimal.Decimal
+}
+
+func GetReconciliationConfig() *ReconciliationConfig {
+	tolerance, err := decimal.NewFromString(getEnv("RECONCILIATION_TOLERANCE", "0"))
+	if err != nil {
+		tolerance = decimal.Zero
+	}
+	return &ReconciliationConfig{
+		Cron:      getEnv("RECONCILIATION_CRON", "0 6 * * *"),
+		Timezone:  getEnv("RECONCILIATION_TIMEZONE", "Asia/Ho_Chi_Minh"),
+		Lookback:  getEnvAsDuration("RECONCILIATION_LOOKBACK", 24*time.Hour),
+		Tolerance: tolerance,
+	}
+}
+
+// Location trả về timezone dùng để tính cutoff, fallback UTC+7
+func (c *ReconciliationConfig) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.FixedZone("UTC+7", 7*60*60)
+	}
+	return loc
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
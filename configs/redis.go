@@ -11,12 +11,17 @@ import (
 
 var RedisClient *redis.Client
 
-func InitRedis() {
-	RedisClient = redis.NewClient(&redis.Options{
+// NewRedisClient tạo client Redis theo REDIS_ADDR/REDIS_PASSWORD (không ping)
+func NewRedisClient() *redis.Client {
+	return redis.NewClient(&redis.Options{
 		Addr:     os.Getenv("REDIS_ADDR"),
 		Password: os.Getenv("REDIS_PASSWORD"),
 		DB:       0,
 	})
+}
+
+func InitRedis() {
+	RedisClient = NewRedisClient()
 
 	ctx := context.Background()
 	for i := 0; i < 5; i++ {
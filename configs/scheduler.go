@@ -0,0 +1,35 @@
+package config
+
+import (
+	"time"
+)
+
+// SchedulerConfig cấu hình scheduler job định kỳ chạy trong worker
+type SchedulerConfig struct {
+	// Enabled tắt thì worker không enqueue job theo lịch (API vẫn liệt kê được schedule)
+	Enabled bool
+	// Timezone mặc định của biểu thức cron
+	Timezone string
+	// LeaderTTL thời gian giữ khoá leader trên Redis, replica khác tiếp quản sau tối đa một TTL khi leader chết
+	LeaderTTL time.Duration
+	// SnapshotCron lịch chốt số dư cuối ngày (mặc định 00:05 cho ngày hôm trước)
+	SnapshotCron string
+}
+
+func GetSchedulerConfig() *SchedulerConfig {
+	return &SchedulerConfig{
+		Enabled:      getEnvAsBool("SCHEDULER_ENABLED", true),
+		Timezone:     getEnv("SCHEDULER_TIMEZONE", "Asia/Ho_Chi_Minh"),
+		LeaderTTL:    getEnvAsDuration("SCHEDULER_LEADER_TTL", 15*time.Second),
+		SnapshotCron: getEnv("SNAPSHOT_EOD_CRON", "5 0 * * *"),
+	}
+}
+
+// Location trả về timezone của scheduler, fallback UTC+7
+func (c *SchedulerConfig) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.FixedZone("UTC+7", 7*60*60)
+	}
+	return loc
+}
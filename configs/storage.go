@@ -0,0 +1,58 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// StorageConfig cấu hình nơi lưu file (file export, file upload)
+type StorageConfig struct {
+	// Driver local (thư mục trên disk dùng chung giữa API và worker) hoặc s3 (S3/MinIO)
+	Driver string
+	// LocalDir thư mục gốc của driver local
+	LocalDir string
+	// SigningKey khoá HMAC ký URL tải file, mặc định dùng JWT_SECRET
+	SigningKey string
+	// PublicURL prefix của URL tải file đã ký, VD https://ledger.example.com/api/v2/files
+	PublicURL string
+	// URLTTL thời hạn mặc định của URL đã ký
+	URLTTL time.Duration
+	S3     S3StorageConfig
+}
+
+// S3StorageConfig driver s3, Endpoint để trống dùng AWS S3, đặt URL để dùng MinIO/dịch vụ tương thích S3
+type S3StorageConfig struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// UsePathStyle URL dạng {endpoint}/{bucket}/{key}, cần cho MinIO
+	UsePathStyle bool
+}
+
+// ErrStorageS3BucketMissing STORAGE_DRIVER=s3 nhưng chưa cấu hình bucket
+var ErrStorageS3BucketMissing = errors.New("STORAGE_S3_BUCKET is required when STORAGE_DRIVER=s3")
+
+// ErrStorageSigningKeyMissing thiếu cả STORAGE_SIGNING_KEY và JWT_SECRET
+var ErrStorageSigningKeyMissing = errors.New("STORAGE_SIGNING_KEY (or JWT_SECRET) is required to sign download URLs")
+
+func GetStorageConfig() *StorageConfig {
+	signingKey := getEnv("STORAGE_SIGNING_KEY", GetConfig().JWT.Secret)
+	return &StorageConfig{
+		Driver:     strings.ToLower(getEnv("STORAGE_DRIVER", "local")),
+		LocalDir:   getEnv("STORAGE_LOCAL_DIR", "./storage"),
+		SigningKey: signingKey,
+		PublicURL:  strings.TrimSuffix(getEnv("STORAGE_PUBLIC_URL", "/api/v2/files"), "/"),
+		URLTTL:     getEnvAsDuration("STORAGE_URL_TTL", 15*time.Minute),
+		S3: S3StorageConfig{
+			Endpoint:     getEnv("STORAGE_S3_ENDPOINT", ""),
+			Region:       getEnv("STORAGE_S3_REGION", "us-east-1"),
+			Bucket:       getEnv("STORAGE_S3_BUCKET", ""),
+			AccessKey:    getEnv("STORAGE_S3_ACCESS_KEY", ""),
+			SecretKey:    getEnv("STORAGE_S3_SECRET_KEY", ""),
+			UsePathStyle: getEnvAsBool("STORAGE_S3_PATH_STYLE", false),
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// Exporter của tracing
+const (
+	TracingExporterNone     = "none"
+	TracingExporterStdout   = "stdout"
+	TracingExporterOTLPFile = "otlp-file"
+	TracingExporterOTLP     = "otlp"
+)
+
+// TracingConfig cấu hình OpenTelemetry tracing
+type TracingConfig struct {
+	Enabled     bool
+	ServiceName string
+	// Exporter none | stdout | otlp-file (OTLP JSON theo dòng, dùng khi chạy local) | otlp (OTLP/HTTP)
+	Exporter string
+	// FilePath file đích của exporter otlp-file
+	FilePath string
+	// OTLPEndpoint host:port của collector nhận OTLP/HTTP
+	OTLPEndpoint string
+	OTLPInsecure bool
+	// SampleRatio tỉ lệ lấy mẫu trace gốc (0..1), span con theo quyết định của span cha
+	SampleRatio float64
+}
+
+func GetTracingConfig() *TracingConfig {
+	return &TracingConfig{
+		Enabled:      getEnvAsBool("TRACING_ENABLED", false),
+		ServiceName:  getEnv("OTEL_SERVICE_NAME", "core-ledger"),
+		Exporter:     getEnv("TRACING_EXPORTER", TracingExporterStdout),
+		FilePath:     getEnv("TRACING_FILE_PATH", "traces.jsonl"),
+		OTLPEndpoint: getEnv("TRACING_OTLP_ENDPOINT", "localhost:4318"),
+		OTLPInsecure: getEnvAsBool("TRACING_OTLP_INSECURE", true),
+		SampleRatio:  getEnvAsFloat("TRACING_SAMPLE_RATIO", 1),
+	}
+}
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,27 @@
+package config
+
+import "time"
+
+// WebhookConfig cấu hình relay outbox (transaction_logs) và gửi webhook ledger event
+type WebhookConfig struct {
+	// RelayCron lịch chạy job chuyển event PENDING trong outbox sang hàng đợi gửi webhook
+	RelayCron string
+	// RelayBatchSize số event tối đa xử lý trong một lần relay
+	RelayBatchSize int
+	// RelayMaxAttempts số lần relay lỗi tối đa trước khi event chuyển DEAD
+	RelayMaxAttempts int
+	// Timeout thời gian chờ response của endpoint nhận webhook
+	Timeout time.Duration
+	// DeliveryRetry số lần gửi lại qua asynq khi endpoint lỗi/không trả 2xx
+	DeliveryRetry int
+}
+
+func GetWebhookConfig() *WebhookConfig {
+	return &WebhookConfig{
+		RelayCron:        getEnv("WEBHOOK_RELAY_CRON", "@every 10s"),
+		RelayBatchSize:   getEnvAsInt("WEBHOOK_RELAY_BATCH_SIZE", 200),
+		RelayMaxAttempts: getEnvAsInt("WEBHOOK_RELAY_MAX_ATTEMPTS", 10),
+		Timeout:          getEnvAsDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		DeliveryRetry:    getEnvAsInt("WEBHOOK_DELIVERY_RETRY", 8),
+	}
+}
@@ -0,0 +1,15 @@
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'provider_reconciliation_items'
+    ) THEN
+        DROP TABLE provider_reconciliation_items;
+    END IF;
+
+    IF EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'provider_reconciliations'
+    ) THEN
+        DROP TABLE provider_reconciliations;
+    END IF;
+END
+$$;
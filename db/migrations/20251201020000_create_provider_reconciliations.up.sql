@@ -0,0 +1,75 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'provider_reconciliations'
+    ) THEN
+        CREATE TABLE provider_reconciliations (
+            id BIGSERIAL PRIMARY KEY,
+            cutoff_at TIMESTAMP NOT NULL,
+            system_payment_id VARCHAR(64) NOT NULL,
+            provider VARCHAR(64) NOT NULL,
+            network VARCHAR(32),
+            currency CHAR(8) NOT NULL,
+            account_id BIGINT REFERENCES coa_accounts(id) ON DELETE SET NULL ON UPDATE CASCADE,
+            account_code VARCHAR(128),
+            provider_balance NUMERIC(28,8) NOT NULL,
+            ledger_balance NUMERIC(28,8) NOT NULL,
+            variance NUMERIC(28,8) NOT NULL,
+            unmatched_count INT NOT NULL DEFAULT 0,
+            status VARCHAR(16) NOT NULL CHECK (status IN ('MATCHED','VARIANCE','NO_ACCOUNT')),
+            meta JSONB,
+            created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+
+            CONSTRAINT uniq_provider_reconciliation UNIQUE (cutoff_at, system_payment_id, currency)
+        );
+
+        CREATE INDEX idx_provider_reconciliations_cutoff ON provider_reconciliations(cutoff_at);
+        CREATE INDEX idx_provider_reconciliations_status ON provider_reconciliations(status);
+
+        COMMENT ON TABLE provider_reconciliations IS 'Kết quả đối soát số dư nhà cung cấp thanh toán với tài khoản CoA tại thời điểm cutoff';
+
+        COMMENT ON COLUMN provider_reconciliations.cutoff_at IS 'Thời điểm chốt số liệu đối soát';
+        COMMENT ON COLUMN provider_reconciliations.system_payment_id IS 'ID system payment (tài khoản tại nhà cung cấp)';
+        COMMENT ON COLUMN provider_reconciliations.provider IS 'Nhà cung cấp thanh toán';
+        COMMENT ON COLUMN provider_reconciliations.network IS 'Kênh thanh toán (payment_type của system payment)';
+        COMMENT ON COLUMN provider_reconciliations.currency IS 'Mã tiền tệ';
+        COMMENT ON COLUMN provider_reconciliations.account_id IS 'Tài khoản CoA khớp theo provider + network';
+        COMMENT ON COLUMN provider_reconciliations.account_code IS 'Mã tài khoản CoA khớp';
+        COMMENT ON COLUMN provider_reconciliations.provider_balance IS 'Số dư phía nhà cung cấp tại cutoff';
+        COMMENT ON COLUMN provider_reconciliations.ledger_balance IS 'Số dư sổ cái tại cutoff';
+        COMMENT ON COLUMN provider_reconciliations.variance IS 'Chênh lệch = provider_balance - ledger_balance';
+        COMMENT ON COLUMN provider_reconciliations.unmatched_count IS 'Số giao dịch nhà cung cấp chưa khớp sổ cái';
+        COMMENT ON COLUMN provider_reconciliations.status IS 'MATCHED, VARIANCE hoặc NO_ACCOUNT (không tìm thấy tài khoản CoA)';
+        COMMENT ON COLUMN provider_reconciliations.meta IS 'Thông tin bổ sung dạng JSON';
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'provider_reconciliation_items'
+    ) THEN
+        CREATE TABLE provider_reconciliation_items (
+            id BIGSERIAL PRIMARY KEY,
+            reconciliation_id BIGINT NOT NULL REFERENCES provider_reconciliations(id) ON DELETE CASCADE,
+            transaction_id VARCHAR(64) NOT NULL,
+            transaction_code VARCHAR(191),
+            direction VARCHAR(10),
+            provider_status VARCHAR(100),
+            amount NUMERIC(28,8) NOT NULL,
+            currency CHAR(8) NOT NULL,
+            transaction_at TIMESTAMP NOT NULL,
+            created_at TIMESTAMP DEFAULT NOW() NOT NULL
+        );
+
+        CREATE INDEX idx_provider_reconciliation_items_reconciliation ON provider_reconciliation_items(reconciliation_id);
+
+        COMMENT ON TABLE provider_reconciliation_items IS 'Giao dịch phía nhà cung cấp chưa khớp với bút toán sổ cái trong kỳ đối soát';
+
+        COMMENT ON COLUMN provider_reconciliation_items.reconciliation_id IS 'Tham chiếu provider_reconciliations';
+        COMMENT ON COLUMN provider_reconciliation_items.transaction_id IS 'ID system_payment_transactions';
+        COMMENT ON COLUMN provider_reconciliation_items.transaction_code IS 'Mã giao dịch phía nhà cung cấp';
+        COMMENT ON COLUMN provider_reconciliation_items.direction IS 'Chiều giao dịch: IN / OUT';
+        COMMENT ON COLUMN provider_reconciliation_items.provider_status IS 'Trạng thái giao dịch phía nhà cung cấp';
+        COMMENT ON COLUMN provider_reconciliation_items.amount IS 'Số tiền giao dịch';
+        COMMENT ON COLUMN provider_reconciliation_items.transaction_at IS 'Thời điểm phát sinh giao dịch';
+    END IF;
+END
+$$;
@@ -0,0 +1,9 @@
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'holds'
+    ) THEN
+        DROP TABLE holds;
+    END IF;
+END
+$$;
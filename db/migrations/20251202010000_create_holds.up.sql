@@ -0,0 +1,44 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'holds'
+    ) THEN
+        CREATE TABLE holds (
+            id BIGSERIAL PRIMARY KEY,
+            account_id BIGINT NOT NULL REFERENCES coa_accounts(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+            amount NUMERIC(28,8) NOT NULL CHECK (amount > 0),
+            captured_amount NUMERIC(28,8) NOT NULL DEFAULT 0 CHECK (captured_amount >= 0 AND captured_amount <= amount),
+            currency CHAR(8) NOT NULL,
+            reference VARCHAR(191) NOT NULL UNIQUE,
+            status VARCHAR(16) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING','CAPTURED','RELEASED','EXPIRED')),
+            expires_at TIMESTAMP NOT NULL,
+            memo VARCHAR(256),
+            meta JSONB,
+            captured_at TIMESTAMP,
+            released_at TIMESTAMP,
+            tenant_id VARCHAR(36),
+            ledger_code VARCHAR(32),
+            created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            updated_at TIMESTAMP DEFAULT NOW() NOT NULL
+        );
+
+        CREATE INDEX idx_holds_account_status ON holds(account_id, status);
+        CREATE INDEX idx_holds_expires_at ON holds(expires_at) WHERE status = 'PENDING';
+
+        COMMENT ON TABLE holds IS 'Tạm giữ số dư tài khoản trước khi thanh toán được settle';
+
+        COMMENT ON COLUMN holds.account_id IS 'Tài khoản CoA bị tạm giữ số dư';
+        COMMENT ON COLUMN holds.amount IS 'Số tiền tạm giữ ban đầu';
+        COMMENT ON COLUMN holds.captured_amount IS 'Số tiền đã capture (ghi sổ), còn giữ = amount - captured_amount';
+        COMMENT ON COLUMN holds.currency IS 'Mã tiền tệ, trùng với currency của tài khoản';
+        COMMENT ON COLUMN holds.reference IS 'Mã tham chiếu duy nhất từ hệ thống gọi (VD: mã thanh toán)';
+        COMMENT ON COLUMN holds.status IS 'PENDING, CAPTURED, RELEASED hoặc EXPIRED';
+        COMMENT ON COLUMN holds.expires_at IS 'Thời điểm hết hạn, job định kỳ sẽ chuyển hold PENDING quá hạn sang EXPIRED';
+        COMMENT ON COLUMN holds.memo IS 'Ghi chú';
+        COMMENT ON COLUMN holds.meta IS 'Thông tin bổ sung dạng JSON (journal đã capture...)';
+        COMMENT ON COLUMN holds.captured_at IS 'Thời điểm capture gần nhất';
+        COMMENT ON COLUMN holds.released_at IS 'Thời điểm release/hết hạn';
+        COMMENT ON COLUMN holds.tenant_id IS 'Tenant sở hữu';
+        COMMENT ON COLUMN holds.ledger_code IS 'Mã sổ cái';
+    END IF;
+END $$;
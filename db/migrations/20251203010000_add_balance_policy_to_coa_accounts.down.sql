@@ -0,0 +1,8 @@
+DO $$
+BEGIN
+    ALTER TABLE coa_accounts
+        DROP COLUMN IF EXISTS allow_negative,
+        DROP COLUMN IF EXISTS min_balance,
+        DROP COLUMN IF EXISTS overdraft_limit;
+END
+$$;
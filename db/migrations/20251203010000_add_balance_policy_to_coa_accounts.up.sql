@@ -0,0 +1,20 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM information_schema.columns
+        WHERE table_schema = 'public' AND table_name = 'coa_accounts' AND column_name = 'allow_negative'
+    ) THEN
+        ALTER TABLE coa_accounts
+            ADD COLUMN allow_negative BOOLEAN NOT NULL DEFAULT FALSE,
+            ADD COLUMN min_balance NUMERIC(28,8) NULL CHECK (min_balance >= 0),
+            ADD COLUMN overdraft_limit NUMERIC(28,8) NULL CHECK (overdraft_limit >= 0);
+
+        -- Giữ nguyên hành vi cho tài khoản hệ thống hiện có, chỉ chặn âm tài khoản công nợ khách hàng (LIAB)
+        UPDATE coa_accounts SET allow_negative = TRUE WHERE type <> 'LIAB';
+
+        COMMENT ON COLUMN coa_accounts.allow_negative IS 'Cho phép số dư âm không giới hạn (bỏ qua min_balance/overdraft_limit)';
+        COMMENT ON COLUMN coa_accounts.min_balance IS 'Số dư tối thiểu phải giữ lại sau khi ghi sổ (mặc định 0)';
+        COMMENT ON COLUMN coa_accounts.overdraft_limit IS 'Hạn mức thấu chi: số dư được phép xuống tới min_balance - overdraft_limit';
+    END IF;
+END
+$$;
@@ -0,0 +1,9 @@
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'account_balances'
+    ) THEN
+        DROP TABLE account_balances;
+    END IF;
+END
+$$;
@@ -0,0 +1,46 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'account_balances'
+    ) THEN
+        CREATE TABLE account_balances (
+            account_id BIGINT PRIMARY KEY REFERENCES coa_accounts(id) ON DELETE CASCADE ON UPDATE CASCADE,
+            currency CHAR(8) NOT NULL,
+            debit_total NUMERIC(28,8) NOT NULL DEFAULT 0,
+            credit_total NUMERIC(28,8) NOT NULL DEFAULT 0,
+            balance NUMERIC(28,8) NOT NULL DEFAULT 0,
+            version BIGINT NOT NULL DEFAULT 0,
+            last_entry_id BIGINT,
+            updated_at TIMESTAMP DEFAULT NOW() NOT NULL
+        );
+
+        -- Khởi tạo số dư từ entries của các journal đã ghi sổ
+        INSERT INTO account_balances (account_id, currency, debit_total, credit_total, balance, version, last_entry_id, updated_at)
+        SELECT a.id,
+               a.currency,
+               COALESCE(SUM(e.amount) FILTER (WHERE e.dc = 'D'), 0),
+               COALESCE(SUM(e.amount) FILTER (WHERE e.dc = 'C'), 0),
+               CASE WHEN a.type IN ('ASSET','EXP')
+                    THEN COALESCE(SUM(e.amount) FILTER (WHERE e.dc = 'D'), 0) - COALESCE(SUM(e.amount) FILTER (WHERE e.dc = 'C'), 0)
+                    ELSE COALESCE(SUM(e.amount) FILTER (WHERE e.dc = 'C'), 0) - COALESCE(SUM(e.amount) FILTER (WHERE e.dc = 'D'), 0)
+               END,
+               0,
+               MAX(e.id),
+               NOW()
+        FROM coa_accounts a
+        JOIN entries e ON e.account_id = a.id
+        JOIN journals j ON j.id = e.journal_id AND j.status IN ('POSTED','REVERSED')
+        GROUP BY a.id, a.currency, a.type;
+
+        COMMENT ON TABLE account_balances IS 'Số dư tổng hợp theo tài khoản, cập nhật cùng transaction ghi entries';
+
+        COMMENT ON COLUMN account_balances.account_id IS 'Tài khoản CoA';
+        COMMENT ON COLUMN account_balances.currency IS 'Mã tiền tệ của tài khoản';
+        COMMENT ON COLUMN account_balances.debit_total IS 'Tổng phát sinh Nợ';
+        COMMENT ON COLUMN account_balances.credit_total IS 'Tổng phát sinh Có';
+        COMMENT ON COLUMN account_balances.balance IS 'Số dư theo tính chất tài khoản (ASSET/EXP: Nợ - Có, còn lại: Có - Nợ)';
+        COMMENT ON COLUMN account_balances.version IS 'Phiên bản cho optimistic locking, tăng mỗi lần cập nhật';
+        COMMENT ON COLUMN account_balances.last_entry_id IS 'Entry mới nhất đã được cộng vào số dư';
+        COMMENT ON COLUMN account_balances.updated_at IS 'Thời điểm cập nhật cuối';
+    END IF;
+END $$;
@@ -0,0 +1,10 @@
+DO $$
+BEGIN
+    DROP INDEX IF EXISTS idx_journals_batch_reversal;
+    IF EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'journal_batches'
+    ) THEN
+        DROP TABLE journal_batches;
+    END IF;
+END
+$$;
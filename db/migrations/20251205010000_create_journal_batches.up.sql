@@ -0,0 +1,47 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'journal_batches'
+    ) THEN
+        CREATE TABLE journal_batches (
+            id VARCHAR(36) PRIMARY KEY,
+            mode VARCHAR(16) NOT NULL CHECK (mode IN ('ATOMIC','BEST_EFFORT')),
+            status VARCHAR(16) NOT NULL CHECK (status IN ('PROCESSING','POSTED','PARTIAL','FAILED','REVERSED')),
+            source VARCHAR(64) NOT NULL,
+            memo VARCHAR(256),
+            meta JSONB,
+            total_items INT NOT NULL DEFAULT 0,
+            posted_count INT NOT NULL DEFAULT 0,
+            failed_count INT NOT NULL DEFAULT 0,
+            error TEXT,
+            created_by VARCHAR(64),
+            reversed_by VARCHAR(64),
+            reversed_at TIMESTAMP,
+            tenant_id VARCHAR(36),
+            ledger_code VARCHAR(32),
+            created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            updated_at TIMESTAMP DEFAULT NOW() NOT NULL
+        );
+
+        CREATE INDEX idx_journal_batches_status ON journal_batches(status);
+        CREATE INDEX idx_journals_batch_reversal ON journals(batch_id, reversal_of);
+
+        COMMENT ON TABLE journal_batches IS 'Batch ghi sổ nhiều journal cùng lúc (VD: file settlement cuối ngày)';
+
+        COMMENT ON COLUMN journal_batches.id IS 'batch_id (UUID), trùng với journals.batch_id và entries.batch_id';
+        COMMENT ON COLUMN journal_batches.mode IS 'ATOMIC: tất cả cùng thành công hoặc cùng thất bại, BEST_EFFORT: ghi từng journal, trả kết quả từng item';
+        COMMENT ON COLUMN journal_batches.status IS 'PROCESSING, POSTED, PARTIAL, FAILED hoặc REVERSED';
+        COMMENT ON COLUMN journal_batches.source IS 'Nguồn phát sinh batch';
+        COMMENT ON COLUMN journal_batches.memo IS 'Ghi chú';
+        COMMENT ON COLUMN journal_batches.meta IS 'Thông tin bổ sung dạng JSON (tên file settlement...)';
+        COMMENT ON COLUMN journal_batches.total_items IS 'Số journal trong request';
+        COMMENT ON COLUMN journal_batches.posted_count IS 'Số journal ghi sổ thành công';
+        COMMENT ON COLUMN journal_batches.failed_count IS 'Số journal lỗi (ATOMIC lỗi thì toàn bộ batch bị rollback)';
+        COMMENT ON COLUMN journal_batches.error IS 'Lỗi khiến batch ATOMIC bị rollback';
+        COMMENT ON COLUMN journal_batches.created_by IS 'Người tạo batch';
+        COMMENT ON COLUMN journal_batches.reversed_by IS 'Người đảo batch';
+        COMMENT ON COLUMN journal_batches.reversed_at IS 'Thời điểm đảo batch';
+        COMMENT ON COLUMN journal_batches.tenant_id IS 'Tenant sở hữu';
+        COMMENT ON COLUMN journal_batches.ledger_code IS 'Mã sổ cái';
+    END IF;
+END $$;
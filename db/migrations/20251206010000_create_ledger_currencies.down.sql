@@ -0,0 +1,9 @@
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'ledger_currencies'
+    ) THEN
+        DROP TABLE ledger_currencies;
+    END IF;
+END
+$$;
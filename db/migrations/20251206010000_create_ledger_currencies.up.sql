@@ -0,0 +1,62 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'ledger_currencies'
+    ) THEN
+        CREATE TABLE ledger_currencies (
+            code VARCHAR(8) PRIMARY KEY,
+            name VARCHAR(64) NOT NULL,
+            symbol VARCHAR(16),
+            scale INT NOT NULL CHECK (scale BETWEEN 0 AND 8),
+            active BOOLEAN NOT NULL DEFAULT TRUE,
+            source VARCHAR(32) NOT NULL,
+            created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            updated_at TIMESTAMP DEFAULT NOW() NOT NULL
+        );
+
+        COMMENT ON TABLE ledger_currencies IS 'Danh mục loại tiền của sổ cái và số chữ số thập phân của đơn vị nhỏ nhất';
+
+        COMMENT ON COLUMN ledger_currencies.code IS 'Mã tiền tệ (VND, USD, USDT...), trùng với journals.currency / coa_accounts.currency';
+        COMMENT ON COLUMN ledger_currencies.name IS 'Tên loại tiền';
+        COMMENT ON COLUMN ledger_currencies.symbol IS 'Ký hiệu hiển thị';
+        COMMENT ON COLUMN ledger_currencies.scale IS 'Số chữ số thập phân cho phép, amount_atoms = amount * 10^scale';
+        COMMENT ON COLUMN ledger_currencies.active IS 'FALSE: không cho ghi sổ mới bằng loại tiền này';
+        COMMENT ON COLUMN ledger_currencies.source IS 'Nguồn khởi tạo: DEFAULT, RULE_CATEGORY, WEALIFY hoặc MANUAL';
+
+        INSERT INTO ledger_currencies (code, name, symbol, scale, source) VALUES
+            ('VND', 'Vietnamese Dong', '₫', 0, 'DEFAULT'),
+            ('VNDW', 'Wealify VND Wallet', '₫', 0, 'DEFAULT'),
+            ('VNDY', 'Wealify VND Wallet Y', '₫', 0, 'DEFAULT'),
+            ('USD', 'US Dollar', '$', 2, 'DEFAULT'),
+            ('EUR', 'Euro', '€', 2, 'DEFAULT'),
+            ('GBP', 'Pound Sterling', '£', 2, 'DEFAULT'),
+            ('SGD', 'Singapore Dollar', 'S$', 2, 'DEFAULT'),
+            ('JPY', 'Japanese Yen', '¥', 0, 'DEFAULT'),
+            ('KRW', 'Korean Won', '₩', 0, 'DEFAULT'),
+            ('USDT', 'Tether USD', NULL, 6, 'DEFAULT'),
+            ('USDC', 'USD Coin', NULL, 6, 'DEFAULT'),
+            ('BTC', 'Bitcoin', '₿', 8, 'DEFAULT'),
+            ('ETH', 'Ether', 'Ξ', 8, 'DEFAULT')
+        ON CONFLICT (code) DO NOTHING;
+
+        -- Loại tiền đang có trong sổ cái nhưng chưa có trong danh mục: giữ scale 8 như numeric(28,8) hiện tại
+        INSERT INTO ledger_currencies (code, name, scale, source)
+        SELECT DISTINCT TRIM(currency), TRIM(currency), 8, 'DEFAULT'
+        FROM (
+            SELECT currency FROM coa_accounts
+            UNION
+            SELECT currency FROM journals
+        ) c
+        WHERE TRIM(currency) <> ''
+        ON CONFLICT (code) DO NOTHING;
+
+        -- Điền amount_atoms cho entries cũ nếu amount khớp scale
+        UPDATE entries e
+        SET amount_atoms = (e.amount * power(10::numeric, lc.scale))::BIGINT
+        FROM journals j
+        JOIN ledger_currencies lc ON lc.code = TRIM(j.currency)
+        WHERE e.journal_id = j.id
+          AND e.amount_atoms IS NULL
+          AND e.amount * power(10::numeric, lc.scale) = trunc(e.amount * power(10::numeric, lc.scale));
+    END IF;
+END $$;
@@ -0,0 +1,9 @@
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'api_keys'
+    ) THEN
+        DROP TABLE api_keys;
+    END IF;
+END
+$$;
@@ -0,0 +1,39 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'api_keys'
+    ) THEN
+        CREATE TABLE api_keys (
+            id BIGSERIAL PRIMARY KEY,
+            name VARCHAR(128) NOT NULL,
+            prefix VARCHAR(16) NOT NULL,
+            key_hash VARCHAR(64) NOT NULL UNIQUE,
+            owner_service VARCHAR(64) NOT NULL,
+            tenant_id VARCHAR(36),
+            scopes JSONB NOT NULL DEFAULT '[]'::jsonb,
+            expires_at TIMESTAMP,
+            last_used_at TIMESTAMP,
+            revoked_at TIMESTAMP,
+            rotated_from_id BIGINT REFERENCES api_keys(id) ON DELETE SET NULL,
+            created_by VARCHAR(64),
+            created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            updated_at TIMESTAMP DEFAULT NOW() NOT NULL
+        );
+
+        CREATE INDEX idx_api_keys_owner_service ON api_keys(owner_service);
+
+        COMMENT ON TABLE api_keys IS 'API key cho service nội bộ gọi ledger (header X-API-Key)';
+
+        COMMENT ON COLUMN api_keys.name IS 'Tên gợi nhớ của key';
+        COMMENT ON COLUMN api_keys.prefix IS 'Phần đầu của key để nhận diện, không dùng để xác thực';
+        COMMENT ON COLUMN api_keys.key_hash IS 'HMAC-SHA256 của key (API_SECRET_KEY), không lưu plaintext';
+        COMMENT ON COLUMN api_keys.owner_service IS 'Service sở hữu key (wallet, va, card...)';
+        COMMENT ON COLUMN api_keys.tenant_id IS 'Tenant được phép thao tác';
+        COMMENT ON COLUMN api_keys.scopes IS 'Danh sách quyền (trùng tên permission, VD: ledger.journal.post)';
+        COMMENT ON COLUMN api_keys.expires_at IS 'Thời điểm hết hạn, NULL = không hết hạn';
+        COMMENT ON COLUMN api_keys.last_used_at IS 'Lần xác thực thành công gần nhất';
+        COMMENT ON COLUMN api_keys.revoked_at IS 'Thời điểm thu hồi';
+        COMMENT ON COLUMN api_keys.rotated_from_id IS 'Key cũ đã được rotate sang key này';
+        COMMENT ON COLUMN api_keys.created_by IS 'Người tạo key';
+    END IF;
+END $$;
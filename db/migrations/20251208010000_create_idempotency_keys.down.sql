@@ -0,0 +1,9 @@
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'idempotency_keys'
+    ) THEN
+        DROP TABLE idempotency_keys;
+    END IF;
+END
+$$;
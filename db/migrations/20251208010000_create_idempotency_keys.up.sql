@@ -0,0 +1,39 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'idempotency_keys'
+    ) THEN
+        CREATE TABLE idempotency_keys (
+            id BIGSERIAL PRIMARY KEY,
+            principal VARCHAR(64) NOT NULL,
+            idempotency_key VARCHAR(128) NOT NULL,
+            route VARCHAR(255) NOT NULL,
+            request_hash VARCHAR(64) NOT NULL,
+            status VARCHAR(16) NOT NULL,
+            response_code INT NOT NULL DEFAULT 0,
+            response_body BYTEA,
+            content_type VARCHAR(128),
+            lock_token VARCHAR(36),
+            locked_until TIMESTAMP,
+            expires_at TIMESTAMP NOT NULL,
+            created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            updated_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            CONSTRAINT uq_idempotency_keys_scope UNIQUE (principal, idempotency_key, route)
+        );
+
+        CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+        COMMENT ON TABLE idempotency_keys IS 'Kết quả request ghi sổ theo Idempotency-Key để replay khi client gửi lại';
+
+        COMMENT ON COLUMN idempotency_keys.principal IS 'Chủ thể gửi request (employee:<id> hoặc api_key:<id>)';
+        COMMENT ON COLUMN idempotency_keys.idempotency_key IS 'Giá trị header Idempotency-Key';
+        COMMENT ON COLUMN idempotency_keys.route IS 'Method + path của request';
+        COMMENT ON COLUMN idempotency_keys.request_hash IS 'SHA-256 của body, khác hash = dùng lại key cho request khác';
+        COMMENT ON COLUMN idempotency_keys.status IS 'IN_PROGRESS, COMPLETED';
+        COMMENT ON COLUMN idempotency_keys.response_code IS 'HTTP status của response đã lưu';
+        COMMENT ON COLUMN idempotency_keys.response_body IS 'Body của response đã lưu';
+        COMMENT ON COLUMN idempotency_keys.lock_token IS 'Token của request đang giữ key';
+        COMMENT ON COLUMN idempotency_keys.locked_until IS 'Hết thời gian này request IN_PROGRESS được coi là treo, cho phép chạy lại';
+        COMMENT ON COLUMN idempotency_keys.expires_at IS 'Hết hạn replay, job dọn dẹp sẽ xoá';
+    END IF;
+END $$;
@@ -0,0 +1,7 @@
+DO $$
+BEGIN
+    DROP INDEX IF EXISTS idx_transaction_logs_relay;
+    ALTER TABLE coa_accounts
+        DROP COLUMN IF EXISTS alert_threshold;
+END
+$$;
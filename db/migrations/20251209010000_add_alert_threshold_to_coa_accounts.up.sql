@@ -0,0 +1,20 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM information_schema.columns
+        WHERE table_schema = 'public' AND table_name = 'coa_accounts' AND column_name = 'alert_threshold'
+    ) THEN
+        ALTER TABLE coa_accounts
+            ADD COLUMN alert_threshold NUMERIC(28,8) NULL CHECK (alert_threshold >= 0);
+
+        COMMENT ON COLUMN coa_accounts.alert_threshold IS 'Ngưỡng cảnh báo số dư, số dư đi qua ngưỡng sinh event balance.threshold_crossed';
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT FROM pg_indexes WHERE schemaname = 'public' AND indexname = 'idx_transaction_logs_relay'
+    ) THEN
+        -- relay webhook quét outbox PENDING theo thứ tự id
+        CREATE INDEX idx_transaction_logs_relay ON transaction_logs(id) WHERE status = 'PENDING';
+    END IF;
+END
+$$;
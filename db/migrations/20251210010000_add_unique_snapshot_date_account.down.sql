@@ -0,0 +1 @@
+DROP INDEX IF EXISTS uniq_snapshots_date_account;
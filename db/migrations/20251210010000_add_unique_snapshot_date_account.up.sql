@@ -0,0 +1,10 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM pg_indexes WHERE schemaname = 'public' AND indexname = 'uniq_snapshots_date_account'
+    ) THEN
+        -- mỗi tài khoản chỉ có một snapshot EOD cho mỗi ngày, job chạy lại không tạo bản ghi trùng
+        CREATE UNIQUE INDEX uniq_snapshots_date_account ON snapshots(as_of_date, account_id);
+    END IF;
+END
+$$;
@@ -0,0 +1,9 @@
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'failed_jobs'
+    ) THEN
+        DROP TABLE failed_jobs;
+    END IF;
+END
+$$;
@@ -0,0 +1,45 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'failed_jobs'
+    ) THEN
+        CREATE TABLE failed_jobs (
+            id BIGSERIAL PRIMARY KEY,
+            task_id VARCHAR(64) NOT NULL,
+            type VARCHAR(128) NOT NULL,
+            queue VARCHAR(64) NOT NULL,
+            payload JSONB NOT NULL,
+            error TEXT NOT NULL,
+            attempts INT NOT NULL,
+            max_retry INT NOT NULL,
+            handler VARCHAR(255),
+            status VARCHAR(16) NOT NULL,
+            retry_count INT NOT NULL DEFAULT 0,
+            first_failed_at TIMESTAMP NOT NULL,
+            last_failed_at TIMESTAMP NOT NULL,
+            retried_at TIMESTAMP,
+            created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            updated_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            CONSTRAINT uq_failed_jobs_task_id UNIQUE (task_id)
+        );
+
+        CREATE INDEX idx_failed_jobs_type ON failed_jobs(type);
+        CREATE INDEX idx_failed_jobs_status ON failed_jobs(status);
+
+        COMMENT ON TABLE failed_jobs IS 'Job hàng đợi lỗi lần cuối (hết retry hoặc SkipRetry), dùng để tra cứu và chạy lại';
+
+        COMMENT ON COLUMN failed_jobs.task_id IS 'ID task asynq, chạy lại giữ nguyên ID';
+        COMMENT ON COLUMN failed_jobs.type IS 'Loại job, VD import_coa_account';
+        COMMENT ON COLUMN failed_jobs.queue IS 'Queue asynq của job';
+        COMMENT ON COLUMN failed_jobs.payload IS 'Payload gốc của task, dùng để enqueue lại';
+        COMMENT ON COLUMN failed_jobs.error IS 'Lỗi của lần chạy cuối';
+        COMMENT ON COLUMN failed_jobs.attempts IS 'Tổng số lần đã chạy, cộng dồn qua các lần chạy lại';
+        COMMENT ON COLUMN failed_jobs.max_retry IS 'Số lần retry tối đa của task';
+        COMMENT ON COLUMN failed_jobs.handler IS 'Kiểu handler xử lý job';
+        COMMENT ON COLUMN failed_jobs.status IS 'FAILED, RETRIED';
+        COMMENT ON COLUMN failed_jobs.retry_count IS 'Số lần chạy lại thủ công';
+        COMMENT ON COLUMN failed_jobs.first_failed_at IS 'Thời điểm lỗi lần cuối đầu tiên';
+        COMMENT ON COLUMN failed_jobs.last_failed_at IS 'Thời điểm lỗi gần nhất';
+        COMMENT ON COLUMN failed_jobs.retried_at IS 'Thời điểm chạy lại thủ công gần nhất';
+    END IF;
+END $$;
@@ -0,0 +1,9 @@
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'job_runs'
+    ) THEN
+        DROP TABLE job_runs;
+    END IF;
+END
+$$;
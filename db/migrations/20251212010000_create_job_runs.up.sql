@@ -0,0 +1,44 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'job_runs'
+    ) THEN
+        CREATE TABLE job_runs (
+            id BIGSERIAL PRIMARY KEY,
+            task_id VARCHAR(64) NOT NULL,
+            type VARCHAR(128) NOT NULL,
+            queue VARCHAR(64) NOT NULL,
+            state VARCHAR(16) NOT NULL,
+            processed BIGINT NOT NULL DEFAULT 0,
+            total BIGINT NOT NULL DEFAULT 0,
+            message TEXT,
+            result JSONB,
+            error TEXT,
+            attempts INT NOT NULL DEFAULT 0,
+            max_retry INT NOT NULL DEFAULT 0,
+            started_at TIMESTAMP NOT NULL,
+            finished_at TIMESTAMP,
+            created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            updated_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            CONSTRAINT uq_job_runs_task_id UNIQUE (task_id)
+        );
+
+        CREATE INDEX idx_job_runs_type ON job_runs(type);
+
+        COMMENT ON TABLE job_runs IS 'Tiến độ và kết quả job do handler báo, đọc qua GET /jobs/:id';
+
+        COMMENT ON COLUMN job_runs.task_id IS 'ID task asynq (hoặc ID do driver sync/memory sinh)';
+        COMMENT ON COLUMN job_runs.type IS 'Loại job, VD import_coa_account';
+        COMMENT ON COLUMN job_runs.queue IS 'Queue của job';
+        COMMENT ON COLUMN job_runs.state IS 'active, retry, completed, failed';
+        COMMENT ON COLUMN job_runs.processed IS 'Số đơn vị đã xử lý';
+        COMMENT ON COLUMN job_runs.total IS 'Tổng số đơn vị cần xử lý, 0 nếu chưa biết';
+        COMMENT ON COLUMN job_runs.message IS 'Mô tả bước đang chạy';
+        COMMENT ON COLUMN job_runs.result IS 'Kết quả JSON handler ghi qua SetResult';
+        COMMENT ON COLUMN job_runs.error IS 'Lỗi của lần chạy gần nhất';
+        COMMENT ON COLUMN job_runs.attempts IS 'Số lần đã chạy';
+        COMMENT ON COLUMN job_runs.max_retry IS 'Số lần retry tối đa của task';
+        COMMENT ON COLUMN job_runs.started_at IS 'Thời điểm bắt đầu lần chạy gần nhất';
+        COMMENT ON COLUMN job_runs.finished_at IS 'Thời điểm kết thúc (thành công hoặc lỗi lần cuối)';
+    END IF;
+END $$;
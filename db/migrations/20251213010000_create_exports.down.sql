@@ -0,0 +1,9 @@
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'exports'
+    ) THEN
+        DROP TABLE exports;
+    END IF;
+END
+$$;
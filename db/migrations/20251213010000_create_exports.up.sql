@@ -0,0 +1,46 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'exports'
+    ) THEN
+        CREATE TABLE exports (
+            id BIGSERIAL PRIMARY KEY,
+            resource VARCHAR(64) NOT NULL,
+            format VARCHAR(8) NOT NULL,
+            status VARCHAR(16) NOT NULL DEFAULT 'PENDING',
+            file_name VARCHAR(255) NOT NULL,
+            storage_key VARCHAR(512),
+            columns JSONB,
+            query JSONB,
+            rows BIGINT NOT NULL DEFAULT 0,
+            size BIGINT NOT NULL DEFAULT 0,
+            error TEXT,
+            job_id VARCHAR(64),
+            requested_by VARCHAR(128) NOT NULL,
+            expires_at TIMESTAMP,
+            started_at TIMESTAMP,
+            completed_at TIMESTAMP,
+            created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            updated_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            CONSTRAINT chk_exports_status CHECK (status IN ('PENDING', 'RUNNING', 'COMPLETED', 'FAILED'))
+        );
+
+        CREATE INDEX idx_exports_requested_by ON exports(requested_by, id DESC);
+
+        COMMENT ON TABLE exports IS 'Lịch sử export dữ liệu (XLSX/CSV/JSONL) chạy qua queue';
+
+        COMMENT ON COLUMN exports.resource IS 'Resource được export: coa_accounts, entries, journals, snapshots, transaction_logs';
+        COMMENT ON COLUMN exports.format IS 'xlsx, csv, jsonl';
+        COMMENT ON COLUMN exports.status IS 'PENDING, RUNNING, COMPLETED, FAILED';
+        COMMENT ON COLUMN exports.file_name IS 'Tên file khi tải về';
+        COMMENT ON COLUMN exports.storage_key IS 'Key của file trong storage, có khi COMPLETED';
+        COMMENT ON COLUMN exports.columns IS 'Danh sách key cột đã chọn, rỗng là tất cả';
+        COMMENT ON COLUMN exports.query IS 'Filter của list endpoint tương ứng';
+        COMMENT ON COLUMN exports.rows IS 'Số dòng dữ liệu đã ghi';
+        COMMENT ON COLUMN exports.size IS 'Kích thước file (byte)';
+        COMMENT ON COLUMN exports.error IS 'Lỗi khi export thất bại';
+        COMMENT ON COLUMN exports.job_id IS 'Task ID của job export, tra tiến độ qua GET /jobs/:id';
+        COMMENT ON COLUMN exports.requested_by IS 'Principal tạo export (employee:<id>, api_key:<id>)';
+        COMMENT ON COLUMN exports.expires_at IS 'Hết hạn tải file';
+    END IF;
+END $$;
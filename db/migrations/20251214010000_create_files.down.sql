@@ -0,0 +1,29 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM information_schema.columns
+        WHERE table_schema = 'public' AND table_name = 'exports' AND column_name = 'storage_key'
+    ) THEN
+        ALTER TABLE exports ADD COLUMN storage_key VARCHAR(512);
+
+        COMMENT ON COLUMN exports.storage_key IS 'Key của file trong storage, có khi COMPLETED';
+    END IF;
+
+    IF EXISTS (
+        SELECT FROM information_schema.columns
+        WHERE table_schema = 'public' AND table_name = 'exports' AND column_name = 'file_id'
+    ) THEN
+        UPDATE exports e SET storage_key = f.storage_key
+        FROM files f
+        WHERE f.id = e.file_id;
+
+        ALTER TABLE exports DROP COLUMN file_id;
+    END IF;
+
+    IF EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'files'
+    ) THEN
+        DROP TABLE files;
+    END IF;
+END
+$$;
@@ -0,0 +1,60 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT FROM pg_tables WHERE schemaname = 'public' AND tablename = 'files'
+    ) THEN
+        CREATE TABLE files (
+            id BIGSERIAL PRIMARY KEY,
+            storage_key VARCHAR(512) NOT NULL,
+            driver VARCHAR(16) NOT NULL,
+            original_name VARCHAR(255) NOT NULL,
+            content_type VARCHAR(255),
+            size BIGINT NOT NULL DEFAULT 0,
+            sha256 VARCHAR(64) NOT NULL,
+            purpose VARCHAR(32) NOT NULL,
+            created_by VARCHAR(128),
+            created_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            updated_at TIMESTAMP DEFAULT NOW() NOT NULL,
+            CONSTRAINT uq_files_storage_key UNIQUE (storage_key)
+        );
+
+        CREATE INDEX idx_files_sha256 ON files(sha256);
+
+        COMMENT ON TABLE files IS 'Metadata file lưu trong storage (upload import, file export)';
+
+        COMMENT ON COLUMN files.storage_key IS 'Key của file trong storage';
+        COMMENT ON COLUMN files.driver IS 'Storage driver lúc ghi file: local, s3';
+        COMMENT ON COLUMN files.original_name IS 'Tên file gốc (upload) hoặc tên file tải về (export)';
+        COMMENT ON COLUMN files.size IS 'Kích thước file (byte)';
+        COMMENT ON COLUMN files.sha256 IS 'SHA-256 (hex) của nội dung file';
+        COMMENT ON COLUMN files.purpose IS 'Mục đích: imports, exports';
+        COMMENT ON COLUMN files.created_by IS 'Principal tạo file (employee:<id>, api_key:<id>)';
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT FROM information_schema.columns
+        WHERE table_schema = 'public' AND table_name = 'exports' AND column_name = 'file_id'
+    ) THEN
+        ALTER TABLE exports ADD COLUMN file_id BIGINT REFERENCES files(id) ON DELETE SET NULL;
+
+        COMMENT ON COLUMN exports.file_id IS 'File kết quả (bảng files), có khi COMPLETED';
+    END IF;
+
+    -- chuyển file export cũ sang bảng files; export ghi trước khi có hash nên sha256 để trống
+    IF EXISTS (
+        SELECT FROM information_schema.columns
+        WHERE table_schema = 'public' AND table_name = 'exports' AND column_name = 'storage_key'
+    ) THEN
+        INSERT INTO files (storage_key, driver, original_name, content_type, size, sha256, purpose, created_by, created_at, updated_at)
+        SELECT e.storage_key, 'local', e.file_name, NULL, e.size, '', 'exports', e.requested_by, COALESCE(e.completed_at, e.created_at), COALESCE(e.completed_at, e.created_at)
+        FROM exports e
+        WHERE e.storage_key IS NOT NULL
+        ON CONFLICT (storage_key) DO NOTHING;
+
+        UPDATE exports e SET file_id = f.id
+        FROM files f
+        WHERE f.storage_key = e.storage_key AND e.file_id IS NULL;
+
+        ALTER TABLE exports DROP COLUMN storage_key;
+    END IF;
+END $$;
@@ -0,0 +1,89 @@
+package seeder
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+	wealify "core-ledger/model/wealify"
+	"core-ledger/pkg/repo"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// defaultCurrencyScale scale cho loại tiền chưa có trong knownCurrencyScales
+const defaultCurrencyScale = 2
+
+// knownCurrencyScales số chữ số thập phân của đơn vị nhỏ nhất
+var knownCurrencyScales = map[string]int32{
+	"VND":  0,
+	"VNDW": 0,
+	"VNDY": 0,
+	"JPY":  0,
+	"KRW":  0,
+	"USD":  2,
+	"EUR":  2,
+	"GBP":  2,
+	"SGD":  2,
+	"USDT": 6,
+	"USDC": 6,
+	"BTC":  8,
+	"ETH":  8,
+}
+
+// SeederLedgerCurrencies bổ sung ledger_currencies từ rule category CURRENCY và bảng currencies (wealify).
+// Currency đã có không bị ghi đè scale.
+func SeederLedgerCurrencies(db *gorm.DB) error {
+	byCode := map[string]*model.LedgerCurrency{}
+	add := func(code, name, source string, symbol *string) {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" || len(code) > 8 {
+			return
+		}
+		if _, ok := byCode[code]; ok {
+			return
+		}
+		scale, ok := knownCurrencyScales[code]
+		if !ok {
+			scale = defaultCurrencyScale
+		}
+		if name == "" {
+			name = code
+		}
+		byCode[code] = &model.LedgerCurrency{Code: code, Name: name, Symbol: symbol, Scale: scale, Active: true, Source: source}
+	}
+
+	var ruleValues []*model.RuleValue
+	err := db.Joins("JOIN rule_categories rc ON rc.id = rule_values.category_id").
+		Where("rc.code = ? AND rule_values.is_delete = false", "CURRENCY").
+		Find(&ruleValues).Error
+	if err != nil {
+		fmt.Println("Error loading CURRENCY rule values:", err)
+	}
+	for _, v := range ruleValues {
+		add(v.Value, v.Name, model.CurrencySourceRuleCategory, nil)
+	}
+
+	var currencies []*wealify.Currency
+	if err := db.Where("status = ? AND is_deleted = ?", true, false).Find(&currencies).Error; err != nil {
+		fmt.Println("Error loading wealify currencies:", err)
+	}
+	for _, c := range currencies {
+		var symbol *string
+		if c.Symbol != "" {
+			symbol = &c.Symbol
+		}
+		add(c.Code, c.Name, model.CurrencySourceWealify, symbol)
+	}
+
+	list := make([]*model.LedgerCurrency, 0, len(byCode))
+	for _, c := range byCode {
+		list = append(list, c)
+	}
+	created, err := repo.NewLedgerCurrencyRepo(db).InsertMissing(context.Background(), list)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Created ledger currencies:", created)
+	return nil
+}
@@ -1,7 +1,9 @@
 package app
 
 import (
+	config "core-ledger/configs"
 	"core-ledger/pkg/database"
+	"core-ledger/pkg/storage"
 
 	"go.uber.org/fx"
 	// "core-ledger/internal/app/core/mail"
@@ -11,5 +13,9 @@ import (
 var CoreModule = fx.Module("core",
 	fx.Provide(
 		database.Instance,
+		// Nơi lưu file export/upload dùng chung giữa API và worker (STORAGE_DRIVER)
+		func() (storage.Store, error) {
+			return storage.NewFromConfig(config.GetStorageConfig())
+		},
 	),
 )
@@ -9,7 +9,10 @@ var FXProviders = fx.Options(
 	HandlerModule,
 	RouterModule,
 	QueueClientModule,
+	SchedulerModule,
 	ValidateModule,
+	MetricsModule,
+	TracingModule,
 	// QueueModule,
 	fx.Provide(NewApplication),
 )
@@ -1,16 +1,31 @@
 package app
 
 import (
+	"core-ledger/internal/module/admin"
+	"core-ledger/internal/module/apikeys"
 	// "core-ledger/internal/auth/authhandler"
 	// "core-ledger/internal/module/accounts/accounthandler"
 	// "core-ledger/internal/module/transactions"
 	// "core-ledger/internal/module/wallets"
 	coaaccount "core-ledger/internal/module/coaAccount"
+	"core-ledger/internal/module/currencies"
 	"core-ledger/internal/module/entries"
 	"core-ledger/internal/module/excel"
+	"core-ledger/internal/module/exports"
+	"core-ledger/internal/module/failedjobs"
+	"core-ledger/internal/module/holds"
+	"core-ledger/internal/module/idempotency"
+	"core-ledger/internal/module/jobruns"
+	"core-ledger/internal/module/journals"
+	"core-ledger/internal/module/me"
+	"core-ledger/internal/module/queuemonitor"
+	"core-ledger/internal/module/ratelimit"
+	"core-ledger/internal/module/reconciliation"
 	"core-ledger/internal/module/ruleCategory"
 	"core-ledger/internal/module/ruleValue"
+	"core-ledger/internal/module/snapshots"
 	"core-ledger/internal/module/transactions"
+	"core-ledger/internal/module/webhooks"
 
 	"go.uber.org/fx"
 )
@@ -23,6 +38,21 @@ var HandlerModule = fx.Module("handler",
 		entries.NewEntriesHandler,
 		ruleCategory.NewRuleCategoryHandler,
 		ruleValue.NewRuleValueHandler,
+		reconciliation.NewReconciliationHandler,
+		journals.NewJournalHandler,
+		holds.NewHoldHandler,
+		currencies.NewCurrencyHandler,
+		me.NewMeHandler,
+		apikeys.NewApiKeyHandler,
+		idempotency.NewMiddleware,
+		ratelimit.NewRateLimiter,
+		webhooks.NewWebhookHandler,
+		admin.NewAdminHandler,
+		failedjobs.NewFailedJobHandler,
+		jobruns.NewJobRunHandler,
+		queuemonitor.NewQueueMonitorHandler,
+		exports.NewExportHandler,
+		snapshots.NewSnapshotHandler,
 	// accounthandler.NewAccountHandler,
 	// authhandler.NewHandler,
 	// wallets.NewWalletHandler,
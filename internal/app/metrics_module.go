@@ -0,0 +1,22 @@
+package app
+
+import (
+	config "core-ledger/configs"
+	"core-ledger/pkg/metrics"
+
+	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// MetricsModule đăng ký collector đọc từ DB (connection pool, backlog outbox) cho endpoint /metrics của API
+var MetricsModule = fx.Module("metrics",
+	fx.Invoke(func(db *gorm.DB) error {
+		if !config.GetMetricsConfig().Enabled {
+			return nil
+		}
+		if err := metrics.RegisterDBStats(db, "core_ledger"); err != nil {
+			return err
+		}
+		return metrics.RegisterOutbox(db)
+	}),
+)
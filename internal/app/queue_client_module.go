@@ -1,9 +1,15 @@
 package app
 
 import (
+	"context"
 	config "core-ledger/configs"
+	"core-ledger/internal/module/failedjobs"
+	"core-ledger/internal/module/jobruns"
+	"core-ledger/internal/module/queuemonitor"
 	"core-ledger/pkg/queue"
+	"fmt"
 	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/fx"
 )
 
@@ -20,9 +26,73 @@ var QueueClientModule = fx.Module("queue-client",
 			})
 			return client, nil
 		},
-		// Dispatcher abstraction
-		queue.NewDispatcher,
+		// asynq.Inspector để tra cứu/xoá task khi chạy lại job lỗi
+		func(lc fx.Lifecycle, cfg *config.QueueConfig) *asynq.Inspector {
+			inspector := asynq.NewInspector(asynq.RedisClientOpt{
+				Addr:     cfg.RedisAddr,
+				Password: cfg.RedisPassword,
+				DB:       cfg.RedisDB,
+			})
+			lc.Append(fx.Hook{
+				OnStop: func(_ context.Context) error {
+					return inspector.Close()
+				},
+			})
+			return inspector
+		},
+		// asynqmon gắn vào router chính ở /admin/queues
+		queuemonitor.NewDashboard,
+		// Dispatcher abstraction theo QUEUE_DRIVER; asynq kèm Redis để lưu trạng thái workflow (chain/batch)
+		func(client *asynq.Client, cfg *config.QueueConfig) queue.Dispatcher {
+			switch cfg.Driver {
+			case queue.DriverSync:
+				return queue.NewSyncDispatcher(queue.LocalOptions{})
+			case queue.DriverMemory:
+				return queue.NewMemoryDispatcher(queue.LocalOptions{Concurrency: cfg.Concurrency})
+			}
+			return queue.NewDispatcherWithRedis(client, redis.NewClient(&redis.Options{
+				Addr:     cfg.RedisAddr,
+				Password: cfg.RedisPassword,
+				DB:       cfg.RedisDB,
+			}))
+		},
 	),
 )
 
+// LocalQueueModule: driver sync/memory chạy job ngay trong process API, đăng ký handler vào dispatcher thay cho worker
+var LocalQueueModule = fx.Module("queue-local",
+	QueueJobsModule,
+	fx.Invoke(func(lc fx.Lifecycle, dispatcher queue.Dispatcher, failedJobs *failedjobs.FailedJobService, jobRuns *jobruns.JobRunService, in struct {
+		fx.In
+		Registrations []queue.Registration `group:"queue-registrations"`
+	}) error {
+		local, ok := dispatcher.(*queue.LocalDispatcher)
+		if !ok {
+			return fmt.Errorf("LocalQueueModule requires QUEUE_DRIVER=%s or %s", queue.DriverSync, queue.DriverMemory)
+		}
+		for _, r := range in.Registrations {
+			local.RegisterJob(r.Type, r.Template, r.Handler)
+		}
+		local.SetFailedJobRecorder(failedJobs)
+		local.SetJobRunStore(jobRuns)
+		lc.Append(fx.Hook{
+			OnStart: func(_ context.Context) error {
+				return local.Start()
+			},
+			OnStop: func(_ context.Context) error {
+				local.Stop()
+				return nil
+			},
+		})
+		return nil
+	}),
+)
 
+// LocalQueueOption trả về LocalQueueModule khi QUEUE_DRIVER là sync/memory; với asynq job chạy ở cmd/queue_worker
+func LocalQueueOption() fx.Option {
+	switch config.GetQueueConfig().Driver {
+	case queue.DriverSync, queue.DriverMemory:
+		return LocalQueueModule
+	}
+	return fx.Options()
+}
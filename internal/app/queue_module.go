@@ -3,17 +3,76 @@ package app
 import (
 	"context"
 	config "core-ledger/configs"
+	"core-ledger/internal/module/failedjobs"
+	"core-ledger/internal/module/jobruns"
+	"core-ledger/pkg/logging"
+	"core-ledger/pkg/metrics"
 	"core-ledger/pkg/queue"
 	"core-ledger/pkg/queue/handlers"
-	"fmt"
+	"errors"
+	"net/http"
 	"reflect"
+	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/fx"
+	"gorm.io/gorm"
+)
+
+// QueueJobsModule: handler của các job và registration theo group "queue-registrations",
+// dùng chung cho worker (QueueModule) và driver chạy trong process (LocalQueueModule)
+var QueueJobsModule = fx.Options(
+	fx.Provide(
+		// Cấp phát handler có DI repo bên trong
+		handlers.NewDataProcessHandler,
+		handlers.NewMyJobHandler,
+		handlers.NewImportCoaAccountHandler,
+		handlers.NewReconcileProviderBalanceHandler,
+		handlers.NewExpireHoldsHandler,
+		handlers.NewCleanupIdempotencyKeysHandler,
+		handlers.NewRelayOutboxHandler,
+		handlers.NewDeliverWebhookHandler,
+		handlers.NewSnapshotEODHandler,
+		handlers.NewExportRunHandler,
+
+		fx.Annotate(handlers.NewDataProcessRegistration,
+			fx.ResultTags(`group:"queue-registrations"`),
+		),
+		fx.Annotate(handlers.NewImportCoaAccountHandlerRegistration,
+			fx.ResultTags(`group:"queue-registrations"`),
+		),
+		fx.Annotate(handlers.NewReconcileProviderBalanceRegistration,
+			fx.ResultTags(`group:"queue-registrations"`),
+		),
+		fx.Annotate(handlers.NewExpireHoldsRegistration,
+			fx.ResultTags(`group:"queue-registrations"`),
+		),
+		fx.Annotate(handlers.NewCleanupIdempotencyKeysRegistration,
+			fx.ResultTags(`group:"queue-registrations"`),
+		),
+		fx.Annotate(handlers.NewRelayOutboxRegistration,
+			fx.ResultTags(`group:"queue-registrations"`),
+		),
+		fx.Annotate(handlers.NewDeliverWebhookRegistration,
+			fx.ResultTags(`group:"queue-registrations"`),
+		),
+		fx.Annotate(handlers.NewSnapshotEODRegistration,
+			fx.ResultTags(`group:"queue-registrations"`),
+		),
+		fx.Annotate(handlers.NewExportRunRegistration,
+			fx.ResultTags(`group:"queue-registrations"`),
+		),
+		// Cấp phát registration theo group để dễ mở rộng nhiều job/handler
+		fx.Annotate(handlers.NewMyJobHandlerRegistration,
+			fx.ResultTags(`group:"queue-registrations"`),
+		),
+	),
 )
 
 // QueueModule: cung cấp QueueConfig, Worker và đăng ký handler + lifecycle start/stop
 var QueueModule = fx.Module("queue",
+	QueueJobsModule,
 	fx.Provide(
 		// Cấp phát QueueConfig từ env (có validate)
 		config.GetQueueConfigWithValidation,
@@ -26,6 +85,20 @@ var QueueModule = fx.Module("queue",
 			})
 			return client, nil
 		},
+		// asynq.Inspector để tra cứu/xoá task khi chạy lại job lỗi
+		func(lc fx.Lifecycle, cfg *config.QueueConfig) *asynq.Inspector {
+			inspector := asynq.NewInspector(asynq.RedisClientOpt{
+				Addr:     cfg.RedisAddr,
+				Password: cfg.RedisPassword,
+				DB:       cfg.RedisDB,
+			})
+			lc.Append(fx.Hook{
+				OnStop: func(_ context.Context) error {
+					return inspector.Close()
+				},
+			})
+			return inspector
+		},
 		// Tạo worker theo config
 		func(cfg *config.QueueConfig) *queue.Worker {
 			return queue.NewWorkerWithRedis(asynq.RedisClientOpt{
@@ -34,33 +107,29 @@ var QueueModule = fx.Module("queue",
 				DB:       cfg.RedisDB,
 			}, cfg.Concurrency, cfg.Queues)
 		},
-		queue.NewDispatcher,
-		// Cấp phát handler có DI repo bên trong
-		handlers.NewDataProcessHandler,
-		handlers.NewMyJobHandler,
-		handlers.NewImportCoaAccountHandler,
-
-		fx.Annotate(handlers.NewDataProcessRegistration,
-			fx.ResultTags(`group:"queue-registrations"`),
-		),
-		fx.Annotate(handlers.NewImportCoaAccountHandlerRegistration,
-			fx.ResultTags(`group:"queue-registrations"`),
-		),
-		// Cấp phát registration theo group để dễ mở rộng nhiều job/handler
-		fx.Annotate(handlers.NewMyJobHandlerRegistration,
-			fx.ResultTags(`group:"queue-registrations"`),
-		),
+		// Dispatcher kèm Redis để lưu trạng thái workflow (chain/batch)
+		func(client *asynq.Client, cfg *config.QueueConfig) queue.Dispatcher {
+			return queue.NewDispatcherWithRedis(client, redis.NewClient(&redis.Options{
+				Addr:     cfg.RedisAddr,
+				Password: cfg.RedisPassword,
+				DB:       cfg.RedisDB,
+			}))
+		},
 	),
 	// Đăng ký routes của worker và khởi chạy theo lifecycle
-	fx.Invoke(func(lc fx.Lifecycle, w *queue.Worker, client *asynq.Client, in struct {
+	fx.Invoke(func(lc fx.Lifecycle, w *queue.Worker, client *asynq.Client, failedJobs *failedjobs.FailedJobService, jobRuns *jobruns.JobRunService, in struct {
 		fx.In
 		Registrations []queue.Registration `group:"queue-registrations"`
 	}) {
 		// đăng ký toàn bộ job/handler đã provide vào group
 		for _, r := range in.Registrations {
-			fmt.Println("Type:", r.Type, "Template:", r.Template, "Handler:", reflect.TypeOf(r.Handler))
+			logging.DefaultLogger().Debugf("queue: register type=%s template=%s handler=%s", r.Type, r.Template, reflect.TypeOf(r.Handler))
 			w.RegisterJob(r.Type, r.Template, r.Handler)
 		}
+		// job lỗi lần cuối của mọi type được ghi vào failed_jobs
+		w.SetFailedJobRecorder(failedJobs)
+		// tiến độ/kết quả handler báo qua Reporter được lưu vào job_runs
+		w.SetJobRunStore(jobRuns)
 
 		// khởi chạy/dừng worker theo lifecycle
 		lc.Append(fx.Hook{
@@ -79,4 +148,42 @@ var QueueModule = fx.Module("queue",
 			},
 		})
 	}),
+	// Endpoint Prometheus riêng của worker (metrics queue job + connection pool)
+	fx.Invoke(func(lc fx.Lifecycle, db *gorm.DB) error {
+		cfg := config.GetMetricsConfig()
+		if !cfg.Enabled || cfg.WorkerAddr == "" {
+			return nil
+		}
+		if err := metrics.RegisterDBStats(db, "core_ledger"); err != nil {
+			return err
+		}
+		mux := http.NewServeMux()
+		mux.Handle(cfg.Path, metrics.Handler())
+		srv := &http.Server{Addr: cfg.WorkerAddr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		lc.Append(fx.Hook{
+			OnStart: func(_ context.Context) error {
+				go func() {
+					if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+						logging.DefaultLogger().Errorf("metrics server: %v", err)
+					}
+				}()
+				return nil
+			},
+			OnStop: func(ctx context.Context) error {
+				return srv.Shutdown(ctx)
+			},
+		})
+		return nil
+	}),
+	// Enqueue job theo lịch (SchedulerModule), chỉ replica giữ khoá leader mới enqueue
+	fx.Invoke(func(lc fx.Lifecycle, scheduler *queue.Scheduler) {
+		if !config.GetSchedulerConfig().Enabled {
+			return
+		}
+		lc.Append(fx.Hook{
+			OnStart: func(_ context.Context) error {
+				return scheduler.Start()
+			},
+		})
+	}),
 )
@@ -16,7 +16,23 @@ var RepoModule = fx.Module("repo",
 		repo.NewTransactionLogRepo,
 		repo.NewSnapshotRepo,
 		repo.NewJournalRepo,
+		repo.NewJournalBatchRepo,
+		repo.NewLedgerCurrencyRepo,
+		repo.NewApiKeyRepo,
+		repo.NewIdempotencyKeyRepo,
+		repo.NewWebhookRepo,
 		repo.NewRuleCategoryRepo,
 		repo.NewRuleValueRepo,
+		repo.NewSystemPaymentRepo,
+		repo.NewProviderReconciliationRepo,
+		repo.NewFeeRepo,
+		repo.NewCustomerRepo,
+		repo.NewHoldRepo,
+		repo.NewPermissionRepo,
+		repo.NewAccountBalanceRepo,
+		repo.NewFailedJobRepo,
+		repo.NewJobRunRepo,
+		repo.NewExportRepo,
+		repo.NewFileRepo,
 	),
 )
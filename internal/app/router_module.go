@@ -3,18 +3,35 @@ package app
 import (
 	"context"
 	config "core-ledger/configs"
+	"core-ledger/internal/module/admin"
+	"core-ledger/internal/module/apikeys"
 	coaaccount "core-ledger/internal/module/coaAccount"
+	"core-ledger/internal/module/currencies"
 	"core-ledger/internal/module/entries"
 	"core-ledger/internal/module/excel"
+	"core-ledger/internal/module/exports"
+	"core-ledger/internal/module/failedjobs"
+	"core-ledger/internal/module/holds"
+	"core-ledger/internal/module/idempotency"
+	"core-ledger/internal/module/jobruns"
+	"core-ledger/internal/module/journals"
+	"core-ledger/internal/module/me"
 	"core-ledger/internal/module/middleware"
+	"core-ledger/internal/module/queuemonitor"
+	"core-ledger/internal/module/ratelimit"
+	"core-ledger/internal/module/rbac"
+	"core-ledger/internal/module/reconciliation"
 	"core-ledger/internal/module/ruleCategory"
 	"core-ledger/internal/module/ruleValue"
+	"core-ledger/internal/module/snapshots"
 	"core-ledger/internal/module/transactions"
+	"core-ledger/internal/module/webhooks"
 	"core-ledger/model/dto"
+	"core-ledger/pkg/logging"
+	"core-ledger/pkg/storage"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
 	"go.uber.org/fx"
 	// "core-ledger/internal/module/accounts"
 	// "core-ledger/internal/module/customers"
@@ -26,14 +43,32 @@ import (
 type RouterParams struct {
 	fx.In
 
-	Router              *gin.Engine
-	Lifecycle           fx.Lifecycle
-	TransactionHandler  *transactions.TransactionHandler
-	ExcelHandler        *excel.ExcelHandler
-	CoaAccountHandler   *coaaccount.CoaAccountHandler
-	EntriesHandler      *entries.EntriesHandler
-	RuleCategoryHandler *ruleCategory.RuleCategoryHandler
-	RuleValueHander     *ruleValue.RuleValueHandler
+	Router                *gin.Engine
+	Lifecycle             fx.Lifecycle
+	TransactionHandler    *transactions.TransactionHandler
+	ExcelHandler          *excel.ExcelHandler
+	CoaAccountHandler     *coaaccount.CoaAccountHandler
+	EntriesHandler        *entries.EntriesHandler
+	RuleCategoryHandler   *ruleCategory.RuleCategoryHandler
+	RuleValueHander       *ruleValue.RuleValueHandler
+	ReconciliationHandler *reconciliation.ReconciliationHandler
+	JournalHandler        *journals.JournalHandler
+	HoldHandler           *holds.HoldHandler
+	CurrencyHandler       *currencies.CurrencyHandler
+	MeHandler             *me.MeHandler
+	PermissionResolver    *rbac.PermissionResolver
+	ApiKeyHandler         *apikeys.ApiKeyHandler
+	ApiKeyService         *apikeys.ApiKeyService
+	Idempotency           *idempotency.Middleware
+	RateLimiter           *ratelimit.RateLimiter
+	WebhookHandler        *webhooks.WebhookHandler
+	AdminHandler          *admin.AdminHandler
+	FailedJobHandler      *failedjobs.FailedJobHandler
+	JobRunHandler         *jobruns.JobRunHandler
+	QueueMonitorHandler   *queuemonitor.QueueMonitorHandler
+	ExportHandler         *exports.ExportHandler
+	SnapshotHandler       *snapshots.SnapshotHandler
+	Store                 storage.Store
 	// Add more handlers here as needed:
 	// UserHandler    *handler.UserHandler
 	// OrderHandler   *handler.OrderHandler
@@ -46,8 +81,14 @@ func NewRouter() *gin.Engine {
 	router := gin.New()
 
 	router.Use(gin.Recovery())
+	// tracing chạy trước LogRequest để log request mang trace_id
+	if tracingCfg := config.GetTracingConfig(); tracingCfg.Enabled {
+		router.Use(middleware.Tracing(tracingCfg))
+	}
 	router.Use(middleware.LogRequest)
-	// router.Use(middleware.RateLimitMiddleware())
+	if config.GetMetricsConfig().Enabled {
+		router.Use(middleware.Metrics)
+	}
 	return router
 }
 
@@ -70,6 +111,14 @@ func SetupAllRoutes(params RouterParams) {
 			}},
 		)
 	})
+	// Prometheus metrics, danh sách metric xem docs/metrics.md. Router chính public nên bắt buộc METRICS_TOKEN
+	if metricsCfg := config.GetMetricsConfig(); metricsCfg.Enabled {
+		if metricsCfg.Token != "" {
+			params.Router.GET(metricsCfg.Path, middleware.MetricsHandler(metricsCfg))
+		} else {
+			logging.DefaultLogger().Warnf("METRICS_TOKEN is empty, %s is not exposed on the API server", metricsCfg.Path)
+		}
+	}
 	params.Router.NoRoute(func(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error":   "Route not found",
@@ -80,8 +129,9 @@ func SetupAllRoutes(params RouterParams) {
 	})
 	// Protected routes (with middleware)
 	// Option 1: Apply middleware to entire protected group
-	protected := api.Group("")
-	// protected.Use(middleware.RateLimitMiddleware())
+	// JWT nhân viên hoặc X-API-Key của service nội bộ + nạp permission/scope, từng route khai báo permission cần có bằng rbac.Require
+	// Rate limit theo principal/tenant (Redis) chạy sau xác thực
+	protected := api.Group("", params.PermissionResolver.Authenticate(params.ApiKeyService), params.RateLimiter.Handler())
 	// protected.Use(authMiddleware, loggingMiddleware) // Uncomment when you have middleware
 
 	// Option 2: Apply middleware per module (more flexible)
@@ -95,6 +145,27 @@ func SetupAllRoutes(params RouterParams) {
 	entries.SetupRoutes(protected, params.EntriesHandler)
 	ruleCategory.SetupRoutes(protected, params.RuleCategoryHandler)
 	ruleValue.SetupRoutes(protected, params.RuleValueHander)
+	reconciliation.SetupRoutes(protected, params.ReconciliationHandler)
+	// Idempotency-Key cho các endpoint ghi sổ (posting, batch, hold)
+	journals.SetupRoutes(protected, params.JournalHandler, params.Idempotency.Handler())
+	holds.SetupRoutes(protected, params.HoldHandler, params.Idempotency.Handler())
+	currencies.SetupRoutes(protected, params.CurrencyHandler)
+	me.SetupRoutes(protected, params.MeHandler)
+	apikeys.SetupRoutes(protected, params.ApiKeyHandler)
+	webhooks.SetupRoutes(protected, params.WebhookHandler)
+	admin.SetupRoutes(protected, params.AdminHandler)
+	failedjobs.SetupRoutes(protected, params.FailedJobHandler)
+	jobruns.SetupRoutes(protected, params.JobRunHandler)
+	queuemonitor.SetupRoutes(protected, params.QueueMonitorHandler)
+	exports.SetupRoutes(protected, params.ExportHandler)
+	snapshots.SetupRoutes(protected, params.SnapshotHandler)
+	// File của storage local tải qua URL ký (export, upload), không cần đăng nhập: LocalStore kiểm tra chữ ký HMAC và hạn
+	if local, ok := params.Store.(*storage.LocalStore); ok {
+		prefix := local.PublicPath()
+		params.Router.GET(prefix+"/*key", gin.WrapH(http.StripPrefix(prefix, local)))
+	}
+	// asynqmon ở /admin/queues (ngoài /api/v2): cùng xác thực nhân viên/API key, trình duyệt dùng cookie tạo từ POST /api/v2/admin/queues/session
+	queuemonitor.SetupDashboardRoutes(params.Router, params.QueueMonitorHandler, params.PermissionResolver.Authenticate(params.ApiKeyService))
 	// With middleware (example):
 	// transactions.SetupRoutes(protected, params.TransactionHandler, transactions.AuthMiddleware(), transactions.LoggingMiddleware())
 
@@ -109,7 +180,8 @@ func StartHTTPServer(params RouterParams) {
 	params.Router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, Idempotency-Key")
+		c.Header("Access-Control-Expose-Headers", "X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, Retry-After, Idempotent-Replayed")
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -129,16 +201,19 @@ func StartHTTPServer(params RouterParams) {
 
 	params.Lifecycle.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			logrus.Printf("Server starting on port %s", port)
+			if err := config.GetConfig().ValidateJWT(); err != nil {
+				return err
+			}
+			logging.DefaultLogger().Infof("Server starting on port %s", port)
 			go func() {
 				if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-					logrus.Errorf("Failed to start server: %v", err)
+					logging.DefaultLogger().Errorf("Failed to start server: %v", err)
 				}
 			}()
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
-			logrus.Printf("Server shutting down")
+			logging.DefaultLogger().Info("Server shutting down")
 			return srv.Shutdown(ctx)
 		},
 	})
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/queue/handlers"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/fx"
+)
+
+// SchedulerModule: cung cấp queue.Scheduler từ các schedule trong group "queue-schedules".
+// API dùng để liệt kê lịch (/admin/schedules), chỉ worker (QueueModule) mới khởi chạy enqueue theo lịch.
+var SchedulerModule = fx.Module("scheduler",
+	fx.Provide(
+		fx.Annotate(handlers.NewReconcileProviderBalanceSchedule,
+			fx.ResultTags(`group:"queue-schedules"`),
+		),
+		fx.Annotate(handlers.NewExpireHoldsSchedule,
+			fx.ResultTags(`group:"queue-schedules"`),
+		),
+		fx.Annotate(handlers.NewCleanupIdempotencyKeysSchedule,
+			fx.ResultTags(`group:"queue-schedules"`),
+		),
+		fx.Annotate(handlers.NewRelayOutboxSchedule,
+			fx.ResultTags(`group:"queue-schedules"`),
+		),
+		fx.Annotate(handlers.NewSnapshotEODSchedule,
+			fx.ResultTags(`group:"queue-schedules"`),
+		),
+		func(cfg *config.QueueConfig, in struct {
+			fx.In
+			Schedules []queue.Schedule `group:"queue-schedules"`
+		}) (*queue.Scheduler, error) {
+			sc := config.GetSchedulerConfig()
+			return queue.NewScheduler(asynq.RedisClientOpt{
+				Addr:     cfg.RedisAddr,
+				Password: cfg.RedisPassword,
+				DB:       cfg.RedisDB,
+			}, queue.SchedulerOptions{
+				Location:  sc.Location(),
+				LeaderTTL: sc.LeaderTTL,
+			}, in.Schedules...)
+		},
+	),
+	fx.Invoke(func(lc fx.Lifecycle, scheduler *queue.Scheduler) {
+		lc.Append(fx.Hook{
+			OnStop: func(_ context.Context) error {
+				scheduler.Stop()
+				return nil
+			},
+		})
+	}),
+)
@@ -1,12 +1,26 @@
 package app
 
 import (
+	"core-ledger/internal/module/apikeys"
 	coaaccount "core-ledger/internal/module/coaAccount"
+	"core-ledger/internal/module/currencies"
 	"core-ledger/internal/module/entries"
 	"core-ledger/internal/module/excel"
+	"core-ledger/internal/module/exports"
+	"core-ledger/internal/module/failedjobs"
+	"core-ledger/internal/module/files"
+	"core-ledger/internal/module/holds"
+	"core-ledger/internal/module/idempotency"
+	"core-ledger/internal/module/jobruns"
+	"core-ledger/internal/module/journals"
+	"core-ledger/internal/module/queuemonitor"
+	"core-ledger/internal/module/rbac"
+	"core-ledger/internal/module/reconciliation"
 	"core-ledger/internal/module/ruleCategory"
 	"core-ledger/internal/module/ruleValue"
+	"core-ledger/internal/module/snapshots"
 	"core-ledger/internal/module/transactions"
+	"core-ledger/internal/module/webhooks"
 
 	"go.uber.org/fx"
 	// ... import thêm các service khác
@@ -20,5 +34,23 @@ var ServiceModule = fx.Module("service",
 		entries.NewEntriesService,
 		ruleCategory.NewRuleCateogySerive,
 		ruleValue.NewRuleCateogySerive,
+		reconciliation.NewReconciliationService,
+		journals.NewFeeService,
+		journals.NewJournalService,
+		journals.NewBatchService,
+		journals.NewBalanceService,
+		holds.NewHoldService,
+		currencies.NewCurrencyService,
+		rbac.NewPermissionResolver,
+		apikeys.NewApiKeyService,
+		idempotency.NewStore,
+		webhooks.NewWebhookService,
+		snapshots.NewSnapshotService,
+		failedjobs.NewFailedJobService,
+		jobruns.NewJobRunService,
+		queuemonitor.NewQueueMonitorService,
+		files.NewFileService,
+		exports.NewRegistry,
+		exports.NewExportService,
 	),
 )
@@ -0,0 +1,21 @@
+package app
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/pkg/tracing"
+
+	"go.uber.org/fx"
+)
+
+// TracingModule cài TracerProvider OpenTelemetry cho API và worker, flush span còn lại khi dừng
+var TracingModule = fx.Module("tracing",
+	fx.Invoke(func(lc fx.Lifecycle) error {
+		shutdown, err := tracing.Init(context.Background(), config.GetTracingConfig())
+		if err != nil {
+			return err
+		}
+		lc.Append(fx.Hook{OnStop: shutdown})
+		return nil
+	}),
+)
@@ -6,12 +6,25 @@ import (
 	"core-ledger/pkg/constants"
 	"errors"
 	"strings"
+
+	"github.com/shopspring/decimal"
 )
 
+// FeeScale số chữ số thập phân giữ lại khi tính phí (khớp numeric(28,8) của entries.amount)
+const FeeScale = 8
+
+var ErrFeeRangeNotFound = errors.New("no fee range matches amount")
+
 func CalculateFee(feeType string, feeValue float64, amount float64) float64 {
+	fee, _ := CalculateFeeDecimal(feeType, decimal.NewFromFloat(feeValue), decimal.NewFromFloat(amount)).Float64()
+	return fee
+}
+
+// CalculateFeeDecimal tính phí bằng decimal: PERCENT = value * amount (value dạng tỉ lệ, 0.01 = 1%), FIXED = value
+func CalculateFeeDecimal(feeType string, feeValue decimal.Decimal, amount decimal.Decimal) decimal.Decimal {
 	switch feeType {
 	case "PERCENT":
-		return feeValue * amount
+		return feeValue.Mul(amount).Round(FeeScale)
 	case "FIXED":
 		return feeValue
 	default:
@@ -19,6 +32,28 @@ func CalculateFee(feeType string, feeValue float64, amount float64) float64 {
 	}
 }
 
+// SelectFeeRange chọn bậc phí có min <= amount < max (bỏ qua bậc đã xoá/tắt)
+func SelectFeeRange(ranges []*model.FeeRange, amount decimal.Decimal) (*model.FeeRange, error) {
+	for _, r := range ranges {
+		if r == nil || r.IsDeleted != 0 || r.Status != 1 {
+			continue
+		}
+		if amount.GreaterThanOrEqual(decimal.NewFromFloat(r.Min)) && amount.LessThan(decimal.NewFromFloat(r.Max)) {
+			return r, nil
+		}
+	}
+	return nil, ErrFeeRangeNotFound
+}
+
+// CalculateTieredFee tính phí theo biểu phí bậc thang, trả về số phí và bậc phí đã áp dụng
+func CalculateTieredFee(ranges []*model.FeeRange, amount decimal.Decimal) (decimal.Decimal, *model.FeeRange, error) {
+	r, err := SelectFeeRange(ranges, amount)
+	if err != nil {
+		return decimal.Zero, nil, err
+	}
+	return CalculateFeeDecimal(r.Type, decimal.NewFromFloat(r.Value), amount), r, nil
+}
+
 func CalculateTopUpFee(tier enum.Tier, platform *model.PlatformFee) (float64, error) {
 	platformIncomplete := platform == nil ||
 		platform.TopUpDiamondFee == nil ||
@@ -0,0 +1,76 @@
+package core
+
+import (
+	model "core-ledger/model/wealify"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCalculateFeeDecimal(t *testing.T) {
+	cases := []struct {
+		name    string
+		feeType string
+		value   string
+		amount  string
+		want    string
+	}{
+		{"percent", "PERCENT", "0.01", "250", "2.5"},
+		{"percent exact decimal", "PERCENT", "0.015", "0.1", "0.0015"},
+		// float64 0.1 * 0.2 lệch ở chữ số thứ 17, decimal thì không
+		{"percent no float drift", "PERCENT", "0.1", "0.2", "0.02"},
+		{"percent rounds half up to FeeScale", "PERCENT", "0.00000001", "0.5", "0.00000001"},
+		{"percent rounds down to FeeScale", "PERCENT", "0.00000001", "0.49", "0"},
+		{"percent large amount", "PERCENT", "0.0035", "123456789.12345678", "432098.7619321"},
+		{"percent zero amount", "PERCENT", "0.01", "0", "0"},
+		{"fixed ignores amount", "FIXED", "1.5", "1000000", "1.5"},
+		{"fixed keeps scale", "FIXED", "0.123456789", "1", "0.123456789"},
+		{"unknown type falls back to fixed", "OTHER", "3", "100", "3"},
+	}
+	for _, tc := range cases {
+		got := CalculateFeeDecimal(tc.feeType, decimal.RequireFromString(tc.value), decimal.RequireFromString(tc.amount))
+		if !got.Equal(decimal.RequireFromString(tc.want)) {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestCalculateFee(t *testing.T) {
+	if got := CalculateFee("PERCENT", 0.1, 0.2); got != 0.02 {
+		t.Fatalf("PERCENT: got %v", got)
+	}
+	if got := CalculateFee("FIXED", 5, 100); got != 5 {
+		t.Fatalf("FIXED: got %v", got)
+	}
+}
+
+func TestCalculateTieredFee(t *testing.T) {
+	ranges := []*model.FeeRange{
+		{ID: "deleted", Min: 0, Max: 100, Value: 9, Type: "FIXED", Status: 1, IsDeleted: 1},
+		{ID: "small", Min: 0, Max: 100, Value: 1, Type: "FIXED", Status: 1},
+		{ID: "medium", Min: 100, Max: 1000, Value: 0.02, Type: "PERCENT", Status: 1},
+		{ID: "disabled", Min: 1000, Max: 5000, Value: 0.5, Type: "PERCENT", Status: 0},
+	}
+	cases := []struct {
+		amount string
+		id     string
+		fee    string
+	}{
+		{"0", "small", "1"},
+		{"99.99999999", "small", "1"},
+		{"100", "medium", "2"},
+		{"999.99", "medium", "19.9998"},
+	}
+	for _, tc := range cases {
+		fee, r, err := CalculateTieredFee(ranges, decimal.RequireFromString(tc.amount))
+		if err != nil || r.ID != tc.id || !fee.Equal(decimal.RequireFromString(tc.fee)) {
+			t.Errorf("%s: fee = %s range = %+v err = %v", tc.amount, fee, r, err)
+		}
+	}
+	for _, amount := range []string{"1000", "-1"} {
+		if _, _, err := CalculateTieredFee(ranges, decimal.RequireFromString(amount)); !errors.Is(err, ErrFeeRangeNotFound) {
+			t.Errorf("%s: err = %v", amount, err)
+		}
+	}
+}
@@ -9,6 +9,7 @@ var ErrInvalidPlatform = errors.New("invalid platform")
 // error code module + service + category + sequence
 // example
 // + module VA: 02
+// + module ledger: 03
 // + service user: 001
 // + category: 01
 // + sequence: 000
@@ -32,6 +33,52 @@ const (
 	ErrCodeVAPayoutProviderNotEnoughBalance     AppErrorCode = "0200402001"
 	ErrCodeVAPayoutProviderCallThirdPartyFailed AppErrorCode = "0200403001"
 	ErrCodeVAPayoutCannotSplitTransaction       AppErrorCode = "0200403001"
+
+	// ledger - service journal: 001, service fee: 002, service hold: 003
+	ErrCodeLedgerJournalUnbalanced      AppErrorCode = "0300101001"
+	ErrCodeLedgerAccountNotFound        AppErrorCode = "0300101002"
+	ErrCodeLedgerAccountInactive        AppErrorCode = "0300101003"
+	ErrCodeLedgerCurrencyMismatch       AppErrorCode = "0300101004"
+	ErrCodeLedgerInvalidAmount          AppErrorCode = "0300101005"
+	ErrCodeLedgerCurrencyNotSupported   AppErrorCode = "0300101006"
+	ErrCodeLedgerAmountPrecision        AppErrorCode = "0300101007"
+	ErrCodeLedgerJournalNotFound        AppErrorCode = "0300101008"
+	ErrCodeLedgerInsufficientAvailable  AppErrorCode = "0300102001"
+	ErrCodeLedgerNegativeBalance        AppErrorCode = "0300102002"
+	ErrCodeLedgerMinBalanceViolated     AppErrorCode = "0300102003"
+	ErrCodeLedgerOverdraftExceeded      AppErrorCode = "0300102004"
+	ErrCodeLedgerJournalNotReversible   AppErrorCode = "0300102005"
+	ErrCodeLedgerFeeScheduleNotFound    AppErrorCode = "0300201001"
+	ErrCodeLedgerFeeRangeNotFound       AppErrorCode = "0300201002"
+	ErrCodeLedgerRevenueKindNotFound    AppErrorCode = "0300201003"
+	ErrCodeLedgerRevenueAccountNotFound AppErrorCode = "0300201004"
+	ErrCodeLedgerHoldNotFound           AppErrorCode = "0300301001"
+	ErrCodeLedgerHoldNotPending         AppErrorCode = "0300302001"
+	ErrCodeLedgerHoldCaptureExceeded    AppErrorCode = "0300302002"
+	ErrCodeLedgerHoldExpired            AppErrorCode = "0300302003"
+	ErrCodeLedgerHoldIdempotencyKeyUsed AppErrorCode = "0300302004"
+	ErrCodeLedgerBatchNotFound          AppErrorCode = "0300401001"
+	ErrCodeLedgerBatchDuplicateKey      AppErrorCode = "0300401002"
+	ErrCodeLedgerBatchNotReversible     AppErrorCode = "0300402001"
+	ErrCodeLedgerCurrencyExists         AppErrorCode = "0300501001"
+	ErrCodeLedgerApiKeyNotFound         AppErrorCode = "0300601001"
+	ErrCodeLedgerApiKeyInvalidScope     AppErrorCode = "0300601002"
+	ErrCodeLedgerApiKeyRevoked          AppErrorCode = "0300602001"
+	ErrCodeLedgerWebhookNotFound        AppErrorCode = "0300701001"
+	ErrCodeLedgerWebhookInvalidEvent    AppErrorCode = "0300701002"
+	ErrCodeLedgerWebhookLogNotFound     AppErrorCode = "0300701003"
+	ErrCodeLedgerWebhookInactive        AppErrorCode = "0300702001"
+	ErrCodeLedgerFailedJobNotFound      AppErrorCode = "0300801001"
+	ErrCodeLedgerFailedJobActive        AppErrorCode = "0300802001"
+	ErrCodeLedgerJobNotFound            AppErrorCode = "0300901001"
+	ErrCodeLedgerExportNotFound         AppErrorCode = "0301001001"
+	ErrCodeLedgerExportInvalidResource  AppErrorCode = "0301001002"
+	ErrCodeLedgerExportInvalidColumn    AppErrorCode = "0301001003"
+	ErrCodeLedgerExportInvalidFormat    AppErrorCode = "0301001004"
+	ErrCodeLedgerExportInvalidQuery     AppErrorCode = "0301001005"
+	ErrCodeLedgerExportTooLarge         AppErrorCode = "0301002001"
+	ErrCodeLedgerExportNotReady         AppErrorCode = "0301002002"
+	ErrCodeLedgerFileNotFound           AppErrorCode = "0301101001"
 )
 
 type AppError struct {
@@ -61,6 +108,51 @@ var MapCodeToScope = map[AppErrorCode]string{
 	ErrCodeVACardHolder:                         "VA.CREATE.VALIDATE.CARD_HOLDER",
 	ErrCodeVAPayoutProviderNotEnoughBalance:     "VA.TRANSACTION.WITHDRAW.NOT_ENOUGH_BALANCE",
 	ErrCodeVAPayoutProviderCallThirdPartyFailed: "VA.TRANSACTION.UNKNOWN_ERROR",
+
+	ErrCodeLedgerJournalUnbalanced:      "LEDGER.JOURNAL.VALIDATE.UNBALANCED",
+	ErrCodeLedgerAccountNotFound:        "LEDGER.JOURNAL.VALIDATE.ACCOUNT_NOT_FOUND",
+	ErrCodeLedgerAccountInactive:        "LEDGER.JOURNAL.VALIDATE.ACCOUNT_INACTIVE",
+	ErrCodeLedgerCurrencyMismatch:       "LEDGER.JOURNAL.VALIDATE.CURRENCY_MISMATCH",
+	ErrCodeLedgerInvalidAmount:          "LEDGER.JOURNAL.VALIDATE.INVALID_AMOUNT",
+	ErrCodeLedgerCurrencyNotSupported:   "LEDGER.JOURNAL.VALIDATE.CURRENCY_NOT_SUPPORTED",
+	ErrCodeLedgerAmountPrecision:        "LEDGER.JOURNAL.VALIDATE.AMOUNT_PRECISION",
+	ErrCodeLedgerJournalNotFound:        "LEDGER.JOURNAL.VALIDATE.NOT_FOUND",
+	ErrCodeLedgerFeeScheduleNotFound:    "LEDGER.FEE.VALIDATE.SCHEDULE_NOT_FOUND",
+	ErrCodeLedgerFeeRangeNotFound:       "LEDGER.FEE.VALIDATE.RANGE_NOT_FOUND",
+	ErrCodeLedgerRevenueKindNotFound:    "LEDGER.FEE.VALIDATE.REVENUE_KIND_NOT_FOUND",
+	ErrCodeLedgerRevenueAccountNotFound: "LEDGER.FEE.VALIDATE.REVENUE_ACCOUNT_NOT_FOUND",
+	ErrCodeLedgerInsufficientAvailable:  "LEDGER.JOURNAL.BUSINESS.INSUFFICIENT_AVAILABLE",
+	ErrCodeLedgerNegativeBalance:        "LEDGER.JOURNAL.BUSINESS.NEGATIVE_BALANCE",
+	ErrCodeLedgerMinBalanceViolated:     "LEDGER.JOURNAL.BUSINESS.MIN_BALANCE",
+	ErrCodeLedgerOverdraftExceeded:      "LEDGER.JOURNAL.BUSINESS.OVERDRAFT_EXCEEDED",
+	ErrCodeLedgerJournalNotReversible:   "LEDGER.JOURNAL.BUSINESS.NOT_REVERSIBLE",
+	ErrCodeLedgerHoldNotFound:           "LEDGER.HOLD.VALIDATE.NOT_FOUND",
+	ErrCodeLedgerHoldNotPending:         "LEDGER.HOLD.BUSINESS.NOT_PENDING",
+	ErrCodeLedgerHoldCaptureExceeded:    "LEDGER.HOLD.BUSINESS.CAPTURE_EXCEEDED",
+	ErrCodeLedgerHoldExpired:            "LEDGER.HOLD.BUSINESS.EXPIRED",
+	ErrCodeLedgerHoldIdempotencyKeyUsed: "LEDGER.HOLD.BUSINESS.IDEMPOTENCY_KEY_USED",
+	ErrCodeLedgerBatchNotFound:          "LEDGER.BATCH.VALIDATE.NOT_FOUND",
+	ErrCodeLedgerBatchDuplicateKey:      "LEDGER.BATCH.VALIDATE.DUPLICATE_KEY",
+	ErrCodeLedgerBatchNotReversible:     "LEDGER.BATCH.BUSINESS.NOT_REVERSIBLE",
+	ErrCodeLedgerCurrencyExists:         "LEDGER.CURRENCY.VALIDATE.EXISTS",
+	ErrCodeLedgerApiKeyNotFound:         "LEDGER.API_KEY.VALIDATE.NOT_FOUND",
+	ErrCodeLedgerApiKeyInvalidScope:     "LEDGER.API_KEY.VALIDATE.INVALID_SCOPE",
+	ErrCodeLedgerApiKeyRevoked:          "LEDGER.API_KEY.BUSINESS.REVOKED",
+	ErrCodeLedgerWebhookNotFound:        "LEDGER.WEBHOOK.VALIDATE.NOT_FOUND",
+	ErrCodeLedgerWebhookInvalidEvent:    "LEDGER.WEBHOOK.VALIDATE.INVALID_EVENT",
+	ErrCodeLedgerWebhookLogNotFound:     "LEDGER.WEBHOOK.VALIDATE.DELIVERY_NOT_FOUND",
+	ErrCodeLedgerWebhookInactive:        "LEDGER.WEBHOOK.BUSINESS.INACTIVE",
+	ErrCodeLedgerFailedJobNotFound:      "LEDGER.FAILED_JOB.VALIDATE.NOT_FOUND",
+	ErrCodeLedgerFailedJobActive:        "LEDGER.FAILED_JOB.BUSINESS.TASK_ACTIVE",
+	ErrCodeLedgerJobNotFound:            "LEDGER.JOB.VALIDATE.NOT_FOUND",
+	ErrCodeLedgerExportNotFound:         "LEDGER.EXPORT.VALIDATE.NOT_FOUND",
+	ErrCodeLedgerExportInvalidResource:  "LEDGER.EXPORT.VALIDATE.INVALID_RESOURCE",
+	ErrCodeLedgerExportInvalidColumn:    "LEDGER.EXPORT.VALIDATE.INVALID_COLUMN",
+	ErrCodeLedgerExportInvalidFormat:    "LEDGER.EXPORT.VALIDATE.INVALID_FORMAT",
+	ErrCodeLedgerExportInvalidQuery:     "LEDGER.EXPORT.VALIDATE.INVALID_QUERY",
+	ErrCodeLedgerExportTooLarge:         "LEDGER.EXPORT.BUSINESS.TOO_LARGE",
+	ErrCodeLedgerExportNotReady:         "LEDGER.EXPORT.BUSINESS.NOT_READY",
+	ErrCodeLedgerFileNotFound:           "LEDGER.FILE.VALIDATE.NOT_FOUND",
 }
 
 var MapCodeToMessage = map[AppErrorCode]string{
@@ -72,6 +164,51 @@ var MapCodeToMessage = map[AppErrorCode]string{
 	ErrCodeVAFeatureInactive:                    "Bạn chưa được active tính năng VA",
 	ErrCodeVAPayoutProviderNotEnoughBalance:     "Số dư không đủ",
 	ErrCodeVAPayoutProviderCallThirdPartyFailed: "Có gì đó không đúng",
+
+	ErrCodeLedgerJournalUnbalanced:      "Bút toán không cân (tổng Nợ khác tổng Có)",
+	ErrCodeLedgerAccountNotFound:        "Tài khoản kế toán không tồn tại",
+	ErrCodeLedgerAccountInactive:        "Tài khoản kế toán đang ngừng hoạt động",
+	ErrCodeLedgerCurrencyMismatch:       "Loại tiền của tài khoản không khớp với bút toán",
+	ErrCodeLedgerInvalidAmount:          "Số tiền không hợp lệ",
+	ErrCodeLedgerCurrencyNotSupported:   "Loại tiền chưa được đăng ký hoặc đã ngừng sử dụng",
+	ErrCodeLedgerAmountPrecision:        "Số tiền có nhiều chữ số thập phân hơn loại tiền cho phép",
+	ErrCodeLedgerJournalNotFound:        "Không tìm thấy bút toán",
+	ErrCodeLedgerFeeScheduleNotFound:    "Không tìm thấy biểu phí",
+	ErrCodeLedgerFeeRangeNotFound:       "Không có bậc phí phù hợp với số tiền",
+	ErrCodeLedgerRevenueKindNotFound:    "Loại doanh thu không hợp lệ",
+	ErrCodeLedgerRevenueAccountNotFound: "Không tìm thấy tài khoản doanh thu",
+	ErrCodeLedgerInsufficientAvailable:  "Số dư khả dụng không đủ",
+	ErrCodeLedgerNegativeBalance:        "Tài khoản không được phép âm số dư",
+	ErrCodeLedgerMinBalanceViolated:     "Số dư sau giao dịch thấp hơn số dư tối thiểu",
+	ErrCodeLedgerOverdraftExceeded:      "Vượt quá hạn mức thấu chi",
+	ErrCodeLedgerJournalNotReversible:   "Bút toán không thể đảo",
+	ErrCodeLedgerHoldNotFound:           "Không tìm thấy khoản tạm giữ",
+	ErrCodeLedgerHoldNotPending:         "Khoản tạm giữ đã được xử lý",
+	ErrCodeLedgerHoldCaptureExceeded:    "Số tiền capture vượt quá số tiền đang tạm giữ",
+	ErrCodeLedgerHoldExpired:            "Khoản tạm giữ đã hết hạn",
+	ErrCodeLedgerHoldIdempotencyKeyUsed: "idempotency_key đã dùng cho bút toán khác",
+	ErrCodeLedgerBatchNotFound:          "Không tìm thấy batch",
+	ErrCodeLedgerBatchDuplicateKey:      "Trùng idempotency_key trong cùng batch",
+	ErrCodeLedgerBatchNotReversible:     "Batch không thể đảo",
+	ErrCodeLedgerCurrencyExists:         "Loại tiền đã tồn tại",
+	ErrCodeLedgerApiKeyNotFound:         "Không tìm thấy API key",
+	ErrCodeLedgerApiKeyInvalidScope:     "Scope của API key không hợp lệ",
+	ErrCodeLedgerApiKeyRevoked:          "API key đã bị thu hồi",
+	ErrCodeLedgerWebhookNotFound:        "Không tìm thấy webhook",
+	ErrCodeLedgerWebhookInvalidEvent:    "Event đăng ký không được hỗ trợ",
+	ErrCodeLedgerWebhookLogNotFound:     "Không tìm thấy lần gửi webhook",
+	ErrCodeLedgerWebhookInactive:        "Webhook đang tắt",
+	ErrCodeLedgerFailedJobNotFound:      "Không tìm thấy job lỗi",
+	ErrCodeLedgerFailedJobActive:        "Job đang chờ hoặc đang chạy trong hàng đợi",
+	ErrCodeLedgerJobNotFound:            "Không tìm thấy job",
+	ErrCodeLedgerExportNotFound:         "Không tìm thấy export",
+	ErrCodeLedgerExportInvalidResource:  "Dữ liệu export không được hỗ trợ",
+	ErrCodeLedgerExportInvalidColumn:    "Cột export không hợp lệ",
+	ErrCodeLedgerExportInvalidFormat:    "Định dạng file export không được hỗ trợ",
+	ErrCodeLedgerExportInvalidQuery:     "Điều kiện lọc export không hợp lệ",
+	ErrCodeLedgerExportTooLarge:         "Số dòng export vượt quá giới hạn",
+	ErrCodeLedgerExportNotReady:         "File export chưa sẵn sàng hoặc đã hết hạn",
+	ErrCodeLedgerFileNotFound:           "Không tìm thấy file",
 }
 
 var MapCodeToDescription = map[AppErrorCode]string{
@@ -79,6 +216,51 @@ var MapCodeToDescription = map[AppErrorCode]string{
 	ErrCodeVACardHolder:                         "Tên VA không hợp lệ",
 	ErrCodeVAPayoutProviderNotEnoughBalance:     "Số dư không đủ",
 	ErrCodeVAPayoutProviderCallThirdPartyFailed: "Có gì đó không đúng",
+
+	ErrCodeLedgerJournalUnbalanced:      "Bút toán không cân (tổng Nợ khác tổng Có)",
+	ErrCodeLedgerAccountNotFound:        "Tài khoản kế toán không tồn tại",
+	ErrCodeLedgerAccountInactive:        "Tài khoản kế toán đang ngừng hoạt động",
+	ErrCodeLedgerCurrencyMismatch:       "Loại tiền của tài khoản không khớp với bút toán",
+	ErrCodeLedgerInvalidAmount:          "Số tiền không hợp lệ",
+	ErrCodeLedgerCurrencyNotSupported:   "Loại tiền chưa được đăng ký hoặc đã ngừng sử dụng",
+	ErrCodeLedgerAmountPrecision:        "Số tiền có nhiều chữ số thập phân hơn loại tiền cho phép",
+	ErrCodeLedgerJournalNotFound:        "Không tìm thấy bút toán",
+	ErrCodeLedgerFeeScheduleNotFound:    "Không tìm thấy biểu phí",
+	ErrCodeLedgerFeeRangeNotFound:       "Không có bậc phí phù hợp với số tiền",
+	ErrCodeLedgerRevenueKindNotFound:    "Loại doanh thu không hợp lệ",
+	ErrCodeLedgerRevenueAccountNotFound: "Không tìm thấy tài khoản doanh thu",
+	ErrCodeLedgerInsufficientAvailable:  "Số dư khả dụng không đủ",
+	ErrCodeLedgerNegativeBalance:        "Tài khoản không được phép âm số dư",
+	ErrCodeLedgerMinBalanceViolated:     "Số dư sau giao dịch thấp hơn số dư tối thiểu",
+	ErrCodeLedgerOverdraftExceeded:      "Vượt quá hạn mức thấu chi",
+	ErrCodeLedgerJournalNotReversible:   "Bút toán không thể đảo",
+	ErrCodeLedgerHoldNotFound:           "Không tìm thấy khoản tạm giữ",
+	ErrCodeLedgerHoldNotPending:         "Khoản tạm giữ đã được xử lý",
+	ErrCodeLedgerHoldCaptureExceeded:    "Số tiền capture vượt quá số tiền đang tạm giữ",
+	ErrCodeLedgerHoldExpired:            "Khoản tạm giữ đã hết hạn",
+	ErrCodeLedgerHoldIdempotencyKeyUsed: "idempotency_key đã dùng cho bút toán khác",
+	ErrCodeLedgerBatchNotFound:          "Không tìm thấy batch",
+	ErrCodeLedgerBatchDuplicateKey:      "Trùng idempotency_key trong cùng batch",
+	ErrCodeLedgerBatchNotReversible:     "Batch không thể đảo",
+	ErrCodeLedgerCurrencyExists:         "Loại tiền đã tồn tại",
+	ErrCodeLedgerApiKeyNotFound:         "Không tìm thấy API key",
+	ErrCodeLedgerApiKeyInvalidScope:     "Scope của API key không hợp lệ",
+	ErrCodeLedgerApiKeyRevoked:          "API key đã bị thu hồi",
+	ErrCodeLedgerWebhookNotFound:        "Không tìm thấy webhook",
+	ErrCodeLedgerWebhookInvalidEvent:    "Event đăng ký không được hỗ trợ",
+	ErrCodeLedgerWebhookLogNotFound:     "Không tìm thấy lần gửi webhook",
+	ErrCodeLedgerWebhookInactive:        "Webhook đang tắt",
+	ErrCodeLedgerFailedJobNotFound:      "Không tìm thấy job lỗi",
+	ErrCodeLedgerFailedJobActive:        "Job đang chờ hoặc đang chạy trong hàng đợi",
+	ErrCodeLedgerJobNotFound:            "Không tìm thấy job",
+	ErrCodeLedgerExportNotFound:         "Không tìm thấy export",
+	ErrCodeLedgerExportInvalidResource:  "Dữ liệu export không được hỗ trợ",
+	ErrCodeLedgerExportInvalidColumn:    "Cột export không hợp lệ",
+	ErrCodeLedgerExportInvalidFormat:    "Định dạng file export không được hỗ trợ",
+	ErrCodeLedgerExportInvalidQuery:     "Điều kiện lọc export không hợp lệ",
+	ErrCodeLedgerExportTooLarge:         "Số dòng export vượt quá giới hạn",
+	ErrCodeLedgerExportNotReady:         "File export chưa sẵn sàng hoặc đã hết hạn",
+	ErrCodeLedgerFileNotFound:           "Không tìm thấy file",
 }
 
 func NewError(code AppErrorCode, customDescription ...string) *AppError {
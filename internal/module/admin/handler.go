@@ -0,0 +1,65 @@
+package admin
+
+import (
+	"core-ledger/internal/module/rbac"
+	"core-ledger/internal/module/validate"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logging"
+	"core-ledger/pkg/queue"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler endpoint vận hành của process API
+type AdminHandler struct {
+	scheduler *queue.Scheduler
+}
+
+func NewAdminHandler(scheduler *queue.Scheduler) *AdminHandler {
+	return &AdminHandler{scheduler: scheduler}
+}
+
+// GetLogLevel level hiện tại của logger dùng chung
+func (h *AdminHandler) GetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: LogLevelResponse{Level: logging.Level()},
+	})
+}
+
+// SetLogLevel đổi level log lúc chạy (chỉ process đang nhận request), khởi động lại sẽ về LOG_LEVEL
+func (h *AdminHandler) SetLogLevel(c *gin.Context) {
+	var req SetLogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	previous := logging.Level()
+	if err := logging.SetLevel(req.Level); err != nil {
+		ginhp.RespondError(c, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	logging.FromContext(c).Warnw("log level changed", "from", previous, "to", logging.Level(), logging.FieldPrincipal, rbac.Principal(c))
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: LogLevelResponse{Level: logging.Level()},
+	})
+}
+
+// ListSchedules danh sách job định kỳ kèm lần chạy kế tiếp/gần nhất và replica đang giữ quyền leader
+func (h *AdminHandler) ListSchedules(c *gin.Context) {
+	schedules, err := h.scheduler.Schedules(c)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	leader, err := h.scheduler.Leader(c)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: ScheduleListResponse{Leader: leader, Schedules: schedules},
+	})
+}
@@ -0,0 +1,18 @@
+package admin
+
+import "core-ledger/pkg/queue"
+
+type SetLogLevelRequest struct {
+	// Level DEBUG | INFO | WARNING | ERROR | CRITICAL | ALERT | EMERGENCY (không phân biệt hoa thường)
+	Level string `json:"level" binding:"required"`
+}
+
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
+
+type ScheduleListResponse struct {
+	// Leader id replica worker đang enqueue job theo lịch, rỗng nếu không có worker nào chạy scheduler
+	Leader    string               `json:"leader"`
+	Schedules []queue.ScheduleInfo `json:"schedules"`
+}
@@ -0,0 +1,22 @@
+package admin
+
+import (
+	"core-ledger/internal/module/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerAPIRoutes(r *gin.RouterGroup, h *AdminHandler, middleware ...gin.HandlerFunc) {
+	// Apply middleware to the group if provided
+	tx := r.Group("admin", middleware...)
+	{
+		tx.GET("/log-level", rbac.Require(rbac.PermConfigRead), h.GetLogLevel)
+		tx.PUT("/log-level", rbac.Require(rbac.PermConfigWrite), h.SetLogLevel)
+		tx.GET("/schedules", rbac.Require(rbac.PermConfigRead), h.ListSchedules)
+	}
+}
+
+// SetupRoutes registers operational admin routes with optional middleware
+func SetupRoutes(rg *gin.RouterGroup, h *AdminHandler, middleware ...gin.HandlerFunc) {
+	registerAPIRoutes(rg, h, middleware...)
+}
@@ -0,0 +1,125 @@
+package apikeys
+
+import (
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/middleware"
+	"core-ledger/internal/module/rbac"
+	"core-ledger/internal/module/validate"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiKeyHandler struct {
+	logger  logger.CustomLogger
+	service *ApiKeyService
+}
+
+func NewApiKeyHandler(service *ApiKeyService) *ApiKeyHandler {
+	return &ApiKeyHandler{
+		logger:  logger.NewSystemLog("ApiKeyHandler"),
+		service: service,
+	}
+}
+
+func (h *ApiKeyHandler) List(c *gin.Context) {
+	var q ListApiKeyQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		out := validate.FormatErrorMessage(q, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	res, err := h.service.List(c, &q)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// Create tạo API key, plaintext key chỉ trả về trong response này
+func (h *ApiKeyHandler) Create(c *gin.Context) {
+	var req CreateApiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	res, err := h.service.Create(c, &req, rbac.GetPermissions(c), actor(c))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// Rotate cấp key mới thay thế, key cũ còn hiệu lực trong grace period
+func (h *ApiKeyHandler) Rotate(c *gin.Context) {
+	id, err := utils.ParseIntIdParam(c.Param("id"))
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "Invalid id")
+		return
+	}
+	var req RotateApiKeyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			out := validate.FormatErrorMessage(req, err)
+			ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+			return
+		}
+	}
+	res, err := h.service.Rotate(c, id, &req, rbac.GetPermissions(c), actor(c))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+func (h *ApiKeyHandler) Revoke(c *gin.Context) {
+	id, err := utils.ParseIntIdParam(c.Param("id"))
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "Invalid id")
+		return
+	}
+	res, err := h.service.Revoke(c, id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// actor id nhân viên thao tác, nil nếu request xác thực bằng API key
+func actor(c *gin.Context) *string {
+	employeeID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		return nil
+	}
+	id := strconv.FormatInt(employeeID, 10)
+	return &id
+}
+
+// respondServiceError: AppError trả về theo chuẩn RespondOKWithError, lỗi hệ thống trả 500
+func respondServiceError(c *gin.Context, err error) {
+	var appErr *core.AppError
+	if errors.As(err, &appErr) {
+		ginhp.RespondOKWithError(c, appErr)
+		return
+	}
+	ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,32 @@
+package apikeys
+
+import (
+	model "core-ledger/model/core-ledger"
+	"time"
+)
+
+type CreateApiKeyRequest struct {
+	Name         string `json:"name" binding:"required,max=128"`
+	OwnerService string `json:"owner_service" binding:"required,max=64"`
+	// TenantID gắn vào log/rate limit, request của key chỉ được mang tenant_id này
+	TenantID  *string    `json:"tenant_id,omitempty" binding:"omitempty,max=36"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,required,max=64"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type RotateApiKeyRequest struct {
+	// GracePeriodSeconds key cũ còn dùng được thêm bao lâu sau khi rotate (tối đa 7 ngày), 0 = hết hạn ngay
+	GracePeriodSeconds int64      `json:"grace_period_seconds" binding:"min=0,max=604800"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+}
+
+type ListApiKeyQuery struct {
+	OwnerService string `form:"owner_service"`
+}
+
+type ApiKeyResponse struct {
+	*model.ApiKey
+	Status string `json:"status"`
+	// Key plaintext, chỉ trả về một lần khi tạo/rotate
+	Key string `json:"key,omitempty"`
+}
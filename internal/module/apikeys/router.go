@@ -0,0 +1,23 @@
+package apikeys
+
+import (
+	"core-ledger/internal/module/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerAPIRoutes(r *gin.RouterGroup, h *ApiKeyHandler, middleware ...gin.HandlerFunc) {
+	// Apply middleware to the group if provided
+	tx := r.Group("api-keys", middleware...)
+	{
+		tx.GET("", rbac.Require(rbac.PermApiKeysManage), h.List)
+		tx.POST("", rbac.Require(rbac.PermApiKeysManage), h.Create)
+		tx.POST("/:id/rotate", rbac.Require(rbac.PermApiKeysManage), h.Rotate)
+		tx.POST("/:id/revoke", rbac.Require(rbac.PermApiKeysManage), h.Revoke)
+	}
+}
+
+// SetupRoutes registers service API key admin routes with optional middleware
+func SetupRoutes(rg *gin.RouterGroup, h *ApiKeyHandler, middleware ...gin.HandlerFunc) {
+	registerAPIRoutes(rg, h, middleware...)
+}
@@ -0,0 +1,247 @@
+package apikeys
+
+import (
+	"context"
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/rbac"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/repo"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// prefixLength số ký tự đầu của key lưu lại để nhận diện key trên giao diện quản trị
+const prefixLength = 8
+
+// touchInterval khoảng thời gian tối thiểu giữa 2 lần ghi last_used_at của cùng một key
+const touchInterval = time.Minute
+
+// touchedPruneSize số key trong touched mà từ đó mỗi lần ghi sẽ dọn các mốc đã quá touchInterval
+const touchedPruneSize = 1024
+
+var (
+	ErrApiKeyInvalid = errors.New("Invalid api key")
+	ErrApiKeyExpired = errors.New("Api key expired")
+	ErrApiKeyRevoked = errors.New("Api key revoked")
+)
+
+// ApiKeyService quản lý API key cho service nội bộ và xác thực header X-API-Key (implement rbac.ApiKeyAuthenticator).
+// Chỉ lưu HMAC của key (core.HashApiKey), plaintext trả về một lần khi tạo/rotate.
+type ApiKeyService struct {
+	db         *gorm.DB
+	apiKeyRepo repo.ApiKeyRepo
+	logger     logger.CustomLogger
+
+	mu      sync.Mutex
+	touched map[uint64]time.Time
+}
+
+func NewApiKeyService(db *gorm.DB, apiKeyRepo repo.ApiKeyRepo) *ApiKeyService {
+	return &ApiKeyService{
+		db:         db,
+		apiKeyRepo: apiKeyRepo,
+		logger:     logger.NewSystemLog("ApiKeyService"),
+		touched:    map[uint64]time.Time{},
+	}
+}
+
+func (s *ApiKeyService) List(ctx context.Context, q *ListApiKeyQuery) ([]*ApiKeyResponse, error) {
+	apiKeys, err := s.apiKeyRepo.List(ctx, q.OwnerService)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	res := make([]*ApiKeyResponse, 0, len(apiKeys))
+	for _, k := range apiKeys {
+		res = append(res, &ApiKeyResponse{ApiKey: k, Status: k.Status(now)})
+	}
+	return res, nil
+}
+
+// Create tạo key mới, plaintext chỉ có trong response này. granted là permission của người tạo:
+// scope của key không được rộng hơn granted (không tự cấp quyền qua API key)
+func (s *ApiKeyService) Create(ctx context.Context, req *CreateApiKeyRequest, granted rbac.PermissionSet, createdBy *string) (*ApiKeyResponse, error) {
+	scopes, err := normalizeScopes(req.Scopes, granted)
+	if err != nil {
+		return nil, err
+	}
+	plain, apiKey, err := newApiKey()
+	if err != nil {
+		return nil, err
+	}
+	apiKey.Name = req.Name
+	apiKey.OwnerService = strings.TrimSpace(req.OwnerService)
+	apiKey.TenantID = req.TenantID
+	apiKey.Scopes = scopes
+	apiKey.ExpiresAt = req.ExpiresAt
+	apiKey.CreatedBy = createdBy
+	if err := s.apiKeyRepo.Create(apiKey); err != nil {
+		return nil, err
+	}
+	return &ApiKeyResponse{ApiKey: apiKey, Status: apiKey.Status(time.Now()), Key: plain}, nil
+}
+
+// Rotate tạo key mới cùng owner/tenant/scopes, key cũ hết hạn sau GracePeriodSeconds để service kịp đổi cấu hình.
+// Người rotate nhận plaintext của key mới nên cũng phải có đủ quyền của key (granted)
+func (s *ApiKeyService) Rotate(ctx context.Context, id int64, req *RotateApiKeyRequest, granted rbac.PermissionSet, createdBy *string) (*ApiKeyResponse, error) {
+	var res *ApiKeyResponse
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		apiKeyRepo := s.apiKeyRepo.WithTx(tx)
+		old, err := apiKeyRepo.GetByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return core.NewError(core.ErrCodeLedgerApiKeyNotFound, fmt.Sprintf("api key %d", id))
+			}
+			return err
+		}
+		now := time.Now()
+		if old.Status(now) == model.ApiKeyStatusRevoked {
+			return core.NewError(core.ErrCodeLedgerApiKeyRevoked, fmt.Sprintf("api key %d", id))
+		}
+		if _, err := normalizeScopes(old.Scopes, granted); err != nil {
+			return err
+		}
+
+		plain, apiKey, err := newApiKey()
+		if err != nil {
+			return err
+		}
+		apiKey.Name = old.Name
+		apiKey.OwnerService = old.OwnerService
+		apiKey.TenantID = old.TenantID
+		apiKey.Scopes = old.Scopes
+		apiKey.ExpiresAt = req.ExpiresAt
+		apiKey.RotatedFromID = &old.ID
+		apiKey.CreatedBy = createdBy
+		if err := apiKeyRepo.Create(apiKey); err != nil {
+			return err
+		}
+
+		expiresAt := now.Add(time.Duration(req.GracePeriodSeconds) * time.Second)
+		if old.ExpiresAt == nil || old.ExpiresAt.After(expiresAt) {
+			old.ExpiresAt = &expiresAt
+			if err := apiKeyRepo.UpdateSelectField(old, map[string]interface{}{"expires_at": expiresAt}); err != nil {
+				return err
+			}
+		}
+		res = &ApiKeyResponse{ApiKey: apiKey, Status: apiKey.Status(now), Key: plain}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Revoke thu hồi key ngay lập tức, gọi lại với key đã thu hồi không thay đổi gì
+func (s *ApiKeyService) Revoke(ctx context.Context, id int64) (*ApiKeyResponse, error) {
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.NewError(core.ErrCodeLedgerApiKeyNotFound, fmt.Sprintf("api key %d", id))
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if apiKey.RevokedAt == nil {
+		apiKey.RevokedAt = &now
+		if err := s.apiKeyRepo.UpdateSelectField(apiKey, map[string]interface{}{"revoked_at": now}); err != nil {
+			return nil, err
+		}
+	}
+	return &ApiKeyResponse{ApiKey: apiKey, Status: apiKey.Status(now)}, nil
+}
+
+// AuthenticateApiKey tìm key theo HMAC, từ chối key đã thu hồi/hết hạn và ghi nhận last_used_at
+func (s *ApiKeyService) AuthenticateApiKey(ctx context.Context, key string) (*model.ApiKey, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, ErrApiKeyInvalid
+	}
+	apiKey, err := s.apiKeyRepo.GetByHash(ctx, core.HashApiKey(key))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApiKeyInvalid
+		}
+		return nil, err
+	}
+	if !core.VerifyApiKey(key, apiKey.KeyHash) {
+		return nil, ErrApiKeyInvalid
+	}
+	now := time.Now()
+	switch apiKey.Status(now) {
+	case model.ApiKeyStatusRevoked:
+		return nil, ErrApiKeyRevoked
+	case model.ApiKeyStatusExpired:
+		return nil, ErrApiKeyExpired
+	}
+	s.touch(ctx, apiKey, now)
+	return apiKey, nil
+}
+
+// touch ghi last_used_at tối đa một lần mỗi touchInterval cho mỗi key, lỗi chỉ log để không chặn request
+func (s *ApiKeyService) touch(ctx context.Context, apiKey *model.ApiKey, now time.Time) {
+	s.mu.Lock()
+	last, ok := s.touched[apiKey.ID]
+	if ok && now.Sub(last) < touchInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.touched[apiKey.ID] = now
+	// chỉ cần nhớ mốc trong touchInterval gần nhất, dọn để map không lớn dần theo số key từng dùng
+	if len(s.touched) > touchedPruneSize {
+		for id, at := range s.touched {
+			if now.Sub(at) >= touchInterval {
+				delete(s.touched, id)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if err := s.apiKeyRepo.TouchLastUsed(ctx, apiKey.ID, now); err != nil {
+		s.logger.Error(fmt.Sprintf("touch api key %d: %v", apiKey.ID, err))
+		return
+	}
+	apiKey.LastUsedAt = &now
+}
+
+// newApiKey sinh key ngẫu nhiên, trả về plaintext và model đã có prefix/key_hash
+func newApiKey() (string, *model.ApiKey, error) {
+	plain, err := core.GenerateApiKey()
+	if err != nil {
+		return "", nil, err
+	}
+	return plain, &model.ApiKey{
+		Prefix:  plain[:prefixLength],
+		KeyHash: core.HashApiKey(plain),
+	}, nil
+}
+
+// normalizeScopes bỏ trùng và kiểm tra scope khớp permission hệ thống (cho phép wildcard theo nhóm "ledger.*").
+// "*" không cấp được cho API key; mọi permission mà scope bao phủ phải nằm trong granted
+func normalizeScopes(scopes []string, granted rbac.PermissionSet) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	out := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if seen[scope] {
+			continue
+		}
+		if scope == "*" || !rbac.IsKnownPermission(scope) {
+			return nil, core.NewError(core.ErrCodeLedgerApiKeyInvalidScope, fmt.Sprintf("scope %s", scope))
+		}
+		if missing := rbac.MissingPermissions(scope, granted); len(missing) > 0 {
+			return nil, core.NewError(core.ErrCodeLedgerApiKeyInvalidScope,
+				fmt.Sprintf("scope %s vượt quyền người tạo, thiếu %s", scope, strings.Join(missing, ", ")))
+		}
+		seen[scope] = true
+		out = append(out, scope)
+	}
+	return out, nil
+}
@@ -0,0 +1,232 @@
+package apikeys
+
+import (
+	"context"
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/rbac"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/repo"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// fakeApiKeys api_keys trong bộ nhớ, tìm theo key_hash như repo thật
+type fakeApiKeys struct {
+	repo.ApiKeyRepo
+	rows    map[uint64]*model.ApiKey
+	touches int
+}
+
+func newFakeApiKeys() *fakeApiKeys {
+	return &fakeApiKeys{rows: map[uint64]*model.ApiKey{}}
+}
+
+func (f *fakeApiKeys) Create(apiKey *model.ApiKey) error {
+	apiKey.ID = uint64(len(f.rows) + 1)
+	f.rows[apiKey.ID] = apiKey
+	return nil
+}
+
+func (f *fakeApiKeys) GetByID(_ context.Context, id int64) (*model.ApiKey, error) {
+	if row, ok := f.rows[uint64(id)]; ok {
+		return row, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeApiKeys) GetByHash(_ context.Context, keyHash string) (*model.ApiKey, error) {
+	for _, row := range f.rows {
+		if row.KeyHash == keyHash {
+			return row, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeApiKeys) UpdateSelectField(*model.ApiKey, map[string]interface{}) error {
+	return nil
+}
+
+func (f *fakeApiKeys) TouchLastUsed(context.Context, uint64, time.Time) error {
+	f.touches++
+	return nil
+}
+
+func appErrCode(err error) core.AppErrorCode {
+	var appErr *core.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return ""
+}
+
+func TestApiKeyHashing(t *testing.T) {
+	service := NewApiKeyService(nil, newFakeApiKeys())
+	res, err := service.Create(context.Background(), &CreateApiKeyRequest{Name: "wallet", OwnerService: "wallet", Scopes: []string{rbac.PermLedgerJournalRead}},
+		rbac.NewPermissionSet([]string{"*"}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Key == "" || res.KeyHash == res.Key || res.KeyHash != core.HashApiKey(res.Key) || res.Prefix != res.Key[:prefixLength] {
+		t.Fatalf("key = %s hash = %s prefix = %s", res.Key, res.KeyHash, res.Prefix)
+	}
+	if !core.VerifyApiKey(res.Key, res.KeyHash) || core.VerifyApiKey(res.Key+"x", res.KeyHash) {
+		t.Fatal("VerifyApiKey")
+	}
+}
+
+func TestAuthenticateApiKey(t *testing.T) {
+	apiKeys := newFakeApiKeys()
+	service := NewApiKeyService(nil, apiKeys)
+	ctx := context.Background()
+	admin := rbac.NewPermissionSet([]string{"*"})
+	create := func() *ApiKeyResponse {
+		res, err := service.Create(ctx, &CreateApiKeyRequest{Name: "wallet", OwnerService: "wallet", Scopes: []string{"ledger.*"}}, admin, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	active := create()
+	for range 3 {
+		apiKey, err := service.AuthenticateApiKey(ctx, " "+active.Key+" ")
+		if err != nil || apiKey.ID != active.ID || apiKey.LastUsedAt == nil {
+			t.Fatalf("active: key = %+v err = %v", apiKey, err)
+		}
+	}
+	// last_used_at chỉ ghi một lần trong touchInterval
+	if apiKeys.touches != 1 {
+		t.Fatalf("touches = %d", apiKeys.touches)
+	}
+
+	revoked := create()
+	if _, err := service.Revoke(ctx, int64(revoked.ID)); err != nil {
+		t.Fatal(err)
+	}
+	expired := create()
+	past := time.Now().Add(-time.Second)
+	expired.ExpiresAt = &past
+
+	for key, want := range map[string]error{
+		"":                        ErrApiKeyInvalid,
+		"0123456789abcdef":        ErrApiKeyInvalid,
+		active.Key[:prefixLength]: ErrApiKeyInvalid,
+		revoked.Key:               ErrApiKeyRevoked,
+		expired.Key:               ErrApiKeyExpired,
+	} {
+		if _, err := service.AuthenticateApiKey(ctx, key); !errors.Is(err, want) {
+			t.Errorf("%q: err = %v, want %v", key, err, want)
+		}
+	}
+}
+
+func TestApiKeyScopes(t *testing.T) {
+	service := NewApiKeyService(nil, newFakeApiKeys())
+	operator := rbac.NewPermissionSet([]string{rbac.PermApiKeysManage, "ledger.journal.*", rbac.PermCoaRead})
+	cases := []struct {
+		scopes []string
+		ok     bool
+	}{
+		{[]string{rbac.PermLedgerJournalRead, rbac.PermLedgerJournalPost, rbac.PermLedgerJournalRead}, true},
+		{[]string{"ledger.journal.*", rbac.PermCoaRead}, true},
+		{[]string{"*"}, false},
+		{[]string{"ledger.*"}, false},
+		{[]string{rbac.PermCoaWrite}, false},
+		{[]string{"unknown.read"}, false},
+	}
+	for _, tc := range cases {
+		res, err := service.Create(context.Background(), &CreateApiKeyRequest{Name: "svc", OwnerService: "svc", Scopes: tc.scopes}, operator, nil)
+		if tc.ok && (err != nil || len(res.Scopes) != 2) {
+			t.Errorf("%v: res = %+v err = %v", tc.scopes, res, err)
+		}
+		if !tc.ok && appErrCode(err) != core.ErrCodeLedgerApiKeyInvalidScope {
+			t.Errorf("%v: err = %v", tc.scopes, err)
+		}
+	}
+}
+
+func TestApiKeyScopeEnforcement(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := NewApiKeyService(nil, newFakeApiKeys())
+	res, err := service.Create(context.Background(), &CreateApiKeyRequest{Name: "wallet", OwnerService: "wallet", Scopes: []string{rbac.PermLedgerJournalRead}},
+		rbac.NewPermissionSet([]string{"*"}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.Use(rbac.NewPermissionResolver(nil).Authenticate(service))
+	router.GET("/journals", rbac.Require(rbac.PermLedgerJournalRead), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/journals", rbac.Require(rbac.PermLedgerJournalPost), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	cases := []struct {
+		method, key string
+		want        int
+	}{
+		{http.MethodGet, res.Key, http.StatusOK},
+		{http.MethodPost, res.Key, http.StatusForbidden},
+		{http.MethodGet, "0123456789abcdef", http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, "/journals", nil)
+		req.Header.Set(rbac.HeaderApiKey, tc.key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s %s: status = %d, want %d", tc.method, tc.key, w.Code, tc.want)
+		}
+	}
+}
+
+func TestApiKeyTenantEnforcement(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := NewApiKeyService(nil, newFakeApiKeys())
+	tenant := "tenant-a"
+	res, err := service.Create(context.Background(), &CreateApiKeyRequest{Name: "wallet", OwnerService: "wallet", TenantID: &tenant, Scopes: []string{rbac.PermLedgerJournalPost}},
+		rbac.NewPermissionSet([]string{"*"}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.Use(rbac.NewPermissionResolver(nil).Authenticate(service))
+	// handler vẫn đọc được body sau khi middleware kiểm tra tenant
+	router.POST("/journals", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, "%s", body)
+	})
+
+	cases := []struct {
+		name, target, body string
+		want               int
+	}{
+		{"same tenant", "/journals", `{"tenant_id":"tenant-a"}`, http.StatusOK},
+		{"no tenant", "/journals", `{"currency":"USD"}`, http.StatusOK},
+		{"null tenant", "/journals", `{"tenant_id":null}`, http.StatusOK},
+		{"invalid json left to handler", "/journals", `not json`, http.StatusOK},
+		{"other tenant", "/journals", `{"tenant_id":"tenant-b"}`, http.StatusForbidden},
+		{"other tenant in batch journal", "/journals", `{"tenant_id":"tenant-a","journals":[{"tenant_id":"tenant-b"}]}`, http.StatusForbidden},
+		{"query tenant", "/journals?tenant_id=tenant-b", `{}`, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader(tc.body))
+		req.Header.Set(rbac.HeaderApiKey, res.Key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: status = %d, want %d", tc.name, w.Code, tc.want)
+		}
+		if tc.want == http.StatusOK && w.Body.String() != tc.body {
+			t.Errorf("%s: handler body = %q", tc.name, w.Body.String())
+		}
+	}
+}
@@ -2,23 +2,26 @@ package coaaccount
 
 import (
 	"bytes"
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/exports"
+	"core-ledger/internal/module/validate"
+	model "core-ledger/model/core-ledger"
 	"core-ledger/model/dto"
+	"core-ledger/pkg/export"
 	"core-ledger/pkg/ginhp"
 	"core-ledger/pkg/logger"
 	"core-ledger/pkg/queue"
 	"core-ledger/pkg/repo"
 	"core-ledger/pkg/utils"
-	"core-ledger/pkg/utils/helper"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"time"
 
 	// "encoding/json"
 
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/xuri/excelize/v2"
 )
 
 type CoaAccountHandler struct {
@@ -26,14 +29,16 @@ type CoaAccountHandler struct {
 	service       *CoaAccountService
 	coAccountRepo repo.CoAccountRepo
 	dispatcher    queue.Dispatcher
+	exportService *exports.ExportService
 }
 
-func NewCoaAccountHandler(service *CoaAccountService, coAccountRepo repo.CoAccountRepo, dispatcher queue.Dispatcher) *CoaAccountHandler {
+func NewCoaAccountHandler(service *CoaAccountService, coAccountRepo repo.CoAccountRepo, dispatcher queue.Dispatcher, exportService *exports.ExportService) *CoaAccountHandler {
 	return &CoaAccountHandler{
 		logger:        logger.NewSystemLog("CoaAccountHandler"),
 		service:       service,
 		coAccountRepo: coAccountRepo,
 		dispatcher:    dispatcher,
+		exportService: exportService,
 	}
 }
 
@@ -45,6 +50,8 @@ func (h *CoaAccountHandler) List(c *gin.Context) {
 		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	// query param ngoài struct filter là filter DSL (amount_gte, meta.provider...), kiểm tra theo whitelist của model
+	q.Filters = repo.QueryFilters(c.Request.URL.Query(), q)
 	// Log request filter
 	if qBytes, err := json.Marshal(q); err == nil {
 		h.logger.Info("ListCoaAccountFilter request:", string(qBytes))
@@ -52,9 +59,20 @@ func (h *CoaAccountHandler) List(c *gin.Context) {
 		h.logger.Info("ListCoaAccountFilter request:", q)
 	}
 
+	// cursor= (kể cả rỗng cho trang đầu) chuyển sang phân trang keyset, không có thì giữ page=
+	if q.Cursor != nil {
+		res, err := h.coAccountRepo.CursorPaginateWithScopes(c, q)
+		if err != nil {
+			respondListError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.PreResponse{Data: res})
+		return
+	}
+
 	res, err := h.coAccountRepo.PaginateWithScopes(c, q)
 	if err != nil {
-		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		respondListError(c, err)
 		return
 	}
 	h.logger.Info("ListCoaAccountFilter res:", res)
@@ -86,212 +104,103 @@ func (h *CoaAccountHandler) GetCoaAccountDetail(c *gin.Context) {
 	})
 }
 
-func (h *CoaAccountHandler) ExportCoaAccounts(c *gin.Context) {
-	// --- Bind JSON request ---
-	var req *ExportRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Info("ExportRequest err", err)
-		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
-		return
-	}
-
-	if len(req.Select) == 0 {
-		ginhp.RespondError(c, http.StatusBadRequest, "export file must have at least one header key")
+// GetBalance trả về ledger_balance và available_balance (= ledger_balance - held_amount)
+func (h *CoaAccountHandler) GetBalance(c *gin.Context) {
+	id, err := utils.ParseIntIdParam(c.Param("id"))
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "Invalid id")
 		return
 	}
-
-	// --- Parse query filter ---
-	var filter *dto.ListCoaAccountFilter
-	queryBytes, _ := json.Marshal(req.Query)
-	if err := json.Unmarshal(queryBytes, &filter); err != nil {
-		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+	res, err := h.service.GetBalance(c, id)
+	if err != nil {
+		var appErr *core.AppError
+		if errors.As(err, &appErr) {
+			ginhp.RespondOKWithError(c, appErr)
+			return
+		}
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
 		return
 	}
-	h.logger.Info("transactionFilter", filter)
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
 
-	// --- Fetch data ---
-	data, err := h.coAccountRepo.Paginate(filter)
+// UpdateBalancePolicy cập nhật chính sách số dư của tài khoản
+func (h *CoaAccountHandler) UpdateBalancePolicy(c *gin.Context) {
+	id, err := utils.ParseIntIdParam(c.Param("id"))
 	if err != nil {
-		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+		ginhp.RespondError(c, http.StatusBadRequest, "Invalid id")
 		return
 	}
-	if len(data.Items) == 0 {
+	var req UpdateBalancePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
 		return
 	}
-
-	// --- Create Excel file ---
-	f := excelize.NewFile()
-	const sheet = "Title"
-	_ = f.SetSheetName("Sheet1", sheet)
-
-	// Set header
-	for col, key := range req.Select {
-		cell, _ := excelize.CoordinatesToCellName(col+1, 1)
-		_ = f.SetCellValue(sheet, cell, key)
-	}
-
-	// Set data rows
-	for rowIndex, tran := range data.Items {
-		for colIndex, head := range req.Select {
-			err = h.getDataHeader(f, sheet, req.Select)
-			if err != nil {
-				ginhp.RespondError(c, http.StatusBadRequest, err.Error())
-				return
-			}
-			cell, _ := excelize.CoordinatesToCellName(colIndex+1, rowIndex+2)
-
-			var value any
-			switch head {
-			case CoaAccountExportKeyIndex:
-				value = rowIndex + 1
-			case CoaAccountExportKeyCode:
-				value = tran.Code
-			case CoaAccountExportKeyName:
-				value = tran.Name
-			case CoaAccountExportKeyType:
-				value = tran.Type
-			case CoaAccountExportKeyParentCode:
-				value = tran.Parent.Code
-			case CoaAccountExportKeyStatus:
-				value = tran.Status
-			case CoaAccountExportKeyProvider:
-				value = tran.Provider
-			case CoaAccountExportKeyNetwork:
-				value = tran.Network
-			case CoaAccountExportKeyTags:
-				value = helper.FormatJSONForExcel(tran.Tags)
-			case CoaAccountExportKeyMetadata:
-				value = helper.FormatJSONForExcel(tran.Metadata)
-			case CoaAccountExportKeyCreatedAt:
-				loc, _ := time.LoadLocation("Asia/Ho_Chi_Minh")
-				value = tran.CreatedAt.In(loc).Format("2006-01-02 15:04:05")
-			case CoaAccountExportKeyUpdatedAt:
-				loc, _ := time.LoadLocation("Asia/Ho_Chi_Minh")
-				value = tran.UpdatedAt.In(loc).Format("2006-01-02 15:04:05")
-			}
-
-			if value == nil {
-				value = ""
-			}
-			_ = f.SetCellValue(sheet, cell, value)
+	res, err := h.service.UpdateBalancePolicy(c, id, &req)
+	if err != nil {
+		var appErr *core.AppError
+		if errors.As(err, &appErr) {
+			ginhp.RespondOKWithError(c, appErr)
+			return
 		}
-		_ = f.SetRowHeight(sheet, rowIndex+2, 35)
-	}
-
-	// --- Adjust column width ---
-	for i := 1; i <= len(req.Select); i++ {
-		colName, _ := excelize.ColumnNumberToName(i)
-		text, _ := f.GetCellValue(sheet, fmt.Sprintf("%s2", colName))
-		_ = f.SetColWidth(sheet, colName, colName, float64(len(text))*3)
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
 	}
-
-	// --- Set body style ---
-	bodyStyle, _ := f.NewStyle(&excelize.Style{
-		Font:      &excelize.Font{Size: 11, Color: "000000"},
-		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
-		Border: []excelize.Border{
-			{Type: "left", Color: "DDDDDD", Style: 1},
-			{Type: "top", Color: "DDDDDD", Style: 1},
-			{Type: "bottom", Color: "DDDDDD", Style: 1},
-			{Type: "right", Color: "DDDDDD", Style: 1},
-		},
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
 	})
-	lastCol, _ := excelize.ColumnNumberToName(len(req.Select))
-	_ = f.SetCellStyle(sheet, "A2", fmt.Sprintf("%s%d", lastCol, len(data.Items)+1), bodyStyle)
-
-	// --- Prepare file name ---
-	loc := time.FixedZone("UTC+7", 7*60*60)
-	const layout = "02-01-2006"
-	timestamp := time.Now().In(loc).Format(layout)
+}
 
-	if req.Query.StartDate != nil && req.Query.EndDate != nil {
-		startDate, err1 := time.Parse("2006-01-02", *req.Query.StartDate)
-		endDate, err2 := time.Parse("2006-01-02", *req.Query.EndDate)
-		if err1 == nil && err2 == nil {
-			startFormatted := startDate.In(loc).Format(layout)
-			endFormatted := endDate.In(loc).Format(layout)
-			if startFormatted == endFormatted {
-				timestamp = startFormatted
-			} else {
-				timestamp = fmt.Sprintf("%s-%s", startFormatted, endFormatted)
-			}
-		}
+// ExportCoaAccounts xuất trực tiếp danh sách tài khoản (tối đa EXPORT_SYNC_MAX_ROWS dòng),
+// danh sách lớn hơn dùng POST /exports với resource coa_accounts để chạy nền
+func (h *CoaAccountHandler) ExportCoaAccounts(c *gin.Context) {
+	var req ExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Info("ExportRequest err", err)
+		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+		return
 	}
-
-	fileName := req.FileName
-	if fileName == "" {
-		fileName = "Export-Transaction"
+	format, err := export.ParseFormat(req.Format)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+		return
 	}
-	downloadName := fmt.Sprintf("%s-%s.xlsx", fileName, timestamp)
-	h.logger.Info("Exporting Excel for download: " + downloadName)
-	// --- Write Excel to buffer and return ---
-	var buf bytes.Buffer
-	if err := f.Write(&buf); err != nil {
+	query, err := json.Marshal(req.Query)
+	if err != nil {
 		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	// ghi ra buffer trước để lỗi (cột sai, quá số dòng) vẫn trả được JSON
+	var buf bytes.Buffer
+	if _, err := h.exportService.Stream(c, &buf, exports.ResourceCoaAccounts, format, req.Select, query); err != nil {
+		var appErr *core.AppError
+		if errors.As(err, &appErr) {
+			ginhp.RespondOKWithError(c, appErr)
+			return
+		}
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	downloadName := h.exportService.DownloadName(req.FileName, exports.ResourceCoaAccounts, format)
+	h.logger.Info("Exporting CoA accounts for download: " + downloadName)
 	c.Header("Content-Description", "File Transfer")
 	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", downloadName))
-	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
-	c.Header("Content-Transfer-Encoding", "binary")
 	c.Header("Expires", "0")
 	c.Header("Cache-Control", "must-revalidate")
 	c.Header("Pragma", "public")
-	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
 }
 
-func (h *CoaAccountHandler) getDataHeader(f *excelize.File, sheetName string, keys []CoaAccountExportKey) error {
-	if len(keys) == 0 {
-		// giả sử TransactionExportKeys có thể được định nghĩa sẵn nếu cần
-		return nil
-	}
-	headerData := make([]string, len(keys))
-	for i, key := range keys {
-		headerData[i] = TransactionExportHeaders[key]
-	}
-
-	// Ghi hàng header (bắt đầu từ hàng 1)
-	rowIndex := 1
-	for i, header := range headerData {
-		cell, _ := excelize.CoordinatesToCellName(i+1, rowIndex)
-		f.SetCellValue(sheetName, cell, header)
-	}
-
-	// Style cho header
-	style, err := f.NewStyle(&excelize.Style{
-		Alignment: &excelize.Alignment{
-			Horizontal: "center",
-			Vertical:   "middle",
-			WrapText:   true,
-		},
-		Fill: excelize.Fill{
-			Type:    "pattern",
-			Pattern: 1, // solid fill
-			Color:   []string{"#D3D3D3"},
-		},
-		Font: &excelize.Font{
-			Bold: true,
-		},
-		Border: []excelize.Border{
-			{Type: "left", Color: "000000", Style: 1},
-			{Type: "top", Color: "000000", Style: 1},
-			{Type: "right", Color: "000000", Style: 1},
-			{Type: "bottom", Color: "000000", Style: 1},
-		},
-	})
-	if err != nil {
-		return err
+// respondListError cursor sai/không khớp sort hoặc filter không hợp lệ trả 400, lỗi khác 500
+func respondListError(c *gin.Context, err error) {
+	if errors.Is(err, repo.ErrInvalidCursor) || errors.Is(err, model.ErrInvalidFilter) {
+		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+		return
 	}
-
-	// Áp dụng style cho toàn hàng
-	colCount := len(headerData)
-	startCell, _ := excelize.CoordinatesToCellName(1, rowIndex)
-	endCell, _ := excelize.CoordinatesToCellName(colCount, rowIndex)
-	f.SetCellStyle(sheetName, startCell, endCell, style)
-
-	// Đặt chiều cao hàng (40)
-	f.SetRowHeight(sheetName, rowIndex, 40)
-
-	return nil
+	ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
 }
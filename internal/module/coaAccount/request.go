@@ -2,52 +2,20 @@ package coaaccount
 
 import "core-ledger/model/dto"
 
+// ExportRequest xuất trực tiếp danh sách tài khoản. Select là key cột theo thứ tự trong file
+// (xem GET /exports/resources, resource coa_accounts), rỗng thì lấy tất cả; Format xlsx (mặc định), csv, jsonl
 type ExportRequest struct {
-	Select   []CoaAccountExportKey     `json:"select"`
+	Select   []string                  `json:"select"`
 	Query    *dto.ListCoaAccountFilter `json:"query"`
 	FileName string                    `json:"file_name"`
+	Format   string                    `json:"format"`
 }
 
-type ExportResponse struct {
-	FileName string `json:"file_name"`
-	URL      string `json:"url"`
-}
-
-type CoaAccountExportKey string
-
-const (
-	CoaAccountExportKeyIndex      CoaAccountExportKey = "index"
-	CoaAccountExportKeyCode       CoaAccountExportKey = "code"
-	CoaAccountExportKeyName       CoaAccountExportKey = "name"
-	CoaAccountExportKeyType       CoaAccountExportKey = "type"
-	CoaAccountExportKeyParentCode CoaAccountExportKey = "parent_code"
-	CoaAccountExportKeyStatus     CoaAccountExportKey = "status"
-
-	CoaAccountExportKeyProvider CoaAccountExportKey = "provider"
-
-	CoaAccountExportKeyNetwork  CoaAccountExportKey = "network"
-	CoaAccountExportKeyTags     CoaAccountExportKey = "tags"
-	CoaAccountExportKeyMetadata CoaAccountExportKey = "metadata"
-
-	CoaAccountExportKeyCreatedAt CoaAccountExportKey = "created_at"
-	CoaAccountExportKeyUpdatedAt CoaAccountExportKey = "updated_at"
-)
-
-func (t CoaAccountExportKey) String() string {
-	return string(t)
-}
-
-var TransactionExportHeaders = map[CoaAccountExportKey]string{
-	"index":       "STT",
-	"code":        "code",
-	"name":        "name",
-	"type":        "type",
-	"parent_code": "parent_code",
-	"status":      "status",
-	"provider":    "provider",
-	"network":     "network",
-	"tags":        "tags",
-	"metadata":    "metadata",
-	"created_at":  "Ngày tạo",
-	"updated_at":  "Ngày cập nhật",
+// UpdateBalancePolicyRequest cập nhật chính sách số dư của tài khoản, trường nào không truyền giữ nguyên.
+// Truyền chuỗi rỗng cho min_balance/overdraft_limit/alert_threshold để xoá giới hạn.
+type UpdateBalancePolicyRequest struct {
+	AllowNegative  *bool   `json:"allow_negative,omitempty"`
+	MinBalance     *string `json:"min_balance,omitempty"`
+	OverdraftLimit *string `json:"overdraft_limit,omitempty"`
+	AlertThreshold *string `json:"alert_threshold,omitempty"`
 }
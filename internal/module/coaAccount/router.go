@@ -1,6 +1,8 @@
 package coaaccount
 
 import (
+	"core-ledger/internal/module/rbac"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -8,9 +10,11 @@ func registerAPIRoutes(r *gin.RouterGroup, h *CoaAccountHandler, middleware ...g
 	// Apply middleware to the group if provided
 	tx := r.Group("coa-accounts", middleware...)
 	{
-		tx.GET("/list", h.List)
-		tx.GET("/:id", h.GetCoaAccountDetail)
-		tx.GET("export", h.ExportCoaAccounts)
+		tx.GET("/list", rbac.Require(rbac.PermCoaRead), h.List)
+		tx.GET("/:id", rbac.Require(rbac.PermCoaRead), h.GetCoaAccountDetail)
+		tx.GET("/:id/balance", rbac.Require(rbac.PermCoaRead), h.GetBalance)
+		tx.PUT("/:id/policy", rbac.Require(rbac.PermCoaWrite), h.UpdateBalancePolicy)
+		tx.GET("export", rbac.Require(rbac.PermReportsRead), h.ExportCoaAccounts)
 		// Add more routes here
 		// tx.POST("", h.Create)
 		// tx.GET("/:id", h.GetByID)
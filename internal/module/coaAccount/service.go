@@ -2,35 +2,48 @@ package coaaccount
 
 import (
 	"context"
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/journals"
 	model "core-ledger/model/core-ledger"
 	"core-ledger/model/dto"
 	"core-ledger/pkg/logger"
 	"core-ledger/pkg/queue"
 	"core-ledger/pkg/repo"
+	"errors"
+	"strings"
+
+	"github.com/shopspring/decimal"
 
 	"gorm.io/gorm"
 )
 
 type CoaAccountService struct {
-	db            *gorm.DB
-	coAccountRepo repo.CoAccountRepo
-	entriesRepo   repo.EnTriesRepo
-	snapShotRepo  repo.SnapshotRepo
-	logger        logger.CustomLogger
-	dispatcher    queue.Dispatcher
+	db             *gorm.DB
+	coAccountRepo  repo.CoAccountRepo
+	entriesRepo    repo.EnTriesRepo
+	snapShotRepo   repo.SnapshotRepo
+	balanceService *journals.BalanceService
+	logger         logger.CustomLogger
+	dispatcher     queue.Dispatcher
 }
 
-func NewCoaAccountService(dispatcher queue.Dispatcher, db *gorm.DB, coAccountRepo repo.CoAccountRepo, entriesRepo repo.EnTriesRepo, snapShotRepo repo.SnapshotRepo) *CoaAccountService {
+func NewCoaAccountService(dispatcher queue.Dispatcher, db *gorm.DB, coAccountRepo repo.CoAccountRepo, entriesRepo repo.EnTriesRepo, snapShotRepo repo.SnapshotRepo, balanceService *journals.BalanceService) *CoaAccountService {
 	return &CoaAccountService{
-		db:            db,
-		coAccountRepo: coAccountRepo,
-		logger:        logger.NewSystemLog("CoaAccountService"),
-		dispatcher:    dispatcher,
-		entriesRepo:   entriesRepo,
-		snapShotRepo:  snapShotRepo,
+		db:             db,
+		coAccountRepo:  coAccountRepo,
+		logger:         logger.NewSystemLog("CoaAccountService"),
+		dispatcher:     dispatcher,
+		entriesRepo:    entriesRepo,
+		snapShotRepo:   snapShotRepo,
+		balanceService: balanceService,
 	}
 }
 
+// GetBalance số dư sổ cái và số dư khả dụng (đã trừ các hold đang PENDING)
+func (c *CoaAccountService) GetBalance(ctx context.Context, id int64) (*dto.AccountBalanceResponse, error) {
+	return c.balanceService.GetBalance(ctx, id)
+}
+
 func (c *CoaAccountService) GetCoaAccountDetail(ctx context.Context, id int64) (*dto.CoaAccountDetailResponse, error) {
 	data := &dto.CoaAccountDetailResponse{
 		CoaAccount: nil,
@@ -51,3 +64,57 @@ func (c *CoaAccountService) GetCoaAccountDetail(ctx context.Context, id int64) (
 
 	return data, nil
 }
+
+// UpdateBalancePolicy cập nhật chính sách số dư (cho phép âm / số dư tối thiểu / hạn mức thấu chi / ngưỡng cảnh báo)
+func (c *CoaAccountService) UpdateBalancePolicy(ctx context.Context, id int64, req *UpdateBalancePolicyRequest) (*model.CoaAccount, error) {
+	account, err := c.coAccountRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.NewError(core.ErrCodeLedgerAccountNotFound)
+		}
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	if req.AllowNegative != nil {
+		account.AllowNegative = *req.AllowNegative
+		fields["allow_negative"] = account.AllowNegative
+	}
+	if req.MinBalance != nil {
+		if account.MinBalance, err = parsePolicyAmount("min_balance", *req.MinBalance); err != nil {
+			return nil, err
+		}
+		fields["min_balance"] = account.MinBalance
+	}
+	if req.OverdraftLimit != nil {
+		if account.OverdraftLimit, err = parsePolicyAmount("overdraft_limit", *req.OverdraftLimit); err != nil {
+			return nil, err
+		}
+		fields["overdraft_limit"] = account.OverdraftLimit
+	}
+	if req.AlertThreshold != nil {
+		if account.AlertThreshold, err = parsePolicyAmount("alert_threshold", *req.AlertThreshold); err != nil {
+			return nil, err
+		}
+		fields["alert_threshold"] = account.AlertThreshold
+	}
+	if len(fields) == 0 {
+		return account, nil
+	}
+	if err := c.coAccountRepo.UpdateSelectField(account, fields); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// parsePolicyAmount chuỗi rỗng = bỏ giới hạn, ngược lại phải là số >= 0
+func parsePolicyAmount(field, value string) (*decimal.Decimal, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	amount, err := decimal.NewFromString(value)
+	if err != nil || amount.IsNegative() {
+		return nil, core.NewError(core.ErrCodeLedgerInvalidAmount, field+" phải là số >= 0")
+	}
+	return &amount, nil
+}
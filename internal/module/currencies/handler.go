@@ -0,0 +1,82 @@
+package currencies
+
+import (
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/validate"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CurrencyHandler struct {
+	logger  logger.CustomLogger
+	service *CurrencyService
+}
+
+func NewCurrencyHandler(service *CurrencyService) *CurrencyHandler {
+	return &CurrencyHandler{
+		logger:  logger.NewSystemLog("CurrencyHandler"),
+		service: service,
+	}
+}
+
+func (h *CurrencyHandler) List(c *gin.Context) {
+	res, err := h.service.List(c)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// Create đăng ký loại tiền mới, scale không sửa được sau khi tạo
+func (h *CurrencyHandler) Create(c *gin.Context) {
+	var req CreateCurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	res, err := h.service.Create(c, &req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// Update sửa tên/ký hiệu hoặc bật/tắt loại tiền
+func (h *CurrencyHandler) Update(c *gin.Context) {
+	var req UpdateCurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	res, err := h.service.Update(c, c.Param("code"), &req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// respondServiceError: AppError trả về theo chuẩn RespondOKWithError, lỗi hệ thống trả 500
+func respondServiceError(c *gin.Context, err error) {
+	var appErr *core.AppError
+	if errors.As(err, &appErr) {
+		ginhp.RespondOKWithError(c, appErr)
+		return
+	}
+	ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+}
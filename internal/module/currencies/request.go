@@ -0,0 +1,14 @@
+package currencies
+
+type CreateCurrencyRequest struct {
+	Code   string  `json:"code" binding:"required,max=8"`
+	Name   string  `json:"name" binding:"required,max=64"`
+	Symbol *string `json:"symbol,omitempty" binding:"omitempty,max=16"`
+	Scale  *int32  `json:"scale" binding:"required,min=0,max=8"`
+}
+
+type UpdateCurrencyRequest struct {
+	Name   *string `json:"name,omitempty" binding:"omitempty,max=64"`
+	Symbol *string `json:"symbol,omitempty" binding:"omitempty,max=16"`
+	Active *bool   `json:"active,omitempty"`
+}
@@ -0,0 +1,22 @@
+package currencies
+
+import (
+	"core-ledger/internal/module/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerAPIRoutes(r *gin.RouterGroup, h *CurrencyHandler, middleware ...gin.HandlerFunc) {
+	// Apply middleware to the group if provided
+	tx := r.Group("currencies", middleware...)
+	{
+		tx.GET("", rbac.Require(rbac.PermConfigRead), h.List)
+		tx.POST("", rbac.Require(rbac.PermConfigWrite), h.Create)
+		tx.PUT("/:code", rbac.Require(rbac.PermConfigWrite), h.Update)
+	}
+}
+
+// SetupRoutes registers currency registry routes with optional middleware
+func SetupRoutes(rg *gin.RouterGroup, h *CurrencyHandler, middleware ...gin.HandlerFunc) {
+	registerAPIRoutes(rg, h, middleware...)
+}
@@ -0,0 +1,164 @@
+package currencies
+
+import (
+	"context"
+	"core-ledger/internal/core"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/repo"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// cacheTTL thời gian giữ danh mục currency trong bộ nhớ, posting đọc registry ở mọi request
+const cacheTTL = time.Minute
+
+// CurrencyService danh mục loại tiền (ledger_currencies): scale đơn vị nhỏ nhất, quy đổi amount_atoms và định dạng số tiền.
+// Scale không cho phép sửa sau khi tạo vì amount_atoms đã ghi phụ thuộc vào scale.
+type CurrencyService struct {
+	currencyRepo repo.LedgerCurrencyRepo
+	logger       logger.CustomLogger
+
+	mu       sync.RWMutex
+	cache    map[string]*model.LedgerCurrency
+	loadedAt time.Time
+}
+
+func NewCurrencyService(currencyRepo repo.LedgerCurrencyRepo) *CurrencyService {
+	return &CurrencyService{
+		currencyRepo: currencyRepo,
+		logger:       logger.NewSystemLog("CurrencyService"),
+	}
+}
+
+func (s *CurrencyService) List(ctx context.Context) ([]*model.LedgerCurrency, error) {
+	return s.currencyRepo.List(ctx)
+}
+
+// Get trả về currency đang active theo code, lỗi CURRENCY_NOT_SUPPORTED nếu chưa đăng ký hoặc đã tắt
+func (s *CurrencyService) Get(ctx context.Context, code string) (*model.LedgerCurrency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	currencies, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	currency, ok := currencies[code]
+	if !ok || !currency.Active {
+		return nil, core.NewError(core.ErrCodeLedgerCurrencyNotSupported, fmt.Sprintf("currency %s", code))
+	}
+	return currency, nil
+}
+
+// ToAtoms kiểm tra amount đúng scale của currency và đổi sang amount_atoms
+func (s *CurrencyService) ToAtoms(currency *model.LedgerCurrency, amount decimal.Decimal, field string) (int64, error) {
+	atoms, err := currency.ToAtoms(amount)
+	switch {
+	case errors.Is(err, model.ErrAmountPrecision):
+		return 0, core.NewError(core.ErrCodeLedgerAmountPrecision,
+			fmt.Sprintf("%s: %s chỉ cho phép %d chữ số thập phân", field, currency.Code, currency.Scale))
+	case err != nil:
+		return 0, core.NewError(core.ErrCodeLedgerInvalidAmount, fmt.Sprintf("%s: %s", field, err.Error()))
+	}
+	return atoms, nil
+}
+
+// Format định dạng amount theo scale của currency, currency chưa đăng ký thì giữ nguyên
+func (s *CurrencyService) Format(ctx context.Context, code string, amount decimal.Decimal) string {
+	currencies, err := s.load(ctx)
+	if err != nil {
+		return amount.String()
+	}
+	currency, ok := currencies[strings.ToUpper(strings.TrimSpace(code))]
+	if !ok {
+		return amount.String()
+	}
+	return currency.Format(amount)
+}
+
+func (s *CurrencyService) Create(ctx context.Context, req *CreateCurrencyRequest) (*model.LedgerCurrency, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	if _, err := s.currencyRepo.GetByCode(ctx, code); err == nil {
+		return nil, core.NewError(core.ErrCodeLedgerCurrencyExists, fmt.Sprintf("currency %s", code))
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	currency := &model.LedgerCurrency{
+		Code:   code,
+		Name:   req.Name,
+		Symbol: req.Symbol,
+		Scale:  *req.Scale,
+		Active: true,
+		Source: model.CurrencySourceManual,
+	}
+	if err := s.currencyRepo.Create(currency); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return currency, nil
+}
+
+func (s *CurrencyService) Update(ctx context.Context, code string, req *UpdateCurrencyRequest) (*model.LedgerCurrency, error) {
+	currency, err := s.currencyRepo.GetByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.NewError(core.ErrCodeLedgerCurrencyNotSupported, fmt.Sprintf("currency %s", code))
+		}
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if req.Name != nil {
+		currency.Name = *req.Name
+		fields["name"] = currency.Name
+	}
+	if req.Symbol != nil {
+		currency.Symbol = req.Symbol
+		fields["symbol"] = currency.Symbol
+	}
+	if req.Active != nil {
+		currency.Active = *req.Active
+		fields["active"] = currency.Active
+	}
+	if len(fields) == 0 {
+		return currency, nil
+	}
+	if err := s.currencyRepo.UpdateSelectField(currency, fields); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return currency, nil
+}
+
+// load đọc danh mục từ cache, nạp lại từ DB khi quá cacheTTL
+func (s *CurrencyService) load(ctx context.Context) (map[string]*model.LedgerCurrency, error) {
+	s.mu.RLock()
+	cache, loadedAt := s.cache, s.loadedAt
+	s.mu.RUnlock()
+	if cache != nil && time.Since(loadedAt) < cacheTTL {
+		return cache, nil
+	}
+
+	list, err := s.currencyRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cache = make(map[string]*model.LedgerCurrency, len(list))
+	for _, c := range list {
+		cache[c.Code] = c
+	}
+	s.mu.Lock()
+	s.cache, s.loadedAt = cache, time.Now()
+	s.mu.Unlock()
+	return cache, nil
+}
+
+func (s *CurrencyService) invalidate() {
+	s.mu.Lock()
+	s.cache = nil
+	s.mu.Unlock()
+}
@@ -1,11 +1,13 @@
 package entries
 
 import (
+	model "core-ledger/model/core-ledger"
 	"core-ledger/model/dto"
 	"core-ledger/pkg/ginhp"
 	"core-ledger/pkg/logger"
 	"core-ledger/pkg/queue"
 	"core-ledger/pkg/repo"
+	"errors"
 
 	// "encoding/json"
 
@@ -38,10 +40,36 @@ func (h *EntriesHandler) List(c *gin.Context) {
 		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	// query param ngoài struct filter là filter DSL (amount_gte, meta.provider...), kiểm tra theo whitelist của model
+	q.Filters = repo.QueryFilters(c.Request.URL.Query(), q)
 	h.logger.Info("ListEntriesFilter request", q)
+	// cursor= (kể cả rỗng cho trang đầu) chuyển sang phân trang keyset, không có thì giữ page=
+	if q.Cursor != nil {
+		res, err := h.entryRepo.CursorPaginateWithScopes(c, q)
+		if err != nil {
+			respondListError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, dto.PreResponse{Data: res})
+		return
+	}
+
 	res, err := h.entryRepo.PaginateWithScopes(c, q)
+	if err != nil {
+		respondListError(c, err)
+		return
+	}
 	h.logger.Info("ListEntriesFilter res", res)
 	c.JSON(http.StatusOK, dto.PreResponse{
 		Data: res,
 	})
 }
+
+// respondListError cursor sai/không khớp sort hoặc filter không hợp lệ trả 400, lỗi khác 500
+func respondListError(c *gin.Context, err error) {
+	if errors.Is(err, repo.ErrInvalidCursor) || errors.Is(err, model.ErrInvalidFilter) {
+		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+}
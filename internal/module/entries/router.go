@@ -1,6 +1,8 @@
 package entries
 
 import (
+	"core-ledger/internal/module/rbac"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -8,7 +10,7 @@ func registerAPIRoutes(r *gin.RouterGroup, h *EntriesHandler, middleware ...gin.
 	// Apply middleware to the group if provided
 	tx := r.Group("entries", middleware...)
 	{
-		tx.GET("/list", h.List)
+		tx.GET("/list", rbac.Require(rbac.PermLedgerJournalRead), h.List)
 
 		// Add more routes here
 		// tx.POST("", h.Create)
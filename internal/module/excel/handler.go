@@ -1,10 +1,9 @@
 package excel
 
 import (
+	"core-ledger/internal/module/rbac"
 	"core-ledger/pkg/logger"
 
-	"fmt"
-
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -63,16 +62,25 @@ func (h *ExcelHandler) ImportCoAccounts(c *gin.Context) {
 		return
 	}
 
-	tmpPath := fmt.Sprintf("/tmp/%s", file.Filename)
-	if err := c.SaveUploadedFile(file, tmpPath); err != nil {
+	src, err := file.Open()
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Không thể lưu file"})
 		return
 	}
+	defer src.Close()
 
+	stored, jobID, err := h.service.ImportCoAccounts(c, file.Filename, file.Header.Get("Content-Type"), src, rbac.Principal(c))
+	if err != nil {
+		h.logger.Error("import co-accounts", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Không thể tạo job import"})
+		return
+	}
 	h.logger.Info("File uploaded:", file.Filename)
-	h.service.ImportCoAccounts(c, tmpPath)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "File đã được tải lên và đang chờ xử lý",
+		"job_id":  jobID,
+		"file_id": stored.ID,
+		"sha256":  stored.SHA256,
 	})
 }
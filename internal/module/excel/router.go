@@ -1,6 +1,8 @@
 package excel
 
 import (
+	"core-ledger/internal/module/rbac"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -8,7 +10,7 @@ func registerAPIRoutes(r *gin.RouterGroup, h *ExcelHandler, middleware ...gin.Ha
 	// Apply middleware to the group if provided
 	tx := r.Group("excel", middleware...)
 	{
-		tx.POST("/import/co-accounts", h.ImportCoAccounts)
+		tx.POST("/import/co-accounts", rbac.Require(rbac.PermCoaWrite), h.ImportCoAccounts)
 		// Add more routes here
 		// tx.POST("", h.Create)
 		// tx.GET("/:id", h.GetByID)
@@ -2,10 +2,13 @@ package excel
 
 import (
 	"context"
+	"core-ledger/internal/module/files"
+	model "core-ledger/model/core-ledger"
 	"core-ledger/pkg/logger"
 	"core-ledger/pkg/queue"
 	"core-ledger/pkg/queue/jobs"
 	"core-ledger/pkg/repo"
+	"io"
 	"log"
 
 	"gorm.io/gorm"
@@ -16,27 +19,35 @@ type ExcelService struct {
 	coAccountRepo repo.CoAccountRepo
 	logger        logger.CustomLogger
 	dispatcher    queue.Dispatcher
+	fileService   *files.FileService
 }
 
-func NewExcelService(dispatcher queue.Dispatcher, db *gorm.DB, coAccountRepo repo.CoAccountRepo) *ExcelService {
+func NewExcelService(dispatcher queue.Dispatcher, db *gorm.DB, coAccountRepo repo.CoAccountRepo, fileService *files.FileService) *ExcelService {
 	return &ExcelService{
 		db:            db,
 		coAccountRepo: coAccountRepo,
 		logger:        logger.NewSystemLog("ExcelService"),
 		dispatcher:    dispatcher,
+		fileService:   fileService,
 	}
 }
 
-func (s *ExcelService) ImportCoAccounts(ctx context.Context, tmpFile string) error {
-	s.logger.Info("Importing co-accounts from file: %s", tmpFile)
+// ImportCoAccounts lưu file upload vào storage (bảng files) rồi đẩy job import CoA vào queue,
+// trả về file đã lưu và task ID để theo dõi tiến độ qua GET /jobs/:id
+func (s *ExcelService) ImportCoAccounts(ctx context.Context, fileName, contentType string, r io.Reader, uploadedBy string) (*model.File, string, error) {
+	file, err := s.fileService.Save(ctx, model.FilePurposeImport, fileName, contentType, r, uploadedBy)
+	if err != nil {
+		return nil, "", err
+	}
+	s.logger.Info("Importing co-accounts from file %d (%s)", file.ID, file.StorageKey)
 	dataJob := jobs.NewImportCoaAccount("import_coa_account", "import", jobs.DataImportCoaAccount{
-		TmpFile: tmpFile,
+		FileID: file.ID,
 	})
 	dataJob.SetQueue("critical")
-	if err := s.dispatcher.Dispatch(dataJob); err != nil {
+	jobID, err := s.dispatcher.DispatchContext(ctx, dataJob)
+	if err != nil {
 		log.Printf("❌ Failed to dispatch data job: %v", err)
-
+		return nil, "", err
 	}
-	// TODO: Implement the logic to import co-accounts from the given fi
-	return nil
+	return file, jobID, nil
 }
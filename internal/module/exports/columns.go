@@ -0,0 +1,143 @@
+package exports
+
+import (
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/export"
+
+	"github.com/shopspring/decimal"
+)
+
+// Cột export của từng resource, key dùng cho tham số columns (giữ thứ tự truyền vào), không truyền thì lấy tất cả.
+// Key của CoA giữ như ExportRequest.select cũ của /coa-accounts/export.
+// Cột số tiền định dạng theo scale của currency trong danh mục (VD USD 2 chữ số thập phân) như balance API.
+
+// amountFormatter định dạng số tiền theo currency (currencies.CurrencyService.Format)
+type amountFormatter func(currency string, amount decimal.Decimal) string
+
+// formatAmount giá trị cột số tiền, nil giữ ô trống
+func formatAmount(format amountFormatter, currency string, amount *decimal.Decimal) any {
+	if amount == nil {
+		return nil
+	}
+	return format(currency, *amount)
+}
+
+func coaAccountColumns(format amountFormatter) export.Columns[*model.CoaAccount] {
+	return export.Columns[*model.CoaAccount]{
+		export.IndexCol[*model.CoaAccount]("index", "STT"),
+		export.Col("id", "ID", func(a *model.CoaAccount) any { return a.ID }),
+		export.Col("code", "Mã tài khoản", func(a *model.CoaAccount) any { return a.Code }),
+		export.Col("account_no", "Số tài khoản", func(a *model.CoaAccount) any { return a.AccountNo }),
+		export.Col("name", "Tên tài khoản", func(a *model.CoaAccount) any { return a.Name }),
+		export.Col("type", "Loại", func(a *model.CoaAccount) any { return a.Type }),
+		export.Col("currency", "Loại tiền", func(a *model.CoaAccount) any { return a.Currency }),
+		export.Col("parent_code", "Mã tài khoản cha", func(a *model.CoaAccount) any {
+			if a.Parent == nil {
+				return nil
+			}
+			return a.Parent.Code
+		}),
+		export.Col("status", "Trạng thái", func(a *model.CoaAccount) any { return a.Status }),
+		export.Col("provider", "Provider", func(a *model.CoaAccount) any { return a.Provider }),
+		export.Col("network", "Network", func(a *model.CoaAccount) any { return a.Network }),
+		export.Col("allow_negative", "Cho phép âm", func(a *model.CoaAccount) any { return a.AllowNegative }),
+		export.Col("min_balance", "Số dư tối thiểu", func(a *model.CoaAccount) any { return formatAmount(format, a.Currency, a.MinBalance) }),
+		export.Col("overdraft_limit", "Hạn mức thấu chi", func(a *model.CoaAccount) any { return formatAmount(format, a.Currency, a.OverdraftLimit) }),
+		export.Col("alert_threshold", "Ngưỡng cảnh báo", func(a *model.CoaAccount) any { return formatAmount(format, a.Currency, a.AlertThreshold) }),
+		export.Col("tags", "Tags", func(a *model.CoaAccount) any { return a.Tags }),
+		export.Col("metadata", "Metadata", func(a *model.CoaAccount) any { return a.Metadata }),
+		export.Col("created_at", "Ngày tạo", func(a *model.CoaAccount) any { return a.CreatedAt }),
+		export.Col("updated_at", "Ngày cập nhật", func(a *model.CoaAccount) any { return a.UpdatedAt }),
+	}
+}
+
+func entryColumns(format amountFormatter) export.Columns[*model.Entry] {
+	return export.Columns[*model.Entry]{
+		export.IndexCol[*model.Entry]("index", "STT"),
+		export.Col("id", "ID", func(e *model.Entry) any { return e.ID }),
+		export.Col("journal_id", "Bút toán", func(e *model.Entry) any { return e.JournalID }),
+		export.Col("line_no", "Dòng", func(e *model.Entry) any { return e.LineNo }),
+		export.Col("account_id", "ID tài khoản", func(e *model.Entry) any { return e.AccountID }),
+		export.Col("account_code", "Mã tài khoản", func(e *model.Entry) any {
+			if e.Account == nil {
+				return nil
+			}
+			return e.Account.Code
+		}),
+		export.Col("currency", "Loại tiền", func(e *model.Entry) any {
+			if e.Account == nil {
+				return nil
+			}
+			return e.Account.Currency
+		}),
+		export.Col("dc", "Nợ/Có", func(e *model.Entry) any { return e.DC }),
+		export.Col("amount", "Số tiền", func(e *model.Entry) any {
+			if e.Account == nil {
+				return e.Amount
+			}
+			return format(e.Account.Currency, e.Amount)
+		}),
+		export.Col("memo", "Ghi chú", func(e *model.Entry) any { return e.Memo }),
+		export.Col("ledger_code", "Sổ", func(e *model.Entry) any { return e.LedgerCode }),
+		export.Col("batch_id", "Batch", func(e *model.Entry) any { return e.BatchID }),
+		export.Col("meta", "Meta", func(e *model.Entry) any { return e.Meta }),
+		export.Col("created_at", "Ngày tạo", func(e *model.Entry) any { return e.CreatedAt }),
+	}
+}
+
+var journalColumns = export.Columns[*model.Journal]{
+	export.IndexCol[*model.Journal]("index", "STT"),
+	export.Col("id", "ID", func(j *model.Journal) any { return j.ID }),
+	export.Col("ts", "Thời điểm", func(j *model.Journal) any { return j.Ts }),
+	export.Col("status", "Trạng thái", func(j *model.Journal) any { return j.Status }),
+	export.Col("idempotency_key", "Idempotency key", func(j *model.Journal) any { return j.IdempotencyKey }),
+	export.Col("currency", "Loại tiền", func(j *model.Journal) any { return j.Currency }),
+	export.Col("source", "Nguồn", func(j *model.Journal) any { return j.Source }),
+	export.Col("memo", "Ghi chú", func(j *model.Journal) any { return j.Memo }),
+	export.Col("reversal_of", "Đảo của bút toán", func(j *model.Journal) any { return j.ReversalOfID }),
+	export.Col("posted_by", "Người ghi sổ", func(j *model.Journal) any { return j.PostedBy }),
+	export.Col("posted_at", "Ngày ghi sổ", func(j *model.Journal) any { return j.PostedAt }),
+	export.Col("ledger_code", "Sổ", func(j *model.Journal) any { return j.LedgerCode }),
+	export.Col("batch_id", "Batch", func(j *model.Journal) any { return j.BatchID }),
+	export.Col("meta", "Meta", func(j *model.Journal) any { return j.Meta }),
+	export.Col("created_at", "Ngày tạo", func(j *model.Journal) any { return j.CreatedAt }),
+}
+
+func snapshotColumns(format amountFormatter) export.Columns[*model.Snapshot] {
+	return export.Columns[*model.Snapshot]{
+		export.IndexCol[*model.Snapshot]("index", "STT"),
+		export.Col("id", "ID", func(s *model.Snapshot) any { return s.ID }),
+		// cột date, không đổi timezone
+		export.Col("as_of_date", "Ngày chốt", func(s *model.Snapshot) any { return s.AsOfDate.Format("2006-01-02") }),
+		export.Col("account_id", "ID tài khoản", func(s *model.Snapshot) any { return s.AccountID }),
+		export.Col("account_code", "Mã tài khoản", func(s *model.Snapshot) any { return s.AccountCode }),
+		export.Col("currency", "Loại tiền", func(s *model.Snapshot) any { return s.Currency }),
+		export.Col("opening_balance", "Số dư đầu kỳ", func(s *model.Snapshot) any { return format(s.Currency, s.OpeningBalance) }),
+		export.Col("debit_total", "Phát sinh Nợ", func(s *model.Snapshot) any { return format(s.Currency, s.DebitTotal) }),
+		export.Col("credit_total", "Phát sinh Có", func(s *model.Snapshot) any { return format(s.Currency, s.CreditTotal) }),
+		export.Col("movement", "Biến động", func(s *model.Snapshot) any { return format(s.Currency, s.Movement) }),
+		export.Col("closing_balance", "Số dư cuối kỳ", func(s *model.Snapshot) any { return format(s.Currency, s.ClosingBalance) }),
+		export.Col("entry_count", "Số bút toán", func(s *model.Snapshot) any { return s.EntryCount }),
+		export.Col("status", "Trạng thái", func(s *model.Snapshot) any { return s.Status }),
+		export.Col("hash", "Hash", func(s *model.Snapshot) any { return s.Hash }),
+		export.Col("created_at", "Ngày tạo", func(s *model.Snapshot) any { return s.CreatedAt }),
+	}
+}
+
+var transactionLogColumns = export.Columns[*model.TransactionLog]{
+	export.IndexCol[*model.TransactionLog]("index", "STT"),
+	export.Col("id", "ID", func(t *model.TransactionLog) any { return t.ID }),
+	export.Col("aggregate_type", "Aggregate", func(t *model.TransactionLog) any { return t.AggregateType }),
+	export.Col("aggregate_id", "Aggregate ID", func(t *model.TransactionLog) any { return t.AggregateID }),
+	export.Col("event_type", "Event", func(t *model.TransactionLog) any { return t.EventType }),
+	export.Col("event_key", "Event key", func(t *model.TransactionLog) any { return t.EventKey }),
+	export.Col("status", "Trạng thái", func(t *model.TransactionLog) any { return t.Status }),
+	export.Col("attempts", "Số lần thử", func(t *model.TransactionLog) any { return t.Attempts }),
+	export.Col("published_at", "Ngày publish", func(t *model.TransactionLog) any { return t.PublishedAt }),
+	export.Col("error_last", "Lỗi gần nhất", func(t *model.TransactionLog) any { return t.ErrorLast }),
+	export.Col("tenant_id", "Tenant", func(t *model.TransactionLog) any { return t.TenantID }),
+	export.Col("ledger_code", "Sổ", func(t *model.TransactionLog) any { return t.LedgerCode }),
+	export.Col("seq", "Seq", func(t *model.TransactionLog) any { return t.Seq }),
+	export.Col("payload", "Payload", func(t *model.TransactionLog) any { return t.Payload }),
+	export.Col("created_at", "Ngày tạo", func(t *model.TransactionLog) any { return t.CreatedAt }),
+}
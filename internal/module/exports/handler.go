@@ -0,0 +1,121 @@
+package exports
+
+import (
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/rbac"
+	"core-ledger/internal/module/validate"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	logger  logger.CustomLogger
+	service *ExportService
+}
+
+func NewExportHandler(service *ExportService) *ExportHandler {
+	return &ExportHandler{
+		logger:  logger.NewSystemLog("ExportHandler"),
+		service: service,
+	}
+}
+
+// Resources danh sách resource và cột export được
+func (h *ExportHandler) Resources(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: h.service.Resources(),
+	})
+}
+
+// Create tạo export chạy nền, trả 202 kèm id export và job_id để theo dõi tiến độ
+func (h *ExportHandler) Create(c *gin.Context) {
+	var req CreateExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	resource, err := h.service.Resource(req.Resource)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	// ngoài reports.read, cần quyền xem dữ liệu của resource
+	if !rbac.GetPermissions(c).Has(resource.Permission()) {
+		ginhp.RespondError(c, http.StatusForbidden, fmt.Sprintf("Forbidden: missing permission %s", resource.Permission()))
+		return
+	}
+	res, err := h.service.Create(c, rbac.Principal(c), &req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// List lịch sử export của người đang đăng nhập
+func (h *ExportHandler) List(c *gin.Context) {
+	q := &dto.ListExportFilter{}
+	if err := c.ShouldBindQuery(&q); err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.service.List(c, rbac.Principal(c), q)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+func (h *ExportHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "invalid export id")
+		return
+	}
+	res, err := h.service.Get(c, rbac.Principal(c), id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// Download chuyển hướng (302) sang URL ký của file export
+func (h *ExportHandler) Download(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "invalid export id")
+		return
+	}
+	url, err := h.service.DownloadURL(c, rbac.Principal(c), id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.Redirect(http.StatusFound, url)
+}
+
+// respondServiceError: AppError trả về theo chuẩn RespondOKWithError, lỗi hệ thống trả 500
+func respondServiceError(c *gin.Context, err error) {
+	var appErr *core.AppError
+	if errors.As(err, &appErr) {
+		ginhp.RespondOKWithError(c, appErr)
+		return
+	}
+	ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,60 @@
+package exports
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CreateExportRequest tạo export chạy nền. Query là filter của list endpoint tương ứng
+// (VD dto.ListCoaAccountFilter với coa_accounts), Columns rỗng thì xuất tất cả cột
+type CreateExportRequest struct {
+	Resource string          `json:"resource" binding:"required"`
+	Format   string          `json:"format" binding:"omitempty,oneof=xlsx csv jsonl"`
+	Columns  []string        `json:"columns,omitempty"`
+	Query    json.RawMessage `json:"query,omitempty"`
+	FileName string          `json:"file_name,omitempty" binding:"max=200"`
+}
+
+// ExportResponse một export trong lịch sử, DownloadURL (URL ký, có hạn) chỉ có khi COMPLETED và chưa hết hạn
+type ExportResponse struct {
+	ID          uint64          `json:"id"`
+	Resource    string          `json:"resource"`
+	Format      string          `json:"format"`
+	Status      string          `json:"status"`
+	FileName    string          `json:"file_name"`
+	Columns     []string        `json:"columns,omitempty"`
+	Query       json.RawMessage `json:"query,omitempty"`
+	Rows        int64           `json:"rows"`
+	Size        int64           `json:"size"`
+	SHA256      string          `json:"sha256,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	JobID       string          `json:"job_id,omitempty"`
+	DownloadURL string          `json:"download_url,omitempty"`
+	Expired     bool            `json:"expired"`
+	ExpiresAt   *time.Time      `json:"expires_at,omitempty"`
+	StartedAt   *time.Time      `json:"started_at,omitempty"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// ResourceResponse resource export được và các cột của nó
+type ResourceResponse struct {
+	Name       string           `json:"name"`
+	Permission string           `json:"permission"`
+	Columns    []ColumnResponse `json:"columns"`
+}
+
+type ColumnResponse struct {
+	Key    string `json:"key"`
+	Header string `json:"header"`
+}
+
+// RunResult kết quả job export, đọc lại qua GET /jobs/:id
+type RunResult struct {
+	ExportID    uint64     `json:"export_id"`
+	Rows        int64      `json:"rows"`
+	Size        int64      `json:"size"`
+	FileName    string     `json:"file_name"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,237 @@
+package exports
+
+import (
+	"bytes"
+	"context"
+	"core-ledger/internal/module/currencies"
+	"core-ledger/internal/module/rbac"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/export"
+	"core-ledger/pkg/repo"
+	"encoding/json"
+	"sort"
+
+	"github.com/shopspring/decimal"
+)
+
+// Resource export được
+const (
+	ResourceCoaAccounts     = "coa_accounts"
+	ResourceEntries         = "entries"
+	ResourceJournals        = "journals"
+	ResourceSnapshots       = "snapshots"
+	ResourceTransactionLogs = "transaction_logs"
+)
+
+// Resource dữ liệu export được: cột đăng ký sẵn và cách đọc từng trang theo filter của list endpoint tương ứng
+type Resource interface {
+	Name() string
+	// Permission quyền xem dữ liệu cần có (ngoài reports.read của endpoint export)
+	Permission() string
+	Fields() []export.Field
+	// Validate kiểm tra cột đã chọn và query (JSON filter) trước khi tạo export
+	Validate(columns []string, query json.RawMessage) error
+	// Export ghi dữ liệu khớp query ra w rồi Close writer, trả về số dòng
+	Export(ctx context.Context, w export.Writer, columns []string, query json.RawMessage, opts export.Options) (int64, error)
+}
+
+// resource Resource với bản ghi kiểu T và filter kiểu F (VD dto.ListCoaAccountFilter)
+type resource[T any, F any] struct {
+	name       string
+	permission string
+	columns    export.Columns[T]
+	// fetch đọc một trang, filter đã decode từ query
+	fetch func(ctx context.Context, filter *F, page, limit int64) ([]T, int64, error)
+}
+
+func (r *resource[T, F]) Name() string {
+	return r.name
+}
+
+func (r *resource[T, F]) Permission() string {
+	return r.permission
+}
+
+func (r *resource[T, F]) Fields() []export.Field {
+	return r.columns.Fields()
+}
+
+func (r *resource[T, F]) Validate(columns []string, query json.RawMessage) error {
+	if _, err := r.columns.Select(columns); err != nil {
+		return err
+	}
+	filter, err := decodeQuery[F](query)
+	if err != nil {
+		return err
+	}
+	// filter DSL kiểm tra theo whitelist của model ngay khi tạo export, không đợi tới lúc job chạy
+	if err := repo.ValidateFilters[T](repo.BuildParamsFromFilter(filter)); err != nil {
+		return &queryError{err: err}
+	}
+	return nil
+}
+
+func (r *resource[T, F]) Export(ctx context.Context, w export.Writer, columns []string, query json.RawMessage, opts export.Options) (int64, error) {
+	cols, err := r.columns.Select(columns)
+	if err != nil {
+		return 0, err
+	}
+	filter, err := decodeQuery[F](query)
+	if err != nil {
+		return 0, err
+	}
+	return export.Run(ctx, w, cols, func(ctx context.Context, page, limit int64) ([]T, int64, error) {
+		return r.fetch(ctx, filter, page, limit)
+	}, opts)
+}
+
+// decodeQuery đọc filter của list endpoint: key có trong filter decode như cũ, key còn lại là filter DSL
+// (amount_gte, meta.provider...) gán vào BasePaginationQuery.Filters; filter không nhận DSL thì key lạ là lỗi
+func decodeQuery[F any](query json.RawMessage) (*F, error) {
+	filter := new(F)
+	if len(bytes.TrimSpace(query)) == 0 || bytes.Equal(bytes.TrimSpace(query), []byte("null")) {
+		return filter, nil
+	}
+	if setter, ok := any(filter).(interface{ SetFilters(map[string]any) }); ok {
+		known, extra, err := repo.JSONFilters(query, filter)
+		if err != nil {
+			return nil, &queryError{err: err}
+		}
+		setter.SetFilters(extra)
+		query = known
+	}
+	dec := json.NewDecoder(bytes.NewReader(query))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(filter); err != nil {
+		return nil, &queryError{err: err}
+	}
+	return filter, nil
+}
+
+// queryError query không decode được vào filter của resource
+type queryError struct {
+	err error
+}
+
+func (e *queryError) Error() string {
+	return "invalid export query: " + e.err.Error()
+}
+
+func (e *queryError) Unwrap() error {
+	return e.err
+}
+
+// Registry các resource export được, theo tên
+type Registry struct {
+	resources map[string]Resource
+}
+
+func NewRegistry(
+	coAccountRepo repo.CoAccountRepo,
+	entryRepo repo.EnTriesRepo,
+	journalRepo repo.JournalRepo,
+	snapshotRepo repo.SnapshotRepo,
+	transactionLogRepo repo.TransactionLogRepo,
+	currencyService *currencies.CurrencyService,
+) *Registry {
+	// danh mục currency đọc từ cache của CurrencyService, không phụ thuộc context của từng export
+	format := func(currency string, amount decimal.Decimal) string {
+		return currencyService.Format(context.Background(), currency, amount)
+	}
+	// CoA/entries phân trang theo ScopeSort, mặc định theo id để đọc nhiều trang không trùng/sót
+	defaultSort := "id:1"
+	r := &Registry{resources: map[string]Resource{}}
+	r.register(&resource[*model.CoaAccount, dto.ListCoaAccountFilter]{
+		name:       ResourceCoaAccounts,
+		permission: rbac.PermCoaRead,
+		columns:    coaAccountColumns(format),
+		fetch: func(ctx context.Context, filter *dto.ListCoaAccountFilter, page, limit int64) ([]*model.CoaAccount, int64, error) {
+			filter.Page, filter.Limit = &page, &limit
+			if filter.Sort == nil {
+				filter.Sort = &defaultSort
+			}
+			res, err := coAccountRepo.PaginateWithScopes(ctx, filter, "Parent")
+			if err != nil {
+				return nil, 0, err
+			}
+			return res.Items, res.Total, nil
+		},
+	})
+	r.register(&resource[*model.Entry, dto.ListEntrytFilter]{
+		name:       ResourceEntries,
+		permission: rbac.PermLedgerJournalRead,
+		columns:    entryColumns(format),
+		fetch: func(ctx context.Context, filter *dto.ListEntrytFilter, page, limit int64) ([]*model.Entry, int64, error) {
+			filter.Page, filter.Limit = &page, &limit
+			if filter.Sort == nil {
+				filter.Sort = &defaultSort
+			}
+			res, err := entryRepo.PaginateWithScopes(ctx, filter, "Account")
+			if err != nil {
+				return nil, 0, err
+			}
+			return res.Items, res.Total, nil
+		},
+	})
+	r.register(&resource[*model.Journal, dto.ListJournalFilter]{
+		name:       ResourceJournals,
+		permission: rbac.PermLedgerJournalRead,
+		columns:    journalColumns,
+		fetch: func(ctx context.Context, filter *dto.ListJournalFilter, page, limit int64) ([]*model.Journal, int64, error) {
+			filter.Page, filter.Limit = &page, &limit
+			res, err := journalRepo.Paginate(ctx, filter)
+			if err != nil {
+				return nil, 0, err
+			}
+			return res.Items, res.Total, nil
+		},
+	})
+	r.register(&resource[*model.Snapshot, dto.ListSnapshotFilter]{
+		name:       ResourceSnapshots,
+		permission: rbac.PermReportsRead,
+		columns:    snapshotColumns(format),
+		fetch: func(ctx context.Context, filter *dto.ListSnapshotFilter, page, limit int64) ([]*model.Snapshot, int64, error) {
+			filter.Page, filter.Limit = &page, &limit
+			res, err := snapshotRepo.Paginate(ctx, filter)
+			if err != nil {
+				return nil, 0, err
+			}
+			return res.Items, res.Total, nil
+		},
+	})
+	r.register(&resource[*model.TransactionLog, dto.ListTransactionLogFilter]{
+		name:       ResourceTransactionLogs,
+		permission: rbac.PermLedgerJournalRead,
+		columns:    transactionLogColumns,
+		fetch: func(ctx context.Context, filter *dto.ListTransactionLogFilter, page, limit int64) ([]*model.TransactionLog, int64, error) {
+			filter.Page, filter.Limit = &page, &limit
+			res, err := transactionLogRepo.Paginate(ctx, filter)
+			if err != nil {
+				return nil, 0, err
+			}
+			return res.Items, res.Total, nil
+		},
+	})
+	return r
+}
+
+func (r *Registry) register(res Resource) {
+	r.resources[res.Name()] = res
+}
+
+// Get resource theo tên
+func (r *Registry) Get(name string) (Resource, bool) {
+	res, ok := r.resources[name]
+	return res, ok
+}
+
+// List toàn bộ resource, sắp theo tên
+func (r *Registry) List() []Resource {
+	list := make([]Resource, 0, len(r.resources))
+	for _, res := range r.resources {
+		list = append(list, res)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
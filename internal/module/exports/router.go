@@ -0,0 +1,24 @@
+package exports
+
+import (
+	"core-ledger/internal/module/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerAPIRoutes(r *gin.RouterGroup, h *ExportHandler, middleware ...gin.HandlerFunc) {
+	// Apply middleware to the group if provided
+	tx := r.Group("exports", middleware...)
+	{
+		tx.GET("/resources", rbac.Require(rbac.PermReportsRead), h.Resources)
+		tx.POST("", rbac.Require(rbac.PermReportsRead), h.Create)
+		tx.GET("", rbac.Require(rbac.PermReportsRead), h.List)
+		tx.GET("/:id", rbac.Require(rbac.PermReportsRead), h.Get)
+		tx.GET("/:id/download", rbac.Require(rbac.PermReportsRead), h.Download)
+	}
+}
+
+// SetupRoutes registers export routes with optional middleware
+func SetupRoutes(rg *gin.RouterGroup, h *ExportHandler, middleware ...gin.HandlerFunc) {
+	registerAPIRoutes(rg, h, middleware...)
+}
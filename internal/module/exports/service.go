@@ -0,0 +1,397 @@
+package exports
+
+import (
+	"bufio"
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/files"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/export"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/queue/jobs"
+	"core-ledger/pkg/repo"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ExportService tạo export chạy nền (bản ghi exports + job export_run), ghi file qua FileService và trả URL tải đã ký
+type ExportService struct {
+	exportRepo  repo.ExportRepo
+	registry    *Registry
+	fileService *files.FileService
+	dispatcher  queue.Dispatcher
+	cfg         *config.ExportConfig
+	loc         *time.Location
+	logger      logger.CustomLogger
+}
+
+func NewExportService(exportRepo repo.ExportRepo, registry *Registry, fileService *files.FileService, dispatcher queue.Dispatcher) *ExportService {
+	loc, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	if err != nil {
+		loc = time.FixedZone("UTC+7", 7*60*60)
+	}
+	return &ExportService{
+		exportRepo:  exportRepo,
+		registry:    registry,
+		fileService: fileService,
+		dispatcher:  dispatcher,
+		cfg:         config.GetExportConfig(),
+		loc:         loc,
+		logger:      logger.NewSystemLog("ExportService"),
+	}
+}
+
+// Resource resource export theo tên, AppError nếu không hỗ trợ
+func (s *ExportService) Resource(name string) (Resource, error) {
+	res, ok := s.registry.Get(name)
+	if !ok {
+		return nil, core.NewError(core.ErrCodeLedgerExportInvalidResource, fmt.Sprintf("resource %q", name))
+	}
+	return res, nil
+}
+
+// Resources danh sách resource và cột export được
+func (s *ExportService) Resources() []ResourceResponse {
+	list := s.registry.List()
+	res := make([]ResourceResponse, len(list))
+	for i, r := range list {
+		fields := r.Fields()
+		cols := make([]ColumnResponse, len(fields))
+		for j, f := range fields {
+			cols[j] = ColumnResponse{Key: f.Key, Header: f.Header}
+		}
+		res[i] = ResourceResponse{Name: r.Name(), Permission: r.Permission(), Columns: cols}
+	}
+	return res
+}
+
+// Create kiểm tra request, lưu export PENDING rồi dispatch job export_run; tiến độ xem ở GET /jobs/:id hoặc GET /exports/:id
+func (s *ExportService) Create(ctx context.Context, requestedBy string, req *CreateExportRequest) (*ExportResponse, error) {
+	res, err := s.Resource(req.Resource)
+	if err != nil {
+		return nil, err
+	}
+	format, err := export.ParseFormat(req.Format)
+	if err != nil {
+		return nil, core.NewError(core.ErrCodeLedgerExportInvalidFormat, err.Error())
+	}
+	if err := res.Validate(req.Columns, req.Query); err != nil {
+		return nil, exportError(err)
+	}
+
+	columns, err := json.Marshal(req.Columns)
+	if err != nil {
+		return nil, err
+	}
+	record := &model.Export{
+		Resource:    res.Name(),
+		Format:      string(format),
+		Status:      model.ExportStatusPending,
+		FileName:    s.DownloadName(req.FileName, res.Name(), format),
+		RequestedBy: requestedBy,
+	}
+	if len(req.Columns) > 0 {
+		record.Columns = datatypes.JSON(columns)
+	}
+	if len(req.Query) > 0 {
+		record.Query = datatypes.JSON(req.Query)
+	}
+	if err := s.exportRepo.Create(ctx, record); err != nil {
+		return nil, err
+	}
+
+	jobID, err := s.dispatcher.DispatchContext(ctx, jobs.NewExportRun(record.ID))
+	if err != nil {
+		_ = s.exportRepo.MarkFailed(ctx, record.ID, err.Error(), time.Now())
+		return nil, err
+	}
+	// driver sync chạy job ngay trong Dispatch, đọc lại để trả trạng thái mới nhất
+	if err := s.exportRepo.SetJobID(ctx, record.ID, jobID); err != nil {
+		return nil, err
+	}
+	record, err = s.exportRepo.GetByID(ctx, record.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, record), nil
+}
+
+// Get export của requestedBy, export của người khác coi như không tồn tại
+func (s *ExportService) Get(ctx context.Context, requestedBy string, id uint64) (*ExportResponse, error) {
+	record, err := s.get(ctx, requestedBy, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, record), nil
+}
+
+// DownloadURL URL ký để tải file export, AppError nếu export chưa xong hoặc đã hết hạn
+func (s *ExportService) DownloadURL(ctx context.Context, requestedBy string, id uint64) (string, error) {
+	record, err := s.get(ctx, requestedBy, id)
+	if err != nil {
+		return "", err
+	}
+	if record.Status != model.ExportStatusCompleted || record.File == nil || isExpired(record, time.Now()) {
+		return "", core.NewError(core.ErrCodeLedgerExportNotReady, fmt.Sprintf("export %d status %s", id, record.Status))
+	}
+	return s.fileService.SignedURL(ctx, record.File, record.FileName)
+}
+
+// List lịch sử export của requestedBy, mới nhất trước
+func (s *ExportService) List(ctx context.Context, requestedBy string, filter *dto.ListExportFilter) (*dto.PaginationResponse[*ExportResponse], error) {
+	filter.RequestedBy = requestedBy
+	page, err := s.exportRepo.Paginate(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*ExportResponse, len(page.Items))
+	for i, record := range page.Items {
+		items[i] = s.toResponse(ctx, record)
+	}
+	return &dto.PaginationResponse[*ExportResponse]{
+		Items:     items,
+		Total:     page.Total,
+		Limit:     page.Limit,
+		Page:      page.Page,
+		TotalPage: page.TotalPage,
+		NextPage:  page.NextPage,
+		PrevPage:  page.PrevPage,
+	}, nil
+}
+
+// Run chạy trong job export_run: stream dữ liệu ra file tạm, lưu qua FileService rồi đánh dấu COMPLETED.
+// Lỗi do request (cột, query, quá số dòng) đánh dấu FAILED ngay và trả AppError để job không retry
+func (s *ExportService) Run(ctx context.Context, id uint64) (*RunResult, error) {
+	record, err := s.exportRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.NewError(core.ErrCodeLedgerExportNotFound, fmt.Sprintf("export %d", id))
+		}
+		return nil, err
+	}
+	if record.Status == model.ExportStatusCompleted {
+		return s.runResult(ctx, record), nil
+	}
+	res, err := s.Resource(record.Resource)
+	if err != nil {
+		return nil, s.fail(ctx, record.ID, err)
+	}
+	var columns []string
+	if len(record.Columns) > 0 {
+		if err := json.Unmarshal(record.Columns, &columns); err != nil {
+			return nil, s.fail(ctx, record.ID, core.NewError(core.ErrCodeLedgerExportInvalidColumn, err.Error()))
+		}
+	}
+	format := export.Format(record.Format)
+	if err := s.exportRepo.MarkRunning(ctx, record.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp("", "export-*"+format.Extension())
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	reporter := queue.ReporterFromContext(ctx)
+	rows, err := s.write(ctx, tmp, res, format, columns, json.RawMessage(record.Query), export.Options{
+		PageSize: s.cfg.PageSize,
+		MaxRows:  s.cfg.MaxRows,
+		Progress: func(processed, total int64) {
+			reporter.Progress(ctx, processed, total, "")
+		},
+	})
+	if err != nil {
+		var appErr *core.AppError
+		if errors.As(exportError(err), &appErr) {
+			return nil, s.fail(ctx, record.ID, appErr)
+		}
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	file, err := s.fileService.Save(ctx, model.FilePurposeExport, record.FileName, format.ContentType(), tmp, record.RequestedBy)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	expiresAt := now.Add(s.cfg.Retention)
+	if err := s.exportRepo.MarkCompleted(ctx, record.ID, file.ID, rows, file.Size, expiresAt, now); err != nil {
+		return nil, err
+	}
+	record.Status = model.ExportStatusCompleted
+	record.FileID = &file.ID
+	record.File = file
+	record.Rows = rows
+	record.Size = file.Size
+	record.ExpiresAt = &expiresAt
+	return s.runResult(ctx, record), nil
+}
+
+// MarkFailed đánh dấu export FAILED khi job hết retry
+func (s *ExportService) MarkFailed(ctx context.Context, id uint64, cause error) error {
+	return s.exportRepo.MarkFailed(ctx, id, cause.Error(), time.Now())
+}
+
+// Stream export trực tiếp ra w (không qua queue), tối đa EXPORT_SYNC_MAX_ROWS dòng; lớn hơn thì dùng POST /exports
+func (s *ExportService) Stream(ctx context.Context, w io.Writer, resourceName string, format export.Format, columns []string, query json.RawMessage) (int64, error) {
+	res, err := s.Resource(resourceName)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := s.write(ctx, w, res, format, columns, query, export.Options{
+		PageSize: s.cfg.PageSize,
+		MaxRows:  s.cfg.SyncMaxRows,
+	})
+	if err != nil {
+		return rows, exportError(err)
+	}
+	return rows, nil
+}
+
+// DownloadName tên file tải về: "<base>-<dd-mm-yyyy>.<ext>", base rỗng thì lấy tên resource
+func (s *ExportService) DownloadName(base, resource string, format export.Format) string {
+	base = strings.TrimSpace(base)
+	base = strings.TrimSuffix(base, format.Extension())
+	// bỏ ký tự đường dẫn/ngoặc kép để dùng được trong Content-Disposition
+	base = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', '"', '\r', '\n':
+			return '-'
+		}
+		return r
+	}, base)
+	if base == "" {
+		base = resource
+	}
+	return fmt.Sprintf("%s-%s%s", base, time.Now().In(s.loc).Format("02-01-2006"), format.Extension())
+}
+
+func (s *ExportService) write(ctx context.Context, w io.Writer, res Resource, format export.Format, columns []string, query json.RawMessage, opts export.Options) (int64, error) {
+	buf := bufio.NewWriterSize(w, 64*1024)
+	writer, err := export.NewWriter(format, buf, s.loc)
+	if err != nil {
+		return 0, core.NewError(core.ErrCodeLedgerExportInvalidFormat, err.Error())
+	}
+	rows, err := res.Export(ctx, writer, columns, query, opts)
+	if err != nil {
+		return rows, err
+	}
+	return rows, buf.Flush()
+}
+
+func (s *ExportService) get(ctx context.Context, requestedBy string, id uint64) (*model.Export, error) {
+	record, err := s.exportRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.NewError(core.ErrCodeLedgerExportNotFound, fmt.Sprintf("export %d", id))
+		}
+		return nil, err
+	}
+	if record.RequestedBy != requestedBy {
+		return nil, core.NewError(core.ErrCodeLedgerExportNotFound, fmt.Sprintf("export %d", id))
+	}
+	return record, nil
+}
+
+// fail đánh dấu FAILED với lỗi không retry được, trả lại lỗi cho job
+func (s *ExportService) fail(ctx context.Context, id uint64, cause error) error {
+	if err := s.exportRepo.MarkFailed(ctx, id, cause.Error(), time.Now()); err != nil {
+		s.logger.Warn("mark export %d failed: %v", id, err)
+	}
+	return cause
+}
+
+func (s *ExportService) runResult(ctx context.Context, record *model.Export) *RunResult {
+	res := &RunResult{
+		ExportID:  record.ID,
+		Rows:      record.Rows,
+		Size:      record.Size,
+		FileName:  record.FileName,
+		ExpiresAt: record.ExpiresAt,
+	}
+	res.DownloadURL = s.signedURL(ctx, record)
+	return res
+}
+
+func (s *ExportService) toResponse(ctx context.Context, record *model.Export) *ExportResponse {
+	res := &ExportResponse{
+		ID:          record.ID,
+		Resource:    record.Resource,
+		Format:      record.Format,
+		Status:      record.Status,
+		FileName:    record.FileName,
+		Rows:        record.Rows,
+		Size:        record.Size,
+		Expired:     isExpired(record, time.Now()),
+		ExpiresAt:   record.ExpiresAt,
+		StartedAt:   record.StartedAt,
+		CompletedAt: record.CompletedAt,
+		CreatedAt:   record.CreatedAt,
+	}
+	if len(record.Columns) > 0 {
+		_ = json.Unmarshal(record.Columns, &res.Columns)
+	}
+	if len(record.Query) > 0 {
+		res.Query = json.RawMessage(record.Query)
+	}
+	if record.Error != nil {
+		res.Error = *record.Error
+	}
+	if record.JobID != nil {
+		res.JobID = *record.JobID
+	}
+	if record.File != nil {
+		res.SHA256 = record.File.SHA256
+	}
+	res.DownloadURL = s.signedURL(ctx, record)
+	return res
+}
+
+// signedURL URL tải file khi export đã xong và chưa hết hạn, rỗng nếu không ký được
+func (s *ExportService) signedURL(ctx context.Context, record *model.Export) string {
+	if record.Status != model.ExportStatusCompleted || record.File == nil || isExpired(record, time.Now()) {
+		return ""
+	}
+	url, err := s.fileService.SignedURL(ctx, record.File, record.FileName)
+	if err != nil {
+		s.logger.Warn("sign export %d url: %v", record.ID, err)
+		return ""
+	}
+	return url
+}
+
+func isExpired(record *model.Export, now time.Time) bool {
+	return record.ExpiresAt != nil && now.After(*record.ExpiresAt)
+}
+
+// exportError đổi lỗi do request (cột, query, số dòng) sang AppError, lỗi khác giữ nguyên
+func exportError(err error) error {
+	var appErr *core.AppError
+	var qErr *queryError
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.Is(err, export.ErrUnknownColumn):
+		return core.NewError(core.ErrCodeLedgerExportInvalidColumn, err.Error())
+	case errors.As(err, &qErr), errors.Is(err, model.ErrInvalidFilter):
+		return core.NewError(core.ErrCodeLedgerExportInvalidQuery, err.Error())
+	case errors.Is(err, export.ErrTooManyRows):
+		return core.NewError(core.ErrCodeLedgerExportTooLarge, err.Error())
+	}
+	return err
+}
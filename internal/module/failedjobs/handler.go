@@ -0,0 +1,125 @@
+package failedjobs
+
+import (
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/validate"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/repo"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FailedJobHandler struct {
+	logger  logger.CustomLogger
+	service *FailedJobService
+}
+
+func NewFailedJobHandler(service *FailedJobService) *FailedJobHandler {
+	return &FailedJobHandler{
+		logger:  logger.NewSystemLog("FailedJobHandler"),
+		service: service,
+	}
+}
+
+func (h *FailedJobHandler) List(c *gin.Context) {
+	q := &dto.ListFailedJobFilter{}
+	if err := c.ShouldBindQuery(&q); err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.service.List(c, q)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+func (h *FailedJobHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+	res, err := h.service.Get(c, id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// Retry đưa lại một job lỗi vào hàng đợi, kết quả xem lại qua danh sách job lỗi
+func (h *FailedJobHandler) Retry(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+	res, err := h.service.Retry(c, id)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// RetryByType đưa lại mọi job FAILED cùng type vào hàng đợi
+func (h *FailedJobHandler) RetryByType(c *gin.Context) {
+	var req RetryByTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	res, err := h.service.RetryByType(c, req.Type)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// Purge xoá job lỗi theo type/status/before, phải có ít nhất một điều kiện (không cho xoá toàn bộ)
+func (h *FailedJobHandler) Purge(c *gin.Context) {
+	q := &dto.PurgeFailedJobFilter{}
+	if err := c.ShouldBindQuery(&q); err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.service.Purge(c, q)
+	if errors.Is(err, repo.ErrPurgeFilterRequired) {
+		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// respondServiceError: AppError trả về theo chuẩn RespondOKWithError, lỗi hệ thống trả 500
+func respondServiceError(c *gin.Context, err error) {
+	var appErr *core.AppError
+	if errors.As(err, &appErr) {
+		ginhp.RespondOKWithError(c, appErr)
+		return
+	}
+	ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+}
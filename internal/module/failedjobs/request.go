@@ -0,0 +1,24 @@
+package failedjobs
+
+type RetryByTypeRequest struct {
+	Type string `json:"type" binding:"required,max=128"`
+}
+
+type RetryResponse struct {
+	ID     uint64 `json:"id"`
+	TaskID string `json:"task_id"`
+	Type   string `json:"type"`
+	Queue  string `json:"queue"`
+	Queued bool   `json:"queued"`
+}
+
+// RetryByTypeResponse Skipped là số job đang chờ/chạy trong hàng đợi nên không enqueue lại
+type RetryByTypeResponse struct {
+	Type    string `json:"type"`
+	Retried int    `json:"retried"`
+	Skipped int    `json:"skipped"`
+}
+
+type PurgeResponse struct {
+	Deleted int64 `json:"deleted"`
+}
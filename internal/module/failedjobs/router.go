@@ -0,0 +1,24 @@
+package failedjobs
+
+import (
+	"core-ledger/internal/module/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerAPIRoutes(r *gin.RouterGroup, h *FailedJobHandler, middleware ...gin.HandlerFunc) {
+	// Apply middleware to the group if provided
+	tx := r.Group("admin/failed-jobs", middleware...)
+	{
+		tx.GET("", rbac.Require(rbac.PermQueueManage), h.List)
+		tx.DELETE("", rbac.Require(rbac.PermQueueManage), h.Purge)
+		tx.POST("/retry", rbac.Require(rbac.PermQueueManage), h.RetryByType)
+		tx.GET("/:id", rbac.Require(rbac.PermQueueManage), h.Get)
+		tx.POST("/:id/retry", rbac.Require(rbac.PermQueueManage), h.Retry)
+	}
+}
+
+// SetupRoutes registers failed job listing, retry and purge routes with optional middleware
+func SetupRoutes(rg *gin.RouterGroup, h *FailedJobHandler, middleware ...gin.HandlerFunc) {
+	registerAPIRoutes(rg, h, middleware...)
+}
@@ -0,0 +1,153 @@
+package failedjobs
+
+import (
+	"context"
+	"core-ledger/internal/core"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/repo"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// retryByTypeBatch số job đọc mỗi lần khi chạy lại theo type
+const retryByTypeBatch = 100
+
+// FailedJobService ghi job lỗi lần cuối của worker vào failed_jobs và chạy lại từ payload đã lưu
+type FailedJobService struct {
+	failedJobRepo repo.FailedJobRepo
+	client        *asynq.Client
+	inspector     *asynq.Inspector
+	logger        logger.CustomLogger
+}
+
+func NewFailedJobService(failedJobRepo repo.FailedJobRepo, client *asynq.Client, inspector *asynq.Inspector) *FailedJobService {
+	return &FailedJobService{
+		failedJobRepo: failedJobRepo,
+		client:        client,
+		inspector:     inspector,
+		logger:        logger.NewSystemLog("FailedJobService"),
+	}
+}
+
+// RecordFailedJob implement queue.FailedJobRecorder, worker gọi cho mọi job type đã đăng ký
+func (s *FailedJobService) RecordFailedJob(ctx context.Context, job queue.FailedJob) error {
+	payload := job.Payload
+	if !json.Valid(payload) {
+		// payload không phải JSON (task enqueue ngoài dispatcher) vẫn giữ nguyên dạng chuỗi
+		payload, _ = json.Marshal(string(job.Payload))
+	}
+	return s.failedJobRepo.Record(ctx, &model.FailedJob{
+		TaskID:        job.TaskID,
+		Type:          job.Type,
+		Queue:         job.Queue,
+		Payload:       payload,
+		Error:         job.Error,
+		Attempts:      job.Attempts,
+		MaxRetry:      job.MaxRetry,
+		Handler:       job.Handler,
+		Status:        model.FailedJobStatusFailed,
+		FirstFailedAt: job.FailedAt,
+		LastFailedAt:  job.FailedAt,
+	})
+}
+
+func (s *FailedJobService) List(ctx context.Context, filter *dto.ListFailedJobFilter) (*dto.PaginationResponse[*model.FailedJob], error) {
+	return s.failedJobRepo.Paginate(ctx, filter)
+}
+
+func (s *FailedJobService) Get(ctx context.Context, id uint64) (*model.FailedJob, error) {
+	job, err := s.failedJobRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.NewError(core.ErrCodeLedgerFailedJobNotFound, fmt.Sprintf("failed job %d", id))
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// Retry enqueue lại job từ payload đã lưu với task ID cũ. Task còn trong archive thì xoá trước,
+// task đang chờ/chạy trong hàng đợi thì trả lỗi để không chạy trùng.
+func (s *FailedJobService) Retry(ctx context.Context, id uint64) (*RetryResponse, error) {
+	job, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.enqueue(ctx, job); err != nil {
+		return nil, err
+	}
+	return &RetryResponse{ID: job.ID, TaskID: job.TaskID, Type: job.Type, Queue: job.Queue, Queued: true}, nil
+}
+
+// RetryByType chạy lại mọi job FAILED của jobType, job đang chờ/chạy trong hàng đợi được bỏ qua
+func (s *FailedJobService) RetryByType(ctx context.Context, jobType string) (*RetryByTypeResponse, error) {
+	res := &RetryByTypeResponse{Type: jobType}
+	var afterID uint64
+	for {
+		jobs, err := s.failedJobRepo.ListFailedByType(ctx, jobType, afterID, retryByTypeBatch)
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range jobs {
+			afterID = job.ID
+			if err := s.enqueue(ctx, job); err != nil {
+				var appErr *core.AppError
+				if errors.As(err, &appErr) && appErr.Code == core.ErrCodeLedgerFailedJobActive {
+					res.Skipped++
+					continue
+				}
+				return res, err
+			}
+			res.Retried++
+		}
+		if len(jobs) < retryByTypeBatch {
+			return res, nil
+		}
+	}
+}
+
+func (s *FailedJobService) Purge(ctx context.Context, filter *dto.PurgeFailedJobFilter) (*PurgeResponse, error) {
+	deleted, err := s.failedJobRepo.Purge(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &PurgeResponse{Deleted: deleted}, nil
+}
+
+func (s *FailedJobService) enqueue(ctx context.Context, job *model.FailedJob) error {
+	info, err := s.inspector.GetTaskInfo(job.Queue, job.TaskID)
+	switch {
+	case err == nil && (info.State == asynq.TaskStateArchived || info.State == asynq.TaskStateCompleted):
+		// giải phóng task ID để enqueue lại
+		if err := s.inspector.DeleteTask(job.Queue, job.TaskID); err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+			return err
+		}
+	case err == nil:
+		return core.NewError(core.ErrCodeLedgerFailedJobActive, fmt.Sprintf("task %s đang ở trạng thái %s", job.TaskID, info.State))
+	case errors.Is(err, asynq.ErrTaskNotFound), errors.Is(err, asynq.ErrQueueNotFound):
+		// asynq đã xoá task (hết retention), chạy lại từ payload trong DB
+	default:
+		return err
+	}
+
+	task := asynq.NewTask(job.Type, job.Payload)
+	_, err = s.client.EnqueueContext(ctx, task, asynq.Queue(job.Queue), asynq.MaxRetry(job.MaxRetry), asynq.TaskID(job.TaskID))
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return core.NewError(core.ErrCodeLedgerFailedJobActive, fmt.Sprintf("task %s đã có trong hàng đợi", job.TaskID))
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.failedJobRepo.MarkRetried(ctx, job.ID, time.Now()); err != nil {
+		s.logger.Error(fmt.Sprintf("mark failed job %d retried: %v", job.ID, err))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package files
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/core"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/repo"
+	"core-ledger/pkg/storage"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FileService ghi file vào storage kèm bản ghi files (kích thước, SHA-256), dùng chung cho upload và export.
+// Key sinh theo "<purpose>/YYYY/MM/DD/<uuid><ext>" nên không trùng giữa các user và đọc được từ API lẫn worker.
+type FileService struct {
+	fileRepo repo.FileRepo
+	store    storage.Store
+	driver   string
+	urlTTL   time.Duration
+	loc      *time.Location
+	logger   logger.CustomLogger
+}
+
+func NewFileService(fileRepo repo.FileRepo, store storage.Store) *FileService {
+	loc, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	if err != nil {
+		loc = time.FixedZone("UTC+7", 7*60*60)
+	}
+	cfg := config.GetStorageConfig()
+	return &FileService{
+		fileRepo: fileRepo,
+		store:    store,
+		driver:   cfg.Driver,
+		urlTTL:   cfg.URLTTL,
+		loc:      loc,
+		logger:   logger.NewSystemLog("FileService"),
+	}
+}
+
+// Save ghi r vào storage rồi lưu bản ghi files; createdBy là principal ("employee:<id>", "api_key:<id>"), rỗng nếu do hệ thống tạo
+func (s *FileService) Save(ctx context.Context, purpose, originalName, contentType string, r io.Reader, createdBy string) (*model.File, error) {
+	originalName = path.Base(strings.ReplaceAll(strings.TrimSpace(originalName), "\\", "/"))
+	key := fmt.Sprintf("%s/%s/%s%s", purpose, time.Now().In(s.loc).Format("2006/01/02"), uuid.NewString(), strings.ToLower(path.Ext(originalName)))
+	obj, err := s.store.Put(ctx, key, r, storage.PutOptions{ContentType: contentType})
+	if err != nil {
+		return nil, err
+	}
+
+	file := &model.File{
+		StorageKey:   obj.Key,
+		Driver:       s.driver,
+		OriginalName: originalName,
+		ContentType:  obj.ContentType,
+		Size:         obj.Size,
+		SHA256:       obj.SHA256,
+		Purpose:      purpose,
+	}
+	if createdBy != "" {
+		file.CreatedBy = &createdBy
+	}
+	if err := s.fileRepo.Create(ctx, file); err != nil {
+		// không để object mồ côi khi không lưu được metadata
+		if delErr := s.store.Delete(ctx, obj.Key); delErr != nil {
+			s.logger.Warn("delete orphan object %s: %v", obj.Key, delErr)
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// Get bản ghi files theo id, AppError nếu không tồn tại
+func (s *FileService) Get(ctx context.Context, id uint64) (*model.File, error) {
+	file, err := s.fileRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.NewError(core.ErrCodeLedgerFileNotFound, fmt.Sprintf("file %d", id))
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// Open đọc nội dung file, caller phải Close
+func (s *FileService) Open(ctx context.Context, id uint64) (io.ReadCloser, *model.File, error) {
+	file, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, err := s.store.Get(ctx, file.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, core.NewError(core.ErrCodeLedgerFileNotFound, fmt.Sprintf("file %d: object %s", id, file.StorageKey))
+		}
+		return nil, nil, err
+	}
+	return rc, file, nil
+}
+
+// SignedURL URL tải file có hạn STORAGE_URL_TTL, downloadName rỗng thì dùng tên gốc
+func (s *FileService) SignedURL(ctx context.Context, file *model.File, downloadName string) (string, error) {
+	if downloadName == "" {
+		downloadName = file.OriginalName
+	}
+	return s.store.SignedURL(ctx, file.StorageKey, s.urlTTL, downloadName)
+}
@@ -0,0 +1,135 @@
+package holds
+
+import (
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/validate"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/repo"
+	"core-ledger/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HoldHandler struct {
+	logger   logger.CustomLogger
+	service  *HoldService
+	holdRepo repo.HoldRepo
+}
+
+func NewHoldHandler(service *HoldService, holdRepo repo.HoldRepo) *HoldHandler {
+	return &HoldHandler{
+		logger:   logger.NewSystemLog("HoldHandler"),
+		service:  service,
+		holdRepo: holdRepo,
+	}
+}
+
+func (h *HoldHandler) List(c *gin.Context) {
+	q := &dto.ListHoldFilter{}
+	if err := c.ShouldBindQuery(&q); err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.holdRepo.PaginateWithScopes(c, q)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+func (h *HoldHandler) Detail(c *gin.Context) {
+	id, err := utils.ParseIntIdParam(c.Param("id"))
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "Invalid id")
+		return
+	}
+	res, err := h.holdRepo.GetByID(c, id)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusNotFound, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+func (h *HoldHandler) Place(c *gin.Context) {
+	var req PlaceHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	res, err := h.service.Place(c, &req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// Capture ghi sổ toàn bộ hoặc một phần số tiền đang giữ
+func (h *HoldHandler) Capture(c *gin.Context) {
+	id, err := utils.ParseIntIdParam(c.Param("id"))
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "Invalid id")
+		return
+	}
+	var req CaptureHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	res, err := h.service.Capture(c, id, &req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+func (h *HoldHandler) Release(c *gin.Context) {
+	id, err := utils.ParseIntIdParam(c.Param("id"))
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "Invalid id")
+		return
+	}
+	var req ReleaseHoldRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			out := validate.FormatErrorMessage(req, err)
+			ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+			return
+		}
+	}
+	res, err := h.service.Release(c, id, &req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// respondServiceError: AppError trả về theo chuẩn RespondOKWithError, lỗi hệ thống trả 500
+func respondServiceError(c *gin.Context, err error) {
+	var appErr *core.AppError
+	if errors.As(err, &appErr) {
+		ginhp.RespondOKWithError(c, appErr)
+		return
+	}
+	ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+}
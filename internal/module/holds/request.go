@@ -0,0 +1,41 @@
+package holds
+
+import (
+	model "core-ledger/model/core-ledger"
+	"time"
+)
+
+type PlaceHoldRequest struct {
+	AccountID uint64     `json:"account_id" binding:"required"`
+	Amount    string     `json:"amount" binding:"required"`
+	Currency  string     `json:"currency" binding:"required,max=8"`
+	Reference string     `json:"reference" binding:"required,max=191"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// TTLSeconds dùng khi không truyền expires_at, mặc định theo HOLD_DEFAULT_TTL
+	TTLSeconds *int64         `json:"ttl_seconds,omitempty" binding:"omitempty,min=1"`
+	Memo       *string        `json:"memo,omitempty" binding:"omitempty,max=256"`
+	Meta       map[string]any `json:"meta,omitempty"`
+	TenantID   *string        `json:"tenant_id,omitempty" binding:"omitempty,max=36"`
+	LedgerCode *string        `json:"ledger_code,omitempty" binding:"omitempty,max=32"`
+}
+
+// CaptureHoldRequest ghi sổ một phần hoặc toàn bộ số tiền đang giữ sang tài khoản đối ứng.
+// Bỏ trống amount để capture toàn bộ phần còn lại; final = true để giải phóng phần còn lại sau khi capture một phần.
+type CaptureHoldRequest struct {
+	IdempotencyKey   string         `json:"idempotency_key" binding:"required,max=191"`
+	CounterAccountID uint64         `json:"counter_account_id" binding:"required"`
+	Amount           *string        `json:"amount,omitempty"`
+	Final            bool           `json:"final,omitempty"`
+	Source           *string        `json:"source,omitempty" binding:"omitempty,max=64"`
+	Memo             *string        `json:"memo,omitempty" binding:"omitempty,max=256"`
+	Meta             map[string]any `json:"meta,omitempty"`
+}
+
+type ReleaseHoldRequest struct {
+	Reason *string `json:"reason,omitempty" binding:"omitempty,max=256"`
+}
+
+type CaptureHoldResponse struct {
+	Hold    *model.Hold    `json:"hold"`
+	Journal *model.Journal `json:"journal"`
+}
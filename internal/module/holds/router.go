@@ -0,0 +1,24 @@
+package holds
+
+import (
+	"core-ledger/internal/module/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerAPIRoutes(r *gin.RouterGroup, h *HoldHandler, middleware ...gin.HandlerFunc) {
+	// Apply middleware to the group if provided
+	tx := r.Group("holds", middleware...)
+	{
+		tx.POST("", rbac.Require(rbac.PermLedgerHoldWrite), h.Place)
+		tx.GET("/list", rbac.Require(rbac.PermLedgerHoldRead), h.List)
+		tx.GET("/:id", rbac.Require(rbac.PermLedgerHoldRead), h.Detail)
+		tx.POST("/:id/capture", rbac.Require(rbac.PermLedgerHoldWrite, rbac.PermLedgerJournalPost), h.Capture)
+		tx.POST("/:id/release", rbac.Require(rbac.PermLedgerHoldWrite), h.Release)
+	}
+}
+
+// SetupRoutes registers hold routes with optional middleware
+func SetupRoutes(rg *gin.RouterGroup, h *HoldHandler, middleware ...gin.HandlerFunc) {
+	registerAPIRoutes(rg, h, middleware...)
+}
@@ -0,0 +1,312 @@
+package holds
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/currencies"
+	"core-ledger/internal/module/journals"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/repo"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const defaultCaptureSource = "hold_capture"
+
+type HoldService struct {
+	db              *gorm.DB
+	cfg             *config.HoldConfig
+	holdRepo        repo.HoldRepo
+	coAccountRepo   repo.CoAccountRepo
+	journalRepo     repo.JournalRepo
+	journalService  *journals.JournalService
+	balanceService  *journals.BalanceService
+	currencyService *currencies.CurrencyService
+	logger          logger.CustomLogger
+}
+
+func NewHoldService(
+	db *gorm.DB,
+	holdRepo repo.HoldRepo,
+	coAccountRepo repo.CoAccountRepo,
+	journalRepo repo.JournalRepo,
+	journalService *journals.JournalService,
+	balanceService *journals.BalanceService,
+	currencyService *currencies.CurrencyService,
+) *HoldService {
+	return &HoldService{
+		db:              db,
+		cfg:             config.GetHoldConfig(),
+		holdRepo:        holdRepo,
+		coAccountRepo:   coAccountRepo,
+		journalRepo:     journalRepo,
+		journalService:  journalService,
+		balanceService:  balanceService,
+		currencyService: currencyService,
+		logger:          logger.NewSystemLog("HoldService"),
+	}
+}
+
+// Place tạm giữ số tiền trên tài khoản, yêu cầu số dư khả dụng đủ theo chính sách số dư của tài khoản.
+// Gọi lại với cùng reference sẽ trả về hold đã tạo trước đó.
+func (s *HoldService) Place(ctx context.Context, req *PlaceHoldRequest) (*model.Hold, error) {
+	existing, err := s.holdRepo.GetByReference(ctx, req.Reference)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil || !amount.IsPositive() {
+		return nil, core.NewError(core.ErrCodeLedgerInvalidAmount, "amount không hợp lệ")
+	}
+	currency, err := s.currencyService.Get(ctx, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.currencyService.ToAtoms(currency, amount, "amount"); err != nil {
+		return nil, err
+	}
+	account, err := s.coAccountRepo.GetByID(ctx, int64(req.AccountID))
+	if err != nil {
+		return nil, notFoundOr(err, core.ErrCodeLedgerAccountNotFound)
+	}
+	if account.Status != "ACTIVE" {
+		return nil, core.NewError(core.ErrCodeLedgerAccountInactive)
+	}
+	if strings.TrimSpace(account.Currency) != currency.Code {
+		return nil, core.NewError(core.ErrCodeLedgerCurrencyMismatch, fmt.Sprintf("account %s dùng %s", account.Code, account.Currency))
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.cfg.DefaultTTL)
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	} else if req.TTLSeconds != nil {
+		expiresAt = now.Add(time.Duration(*req.TTLSeconds) * time.Second)
+	}
+	if !expiresAt.After(now) {
+		return nil, core.NewError(core.ErrCodeLedgerHoldExpired, "expires_at phải lớn hơn thời điểm hiện tại")
+	}
+
+	hold := &model.Hold{
+		AccountID:      account.ID,
+		Amount:         amount,
+		CapturedAmount: decimal.Zero,
+		Currency:       currency.Code,
+		Reference:      req.Reference,
+		Status:         model.HoldStatusPending,
+		ExpiresAt:      expiresAt,
+		Memo:           req.Memo,
+		Meta:           req.Meta,
+		TenantID:       req.TenantID,
+		LedgerCode:     req.LedgerCode,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.balanceService.ReserveForHold(ctx, tx, account, amount); err != nil {
+			return err
+		}
+		return tx.Create(hold).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithContext(ctx).Info("Hold placed", hold.ID, hold.Reference)
+	return hold, nil
+}
+
+// Capture ghi sổ số tiền đang giữ sang tài khoản đối ứng (toàn bộ hoặc một phần) trong cùng transaction cập nhật hold.
+// Tài khoản bị giữ được ghi giảm (LIAB/EQUITY/REV: Nợ, ASSET/EXP: Có), tài khoản đối ứng ghi chiều ngược lại.
+func (s *HoldService) Capture(ctx context.Context, id int64, req *CaptureHoldRequest) (*CaptureHoldResponse, error) {
+	// capture lặp lại cùng idempotency_key: trả về kết quả cũ, không cộng dồn captured_amount
+	if journal, err := s.journalRepo.GetByIdempotencyKey(ctx, req.IdempotencyKey); err == nil {
+		// key đã dùng cho journal khác (capture hold khác hoặc bút toán thường) thì không được coi là replay
+		if !isCaptureOf(journal, id) {
+			return nil, core.NewError(core.ErrCodeLedgerHoldIdempotencyKeyUsed,
+				fmt.Sprintf("idempotency_key %s thuộc journal %d", req.IdempotencyKey, journal.ID))
+		}
+		hold, err := s.holdRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, notFoundOr(err, core.ErrCodeLedgerHoldNotFound)
+		}
+		return &CaptureHoldResponse{Hold: hold, Journal: journal}, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	res := &CaptureHoldResponse{}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		hold, err := s.lockPending(tx, id)
+		if err != nil {
+			return err
+		}
+		remaining := hold.Remaining()
+		amount := remaining
+		if req.Amount != nil {
+			amount, err = decimal.NewFromString(*req.Amount)
+			if err != nil || !amount.IsPositive() {
+				return core.NewError(core.ErrCodeLedgerInvalidAmount, "amount không hợp lệ")
+			}
+		}
+		if amount.GreaterThan(remaining) {
+			return core.NewError(core.ErrCodeLedgerHoldCaptureExceeded,
+				fmt.Sprintf("đang giữ %s, yêu cầu capture %s", remaining, amount))
+		}
+
+		account, err := s.coAccountRepo.GetByID(ctx, int64(hold.AccountID))
+		if err != nil {
+			return notFoundOr(err, core.ErrCodeLedgerAccountNotFound)
+		}
+		holdDC, counterDC := "D", "C"
+		if account.IsDebitNormal() {
+			holdDC, counterDC = "C", "D"
+		}
+		source := defaultCaptureSource
+		if req.Source != nil && *req.Source != "" {
+			source = *req.Source
+		}
+		meta := map[string]any{}
+		for k, v := range req.Meta {
+			meta[k] = v
+		}
+		meta["hold_id"] = hold.ID
+		meta["hold_reference"] = hold.Reference
+
+		journal, err := s.journalService.PostTx(ctx, tx, &journals.PostJournalRequest{
+			IdempotencyKey: req.IdempotencyKey,
+			Currency:       hold.Currency,
+			Source:         source,
+			Memo:           req.Memo,
+			Meta:           meta,
+			TenantID:       hold.TenantID,
+			LedgerCode:     hold.LedgerCode,
+			Lines: []*journals.PostingLineRequest{
+				{AccountID: hold.AccountID, DC: holdDC, Amount: amount.String(), Memo: req.Memo},
+				{AccountID: req.CounterAccountID, DC: counterDC, Amount: amount.String(), Memo: req.Memo},
+			},
+		}, nil)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		hold.CapturedAmount = hold.CapturedAmount.Add(amount)
+		hold.CapturedAt = &now
+		fields := map[string]interface{}{
+			"captured_amount": hold.CapturedAmount,
+			"captured_at":     now,
+		}
+		if !hold.Remaining().IsPositive() || req.Final {
+			hold.Status = model.HoldStatusCaptured
+			fields["status"] = hold.Status
+			if hold.Remaining().IsPositive() {
+				hold.ReleasedAt = &now
+				fields["released_at"] = now
+			}
+		}
+		if err := tx.Model(hold).Updates(fields).Error; err != nil {
+			return err
+		}
+		res.Hold = hold
+		res.Journal = journal
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithContext(ctx).Info("Hold captured", res.Hold.ID, res.Journal.ID)
+	return res, nil
+}
+
+// Release giải phóng toàn bộ số tiền còn giữ, không ghi sổ
+func (s *HoldService) Release(ctx context.Context, id int64, req *ReleaseHoldRequest) (*model.Hold, error) {
+	var hold *model.Hold
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		hold, err = s.lockPending(tx, id)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		hold.Status = model.HoldStatusReleased
+		hold.ReleasedAt = &now
+		fields := map[string]interface{}{
+			"status":      hold.Status,
+			"released_at": now,
+		}
+		if req != nil && req.Reason != nil {
+			if hold.Meta == nil {
+				hold.Meta = map[string]any{}
+			}
+			hold.Meta["release_reason"] = *req.Reason
+			fields["meta"] = hold.Meta
+		}
+		return tx.Model(hold).Updates(fields).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithContext(ctx).Info("Hold released", hold.ID)
+	return hold, nil
+}
+
+// ExpireStale chuyển các hold PENDING quá hạn sang EXPIRED theo từng lô
+func (s *HoldService) ExpireStale(ctx context.Context) (int64, error) {
+	var total int64
+	now := time.Now()
+	for {
+		n, err := s.holdRepo.ExpireStale(ctx, now, s.cfg.ExpiryBatchSize)
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n < int64(s.cfg.ExpiryBatchSize) {
+			return total, nil
+		}
+	}
+}
+
+// lockPending khoá hold (FOR UPDATE) và kiểm tra còn PENDING, chưa hết hạn
+func (s *HoldService) lockPending(tx *gorm.DB, id int64) (*model.Hold, error) {
+	hold := &model.Hold{}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(hold, "id = ?", id).Error; err != nil {
+		return nil, notFoundOr(err, core.ErrCodeLedgerHoldNotFound)
+	}
+	if hold.Status != model.HoldStatusPending {
+		return nil, core.NewError(core.ErrCodeLedgerHoldNotPending, fmt.Sprintf("hold đang ở trạng thái %s", hold.Status))
+	}
+	if !hold.ExpiresAt.After(time.Now()) {
+		return nil, core.NewError(core.ErrCodeLedgerHoldExpired)
+	}
+	return hold, nil
+}
+
+func notFoundOr(err error, code core.AppErrorCode) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return core.NewError(code)
+	}
+	return err
+}
+
+// isCaptureOf kiểm tra journal là bút toán capture của hold id (meta.hold_id do Capture ghi)
+func isCaptureOf(journal *model.Journal, id int64) bool {
+	switch v := journal.Meta["hold_id"].(type) {
+	case float64: // đọc từ jsonb
+		return v == float64(id)
+	case uint64:
+		return v == uint64(id)
+	case int64:
+		return v == id
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package holds
+
+import (
+	"context"
+	"core-ledger/internal/core"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/repo"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type fakeJournals struct {
+	repo.JournalRepo
+	byKey map[string]*model.Journal
+}
+
+func (f *fakeJournals) GetByIdempotencyKey(_ context.Context, key string) (*model.Journal, error) {
+	if journal, ok := f.byKey[key]; ok {
+		return journal, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeHolds struct {
+	repo.HoldRepo
+	rows map[int64]*model.Hold
+}
+
+func (f *fakeHolds) GetByID(_ context.Context, id int64) (*model.Hold, error) {
+	if hold, ok := f.rows[id]; ok {
+		return hold, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestCaptureReplay(t *testing.T) {
+	journals := &fakeJournals{byKey: map[string]*model.Journal{
+		// meta đọc từ jsonb: số là float64
+		"cap-1":   {ID: 10, IdempotencyKey: "cap-1", Meta: map[string]any{"hold_id": float64(1)}},
+		"cap-2":   {ID: 11, IdempotencyKey: "cap-2", Meta: map[string]any{"hold_id": float64(2)}},
+		"posting": {ID: 12, IdempotencyKey: "posting"},
+	}}
+	holds := &fakeHolds{rows: map[int64]*model.Hold{
+		1: {ID: 1, Status: model.HoldStatusCaptured},
+		2: {ID: 2, Status: model.HoldStatusCaptured},
+	}}
+	service := &HoldService{holdRepo: holds, journalRepo: journals}
+
+	res, err := service.Capture(context.Background(), 1, &CaptureHoldRequest{IdempotencyKey: "cap-1"})
+	if err != nil || res.Hold.ID != 1 || res.Journal.ID != 10 {
+		t.Fatalf("replay: res = %+v err = %v", res, err)
+	}
+	for _, key := range []string{"cap-2", "posting"} {
+		_, err := service.Capture(context.Background(), 1, &CaptureHoldRequest{IdempotencyKey: key})
+		var appErr *core.AppError
+		if !errors.As(err, &appErr) || appErr.Code != core.ErrCodeLedgerHoldIdempotencyKeyUsed {
+			t.Errorf("%s: err = %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/module/rbac"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderReplayed có trong response được trả lại từ kết quả đã lưu
+	HeaderReplayed = "Idempotent-Replayed"
+
+	maxKeyLength = 128
+)
+
+// Middleware idempotency cho endpoint ghi sổ: request cùng (principal, Idempotency-Key, method + path)
+// được trả lại đúng status/body của lần đầu, request trùng đang xử lý nhận 409 kèm Retry-After.
+// Không có header Idempotency-Key thì bỏ qua.
+type Middleware struct {
+	store       Store
+	ttl         time.Duration
+	lockTimeout time.Duration
+	logger      logger.CustomLogger
+}
+
+func NewMiddleware(store Store) *Middleware {
+	cfg := config.GetIdempotencyConfig()
+	return &Middleware{
+		store:       store,
+		ttl:         cfg.TTL,
+		lockTimeout: cfg.LockTimeout,
+		logger:      logger.NewSystemLog("IdempotencyMiddleware"),
+	}
+}
+
+func (m *Middleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		key := strings.TrimSpace(c.GetHeader(HeaderIdempotencyKey))
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			ginhp.RespondError(c, http.StatusBadRequest, fmt.Sprintf("%s must be at most %d characters", HeaderIdempotencyKey, maxKeyLength))
+			return
+		}
+
+		// đọc body để hash rồi trả lại cho handler
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		now := time.Now()
+		token := uuid.NewString()
+		lockedUntil := now.Add(m.lockTimeout)
+		record := &model.IdempotencyKey{
+			Principal:   rbac.Principal(c),
+			Key:         key,
+			Route:       c.Request.Method + " " + c.Request.URL.Path,
+			RequestHash: hashBody(body),
+			Status:      model.IdempotencyStatusInProgress,
+			LockToken:   &token,
+			LockedUntil: &lockedUntil,
+			ExpiresAt:   now.Add(m.ttl),
+		}
+
+		acquired, existing, err := m.acquire(c, record, now)
+		if err != nil {
+			ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if !acquired {
+			m.respondExisting(c, record, existing)
+			return
+		}
+
+		writer := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+		// client ngắt kết nối vẫn phải lưu/giải phóng key
+		ctx := context.WithoutCancel(c.Request.Context())
+		finished := false
+		defer func() {
+			// handler panic: giải phóng key để client gửi lại được
+			if !finished {
+				m.release(ctx, record)
+			}
+		}()
+
+		c.Next()
+
+		status := writer.Status()
+		if !storable(status) {
+			m.release(ctx, record)
+			finished = true
+			return
+		}
+		record.Status = model.IdempotencyStatusCompleted
+		record.ResponseCode = status
+		record.ResponseBody = writer.body.Bytes()
+		record.ContentType = writer.Header().Get("Content-Type")
+		record.ExpiresAt = time.Now().Add(m.ttl)
+		if err := m.store.Complete(ctx, record); err != nil {
+			m.logger.Error(fmt.Sprintf("complete idempotency key %s %s: %v", record.Route, record.Key, err))
+		}
+		finished = true
+	}
+}
+
+// acquire giữ key; key vừa được giải phóng giữa Acquire và Get thì thử lại một lần
+func (m *Middleware) acquire(c *gin.Context, record *model.IdempotencyKey, now time.Time) (bool, *model.IdempotencyKey, error) {
+	var existing *model.IdempotencyKey
+	for attempt := 0; attempt < 2; attempt++ {
+		acquired, err := m.store.Acquire(c, record, now)
+		if err != nil || acquired {
+			return acquired, nil, err
+		}
+		existing, err = m.store.Get(c, record.Principal, record.Key, record.Route)
+		if err != nil {
+			return false, nil, err
+		}
+		if existing != nil {
+			return false, existing, nil
+		}
+	}
+	return false, nil, nil
+}
+
+func (m *Middleware) respondExisting(c *gin.Context, record, existing *model.IdempotencyKey) {
+	if existing != nil && existing.RequestHash != record.RequestHash {
+		ginhp.RespondError(c, http.StatusUnprocessableEntity, fmt.Sprintf("%s is already used with a different request body", HeaderIdempotencyKey))
+		return
+	}
+	if existing == nil || existing.Status == model.IdempotencyStatusInProgress {
+		c.Header("Retry-After", strconv.Itoa(retryAfterSeconds(existing)))
+		ginhp.RespondError(c, http.StatusConflict, "A request with the same Idempotency-Key is being processed")
+		return
+	}
+	c.Header(HeaderReplayed, "true")
+	contentType := existing.ContentType
+	if contentType == "" {
+		contentType = "application/json; charset=utf-8"
+	}
+	c.Data(existing.ResponseCode, contentType, existing.ResponseBody)
+	c.Abort()
+}
+
+func (m *Middleware) release(ctx context.Context, record *model.IdempotencyKey) {
+	if err := m.store.Release(ctx, record); err != nil {
+		m.logger.Error(fmt.Sprintf("release idempotency key %s %s: %v", record.Route, record.Key, err))
+	}
+}
+
+// storable: lỗi hệ thống (5xx), lỗi xác thực/phân quyền và rate limit không lưu để client gửi lại được
+func storable(status int) bool {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return false
+	case status == http.StatusUnauthorized, status == http.StatusForbidden, status == http.StatusTooManyRequests:
+		return false
+	}
+	return true
+}
+
+// retryAfterSeconds số giây tới khi request đang giữ key hết lock timeout, tối thiểu 1
+func retryAfterSeconds(existing *model.IdempotencyKey) int {
+	if existing == nil || existing.LockedUntil == nil {
+		return 1
+	}
+	seconds := int(math.Ceil(time.Until(*existing.LockedUntil).Seconds()))
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+func hashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// responseRecorder giữ lại body response để lưu cho lần replay
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
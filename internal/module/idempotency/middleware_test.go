@@ -0,0 +1,157 @@
+package idempotency
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryStore Store trong bộ nhớ cho test
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]*model.IdempotencyKey
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: map[string]*model.IdempotencyKey{}}
+}
+
+func (s *memoryStore) Acquire(_ context.Context, record *model.IdempotencyKey, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := redisKey(record.Principal, record.Key, record.Route)
+	if existing, ok := s.records[k]; ok && existing.ExpiresAt.After(now) &&
+		(existing.Status != model.IdempotencyStatusInProgress || existing.LockedUntil.After(now)) {
+		return false, nil
+	}
+	copied := *record
+	s.records[k] = &copied
+	return true, nil
+}
+
+func (s *memoryStore) Get(_ context.Context, principal, key, route string) (*model.IdempotencyKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[redisKey(principal, key, route)]; ok {
+		copied := *record
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (s *memoryStore) Complete(_ context.Context, record *model.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	copied := *record
+	s.records[redisKey(record.Principal, record.Key, record.Route)] = &copied
+	return nil
+}
+
+func (s *memoryStore) Release(_ context.Context, record *model.IdempotencyKey) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, redisKey(record.Principal, record.Key, record.Route))
+	return nil
+}
+
+func (s *memoryStore) PurgeExpired(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func newTestRouter(store Store, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	m := &Middleware{store: store, ttl: time.Hour, lockTimeout: 30 * time.Second}
+	r := gin.New()
+	r.POST("/journals", m.Handler(), handler)
+	return r
+}
+
+func post(r *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/journals", strings.NewReader(body))
+	req.Header.Set(HeaderIdempotencyKey, key)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddlewareReplaysCompletedResponse(t *testing.T) {
+	calls := 0
+	r := newTestRouter(newMemoryStore(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusCreated, gin.H{"call": calls})
+	})
+
+	first := post(r, "k1", `{"amount":"10"}`)
+	second := post(r, "k1", `{"amount":"10"}`)
+
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls)
+	}
+	if second.Code != first.Code || second.Body.String() != first.Body.String() {
+		t.Fatalf("expected replay %d %s, got %d %s", first.Code, first.Body, second.Code, second.Body)
+	}
+	if second.Header().Get(HeaderReplayed) != "true" {
+		t.Fatalf("expected %s header on replay", HeaderReplayed)
+	}
+}
+
+func TestMiddlewareRejectsDifferentBody(t *testing.T) {
+	r := newTestRouter(newMemoryStore(), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	post(r, "k1", `{"amount":"10"}`)
+	w := post(r, "k1", `{"amount":"11"}`)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", w.Code)
+	}
+}
+
+func TestMiddlewareConcurrentDuplicateGetsConflict(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	r := newTestRouter(newMemoryStore(), func(c *gin.Context) {
+		close(started)
+		<-release
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	done := make(chan struct{})
+	go func() {
+		post(r, "k1", `{}`)
+		close(done)
+	}()
+	<-started
+	w := post(r, "k1", `{}`)
+	close(release)
+	<-done
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Fatal("expected Retry-After header")
+	}
+}
+
+func TestMiddlewareReleasesKeyOnServerError(t *testing.T) {
+	calls := 0
+	r := newTestRouter(newMemoryStore(), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusInternalServerError, gin.H{})
+	})
+
+	post(r, "k1", `{}`)
+	post(r, "k1", `{}`)
+
+	if calls != 2 {
+		t.Fatalf("expected retry after 5xx to run handler again, ran %d times", calls)
+	}
+}
@@ -0,0 +1,125 @@
+package idempotency
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// acquireScript chỉ tạo key khi chưa tồn tại; key IN_PROGRESS sống theo lock timeout nên request treo tự được giải phóng
+var acquireScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'request_hash', ARGV[1], 'status', ARGV[2], 'lock_token', ARGV[3], 'locked_until', ARGV[4], 'expires_at', ARGV[5])
+redis.call('PEXPIRE', KEYS[1], ARGV[6])
+return 1
+`)
+
+// completeScript lưu response khi request vẫn giữ key, TTL chuyển sang thời gian replay
+var completeScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'lock_token') ~= ARGV[1] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'status', ARGV[2], 'response_code', ARGV[3], 'response_body', ARGV[4], 'content_type', ARGV[5], 'expires_at', ARGV[6], 'lock_token', '')
+redis.call('PEXPIRE', KEYS[1], ARGV[7])
+return 1
+`)
+
+var releaseScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'lock_token') ~= ARGV[1] then
+	return 0
+end
+return redis.call('DEL', KEYS[1])
+`)
+
+// redisStore lưu key dạng hash "idempotency:<principal>:<route>:<key>", hết hạn bằng TTL của Redis
+type redisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) Store {
+	return &redisStore{client: client}
+}
+
+func (s *redisStore) Acquire(ctx context.Context, record *model.IdempotencyKey, now time.Time) (bool, error) {
+	lockedUntil := now
+	if record.LockedUntil != nil {
+		lockedUntil = *record.LockedUntil
+	}
+	ok, err := acquireScript.Run(ctx, s.client, []string{redisKey(record.Principal, record.Key, record.Route)},
+		record.RequestHash, record.Status, deref(record.LockToken),
+		lockedUntil.UnixMilli(), record.ExpiresAt.UnixMilli(), ttlMillis(lockedUntil, now),
+	).Int()
+	return ok == 1, err
+}
+
+func (s *redisStore) Get(ctx context.Context, principal, key, route string) (*model.IdempotencyKey, error) {
+	fields, err := s.client.HGetAll(ctx, redisKey(principal, key, route)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	record := &model.IdempotencyKey{
+		Principal:    principal,
+		Key:          key,
+		Route:        route,
+		RequestHash:  fields["request_hash"],
+		Status:       fields["status"],
+		ResponseBody: []byte(fields["response_body"]),
+		ContentType:  fields["content_type"],
+	}
+	record.ResponseCode, _ = strconv.Atoi(fields["response_code"])
+	if ms, err := strconv.ParseInt(fields["expires_at"], 10, 64); err == nil {
+		record.ExpiresAt = time.UnixMilli(ms)
+	}
+	if ms, err := strconv.ParseInt(fields["locked_until"], 10, 64); err == nil && record.Status == model.IdempotencyStatusInProgress {
+		lockedUntil := time.UnixMilli(ms)
+		record.LockedUntil = &lockedUntil
+	}
+	return record, nil
+}
+
+func (s *redisStore) Complete(ctx context.Context, record *model.IdempotencyKey) error {
+	now := time.Now()
+	return completeScript.Run(ctx, s.client, []string{redisKey(record.Principal, record.Key, record.Route)},
+		deref(record.LockToken), model.IdempotencyStatusCompleted, record.ResponseCode, record.ResponseBody,
+		record.ContentType, record.ExpiresAt.UnixMilli(), ttlMillis(record.ExpiresAt, now),
+	).Err()
+}
+
+func (s *redisStore) Release(ctx context.Context, record *model.IdempotencyKey) error {
+	return releaseScript.Run(ctx, s.client, []string{redisKey(record.Principal, record.Key, record.Route)},
+		deref(record.LockToken),
+	).Err()
+}
+
+// PurgeExpired Redis tự xoá key theo TTL
+func (s *redisStore) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func redisKey(principal, key, route string) string {
+	return fmt.Sprintf("idempotency:%s:%s:%s", principal, route, key)
+}
+
+// ttlMillis thời gian còn lại tới until, tối thiểu 1ms để PEXPIRE không xoá key ngay
+func ttlMillis(until, now time.Time) int64 {
+	if ms := until.Sub(now).Milliseconds(); ms > 0 {
+		return ms
+	}
+	return 1
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,91 @@
+package idempotency
+
+import (
+	"context"
+	config "core-ledger/configs"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/repo"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Store nơi lưu idempotency key, chọn theo IDEMPOTENCY_STORE (postgres | redis)
+type Store interface {
+	// Acquire giữ key cho request hiện tại, false nếu key đang được giữ hoặc đã có kết quả
+	Acquire(ctx context.Context, record *model.IdempotencyKey, now time.Time) (bool, error)
+	// Get trả về nil nếu key không tồn tại hoặc đã hết hạn
+	Get(ctx context.Context, principal, key, route string) (*model.IdempotencyKey, error)
+	Complete(ctx context.Context, record *model.IdempotencyKey) error
+	Release(ctx context.Context, record *model.IdempotencyKey) error
+	// PurgeExpired xoá key đã hết hạn, trả về số key đã xoá
+	PurgeExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+func NewStore(idempotencyRepo repo.IdempotencyKeyRepo) (Store, error) {
+	cfg := config.GetIdempotencyConfig()
+	switch cfg.Store {
+	case config.IdempotencyStorePostgres:
+		return NewPostgresStore(idempotencyRepo, cfg.CleanupBatchSize), nil
+	case config.IdempotencyStoreRedis:
+		return NewRedisStore(config.NewRedisClient()), nil
+	default:
+		return nil, fmt.Errorf("unsupported IDEMPOTENCY_STORE %q", cfg.Store)
+	}
+}
+
+// postgresStore lưu key trong bảng idempotency_keys, key hết hạn được xoá bởi job cleanup_idempotency_keys
+type postgresStore struct {
+	repo      repo.IdempotencyKeyRepo
+	batchSize int
+}
+
+func NewPostgresStore(idempotencyRepo repo.IdempotencyKeyRepo, batchSize int) Store {
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	return &postgresStore{repo: idempotencyRepo, batchSize: batchSize}
+}
+
+func (s *postgresStore) Acquire(ctx context.Context, record *model.IdempotencyKey, now time.Time) (bool, error) {
+	return s.repo.Acquire(ctx, record, now)
+}
+
+func (s *postgresStore) Get(ctx context.Context, principal, key, route string) (*model.IdempotencyKey, error) {
+	record, err := s.repo.Get(ctx, principal, key, route)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !record.ExpiresAt.After(time.Now()) {
+		return nil, nil
+	}
+	return record, nil
+}
+
+func (s *postgresStore) Complete(ctx context.Context, record *model.IdempotencyKey) error {
+	return s.repo.Complete(ctx, record)
+}
+
+func (s *postgresStore) Release(ctx context.Context, record *model.IdempotencyKey) error {
+	return s.repo.Release(ctx, record)
+}
+
+// PurgeExpired xoá theo từng lô batchSize để không khoá bảng lâu
+func (s *postgresStore) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	for {
+		n, err := s.repo.DeleteExpired(ctx, before, s.batchSize)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < int64(s.batchSize) {
+			return total, nil
+		}
+	}
+}
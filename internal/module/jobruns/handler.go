@@ -0,0 +1,46 @@
+package jobruns
+
+import (
+	"core-ledger/internal/core"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobRunHandler struct {
+	logger  logger.CustomLogger
+	service *JobRunService
+}
+
+func NewJobRunHandler(service *JobRunService) *JobRunHandler {
+	return &JobRunHandler{
+		logger:  logger.NewSystemLog("JobRunHandler"),
+		service: service,
+	}
+}
+
+// Get trạng thái, tiến độ và kết quả của job theo task ID trả về khi dispatch
+func (h *JobRunHandler) Get(c *gin.Context) {
+	res, err := h.service.Get(c, c.Param("id"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// respondServiceError: AppError trả về theo chuẩn RespondOKWithError, lỗi hệ thống trả 500
+func respondServiceError(c *gin.Context, err error) {
+	var appErr *core.AppError
+	if errors.As(err, &appErr) {
+		ginhp.RespondOKWithError(c, appErr)
+		return
+	}
+	ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,31 @@
+package jobruns
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ProgressResponse tiến độ handler báo, Percent = 0 khi chưa biết total
+type ProgressResponse struct {
+	Processed int64   `json:"processed"`
+	Total     int64   `json:"total"`
+	Percent   float64 `json:"percent"`
+	Message   string  `json:"message,omitempty"`
+}
+
+// JobResponse trạng thái job trả về ở GET /jobs/:id.
+// State: pending, scheduled, active, retry, completed, failed
+type JobResponse struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	Queue      string            `json:"queue"`
+	State      string            `json:"state"`
+	Progress   *ProgressResponse `json:"progress,omitempty"`
+	Result     json.RawMessage   `json:"result,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Attempts   int               `json:"attempts"`
+	MaxRetry   int               `json:"max_retry"`
+	StartedAt  *time.Time        `json:"started_at,omitempty"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+	UpdatedAt  *time.Time        `json:"updated_at,omitempty"`
+}
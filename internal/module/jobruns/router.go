@@ -0,0 +1,20 @@
+package jobruns
+
+import (
+	"core-ledger/internal/module/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerAPIRoutes(r *gin.RouterGroup, h *JobRunHandler, middleware ...gin.HandlerFunc) {
+	// Apply middleware to the group if provided
+	tx := r.Group("jobs", middleware...)
+	{
+		tx.GET("/:id", rbac.Require(rbac.PermJobsRead), h.Get)
+	}
+}
+
+// SetupRoutes registers job status routes with optional middleware
+func SetupRoutes(rg *gin.RouterGroup, h *JobRunHandler, middleware ...gin.HandlerFunc) {
+	registerAPIRoutes(rg, h, middleware...)
+}
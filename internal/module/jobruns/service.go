@@ -0,0 +1,169 @@
+package jobruns
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/core"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/repo"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+// JobRunService lưu tiến độ/kết quả job vào job_runs và ghép với trạng thái task trên asynq khi tra cứu
+type JobRunService struct {
+	jobRunRepo repo.JobRunRepo
+	inspector  *asynq.Inspector
+	cfg        *config.QueueConfig
+	logger     logger.CustomLogger
+}
+
+func NewJobRunService(jobRunRepo repo.JobRunRepo, inspector *asynq.Inspector, cfg *config.QueueConfig) *JobRunService {
+	return &JobRunService{
+		jobRunRepo: jobRunRepo,
+		inspector:  inspector,
+		cfg:        cfg,
+		logger:     logger.NewSystemLog("JobRunService"),
+	}
+}
+
+// SaveJobRun implement queue.JobRunStore, worker/local dispatcher gọi mỗi lần reporter flush
+func (s *JobRunService) SaveJobRun(ctx context.Context, run queue.JobRun) error {
+	var result []byte
+	if len(run.Result) > 0 {
+		result = run.Result
+	}
+	return s.jobRunRepo.Save(ctx, &model.JobRun{
+		TaskID:     run.TaskID,
+		Type:       run.Type,
+		Queue:      run.Queue,
+		State:      run.State,
+		Processed:  run.Progress.Processed,
+		Total:      run.Progress.Total,
+		Message:    run.Progress.Message,
+		Result:     result,
+		Error:      run.Error,
+		Attempts:   run.Attempts,
+		MaxRetry:   run.MaxRetry,
+		StartedAt:  run.StartedAt,
+		FinishedAt: run.FinishedAt,
+	})
+}
+
+// Get trạng thái job theo task ID: ưu tiên trạng thái task trên asynq (còn trong retention),
+// tiến độ/kết quả lấy từ ResultWriter, không có thì dùng bản lưu ở job_runs
+func (s *JobRunService) Get(ctx context.Context, id string) (*JobResponse, error) {
+	run, err := s.jobRunRepo.GetByTaskID(ctx, id)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		run = nil
+	}
+
+	info := s.taskInfo(ctx, id, run)
+	if run == nil && info == nil {
+		return nil, core.NewError(core.ErrCodeLedgerJobNotFound, fmt.Sprintf("job %s", id))
+	}
+
+	res := &JobResponse{ID: id}
+	if run != nil {
+		res.Type = run.Type
+		res.Queue = run.Queue
+		res.State = run.State
+		res.Error = run.Error
+		res.Attempts = run.Attempts
+		res.MaxRetry = run.MaxRetry
+		res.Progress = newProgressResponse(queue.Progress{Processed: run.Processed, Total: run.Total, Message: run.Message})
+		if len(run.Result) > 0 {
+			res.Result = json.RawMessage(run.Result)
+		}
+		startedAt := run.StartedAt
+		updatedAt := run.UpdatedAt
+		res.StartedAt = &startedAt
+		res.FinishedAt = run.FinishedAt
+		res.UpdatedAt = &updatedAt
+	}
+	if info != nil {
+		res.Type = info.Type
+		res.Queue = info.Queue
+		res.State = taskState(info.State)
+		res.MaxRetry = info.MaxRetry
+		if info.LastErr != "" {
+			res.Error = info.LastErr
+		}
+		if run == nil {
+			res.Attempts = info.Retried
+			if info.State == asynq.TaskStateActive || info.State == asynq.TaskStateCompleted || info.State == asynq.TaskStateArchived {
+				res.Attempts++
+			}
+		}
+		if !info.CompletedAt.IsZero() {
+			completedAt := info.CompletedAt
+			res.FinishedAt = &completedAt
+		}
+		var output queue.JobOutput
+		if len(info.Result) > 0 && json.Unmarshal(info.Result, &output) == nil {
+			if output.Progress != nil {
+				res.Progress = newProgressResponse(*output.Progress)
+			}
+			if len(output.Result) > 0 {
+				res.Result = output.Result
+			}
+		}
+	}
+	return res, nil
+}
+
+// taskInfo tra task trên asynq, chỉ khi QUEUE_DRIVER=asynq; queue lấy từ job_runs, không có thì dò mọi queue
+func (s *JobRunService) taskInfo(ctx context.Context, id string, run *model.JobRun) *asynq.TaskInfo {
+	if s.inspector == nil || s.cfg == nil || s.cfg.Driver != queue.DriverAsynq {
+		return nil
+	}
+	queues := []string{}
+	if run != nil {
+		queues = append(queues, run.Queue)
+	} else {
+		all, err := s.inspector.Queues()
+		if err != nil {
+			s.logger.Warn("list queues for job %s: %v", id, err)
+			return nil
+		}
+		queues = all
+	}
+	for _, q := range queues {
+		info, err := s.inspector.GetTaskInfo(q, id)
+		if err == nil {
+			return info
+		}
+		if !errors.Is(err, asynq.ErrTaskNotFound) && !errors.Is(err, asynq.ErrQueueNotFound) {
+			s.logger.Warn("get task %s on queue %s: %v", id, q, err)
+		}
+	}
+	return nil
+}
+
+// taskState đổi trạng thái asynq sang state của API, task archived (hết retry) coi là failed
+func taskState(state asynq.TaskState) string {
+	if state == asynq.TaskStateArchived {
+		return queue.JobStateFailed
+	}
+	return state.String()
+}
+
+func newProgressResponse(p queue.Progress) *ProgressResponse {
+	if p == (queue.Progress{}) {
+		return nil
+	}
+	res := &ProgressResponse{Processed: p.Processed, Total: p.Total, Message: p.Message}
+	if p.Total > 0 {
+		res.Percent = float64(p.Processed) * 100 / float64(p.Total)
+	}
+	return res
+}
@@ -0,0 +1,281 @@
+package journals
+
+import (
+	"context"
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/currencies"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/repo"
+	"core-ledger/pkg/tracing"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// balanceUpdateRetries số lần thử lại khi cập nhật account_balances bị xung đột version.
+// Từ lần thử lại đầu tiên dòng số dư được đọc kèm FOR UPDATE nên lần ghi tiếp theo chắc chắn thành công.
+const balanceUpdateRetries = 3
+
+// BalanceService đọc/cập nhật số dư tổng hợp (account_balances) và số dư khả dụng (sổ cái - hold đang PENDING)
+type BalanceService struct {
+	coAccountRepo      repo.CoAccountRepo
+	accountBalanceRepo repo.AccountBalanceRepo
+	holdRepo           repo.HoldRepo
+	currencyService    *currencies.CurrencyService
+	logger             logger.CustomLogger
+}
+
+func NewBalanceService(
+	coAccountRepo repo.CoAccountRepo,
+	accountBalanceRepo repo.AccountBalanceRepo,
+	holdRepo repo.HoldRepo,
+	currencyService *currencies.CurrencyService,
+) *BalanceService {
+	return &BalanceService{
+		coAccountRepo:      coAccountRepo,
+		accountBalanceRepo: accountBalanceRepo,
+		holdRepo:           holdRepo,
+		currencyService:    currencyService,
+		logger:             logger.NewSystemLog("BalanceService"),
+	}
+}
+
+func (s *BalanceService) GetBalance(ctx context.Context, accountID int64) (*dto.AccountBalanceResponse, error) {
+	account, err := s.coAccountRepo.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, notFoundOr(err, core.ErrCodeLedgerAccountNotFound)
+	}
+	balance, err := s.accountBalanceRepo.GetByAccount(ctx, account.ID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		// tài khoản chưa phát sinh
+		balance = &model.AccountBalance{AccountID: account.ID, Currency: account.Currency}
+	}
+	return s.toResponse(ctx, account, balance)
+}
+
+func (s *BalanceService) toResponse(ctx context.Context, account *model.CoaAccount, balance *model.AccountBalance) (*dto.AccountBalanceResponse, error) {
+	held, err := s.holdRepo.SumActiveByAccount(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.AccountBalanceResponse{
+		AccountID:        account.ID,
+		Code:             account.Code,
+		Type:             account.Type,
+		Currency:         account.Currency,
+		LedgerBalance:    s.currencyService.Format(ctx, account.Currency, balance.Balance),
+		HeldAmount:       s.currencyService.Format(ctx, account.Currency, held),
+		AvailableBalance: s.currencyService.Format(ctx, account.Currency, balance.Balance.Sub(held)),
+		AsOf:             time.Now(),
+	}, nil
+}
+
+// ApplyEntries cộng entries vừa ghi vào account_balances trong transaction ghi sổ và kiểm tra chính sách số dư
+// (cấm âm / số dư tối thiểu / hạn mức thấu chi) trên biến động ròng của từng tài khoản.
+// checkAvailable: tập tài khoản (của journal yêu cầu check_available_balance) cần kiểm tra thêm trên số dư khả dụng (đã trừ hold);
+// với các tài khoản này tổng số tiền ghi giảm được so với số dư khả dụng, không bù trừ với khoản ghi tăng của journal khác trong batch.
+//
+// Tài khoản được cập nhật theo thứ tự id tăng dần để tránh deadlock. Mỗi dòng số dư được ghi bằng optimistic locking
+// trên version: nếu giao dịch khác đã cập nhật trước thì đọc lại (kèm FOR UPDATE) và kiểm tra lại chính sách,
+// nên 2 posting đồng thời không thể cùng vượt qua kiểm tra trên cùng một số dư.
+// Số dư đi qua ngưỡng cảnh báo (alert_threshold) thì ghi thêm event balance.threshold_crossed vào outbox cùng transaction.
+func (s *BalanceService) ApplyEntries(ctx context.Context, tx *gorm.DB, accounts map[uint64]*model.CoaAccount, entries []*model.Entry, checkAvailable map[uint64]bool) error {
+	type movement struct {
+		debit, credit decimal.Decimal
+		lastEntryID   uint64
+	}
+	movements := make(map[uint64]*movement)
+	ids := make([]uint64, 0, len(accounts))
+	for _, e := range entries {
+		m, ok := movements[e.AccountID]
+		if !ok {
+			m = &movement{}
+			movements[e.AccountID] = m
+			ids = append(ids, e.AccountID)
+		}
+		if e.DC == "D" {
+			m.debit = m.debit.Add(e.Amount)
+		} else {
+			m.credit = m.credit.Add(e.Amount)
+		}
+		if e.ID > m.lastEntryID {
+			m.lastEntryID = e.ID
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	balances := s.accountBalanceRepo.WithTx(tx)
+	for _, id := range ids {
+		account := accounts[id]
+		if account == nil {
+			return core.NewError(core.ErrCodeLedgerAccountNotFound, fmt.Sprintf("account %d không tồn tại", id))
+		}
+		m := movements[id]
+		delta := account.NormalBalance(m.debit, m.credit)
+		// tổng ghi giảm (bên ngược số dư thông thường), chưa bù trừ với khoản ghi tăng
+		outflow := decimal.Min(account.NormalBalance(m.debit, decimal.Zero), decimal.Zero).
+			Add(decimal.Min(account.NormalBalance(decimal.Zero, m.credit), decimal.Zero))
+
+		for attempt := 0; ; attempt++ {
+			balance, err := balances.GetOrCreate(ctx, account, attempt > 0)
+			if err != nil {
+				return err
+			}
+			if err := s.checkPolicy(ctx, account, balance, delta, outflow, checkAvailable[id]); err != nil {
+				return err
+			}
+
+			version := balance.Version
+			before := balance.Balance
+			balance.DebitTotal = balance.DebitTotal.Add(m.debit)
+			balance.CreditTotal = balance.CreditTotal.Add(m.credit)
+			balance.Balance = balance.Balance.Add(delta)
+			if m.lastEntryID > 0 {
+				lastEntryID := m.lastEntryID
+				balance.LastEntryID = &lastEntryID
+			}
+			ok, err := balances.CompareAndSwap(ctx, balance, version)
+			if err != nil {
+				return err
+			}
+			if ok {
+				if event := newThresholdEvent(account, before, balance); event != nil {
+					event.Headers = tracing.Headers(ctx)
+					if err := tx.Create(event).Error; err != nil {
+						return err
+					}
+				}
+				break
+			}
+			if attempt+1 >= balanceUpdateRetries {
+				return fmt.Errorf("account %d: balance version conflict after %d attempts", id, balanceUpdateRetries)
+			}
+		}
+	}
+	return nil
+}
+
+// newThresholdEvent trả về event outbox khi số dư đi từ dưới lên (UP) hoặc từ trên/bằng xuống dưới (DOWN) ngưỡng cảnh báo
+func newThresholdEvent(account *model.CoaAccount, before decimal.Decimal, balance *model.AccountBalance) *model.TransactionLog {
+	if account.AlertThreshold == nil {
+		return nil
+	}
+	threshold := *account.AlertThreshold
+	direction := ""
+	switch {
+	case before.LessThan(threshold) && !balance.Balance.LessThan(threshold):
+		direction = "UP"
+	case !before.LessThan(threshold) && balance.Balance.LessThan(threshold):
+		direction = "DOWN"
+	default:
+		return nil
+	}
+	var lastEntryID uint64
+	if balance.LastEntryID != nil {
+		lastEntryID = *balance.LastEntryID
+	}
+	return &model.TransactionLog{
+		AggregateType: model.AggregateTypeAccount,
+		AggregateID:   account.ID,
+		EventType:     model.EventBalanceThresholdCrossed,
+		EventKey:      fmt.Sprintf("%s:%d:%d", model.EventBalanceThresholdCrossed, account.ID, lastEntryID),
+		PartitionKey:  account.Code,
+		Payload: map[string]any{
+			"account_id":     account.ID,
+			"account_code":   account.Code,
+			"currency":       account.Currency,
+			"threshold":      threshold.String(),
+			"direction":      direction,
+			"balance_before": before.String(),
+			"balance_after":  balance.Balance.String(),
+			"last_entry_id":  balance.LastEntryID,
+		},
+		Status: model.TransactionLogStatusPending,
+	}
+}
+
+// ReserveForHold khoá dòng số dư (tăng version) và kiểm tra tài khoản còn đủ số dư khả dụng để tạm giữ amount
+func (s *BalanceService) ReserveForHold(ctx context.Context, tx *gorm.DB, account *model.CoaAccount, amount decimal.Decimal) error {
+	balance, err := s.accountBalanceRepo.WithTx(tx).Touch(ctx, account)
+	if err != nil {
+		return err
+	}
+	floor, limited := account.BalanceFloor()
+	if !limited {
+		return nil
+	}
+	held, err := s.holdRepo.SumActiveByAccount(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+	available := balance.Balance.Sub(held)
+	if available.Sub(amount).LessThan(floor) {
+		return core.NewError(core.ErrCodeLedgerInsufficientAvailable,
+			fmt.Sprintf("account %s khả dụng %s, cần %s", account.Code, available, amount))
+	}
+	return nil
+}
+
+// checkPolicy kiểm tra số dư sau khi cộng delta (ròng) theo chính sách của tài khoản,
+// checkAvailable = true thì kiểm tra thêm số dư khả dụng đủ cho outflow (tổng ghi giảm, <= 0)
+func (s *BalanceService) checkPolicy(ctx context.Context, account *model.CoaAccount, balance *model.AccountBalance, delta, outflow decimal.Decimal, checkAvailable bool) error {
+	floor, limited := account.BalanceFloor()
+	if limited && delta.IsNegative() {
+		if err := checkFloor(account, balance.Balance.Add(delta), floor); err != nil {
+			return err
+		}
+	}
+	if !checkAvailable || !outflow.IsNegative() {
+		return nil
+	}
+	if !limited {
+		floor = decimal.Zero
+	}
+	held, err := s.holdRepo.SumActiveByAccount(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+	available := balance.Balance.Sub(held)
+	if available.Add(outflow).LessThan(floor) {
+		return core.NewError(core.ErrCodeLedgerInsufficientAvailable,
+			fmt.Sprintf("account %s khả dụng %s, cần %s", account.Code, available, outflow.Neg()))
+	}
+	return nil
+}
+
+// availableCheck tập tài khoản cần kiểm tra số dư khả dụng: mọi tài khoản của journal nếu journal yêu cầu, nil nếu không
+func availableCheck(accounts map[uint64]*model.CoaAccount, enabled bool) map[uint64]bool {
+	if !enabled {
+		return nil
+	}
+	set := make(map[uint64]bool, len(accounts))
+	for id := range accounts {
+		set[id] = true
+	}
+	return set
+}
+
+// checkFloor trả về AppError tương ứng với chính sách bị vi phạm
+func checkFloor(account *model.CoaAccount, after, floor decimal.Decimal) error {
+	if !after.LessThan(floor) {
+		return nil
+	}
+	desc := fmt.Sprintf("account %s: số dư sau ghi sổ %s, tối thiểu %s", account.Code, after, floor)
+	switch {
+	case account.OverdraftLimit != nil && account.OverdraftLimit.IsPositive():
+		return core.NewError(core.ErrCodeLedgerOverdraftExceeded, desc)
+	case account.MinBalance != nil && account.MinBalance.IsPositive():
+		return core.NewError(core.ErrCodeLedgerMinBalanceViolated, desc)
+	default:
+		return core.NewError(core.ErrCodeLedgerNegativeBalance, desc)
+	}
+}
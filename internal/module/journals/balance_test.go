@@ -0,0 +1,224 @@
+package journals
+
+import (
+	"context"
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/currencies"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/repo"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// fakeBalances account_balances trong bộ nhớ, CompareAndSwap so version như repo thật
+type fakeBalances struct {
+	repo.AccountBalanceRepo
+	rows map[uint64]*model.AccountBalance
+}
+
+func newFakeBalances() *fakeBalances {
+	return &fakeBalances{rows: map[uint64]*model.AccountBalance{}}
+}
+
+func (f *fakeBalances) WithTx(*gorm.DB) repo.AccountBalanceRepo { return f }
+
+func (f *fakeBalances) GetOrCreate(_ context.Context, account *model.CoaAccount, _ bool) (*model.AccountBalance, error) {
+	row, ok := f.rows[account.ID]
+	if !ok {
+		row = &model.AccountBalance{AccountID: account.ID, Currency: account.Currency}
+		f.rows[account.ID] = row
+	}
+	copied := *row
+	return &copied, nil
+}
+
+func (f *fakeBalances) CompareAndSwap(_ context.Context, balance *model.AccountBalance, version int64) (bool, error) {
+	row := f.rows[balance.AccountID]
+	if row.Version != version {
+		return false, nil
+	}
+	balance.Version = version + 1
+	copied := *balance
+	f.rows[balance.AccountID] = &copied
+	return true, nil
+}
+
+// fakeHolds tổng hold PENDING theo tài khoản
+type fakeHolds struct {
+	repo.HoldRepo
+	held map[uint64]decimal.Decimal
+}
+
+func (f *fakeHolds) SumActiveByAccount(_ context.Context, accountID uint64) (decimal.Decimal, error) {
+	return f.held[accountID], nil
+}
+
+func newTestBalanceService(held map[uint64]decimal.Decimal) (*BalanceService, *fakeBalances) {
+	balances := newFakeBalances()
+	return NewBalanceService(nil, balances, &fakeHolds{held: held}, currencies.NewCurrencyService(fakeCurrencies{})), balances
+}
+
+func dec(s string) decimal.Decimal {
+	return decimal.RequireFromString(s)
+}
+
+func decPtr(s string) *decimal.Decimal {
+	d := dec(s)
+	return &d
+}
+
+func entry(accountID uint64, dc, amount string) *model.Entry {
+	return &model.Entry{AccountID: accountID, DC: dc, Amount: dec(amount)}
+}
+
+func appErrCode(err error) core.AppErrorCode {
+	var appErr *core.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return ""
+}
+
+func TestBalanceFloor(t *testing.T) {
+	cases := []struct {
+		name      string
+		account   model.CoaAccount
+		floor     string
+		unlimited bool
+	}{
+		{"allow negative", model.CoaAccount{AllowNegative: true, MinBalance: decPtr("10")}, "0", true},
+		{"default", model.CoaAccount{}, "0", false},
+		{"min balance", model.CoaAccount{MinBalance: decPtr("10")}, "10", false},
+		{"overdraft", model.CoaAccount{OverdraftLimit: decPtr("50")}, "-50", false},
+		{"min balance and overdraft", model.CoaAccount{MinBalance: decPtr("10"), OverdraftLimit: decPtr("50")}, "-40", false},
+	}
+	for _, tc := range cases {
+		floor, limited := tc.account.BalanceFloor()
+		if limited == tc.unlimited || !floor.Equal(dec(tc.floor)) {
+			t.Errorf("%s: floor = %s limited = %v", tc.name, floor, limited)
+		}
+	}
+}
+
+func TestApplyEntriesRejectsBreach(t *testing.T) {
+	cases := []struct {
+		name    string
+		account *model.CoaAccount
+		debit   string
+		held    string
+		avail   bool
+		want    core.AppErrorCode
+		balance string
+	}{
+		// LIAB số dư bên Có: ghi Nợ làm giảm số dư 100
+		{"negative", &model.CoaAccount{}, "150", "0", false, core.ErrCodeLedgerNegativeBalance, "100"},
+		{"down to zero", &model.CoaAccount{}, "100", "0", false, "", "0"},
+		{"min balance", &model.CoaAccount{MinBalance: decPtr("60")}, "50", "0", false, core.ErrCodeLedgerMinBalanceViolated, "100"},
+		{"within overdraft", &model.CoaAccount{OverdraftLimit: decPtr("100")}, "150", "0", false, "", "-50"},
+		{"overdraft exceeded", &model.CoaAccount{OverdraftLimit: decPtr("100")}, "250", "0", false, core.ErrCodeLedgerOverdraftExceeded, "100"},
+		{"held amount", &model.CoaAccount{}, "80", "30", true, core.ErrCodeLedgerInsufficientAvailable, "100"},
+		{"held ignored without available check", &model.CoaAccount{}, "80", "30", false, "", "20"},
+		{"allow negative", &model.CoaAccount{AllowNegative: true}, "500", "0", false, "", "-400"},
+	}
+	for _, tc := range cases {
+		account := tc.account
+		account.ID, account.Code, account.Type, account.Currency = 1, "WALLET", "LIAB", "USD"
+		service, balances := newTestBalanceService(map[uint64]decimal.Decimal{1: dec(tc.held)})
+		balances.rows[1] = &model.AccountBalance{AccountID: 1, Balance: dec("100"), CreditTotal: dec("100")}
+
+		accounts := map[uint64]*model.CoaAccount{1: account}
+		err := service.ApplyEntries(context.Background(), nil, accounts, []*model.Entry{entry(1, "D", tc.debit)}, map[uint64]bool{1: tc.avail})
+		if code := appErrCode(err); code != tc.want || (tc.want == "" && err != nil) {
+			t.Errorf("%s: err = %v", tc.name, err)
+		}
+		if got := balances.rows[1].Balance; !got.Equal(dec(tc.balance)) {
+			t.Errorf("%s: balance = %s, want %s", tc.name, got, tc.balance)
+		}
+	}
+}
+
+func TestApplyEntriesFreshAccountPolicy(t *testing.T) {
+	// tạo như import Excel: file không có chính sách số dư, lấy mặc định theo type
+	create := func(id uint64, accountType string) *model.CoaAccount {
+		account := &model.CoaAccount{ID: id, Code: accountType, Name: accountType, Type: accountType, Currency: "USD"}
+		account.ApplyDefaultBalancePolicy()
+		return account
+	}
+	cash, fee, wallet := create(1, "ASSET"), create(2, "EXP"), create(3, "LIAB")
+	if !cash.AllowNegative || !fee.AllowNegative || wallet.AllowNegative {
+		t.Fatalf("allow_negative ASSET=%v EXP=%v LIAB=%v", cash.AllowNegative, fee.AllowNegative, wallet.AllowNegative)
+	}
+
+	service, balances := newTestBalanceService(nil)
+	accounts := map[uint64]*model.CoaAccount{1: cash, 2: fee, 3: wallet}
+	// ASSET/EXP mới, số dư 0: ghi Có (giảm số dư) vẫn được
+	if err := service.ApplyEntries(context.Background(), nil, accounts, []*model.Entry{
+		entry(1, "C", "10"), entry(2, "C", "5"), entry(3, "D", "15"),
+	}, nil); appErrCode(err) != core.ErrCodeLedgerNegativeBalance {
+		t.Fatalf("LIAB debit: err = %v", err)
+	}
+	balances.rows = map[uint64]*model.AccountBalance{}
+	if err := service.ApplyEntries(context.Background(), nil, accounts, []*model.Entry{
+		entry(1, "C", "10"), entry(2, "C", "5"), entry(3, "C", "15"),
+	}, nil); err != nil {
+		t.Fatalf("ASSET/EXP credit: %v", err)
+	}
+	if !balances.rows[1].Balance.Equal(dec("-10")) || !balances.rows[2].Balance.Equal(dec("-5")) {
+		t.Fatalf("balances = %+v %+v", balances.rows[1], balances.rows[2])
+	}
+
+	// không phải LIAB nhưng đặt MinBalance thì giữ chặn âm
+	floored := &model.CoaAccount{Type: "ASSET", MinBalance: decPtr("0")}
+	if floored.ApplyDefaultBalancePolicy(); floored.AllowNegative {
+		t.Fatalf("explicit min_balance: allow_negative = %v", floored.AllowNegative)
+	}
+}
+
+func (f *fakeBalances) GetByAccount(_ context.Context, accountID uint64) (*model.AccountBalance, error) {
+	if row, ok := f.rows[accountID]; ok {
+		return row, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeCoAccounts) GetByID(_ context.Context, id int64) (*model.CoaAccount, error) {
+	for _, a := range f.accounts {
+		if a.ID == uint64(id) {
+			return a, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestGetBalanceFormatsAmounts(t *testing.T) {
+	service, balances := newTestBalanceService(map[uint64]decimal.Decimal{1: dec("30")})
+	service.coAccountRepo = &fakeCoAccounts{accounts: []*model.CoaAccount{
+		{ID: 1, Code: "WALLET", Type: "LIAB", Currency: "USD"},
+		{ID: 2, Code: "WALLET_B", Type: "LIAB", Currency: "USD"},
+	}}
+	balances.rows[1] = &model.AccountBalance{AccountID: 1, Currency: "USD", Balance: dec("100.5")}
+
+	cases := []struct {
+		id                      int64
+		ledger, held, available string
+	}{
+		{1, "100.50", "30.00", "70.50"},
+		// chưa phát sinh
+		{2, "0.00", "0.00", "0.00"},
+	}
+	for _, tc := range cases {
+		res, err := service.GetBalance(context.Background(), tc.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.LedgerBalance != tc.ledger || res.HeldAmount != tc.held || res.AvailableBalance != tc.available {
+			t.Errorf("account %d: %+v", tc.id, res)
+		}
+	}
+	if _, err := service.GetBalance(context.Background(), 99); appErrCode(err) != core.ErrCodeLedgerAccountNotFound {
+		t.Fatalf("not found: err = %v", err)
+	}
+}
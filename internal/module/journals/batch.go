@@ -0,0 +1,291 @@
+package journals
+
+import (
+	"context"
+	"core-ledger/internal/core"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/metrics"
+	"core-ledger/pkg/repo"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BatchService struct {
+	db             *gorm.DB
+	batchRepo      repo.JournalBatchRepo
+	journalService *JournalService
+	balanceService *BalanceService
+	logger         logger.CustomLogger
+}
+
+func NewBatchService(db *gorm.DB, batchRepo repo.JournalBatchRepo, journalService *JournalService, balanceService *BalanceService) *BatchService {
+	return &BatchService{
+		db:             db,
+		batchRepo:      batchRepo,
+		journalService: journalService,
+		balanceService: balanceService,
+		logger:         logger.NewSystemLog("BatchService"),
+	}
+}
+
+// Post ghi sổ các journal của request với cùng một batch_id mới.
+// ATOMIC: mọi journal ghi trong một transaction, số dư được cộng một lần cho cả batch; lỗi bất kỳ → rollback toàn bộ,
+// batch vẫn được lưu với status FAILED để tra cứu.
+// BEST_EFFORT: mỗi journal một transaction, journal lỗi không ảnh hưởng journal khác.
+func (s *BatchService) Post(ctx context.Context, req *PostJournalBatchRequest, postedBy *string) (res *JournalBatchResponse, err error) {
+	start := time.Now()
+	defer func() { metrics.ObservePosting(operationBatch, start, batchError(res, err)) }()
+
+	seen := make(map[string]int, len(req.Journals))
+	for i, j := range req.Journals {
+		if first, ok := seen[j.IdempotencyKey]; ok {
+			return nil, core.NewError(core.ErrCodeLedgerBatchDuplicateKey,
+				fmt.Sprintf("journals.%d và journals.%d cùng idempotency_key %s", first, i, j.IdempotencyKey))
+		}
+		seen[j.IdempotencyKey] = i
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = model.JournalBatchModeAtomic
+	}
+	batch := &model.JournalBatch{
+		ID:         uuid.NewString(),
+		Mode:       mode,
+		Status:     model.JournalBatchStatusProcessing,
+		Source:     req.Source,
+		Memo:       req.Memo,
+		Meta:       req.Meta,
+		TotalItems: len(req.Journals),
+		CreatedBy:  postedBy,
+		TenantID:   req.TenantID,
+		LedgerCode: req.LedgerCode,
+	}
+	if mode == model.JournalBatchModeBestEffort {
+		return s.postBestEffort(ctx, batch, req, postedBy)
+	}
+	return s.postAtomic(ctx, batch, req, postedBy)
+}
+
+func (s *BatchService) postAtomic(ctx context.Context, batch *model.JournalBatch, req *PostJournalBatchRequest, postedBy *string) (*JournalBatchResponse, error) {
+	items := newBatchItems(req)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		postings := make([]*posting, 0, len(req.Journals))
+		// chỉ tài khoản của journal yêu cầu check_available_balance mới kiểm tra số dư khả dụng
+		checkAvailable := make(map[uint64]bool)
+		for i, j := range req.Journals {
+			p, err := s.journalService.write(ctx, tx, j, postedBy, &batch.ID)
+			if err != nil {
+				items[i].Status = BatchItemFailed
+				items[i].Error = toAppError(err)
+				return err
+			}
+			items[i].JournalID = &p.journal.ID
+			if p.existing {
+				items[i].Status = BatchItemDuplicate
+				continue
+			}
+			items[i].Status = BatchItemPosted
+			postings = append(postings, p)
+			for id := range availableCheck(p.accounts, j.CheckAvailableBalance) {
+				checkAvailable[id] = true
+			}
+		}
+		if err := s.applyPostings(ctx, tx, postings, checkAvailable); err != nil {
+			return err
+		}
+		batch.Status = model.JournalBatchStatusPosted
+		batch.PostedCount = len(postings)
+		return s.batchRepo.WithTx(tx).Create(batch)
+	})
+	if err == nil {
+		s.logger.WithContext(ctx).Info("Batch posted", batch.ID, batch.PostedCount)
+		return &JournalBatchResponse{Batch: batch, Items: items}, nil
+	}
+
+	// transaction đã rollback: không journal nào được ghi, lưu lại batch FAILED kèm lỗi
+	for _, item := range items {
+		if item.Status != BatchItemFailed {
+			item.Status = BatchItemRolledBack
+			item.JournalID = nil
+		}
+	}
+	msg := toAppError(err).Error()
+	batch.Status = model.JournalBatchStatusFailed
+	batch.PostedCount = 0
+	batch.FailedCount = batch.TotalItems
+	batch.Error = &msg
+	if cerr := s.batchRepo.Create(batch); cerr != nil {
+		return nil, errors.Join(err, cerr)
+	}
+	s.logger.WithContext(ctx).Warn("Batch rolled back", batch.ID, msg)
+	return &JournalBatchResponse{Batch: batch, Items: items}, nil
+}
+
+func (s *BatchService) postBestEffort(ctx context.Context, batch *model.JournalBatch, req *PostJournalBatchRequest, postedBy *string) (*JournalBatchResponse, error) {
+	if err := s.batchRepo.Create(batch); err != nil {
+		return nil, err
+	}
+	items := newBatchItems(req)
+	for i, j := range req.Journals {
+		var p *posting
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var err error
+			p, err = s.journalService.write(ctx, tx, j, postedBy, &batch.ID)
+			if err != nil || p.existing {
+				return err
+			}
+			return s.balanceService.ApplyEntries(ctx, tx, p.accounts, p.entries, availableCheck(p.accounts, j.CheckAvailableBalance))
+		})
+		switch {
+		case err != nil:
+			items[i].Status = BatchItemFailed
+			items[i].Error = toAppError(err)
+			batch.FailedCount++
+		case p.existing:
+			items[i].Status = BatchItemDuplicate
+			items[i].JournalID = &p.journal.ID
+		default:
+			items[i].Status = BatchItemPosted
+			items[i].JournalID = &p.journal.ID
+			batch.PostedCount++
+		}
+	}
+
+	switch {
+	case batch.FailedCount == 0:
+		batch.Status = model.JournalBatchStatusPosted
+	case batch.FailedCount == batch.TotalItems:
+		batch.Status = model.JournalBatchStatusFailed
+	default:
+		batch.Status = model.JournalBatchStatusPartial
+	}
+	err := s.batchRepo.UpdateSelectField(batch, map[string]interface{}{
+		"status":       batch.Status,
+		"posted_count": batch.PostedCount,
+		"failed_count": batch.FailedCount,
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithContext(ctx).Info("Batch posted", batch.ID, batch.Status, batch.PostedCount, batch.FailedCount)
+	return &JournalBatchResponse{Batch: batch, Items: items}, nil
+}
+
+// Reverse đảo toàn bộ journal gốc của batch trong một transaction, journal đảo mang cùng batch_id.
+// Chỉ batch POSTED/PARTIAL mới đảo được; batch đã đảo trả về lỗi NOT_REVERSIBLE.
+func (s *BatchService) Reverse(ctx context.Context, id string, reversedBy *string) (*JournalBatchResponse, error) {
+	start := time.Now()
+	var batch *model.JournalBatch
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		batchRepo := s.batchRepo.WithTx(tx)
+		var err error
+		batch, err = batchRepo.LockByID(ctx, id)
+		if err != nil {
+			return notFoundOr(err, core.ErrCodeLedgerBatchNotFound)
+		}
+		if batch.Status != model.JournalBatchStatusPosted && batch.Status != model.JournalBatchStatusPartial {
+			return core.NewError(core.ErrCodeLedgerBatchNotReversible, fmt.Sprintf("batch đang ở trạng thái %s", batch.Status))
+		}
+
+		journals, err := batchRepo.ListJournals(ctx, id)
+		if err != nil {
+			return err
+		}
+		postings := make([]*posting, 0, len(journals))
+		for _, j := range journals {
+			p, err := s.journalService.reverse(ctx, tx, j, reversedBy, &batch.ID)
+			if err != nil {
+				return err
+			}
+			postings = append(postings, p)
+		}
+		if err := s.applyPostings(ctx, tx, postings, nil); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		batch.Status = model.JournalBatchStatusReversed
+		batch.ReversedBy = reversedBy
+		batch.ReversedAt = &now
+		return batchRepo.UpdateSelectField(batch, map[string]interface{}{
+			"status":      batch.Status,
+			"reversed_by": reversedBy,
+			"reversed_at": now,
+		})
+	})
+	metrics.ObservePosting(operationBatchReverse, start, err)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithContext(ctx).Info("Batch reversed", batch.ID)
+	return s.Detail(ctx, id)
+}
+
+// Detail trả về batch kèm tổng số journal / tổng Nợ-Có theo currency
+func (s *BatchService) Detail(ctx context.Context, id string) (*JournalBatchResponse, error) {
+	batch, err := s.batchRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, notFoundOr(err, core.ErrCodeLedgerBatchNotFound)
+	}
+	totals, err := s.batchRepo.Totals(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &JournalBatchResponse{Batch: batch, Totals: totals}, nil
+}
+
+// applyPostings cộng entries của nhiều journal vào account_balances một lần,
+// các tài khoản được khoá theo thứ tự id nên batch lớn không deadlock với posting đồng thời
+func (s *BatchService) applyPostings(ctx context.Context, tx *gorm.DB, postings []*posting, checkAvailable map[uint64]bool) error {
+	if len(postings) == 0 {
+		return nil
+	}
+	accounts := make(map[uint64]*model.CoaAccount)
+	entries := make([]*model.Entry, 0, len(postings)*2)
+	for _, p := range postings {
+		for id, a := range p.accounts {
+			accounts[id] = a
+		}
+		entries = append(entries, p.entries...)
+	}
+	return s.balanceService.ApplyEntries(ctx, tx, accounts, entries, checkAvailable)
+}
+
+func newBatchItems(req *PostJournalBatchRequest) []*JournalBatchItemResult {
+	items := make([]*JournalBatchItemResult, len(req.Journals))
+	for i, j := range req.Journals {
+		items[i] = &JournalBatchItemResult{Index: i, IdempotencyKey: j.IdempotencyKey}
+	}
+	return items
+}
+
+// batchError lỗi dùng cho metrics: batch FAILED (ATOMIC rollback hoặc mọi journal lỗi) tính theo lỗi của journal đầu tiên
+func batchError(res *JournalBatchResponse, err error) error {
+	if err != nil || res == nil || res.Batch.Status != model.JournalBatchStatusFailed {
+		return err
+	}
+	for _, item := range res.Items {
+		if item.Error == nil {
+			continue
+		}
+		if item.Error.Code == core.ErrCodeUnknown {
+			return errors.New(item.Error.Message)
+		}
+		return item.Error
+	}
+	return nil
+}
+
+func toAppError(err error) *core.AppError {
+	var appErr *core.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return &core.AppError{Code: core.ErrCodeUnknown, Message: err.Error(), Description: err.Error()}
+}
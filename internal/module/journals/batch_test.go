@@ -0,0 +1,267 @@
+package journals
+
+import (
+	"context"
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/currencies"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/repo"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var errNoDatabase = errors.New("no database")
+
+// fakeTxPool ConnPool chỉ ghi nhận commit/rollback; câu lệnh chạy ở DryRun nên không tới đây
+type fakeTxPool struct {
+	commits, rollbacks int
+}
+
+func (p *fakeTxPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errNoDatabase
+}
+func (p *fakeTxPool) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errNoDatabase
+}
+func (p *fakeTxPool) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errNoDatabase
+}
+func (p *fakeTxPool) QueryRowContext(context.Context, string, ...interface{}) *sql.Row { return nil }
+func (p *fakeTxPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return &fakeTx{fakeTxPool: p}, nil
+}
+
+type fakeTx struct {
+	*fakeTxPool
+}
+
+func (t *fakeTx) Commit() error   { t.commits++; return nil }
+func (t *fakeTx) Rollback() error { t.rollbacks++; return nil }
+
+type fakeCurrencies struct {
+	repo.LedgerCurrencyRepo
+}
+
+func (fakeCurrencies) List(context.Context) ([]*model.LedgerCurrency, error) {
+	return []*model.LedgerCurrency{{Code: "USD", Scale: 2, Active: true}}, nil
+}
+
+type fakeJournalRepo struct {
+	repo.JournalRepo
+	journals map[int64]*model.Journal
+}
+
+func (fakeJournalRepo) GetByIdempotencyKey(context.Context, string) (*model.Journal, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f fakeJournalRepo) WithTx(*gorm.DB) repo.JournalRepo { return f }
+
+func (f fakeJournalRepo) LockWithEntries(_ context.Context, id int64) (*model.Journal, error) {
+	if journal, ok := f.journals[id]; ok {
+		return journal, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeBatches struct {
+	repo.JournalBatchRepo
+	created []*model.JournalBatch
+}
+
+func (f *fakeBatches) WithTx(*gorm.DB) repo.JournalBatchRepo { return f }
+
+func (f *fakeBatches) Create(batch *model.JournalBatch) error {
+	f.created = append(f.created, batch)
+	return nil
+}
+
+func (f *fakeCoAccounts) GetManyByFields(_ context.Context, fields map[string]interface{}, _ ...string) ([]*model.CoaAccount, error) {
+	ids := map[uint64]bool{}
+	for _, id := range fields["id"].([]uint64) {
+		ids[id] = true
+	}
+	var out []*model.CoaAccount
+	for _, a := range f.accounts {
+		if ids[a.ID] {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func newTestBatchService(t *testing.T) (*BatchService, *fakeTxPool, *fakeBalances, *fakeBatches) {
+	t.Helper()
+	pool := &fakeTxPool{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: pool}), &gorm.Config{DryRun: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts := &fakeCoAccounts{accounts: []*model.CoaAccount{
+		{ID: 1, Code: "CASH", Type: "ASSET", Currency: "USD", Status: "ACTIVE", AllowNegative: true},
+		{ID: 2, Code: "WALLET", Type: "LIAB", Currency: "USD", Status: "ACTIVE"},
+		{ID: 3, Code: "WALLET_B", Type: "LIAB", Currency: "USD", Status: "ACTIVE"},
+	}}
+	balanceService, balances := newTestBalanceService(nil)
+	journalService := NewJournalService(nil, db, fakeJournalRepo{}, accounts, nil, balanceService,
+		currencies.NewCurrencyService(fakeCurrencies{}))
+	batches := &fakeBatches{}
+	return NewBatchService(db, batches, journalService, balanceService), pool, balances, batches
+}
+
+func transfer(key string, debit, credit uint64, amount string) *PostJournalRequest {
+	return &PostJournalRequest{
+		IdempotencyKey: key,
+		Currency:       "USD",
+		Source:         "test",
+		Lines: []*PostingLineRequest{
+			{AccountID: debit, DC: "D", Amount: amount},
+			{AccountID: credit, DC: "C", Amount: amount},
+		},
+	}
+}
+
+func TestPostBatchAtomic(t *testing.T) {
+	service, pool, balances, batches := newTestBatchService(t)
+	res, err := service.Post(context.Background(), &PostJournalBatchRequest{Source: "test", Journals: []*PostJournalRequest{
+		transfer("a", 1, 2, "10"), transfer("b", 1, 2, "5.5"),
+	}}, nil)
+	if err != nil || res.Batch.Status != model.JournalBatchStatusPosted || res.Batch.PostedCount != 2 {
+		t.Fatalf("res = %+v err = %v", res, err)
+	}
+	if pool.commits != 1 || pool.rollbacks != 0 {
+		t.Fatalf("commits = %d rollbacks = %d", pool.commits, pool.rollbacks)
+	}
+	if !balances.rows[2].Balance.Equal(dec("15.5")) || len(batches.created) != 1 {
+		t.Fatalf("balance = %s batches = %d", balances.rows[2].Balance, len(batches.created))
+	}
+}
+
+func TestPostBatchAtomicRollsBackOnBadJournal(t *testing.T) {
+	cases := []struct {
+		name     string
+		journals []*PostJournalRequest
+		failed   int // index journal lỗi, -1 nếu lỗi ở bước cộng số dư
+		want     core.AppErrorCode
+	}{
+		{"unknown account", []*PostJournalRequest{transfer("a", 1, 2, "10"), transfer("b", 1, 99, "10"), transfer("c", 1, 2, "10")},
+			1, core.ErrCodeLedgerAccountNotFound},
+		{"precision", []*PostJournalRequest{transfer("a", 1, 2, "10"), transfer("b", 1, 2, "0.001")},
+			1, core.ErrCodeLedgerAmountPrecision},
+		// từng journal hợp lệ, cộng cả batch thì WALLET âm: lỗi ở bước cộng số dư, không item nào FAILED
+		{"batch balance breach", []*PostJournalRequest{transfer("a", 1, 2, "10"), transfer("b", 2, 1, "30")},
+			-1, ""},
+	}
+	for _, tc := range cases {
+		service, pool, balances, batches := newTestBatchService(t)
+		res, err := service.Post(context.Background(), &PostJournalBatchRequest{Source: "test", Journals: tc.journals}, nil)
+		if err != nil {
+			t.Fatalf("%s: err = %v", tc.name, err)
+		}
+		if pool.commits != 0 || pool.rollbacks != 1 {
+			t.Errorf("%s: commits = %d rollbacks = %d", tc.name, pool.commits, pool.rollbacks)
+		}
+		batch := res.Batch
+		if batch.Status != model.JournalBatchStatusFailed || batch.PostedCount != 0 || batch.FailedCount != len(tc.journals) || batch.Error == nil {
+			t.Errorf("%s: batch = %+v", tc.name, batch)
+		}
+		// batch FAILED vẫn được lưu (ngoài transaction đã rollback) để tra cứu
+		if len(batches.created) != 1 || batches.created[0] != batch {
+			t.Errorf("%s: batches = %+v", tc.name, batches.created)
+		}
+		for i, item := range res.Items {
+			switch {
+			case i == tc.failed:
+				if item.Status != BatchItemFailed || item.Error == nil || item.Error.Code != tc.want {
+					t.Errorf("%s: item %d = %+v", tc.name, i, item)
+				}
+			case item.Status != BatchItemRolledBack || item.JournalID != nil:
+				t.Errorf("%s: item %d = %+v", tc.name, i, item)
+			}
+		}
+		if tc.failed >= 0 && len(balances.rows) != 0 {
+			t.Errorf("%s: balances touched %+v", tc.name, balances.rows)
+		}
+	}
+}
+
+func TestPostBatchAvailableCheckPerAccount(t *testing.T) {
+	checked := func(j *PostJournalRequest) *PostJournalRequest {
+		j.CheckAvailableBalance = true
+		return j
+	}
+	cases := []struct {
+		name     string
+		journals []*PostJournalRequest
+		status   string
+	}{
+		// WALLET bị giữ 30: journal không yêu cầu kiểm tra được tiêu cả phần đang giữ dù journal khác trong batch có yêu cầu
+		{"unchecked account spends held funds", []*PostJournalRequest{checked(transfer("a", 3, 1, "10")), transfer("b", 2, 1, "80")},
+			model.JournalBatchStatusPosted},
+		{"checked account", []*PostJournalRequest{transfer("a", 3, 1, "10"), checked(transfer("b", 2, 1, "80"))},
+			model.JournalBatchStatusFailed},
+		// khoản ghi tăng của journal khác không bù cho khoản ghi giảm vượt số dư khả dụng
+		{"inflow does not offset outflow", []*PostJournalRequest{checked(transfer("a", 2, 1, "80")), transfer("b", 1, 2, "50")},
+			model.JournalBatchStatusFailed},
+		{"within available", []*PostJournalRequest{checked(transfer("a", 2, 1, "70")), transfer("b", 1, 2, "50")},
+			model.JournalBatchStatusPosted},
+	}
+	for _, tc := range cases {
+		service, _, balances, _ := newTestBatchService(t)
+		service.balanceService.holdRepo.(*fakeHolds).held = map[uint64]decimal.Decimal{2: dec("30")}
+		balances.rows[1] = &model.AccountBalance{AccountID: 1, Currency: "USD", Balance: dec("1000")}
+		balances.rows[2] = &model.AccountBalance{AccountID: 2, Currency: "USD", Balance: dec("100")}
+		balances.rows[3] = &model.AccountBalance{AccountID: 3, Currency: "USD", Balance: dec("100")}
+		res, err := service.Post(context.Background(), &PostJournalBatchRequest{Source: "test", Journals: tc.journals}, nil)
+		if err != nil || res.Batch.Status != tc.status {
+			t.Errorf("%s: batch = %+v err = %v", tc.name, res.Batch, err)
+		}
+	}
+}
+
+func TestReverseJournal(t *testing.T) {
+	service, pool, balances, _ := newTestBatchService(t)
+	batchID := "batch-1"
+	posted := func(id int64) *model.Journal {
+		return &model.Journal{ID: uint64(id), Status: model.JournalStatusPosted, Currency: "USD", Source: "test",
+			Entries: []model.Entry{*entry(1, "D", "10"), *entry(2, "C", "10")}}
+	}
+	journals := map[int64]*model.Journal{1: posted(1), 2: posted(2), 3: posted(3)}
+	journals[2].Status = model.JournalStatusReversed
+	journals[3].BatchID = &batchID
+	service.journalService.journalRepo = fakeJournalRepo{journals: journals}
+	balances.rows[2] = &model.AccountBalance{AccountID: 2, Currency: "USD", Balance: dec("10")}
+
+	actor := "employee:7"
+	reversal, err := service.journalService.Reverse(context.Background(), 1, &actor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reversal.ReversalOfID == nil || *reversal.ReversalOfID != 1 || reversal.PostedBy == nil || *reversal.PostedBy != actor {
+		t.Fatalf("reversal = %+v", reversal)
+	}
+	if journals[1].Status != model.JournalStatusReversed || !balances.rows[2].Balance.IsZero() || pool.commits != 1 {
+		t.Fatalf("status = %s balance = %s commits = %d", journals[1].Status, balances.rows[2].Balance, pool.commits)
+	}
+
+	cases := []struct {
+		id   int64
+		want core.AppErrorCode
+	}{
+		{1, core.ErrCodeLedgerJournalNotReversible},
+		{2, core.ErrCodeLedgerJournalNotReversible},
+		// journal trong batch đảo qua /batches/:id/reverse
+		{3, core.ErrCodeLedgerJournalNotReversible},
+		{99, core.ErrCodeLedgerJournalNotFound},
+	}
+	for _, tc := range cases {
+		if _, err := service.journalService.Reverse(context.Background(), tc.id, &actor); appErrCode(err) != tc.want {
+			t.Errorf("journal %d: err = %v", tc.id, err)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package journals
+
+import (
+	"context"
+	"core-ledger/internal/core"
+	model "core-ledger/model/core-ledger"
+	wealify "core-ledger/model/wealify"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/repo"
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// RuleCategoryKindsOfRevenue mã rule category chứa danh sách loại doanh thu (value = mã tài khoản REV)
+const RuleCategoryKindsOfRevenue = "KINDS_OF_REVENUE"
+
+// FeeBreakdown chi tiết cách tính phí, lưu vào Entry.Meta["fee"] của các dòng phí
+type FeeBreakdown struct {
+	ScheduleID       string          `json:"schedule_id"`
+	ResolvedBy       string          `json:"resolved_by"`
+	TransactionType  string          `json:"transaction_type"`
+	Tier             string          `json:"tier,omitempty"`
+	Provider         string          `json:"provider,omitempty"`
+	Currency         string          `json:"currency"`
+	BaseAmount       decimal.Decimal `json:"base_amount"`
+	RangeID          string          `json:"range_id"`
+	RangeMin         decimal.Decimal `json:"range_min"`
+	RangeMax         decimal.Decimal `json:"range_max"`
+	FeeType          string          `json:"fee_type"`
+	FeeValue         decimal.Decimal `json:"fee_value"`
+	FeeAmount        decimal.Decimal `json:"fee_amount"`
+	RevenueKind      string          `json:"revenue_kind"`
+	RevenueAccountID uint64          `json:"revenue_account_id"`
+}
+
+func (b *FeeBreakdown) toMeta() map[string]any {
+	return map[string]any{
+		"schedule_id":        b.ScheduleID,
+		"resolved_by":        b.ResolvedBy,
+		"transaction_type":   b.TransactionType,
+		"tier":               b.Tier,
+		"provider":           b.Provider,
+		"currency":           b.Currency,
+		"base_amount":        b.BaseAmount.String(),
+		"range_id":           b.RangeID,
+		"range_min":          b.RangeMin.String(),
+		"range_max":          b.RangeMax.String(),
+		"fee_type":           b.FeeType,
+		"fee_value":          b.FeeValue.String(),
+		"fee_amount":         b.FeeAmount.String(),
+		"revenue_kind":       b.RevenueKind,
+		"revenue_account_id": b.RevenueAccountID,
+	}
+}
+
+type FeeService struct {
+	feeRepo       repo.FeeRepo
+	customerRepo  repo.CustomerRepo
+	ruleValueRepo repo.RuleValueRepo
+	coAccountRepo repo.CoAccountRepo
+	logger        logger.CustomLogger
+}
+
+func NewFeeService(feeRepo repo.FeeRepo, customerRepo repo.CustomerRepo, ruleValueRepo repo.RuleValueRepo, coAccountRepo repo.CoAccountRepo) *FeeService {
+	return &FeeService{
+		feeRepo:       feeRepo,
+		customerRepo:  customerRepo,
+		ruleValueRepo: ruleValueRepo,
+		coAccountRepo: coAccountRepo,
+		logger:        logger.NewSystemLog("FeeService"),
+	}
+}
+
+// BuildFeeLines tính phí và trả về 2 dòng bút toán: Nợ tài khoản công nợ khách hàng, Có tài khoản doanh thu.
+// Phí được làm tròn theo scale của currency. Trả về nil nếu phí bằng 0.
+func (s *FeeService) BuildFeeLines(ctx context.Context, currency *model.LedgerCurrency, req *FeeRequest) ([]*model.Entry, *FeeBreakdown, error) {
+	baseAmount, err := decimal.NewFromString(req.BaseAmount)
+	if err != nil || baseAmount.IsNegative() {
+		return nil, nil, core.NewError(core.ErrCodeLedgerInvalidAmount, "fee.base_amount không hợp lệ")
+	}
+
+	fee, breakdown, err := s.resolveSchedule(ctx, currency.Code, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ranges, err := s.feeRepo.ListRanges(ctx, fee.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	feeAmount, feeRange, err := core.CalculateTieredFee(ranges, baseAmount)
+	if err != nil {
+		return nil, nil, core.NewError(core.ErrCodeLedgerFeeRangeNotFound)
+	}
+	feeAmount = currency.Round(feeAmount)
+
+	breakdown.BaseAmount = baseAmount
+	breakdown.RangeID = feeRange.ID
+	breakdown.RangeMin = decimal.NewFromFloat(feeRange.Min)
+	breakdown.RangeMax = decimal.NewFromFloat(feeRange.Max)
+	breakdown.FeeType = feeRange.Type
+	breakdown.FeeValue = decimal.NewFromFloat(feeRange.Value)
+	breakdown.FeeAmount = feeAmount
+	if !feeAmount.IsPositive() {
+		return nil, breakdown, nil
+	}
+
+	revenueAccount, kind, err := s.resolveRevenueAccount(ctx, currency.Code, breakdown.TransactionType, req.RevenueKind)
+	if err != nil {
+		return nil, nil, err
+	}
+	atoms, err := currency.ToAtoms(feeAmount)
+	if err != nil {
+		return nil, nil, core.NewError(core.ErrCodeLedgerInvalidAmount, "fee: "+err.Error())
+	}
+	breakdown.RevenueKind = kind
+	breakdown.RevenueAccountID = revenueAccount.ID
+
+	memo := fmt.Sprintf("Fee %s", breakdown.TransactionType)
+	meta := map[string]any{"fee": breakdown.toMeta()}
+	lines := []*model.Entry{
+		{AccountID: req.CustomerAccountID, DC: "D", Amount: feeAmount, AmountAtoms: &atoms, Memo: &memo, Meta: meta},
+		{AccountID: revenueAccount.ID, DC: "C", Amount: feeAmount, AmountAtoms: &atoms, Memo: &memo, Meta: meta},
+	}
+	return lines, breakdown, nil
+}
+
+// resolveSchedule: ưu tiên schedule_id, sau đó biểu phí riêng của khách hàng, cuối cùng là biểu phí theo tier
+func (s *FeeService) resolveSchedule(ctx context.Context, currency string, req *FeeRequest) (*wealify.Fee, *FeeBreakdown, error) {
+	breakdown := &FeeBreakdown{Currency: currency, TransactionType: req.TransactionType}
+	if req.Provider != nil {
+		breakdown.Provider = *req.Provider
+	}
+
+	if req.ScheduleID != nil && *req.ScheduleID != "" {
+		fee, err := s.feeRepo.GetByUuid(ctx, *req.ScheduleID)
+		if err != nil {
+			return nil, nil, notFoundOr(err, core.ErrCodeLedgerFeeScheduleNotFound)
+		}
+		breakdown.ScheduleID = fee.ID
+		breakdown.ResolvedBy = "schedule_id"
+		breakdown.TransactionType = fee.TransactionType
+		breakdown.Tier = fee.Tier
+		breakdown.Provider = fee.Provider
+		return fee, breakdown, nil
+	}
+
+	if req.CustomerID == nil || req.TransactionType == "" {
+		return nil, nil, core.NewError(core.ErrCodeLedgerFeeScheduleNotFound, "cần schedule_id hoặc customer_id + transaction_type")
+	}
+	customer, err := s.customerRepo.GetByID(ctx, *req.CustomerID)
+	if err != nil {
+		return nil, nil, notFoundOr(err, core.ErrCodeLedgerFeeScheduleNotFound, "không tìm thấy khách hàng")
+	}
+	breakdown.Tier = customer.Tier.String()
+
+	q := repo.FeeQuery{
+		TransactionType: req.TransactionType,
+		Currency:        currency,
+		Provider:        breakdown.Provider,
+		Tier:            breakdown.Tier,
+	}
+	fee, err := s.feeRepo.FindForCustomer(ctx, customer.ID, q)
+	if err == nil {
+		breakdown.ScheduleID = fee.ID
+		breakdown.ResolvedBy = "customer"
+		return fee, breakdown, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+
+	fee, err = s.feeRepo.FindByTier(ctx, q)
+	if err != nil {
+		return nil, nil, notFoundOr(err, core.ErrCodeLedgerFeeScheduleNotFound)
+	}
+	breakdown.ScheduleID = fee.ID
+	breakdown.ResolvedBy = "tier"
+	return fee, breakdown, nil
+}
+
+// resolveRevenueAccount lấy tài khoản REV có code = value của rule KINDS_OF_REVENUE
+func (s *FeeService) resolveRevenueAccount(ctx context.Context, currency, transactionType string, revenueKind *string) (*model.CoaAccount, string, error) {
+	kind := transactionType
+	if revenueKind != nil && *revenueKind != "" {
+		kind = *revenueKind
+	}
+	ruleValue, err := s.ruleValueRepo.FindByCategoryCode(ctx, RuleCategoryKindsOfRevenue, kind)
+	if err != nil {
+		return nil, "", notFoundOr(err, core.ErrCodeLedgerRevenueKindNotFound)
+	}
+	account, err := s.coAccountRepo.GetOneByFields(ctx, map[string]interface{}{
+		"code":     ruleValue.Value,
+		"currency": currency,
+		"type":     "REV",
+	})
+	if err != nil {
+		return nil, "", notFoundOr(err, core.ErrCodeLedgerRevenueAccountNotFound)
+	}
+	return account, kind, nil
+}
+
+// notFoundOr chuyển gorm.ErrRecordNotFound thành AppError tương ứng, các lỗi khác giữ nguyên
+func notFoundOr(err error, code core.AppErrorCode, description ...string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return core.NewError(code, description...)
+	}
+	return err
+}
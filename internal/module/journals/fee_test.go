@@ -0,0 +1,93 @@
+package journals
+
+import (
+	"context"
+	"core-ledger/internal/core"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/repo"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// fakeRuleValues rule KINDS_OF_REVENUE theo value
+type fakeRuleValues struct {
+	repo.RuleValueRepo
+	values map[string]*model.RuleValue
+	err    error
+}
+
+func (f *fakeRuleValues) FindByCategoryCode(_ context.Context, categoryCode, value string) (*model.RuleValue, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if rule, ok := f.values[value]; ok && categoryCode == RuleCategoryKindsOfRevenue {
+		return rule, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fakeCoAccounts tài khoản theo code/currency/type
+type fakeCoAccounts struct {
+	repo.CoAccountRepo
+	accounts []*model.CoaAccount
+}
+
+func (f *fakeCoAccounts) GetOneByFields(_ context.Context, fields map[string]interface{}, _ ...string) (*model.CoaAccount, error) {
+	for _, a := range f.accounts {
+		if a.Code == fields["code"] && a.Currency == fields["currency"] && a.Type == fields["type"] {
+			return a, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestResolveRevenueAccount(t *testing.T) {
+	rules := &fakeRuleValues{values: map[string]*model.RuleValue{
+		"TOPUP":   {Value: "REV_TOPUP"},
+		"PREMIUM": {Value: "REV_PREMIUM"},
+		"ORPHAN":  {Value: "REV_MISSING"},
+	}}
+	accounts := &fakeCoAccounts{accounts: []*model.CoaAccount{
+		{ID: 1, Code: "REV_TOPUP", Currency: "USD", Type: "REV"},
+		{ID: 2, Code: "REV_PREMIUM", Currency: "USD", Type: "REV"},
+		{ID: 3, Code: "REV_TOPUP", Currency: "VND", Type: "ASSET"},
+	}}
+	service := NewFeeService(nil, nil, rules, accounts)
+
+	empty, premium, unknown, orphan := "", "PREMIUM", "UNKNOWN", "ORPHAN"
+	cases := []struct {
+		name     string
+		currency string
+		kind     *string
+		account  uint64
+		resolved string
+		want     core.AppErrorCode
+	}{
+		{"fallback to transaction type", "USD", nil, 1, "TOPUP", ""},
+		{"empty kind falls back", "USD", &empty, 1, "TOPUP", ""},
+		{"explicit kind", "USD", &premium, 2, "PREMIUM", ""},
+		{"kind not found", "USD", &unknown, 0, "", core.ErrCodeLedgerRevenueKindNotFound},
+		{"account not found", "USD", &orphan, 0, "", core.ErrCodeLedgerRevenueAccountNotFound},
+		// cùng code nhưng không phải REV / khác currency
+		{"account wrong type", "VND", nil, 0, "", core.ErrCodeLedgerRevenueAccountNotFound},
+	}
+	for _, tc := range cases {
+		account, kind, err := service.resolveRevenueAccount(context.Background(), tc.currency, "TOPUP", tc.kind)
+		if code := appErrCode(err); code != tc.want || (tc.want == "" && err != nil) {
+			t.Errorf("%s: err = %v", tc.name, err)
+			continue
+		}
+		if tc.want == "" && (account.ID != tc.account || kind != tc.resolved) {
+			t.Errorf("%s: account = %d kind = %s", tc.name, account.ID, kind)
+		}
+	}
+
+	// lỗi DB khác không bị đổi thành not found
+	dbErr := errors.New("connection reset")
+	rules.err = dbErr
+	if _, _, err := service.resolveRevenueAccount(context.Background(), "USD", "TOPUP", nil); !errors.Is(err, dbErr) {
+		t.Fatalf("db error: err = %v", err)
+	}
+}
@@ -0,0 +1,139 @@
+package journals
+
+import (
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/rbac"
+	"core-ledger/internal/module/validate"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/repo"
+	"core-ledger/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JournalHandler struct {
+	logger       logger.CustomLogger
+	service      *JournalService
+	batchService *BatchService
+	journalRepo  repo.JournalRepo
+}
+
+func NewJournalHandler(service *JournalService, batchService *BatchService, journalRepo repo.JournalRepo) *JournalHandler {
+	return &JournalHandler{
+		logger:       logger.NewSystemLog("JournalHandler"),
+		service:      service,
+		batchService: batchService,
+		journalRepo:  journalRepo,
+	}
+}
+
+// Post ghi sổ journal (kèm dòng phí tự động nếu có fee)
+func (h *JournalHandler) Post(c *gin.Context) {
+	var req PostJournalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	actor := rbac.Principal(c)
+	res, err := h.service.Post(c, &req, &actor)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+func (h *JournalHandler) Detail(c *gin.Context) {
+	id, err := utils.ParseIntIdParam(c.Param("id"))
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "Invalid id")
+		return
+	}
+	res, err := h.journalRepo.GetWithEntries(c, id)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusNotFound, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// Reverse đảo một journal đã ghi sổ
+func (h *JournalHandler) Reverse(c *gin.Context) {
+	id, err := utils.ParseIntIdParam(c.Param("id"))
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "Invalid id")
+		return
+	}
+	actor := rbac.Principal(c)
+	res, err := h.service.Reverse(c, id, &actor)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// PostBatch ghi sổ nhiều journal với cùng batch_id (ATOMIC hoặc BEST_EFFORT)
+func (h *JournalHandler) PostBatch(c *gin.Context) {
+	var req PostJournalBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	actor := rbac.Principal(c)
+	res, err := h.batchService.Post(c, &req, &actor)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// BatchDetail trạng thái và tổng số liệu của batch
+func (h *JournalHandler) BatchDetail(c *gin.Context) {
+	res, err := h.batchService.Detail(c, c.Param("id"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// ReverseBatch đảo toàn bộ journal của batch
+func (h *JournalHandler) ReverseBatch(c *gin.Context) {
+	actor := rbac.Principal(c)
+	res, err := h.batchService.Reverse(c, c.Param("id"), &actor)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// respondServiceError: AppError trả về theo chuẩn RespondOKWithError, lỗi hệ thống trả 500
+func respondServiceError(c *gin.Context, err error) {
+	var appErr *core.AppError
+	if errors.As(err, &appErr) {
+		ginhp.RespondOKWithError(c, appErr)
+		return
+	}
+	ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,136 @@
+package journals
+
+import (
+	"context"
+	"core-ledger/internal/module/currencies"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/repo"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// BenchmarkPostJournal đo số posting/giây trên DB thật (đã chạy migration).
+// Bỏ qua nếu không set LEDGER_BENCH_DSN, VD:
+//
+//	LEDGER_BENCH_DSN="host=localhost user=postgres password=postgres dbname=ledger port=5432 sslmode=disable" \
+//	  go test ./internal/module/journals -run '^$' -bench BenchmarkPostJournal -benchtime 5s
+//
+// hot-account: mọi posting cùng ghi vào 1 cặp tài khoản (tranh chấp 1 dòng account_balances).
+// spread-accounts: posting rải đều trên 64 cặp tài khoản.
+// Tài khoản, journal, entries và outbox event BENCH-* được xoá khi benchmark kết thúc.
+func BenchmarkPostJournal(b *testing.B) {
+	dsn := os.Getenv("LEDGER_BENCH_DSN")
+	if dsn == "" {
+		b.Skip("LEDGER_BENCH_DSN not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		b.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		b.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(64)
+	sqlDB.SetMaxIdleConns(64)
+
+	coAccountRepo := repo.NewCoAccountRepo(db)
+	currencyService := currencies.NewCurrencyService(repo.NewLedgerCurrencyRepo(db))
+	balanceService := NewBalanceService(coAccountRepo, repo.NewAccountBalanceRepo(db), repo.NewHoldRepo(db), currencyService)
+	service := NewJournalService(nil, db, repo.NewJournalRepo(db), coAccountRepo, nil, balanceService, currencyService)
+
+	b.Run("hot-account", func(b *testing.B) {
+		benchmarkPosting(b, db, service, 1)
+	})
+	b.Run("spread-accounts", func(b *testing.B) {
+		benchmarkPosting(b, db, service, 64)
+	})
+}
+
+func benchmarkPosting(b *testing.B, db *gorm.DB, service *JournalService, pairs int) {
+	prefix := fmt.Sprintf("BENCH-%d", time.Now().UnixNano())
+	var accountIDs []uint64
+	b.Cleanup(func() { cleanupBench(b, db, prefix, accountIDs) })
+	accounts := make([][2]uint64, pairs)
+	for i := range accounts {
+		cash := benchAccount(b, db, fmt.Sprintf("%s-ASSET-%d", prefix, i), "ASSET")
+		wallet := benchAccount(b, db, fmt.Sprintf("%s-LIAB-%d", prefix, i), "LIAB")
+		accounts[i] = [2]uint64{cash.ID, wallet.ID}
+		accountIDs = append(accountIDs, cash.ID, wallet.ID)
+	}
+
+	var seq int64
+	b.ResetTimer()
+	start := time.Now()
+	b.RunParallel(func(pb *testing.PB) {
+		ctx := context.Background()
+		for pb.Next() {
+			n := atomic.AddInt64(&seq, 1)
+			pair := accounts[int(n)%pairs]
+			_, err := service.Post(ctx, &PostJournalRequest{
+				IdempotencyKey: fmt.Sprintf("%s-%d", prefix, n),
+				Currency:       "USD",
+				Source:         "benchmark",
+				Lines: []*PostingLineRequest{
+					{AccountID: pair[0], DC: "D", Amount: "1.00"},
+					{AccountID: pair[1], DC: "C", Amount: "1.00"},
+				},
+			}, nil)
+			if err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+	b.ReportMetric(float64(b.N)/time.Since(start).Seconds(), "postings/s")
+}
+
+func benchAccount(b *testing.B, db *gorm.DB, code, accountType string) *model.CoaAccount {
+	account := &model.CoaAccount{
+		Code:          code,
+		AccountNo:     code,
+		Name:          code,
+		Type:          accountType,
+		Currency:      "USD",
+		Status:        "ACTIVE",
+		AllowNegative: true,
+	}
+	if err := db.Omit("Tags", "Metadata").Create(account).Error; err != nil {
+		b.Fatal(err)
+	}
+	return account
+}
+
+// cleanupBench xoá journal (kèm entries, outbox event) và tài khoản BENCH-* do benchmark tạo
+func cleanupBench(b *testing.B, db *gorm.DB, prefix string, accountIDs []uint64) {
+	journalIDs := db.Model(&model.Journal{}).Select("id").Where("idempotency_key LIKE ?", prefix+"-%")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("aggregate_type = ? AND aggregate_id IN (?)", model.AggregateTypeJournal, journalIDs).
+			Delete(&model.TransactionLog{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("journal_id IN (?)", journalIDs).Delete(&model.Entry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("idempotency_key LIKE ?", prefix+"-%").Delete(&model.Journal{}).Error; err != nil {
+			return err
+		}
+		if len(accountIDs) == 0 {
+			return nil
+		}
+		if err := tx.Where("account_id IN ?", accountIDs).Delete(&model.AccountBalance{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", accountIDs).Delete(&model.CoaAccount{}).Error
+	})
+	if err != nil {
+		b.Errorf("cleanup %s: %v", prefix, err)
+	}
+}
@@ -0,0 +1,78 @@
+package journals
+
+import (
+	"core-ledger/internal/core"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	"time"
+)
+
+type PostingLineRequest struct {
+	AccountID uint64         `json:"account_id" binding:"required"`
+	DC        string         `json:"dc" binding:"required,oneof=D C"`
+	Amount    string         `json:"amount" binding:"required"`
+	Memo      *string        `json:"memo,omitempty" binding:"omitempty,max=256"`
+	Meta      map[string]any `json:"meta,omitempty"`
+}
+
+// FeeRequest yêu cầu tự động sinh dòng phí (Nợ công nợ khách hàng / Có doanh thu).
+// Truyền ScheduleID để chỉ định biểu phí, hoặc CustomerID + TransactionType để tự resolve theo tier.
+type FeeRequest struct {
+	ScheduleID        *string `json:"schedule_id,omitempty"`
+	CustomerID        *int64  `json:"customer_id,omitempty"`
+	TransactionType   string  `json:"transaction_type,omitempty"`
+	Provider          *string `json:"provider,omitempty"`
+	BaseAmount        string  `json:"base_amount" binding:"required"`
+	CustomerAccountID uint64  `json:"customer_account_id" binding:"required"`
+	// RevenueKind giá trị trong rule category KINDS_OF_REVENUE, mặc định = TransactionType
+	RevenueKind *string `json:"revenue_kind,omitempty"`
+}
+
+type PostJournalRequest struct {
+	IdempotencyKey string                `json:"idempotency_key" binding:"required,max=191"`
+	Currency       string                `json:"currency" binding:"required,max=8"`
+	Source         string                `json:"source" binding:"required,max=64"`
+	Memo           *string               `json:"memo,omitempty" binding:"omitempty,max=256"`
+	Meta           map[string]any        `json:"meta,omitempty"`
+	Ts             *time.Time            `json:"ts,omitempty"`
+	TenantID       *string               `json:"tenant_id,omitempty" binding:"omitempty,max=36"`
+	LedgerCode     *string               `json:"ledger_code,omitempty" binding:"omitempty,max=32"`
+	Lines          []*PostingLineRequest `json:"lines" binding:"omitempty,dive"`
+	Fee            *FeeRequest           `json:"fee,omitempty"`
+	// CheckAvailableBalance từ chối ghi sổ nếu tài khoản bị giảm số dư không đủ số dư khả dụng (đã trừ hold)
+	CheckAvailableBalance bool `json:"check_available_balance,omitempty"`
+}
+
+// PostJournalBatchRequest ghi sổ nhiều journal trong một batch (cùng batch_id).
+// Mode ATOMIC (mặc định): tất cả cùng thành công hoặc rollback toàn bộ; BEST_EFFORT: ghi từng journal, trả kết quả từng item.
+type PostJournalBatchRequest struct {
+	Mode       string                `json:"mode,omitempty" binding:"omitempty,oneof=ATOMIC BEST_EFFORT"`
+	Source     string                `json:"source" binding:"required,max=64"`
+	Memo       *string               `json:"memo,omitempty" binding:"omitempty,max=256"`
+	Meta       map[string]any        `json:"meta,omitempty"`
+	TenantID   *string               `json:"tenant_id,omitempty" binding:"omitempty,max=36"`
+	LedgerCode *string               `json:"ledger_code,omitempty" binding:"omitempty,max=32"`
+	Journals   []*PostJournalRequest `json:"journals" binding:"required,min=1,max=10000,dive"`
+}
+
+const (
+	BatchItemPosted     = "POSTED"
+	BatchItemDuplicate  = "DUPLICATE"
+	BatchItemFailed     = "FAILED"
+	BatchItemRolledBack = "ROLLED_BACK"
+)
+
+// JournalBatchItemResult kết quả ghi sổ của từng journal trong batch (theo thứ tự request)
+type JournalBatchItemResult struct {
+	Index          int            `json:"index"`
+	IdempotencyKey string         `json:"idempotency_key"`
+	Status         string         `json:"status"`
+	JournalID      *uint64        `json:"journal_id,omitempty"`
+	Error          *core.AppError `json:"error,omitempty"`
+}
+
+type JournalBatchResponse struct {
+	Batch  *model.JournalBatch       `json:"batch"`
+	Items  []*JournalBatchItemResult `json:"items,omitempty"`
+	Totals []*dto.JournalBatchTotal  `json:"totals,omitempty"`
+}
@@ -0,0 +1,29 @@
+package journals
+
+import (
+	"core-ledger/internal/module/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerAPIRoutes(r *gin.RouterGroup, h *JournalHandler, middleware ...gin.HandlerFunc) {
+	// Apply middleware to the group if provided
+	tx := r.Group("journals", middleware...)
+	{
+		tx.POST("", rbac.Require(rbac.PermLedgerJournalPost), h.Post)
+		tx.POST("/batch", rbac.Require(rbac.PermLedgerJournalPost), h.PostBatch)
+		tx.GET("/:id", rbac.Require(rbac.PermLedgerJournalRead), h.Detail)
+		tx.POST("/:id/reverse", rbac.Require(rbac.PermLedgerJournalReverse), h.Reverse)
+	}
+
+	batches := r.Group("batches", middleware...)
+	{
+		batches.GET("/:id", rbac.Require(rbac.PermLedgerJournalRead), h.BatchDetail)
+		batches.POST("/:id/reverse", rbac.Require(rbac.PermLedgerJournalReverse), h.ReverseBatch)
+	}
+}
+
+// SetupRoutes registers journal routes with optional middleware
+func SetupRoutes(rg *gin.RouterGroup, h *JournalHandler, middleware ...gin.HandlerFunc) {
+	registerAPIRoutes(rg, h, middleware...)
+}
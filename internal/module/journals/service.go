@@ -0,0 +1,408 @@
+package journals
+
+import (
+	"context"
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/currencies"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/metrics"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/repo"
+	"core-ledger/pkg/tracing"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// operation label của metrics ghi sổ
+const (
+	operationJournal      = "journal"
+	operationBatch        = "batch"
+	operationBatchReverse = "batch_reverse"
+	operationReverse      = "reverse"
+)
+
+type JournalService struct {
+	db              *gorm.DB
+	journalRepo     repo.JournalRepo
+	coAccountRepo   repo.CoAccountRepo
+	feeService      *FeeService
+	balanceService  *BalanceService
+	currencyService *currencies.CurrencyService
+	logger          logger.CustomLogger
+	dispatcher      queue.Dispatcher
+}
+
+func NewJournalService(
+	dispatcher queue.Dispatcher,
+	db *gorm.DB,
+	journalRepo repo.JournalRepo,
+	coAccountRepo repo.CoAccountRepo,
+	feeService *FeeService,
+	balanceService *BalanceService,
+	currencyService *currencies.CurrencyService,
+) *JournalService {
+	return &JournalService{
+		db:              db,
+		journalRepo:     journalRepo,
+		coAccountRepo:   coAccountRepo,
+		feeService:      feeService,
+		balanceService:  balanceService,
+		currencyService: currencyService,
+		logger:          logger.NewSystemLog("JournalService"),
+		dispatcher:      dispatcher,
+	}
+}
+
+// Post ghi sổ một journal: kiểm tra cân Nợ/Có, tự động thêm dòng phí (nếu có),
+// lưu journal + entries + outbox event trong cùng một transaction.
+// Gọi lại với cùng idempotency_key sẽ trả về journal đã ghi trước đó.
+func (s *JournalService) Post(ctx context.Context, req *PostJournalRequest, postedBy *string) (*model.Journal, error) {
+	start := time.Now()
+	var journal *model.Journal
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		journal, err = s.PostTx(ctx, tx, req, postedBy)
+		return err
+	})
+	metrics.ObservePosting(operationJournal, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
+
+// PostTx giống Post nhưng chạy trong transaction của caller (VD: capture hold ghi sổ cùng lúc cập nhật hold)
+func (s *JournalService) PostTx(ctx context.Context, tx *gorm.DB, req *PostJournalRequest, postedBy *string) (*model.Journal, error) {
+	p, err := s.write(ctx, tx, req, postedBy, nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.existing {
+		return p.journal, nil
+	}
+	if err := s.balanceService.ApplyEntries(ctx, tx, p.accounts, p.entries, availableCheck(p.accounts, req.CheckAvailableBalance)); err != nil {
+		return nil, err
+	}
+	s.logger.WithContext(ctx).Info("Journal posted", p.journal.ID, p.journal.IdempotencyKey)
+	return p.journal, nil
+}
+
+// Reverse đảo một journal POSTED (không thuộc batch, journal trong batch đảo qua BatchService.Reverse):
+// ghi journal đảo, chuyển journal gốc sang REVERSED và cộng số dư trong cùng một transaction.
+func (s *JournalService) Reverse(ctx context.Context, id int64, reversedBy *string) (*model.Journal, error) {
+	start := time.Now()
+	var journal *model.Journal
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		original, err := s.journalRepo.WithTx(tx).LockWithEntries(ctx, id)
+		if err != nil {
+			return notFoundOr(err, core.ErrCodeLedgerJournalNotFound)
+		}
+		if original.Status != model.JournalStatusPosted || original.ReversalOfID != nil || original.BatchID != nil {
+			return core.NewError(core.ErrCodeLedgerJournalNotReversible,
+				fmt.Sprintf("journal %d không thể đảo (status %s)", original.ID, original.Status))
+		}
+		p, err := s.reverse(ctx, tx, original, reversedBy, nil)
+		if err != nil {
+			return err
+		}
+		if err := s.balanceService.ApplyEntries(ctx, tx, p.accounts, p.entries, nil); err != nil {
+			return err
+		}
+		journal = p.journal
+		return nil
+	})
+	metrics.ObservePosting(operationReverse, start, err)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.WithContext(ctx).Info("Journal reversed", id, journal.ID)
+	return journal, nil
+}
+
+// posting journal đã ghi (journal + entries + outbox) nhưng chưa cộng vào account_balances,
+// để batch cộng số dư một lần cho toàn bộ journal
+type posting struct {
+	journal  *model.Journal
+	entries  []*model.Entry
+	accounts map[uint64]*model.CoaAccount
+	// existing = true: idempotency_key đã được ghi sổ trước đó, journal là bản ghi cũ
+	existing bool
+}
+
+// write kiểm tra và lưu journal + entries + outbox event trong tx, batchID != nil để gắn journal vào batch
+func (s *JournalService) write(ctx context.Context, tx *gorm.DB, req *PostJournalRequest, postedBy *string, batchID *string) (*posting, error) {
+	existing, err := s.journalRepo.GetByIdempotencyKey(ctx, req.IdempotencyKey)
+	if err == nil {
+		return &posting{journal: existing, existing: true}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	currency, err := s.currencyService.Get(ctx, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.buildEntries(currency, req.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Fee != nil {
+		feeLines, breakdown, err := s.feeService.BuildFeeLines(ctx, currency, req.Fee)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, feeLines...)
+		if breakdown != nil {
+			if req.Meta == nil {
+				req.Meta = map[string]any{}
+			}
+			req.Meta["fee_amount"] = breakdown.FeeAmount.String()
+			req.Meta["fee_schedule_id"] = breakdown.ScheduleID
+		}
+	}
+
+	accounts, err := s.validateEntries(ctx, currency, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	ts := now
+	if req.Ts != nil {
+		ts = *req.Ts
+	}
+	journal := &model.Journal{
+		Ts:             ts,
+		Status:         model.JournalStatusPosted,
+		IdempotencyKey: req.IdempotencyKey,
+		Currency:       currency.Code,
+		Source:         req.Source,
+		Memo:           req.Memo,
+		Meta:           req.Meta,
+		PostedBy:       postedBy,
+		PostedAt:       &now,
+		TenantID:       req.TenantID,
+		LedgerCode:     req.LedgerCode,
+		BatchID:        batchID,
+	}
+	if err := s.save(tx, model.EventLedgerPosted, journal, entries); err != nil {
+		return nil, err
+	}
+	return &posting{journal: journal, entries: entries, accounts: accounts}, nil
+}
+
+// reverse ghi journal đảo (đổi chiều Nợ/Có toàn bộ entries) cho original và chuyển original sang REVERSED.
+// original phải được đọc kèm Entries trong tx của caller.
+func (s *JournalService) reverse(ctx context.Context, tx *gorm.DB, original *model.Journal, postedBy *string, batchID *string) (*posting, error) {
+	if original.Status != model.JournalStatusPosted || original.ReversalOfID != nil {
+		return nil, fmt.Errorf("journal %d không thể đảo (status %s)", original.ID, original.Status)
+	}
+
+	ids := make([]uint64, 0, len(original.Entries))
+	entries := make([]*model.Entry, 0, len(original.Entries))
+	for _, e := range original.Entries {
+		dc := "D"
+		if e.DC == "D" {
+			dc = "C"
+		}
+		ids = append(ids, e.AccountID)
+		entries = append(entries, &model.Entry{
+			AccountID:   e.AccountID,
+			DC:          dc,
+			Amount:      e.Amount,
+			AmountAtoms: e.AmountAtoms,
+			Memo:        e.Memo,
+			Meta:        e.Meta,
+		})
+	}
+	list, err := s.coAccountRepo.GetManyByFields(ctx, map[string]interface{}{"id": ids})
+	if err != nil {
+		return nil, err
+	}
+	accounts := make(map[uint64]*model.CoaAccount, len(list))
+	for _, a := range list {
+		accounts[a.ID] = a
+	}
+
+	now := time.Now()
+	memo := fmt.Sprintf("Reversal of journal %d", original.ID)
+	journal := &model.Journal{
+		Ts:             now,
+		Status:         model.JournalStatusPosted,
+		IdempotencyKey: fmt.Sprintf("reversal:%d", original.ID),
+		Currency:       strings.TrimSpace(original.Currency),
+		Source:         original.Source,
+		Memo:           &memo,
+		ReversalOfID:   &original.ID,
+		PostedBy:       postedBy,
+		PostedAt:       &now,
+		TenantID:       original.TenantID,
+		LedgerCode:     original.LedgerCode,
+		BatchID:        batchID,
+	}
+	if err := s.save(tx, model.EventLedgerReversed, journal, entries); err != nil {
+		return nil, err
+	}
+	err = tx.Model(original).Updates(map[string]interface{}{
+		"status":       model.JournalStatusReversed,
+		"lock_version": gorm.Expr("lock_version + 1"),
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	original.Status = model.JournalStatusReversed
+	return &posting{journal: journal, entries: entries, accounts: accounts}, nil
+}
+
+// save lưu journal, entries và outbox event, gán lại journal.Entries cho response
+func (s *JournalService) save(tx *gorm.DB, eventType string, journal *model.Journal, entries []*model.Entry) error {
+	if err := tx.Create(journal).Error; err != nil {
+		return err
+	}
+	for i, e := range entries {
+		e.JournalID = journal.ID
+		e.LineNo = i + 1
+		e.TenantID = journal.TenantID
+		e.LedgerCode = journal.LedgerCode
+		e.BatchID = journal.BatchID
+	}
+	if err := tx.Create(&entries).Error; err != nil {
+		return err
+	}
+	event := newOutboxEvent(eventType, journal, entries)
+	event.Headers = tracing.Headers(tx.Statement.Context)
+	if err := tx.Create(event).Error; err != nil {
+		return err
+	}
+	journal.Entries = make([]model.Entry, 0, len(entries))
+	for _, e := range entries {
+		journal.Entries = append(journal.Entries, *e)
+	}
+	return nil
+}
+
+// validateEntries kiểm tra tối thiểu 2 dòng, tài khoản tồn tại/ACTIVE/cùng currency và tổng Nợ = tổng Có.
+// Cân Nợ/Có so sánh theo amount_atoms (số nguyên) nên không bị sai số thập phân.
+func (s *JournalService) validateEntries(ctx context.Context, currency *model.LedgerCurrency, entries []*model.Entry) (map[uint64]*model.CoaAccount, error) {
+	if len(entries) < 2 {
+		return nil, core.NewError(core.ErrCodeLedgerJournalUnbalanced, "journal cần tối thiểu 2 dòng")
+	}
+
+	ids := make([]uint64, 0, len(entries))
+	var debit, credit int64
+	for _, e := range entries {
+		ids = append(ids, e.AccountID)
+		if e.AmountAtoms == nil {
+			return nil, fmt.Errorf("entry account %d thiếu amount_atoms", e.AccountID)
+		}
+		total := &credit
+		if e.DC == "D" {
+			total = &debit
+		}
+		if *total > math.MaxInt64-*e.AmountAtoms {
+			return nil, core.NewError(core.ErrCodeLedgerInvalidAmount, "tổng số tiền vượt quá giới hạn amount_atoms")
+		}
+		*total += *e.AmountAtoms
+	}
+	if debit != credit {
+		return nil, core.NewError(core.ErrCodeLedgerJournalUnbalanced,
+			fmt.Sprintf("tổng Nợ %s khác tổng Có %s", currency.Format(currency.FromAtoms(debit)), currency.Format(currency.FromAtoms(credit))))
+	}
+
+	accounts, err := s.coAccountRepo.GetManyByFields(ctx, map[string]interface{}{"id": ids})
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint64]*model.CoaAccount, len(accounts))
+	for _, a := range accounts {
+		byID[a.ID] = a
+	}
+	for _, id := range ids {
+		account, ok := byID[id]
+		if !ok {
+			return nil, core.NewError(core.ErrCodeLedgerAccountNotFound, fmt.Sprintf("account %d không tồn tại", id))
+		}
+		if account.Status != "ACTIVE" {
+			return nil, core.NewError(core.ErrCodeLedgerAccountInactive, fmt.Sprintf("account %s đang INACTIVE", account.Code))
+		}
+		if strings.TrimSpace(account.Currency) != currency.Code {
+			return nil, core.NewError(core.ErrCodeLedgerCurrencyMismatch, fmt.Sprintf("account %s dùng %s", account.Code, account.Currency))
+		}
+	}
+	return byID, nil
+}
+
+// buildEntries parse số tiền từng dòng, từ chối số tiền vượt quá scale của currency và điền amount_atoms
+func (s *JournalService) buildEntries(currency *model.LedgerCurrency, lines []*PostingLineRequest) ([]*model.Entry, error) {
+	entries := make([]*model.Entry, 0, len(lines)+2)
+	for i, l := range lines {
+		amount, err := decimal.NewFromString(l.Amount)
+		if err != nil || !amount.IsPositive() {
+			return nil, core.NewError(core.ErrCodeLedgerInvalidAmount, fmt.Sprintf("lines.%d.amount không hợp lệ", i))
+		}
+		atoms, err := s.currencyService.ToAtoms(currency, amount, fmt.Sprintf("lines.%d.amount", i))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &model.Entry{
+			AccountID:   l.AccountID,
+			DC:          l.DC,
+			Amount:      amount,
+			AmountAtoms: &atoms,
+			Memo:        l.Memo,
+			Meta:        l.Meta,
+		})
+	}
+	return entries, nil
+}
+
+// newOutboxEvent tạo bản ghi outbox cho journal vừa ghi sổ
+func newOutboxEvent(eventType string, journal *model.Journal, entries []*model.Entry) *model.TransactionLog {
+	lines := make([]map[string]any, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, map[string]any{
+			"line_no":    e.LineNo,
+			"account_id": e.AccountID,
+			"dc":         e.DC,
+			"amount":     e.Amount.String(),
+			"atoms":      e.AmountAtoms,
+		})
+	}
+	tenantID := ""
+	if journal.TenantID != nil {
+		tenantID = *journal.TenantID
+	}
+	partitionKey := journal.Currency
+	if journal.LedgerCode != nil {
+		partitionKey = *journal.LedgerCode
+	}
+	return &model.TransactionLog{
+		AggregateType: model.AggregateTypeJournal,
+		AggregateID:   journal.ID,
+		EventType:     eventType,
+		EventKey:      fmt.Sprintf("%s:%d", eventType, journal.ID),
+		PartitionKey:  partitionKey,
+		Payload: map[string]any{
+			"journal_id":      journal.ID,
+			"idempotency_key": journal.IdempotencyKey,
+			"status":          journal.Status,
+			"currency":        journal.Currency,
+			"source":          journal.Source,
+			"ts":              journal.Ts,
+			"reversal_of":     journal.ReversalOfID,
+			"lines":           lines,
+		},
+		Status:     model.TransactionLogStatusPending,
+		TenantID:   tenantID,
+		LedgerCode: journal.LedgerCode,
+	}
+}
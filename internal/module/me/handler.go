@@ -0,0 +1,51 @@
+package me
+
+import (
+	"core-ledger/internal/module/middleware"
+	"core-ledger/internal/module/rbac"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MeHandler struct {
+	logger logger.CustomLogger
+}
+
+func NewMeHandler() *MeHandler {
+	return &MeHandler{
+		logger: logger.NewSystemLog("MeHandler"),
+	}
+}
+
+type PermissionsResponse struct {
+	EmployeeID   int64    `json:"employee_id,omitempty"`
+	ApiKeyID     uint64   `json:"api_key_id,omitempty"`
+	OwnerService string   `json:"owner_service,omitempty"`
+	Permissions  []string `json:"permissions"`
+}
+
+// Permissions danh sách permission của nhân viên đang đăng nhập (đã gồm permission con),
+// request xác thực bằng X-API-Key thì trả về scopes của key
+func (h *MeHandler) Permissions(c *gin.Context) {
+	res := PermissionsResponse{
+		Permissions: rbac.GetPermissions(c).List(),
+	}
+	if apiKey := rbac.GetApiKey(c); apiKey != nil {
+		res.ApiKeyID = apiKey.ID
+		res.OwnerService = apiKey.OwnerService
+	} else {
+		employeeID, err := middleware.GetUserIDFromContext(c)
+		if err != nil {
+			ginhp.RespondError(c, http.StatusUnauthorized, err.Error())
+			return
+		}
+		res.EmployeeID = employeeID
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
@@ -0,0 +1,18 @@
+package me
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func registerAPIRoutes(r *gin.RouterGroup, h *MeHandler, middleware ...gin.HandlerFunc) {
+	// Apply middleware to the group if provided
+	tx := r.Group("me", middleware...)
+	{
+		tx.GET("/permissions", h.Permissions)
+	}
+}
+
+// SetupRoutes registers routes of the logged-in employee with optional middleware
+func SetupRoutes(rg *gin.RouterGroup, h *MeHandler, middleware ...gin.HandlerFunc) {
+	registerAPIRoutes(rg, h, middleware...)
+}
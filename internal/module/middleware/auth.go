@@ -7,18 +7,16 @@ import (
 	"core-ledger/model/dto"
 	"core-ledger/pkg/ginhp"
 	"core-ledger/pkg/logger"
+	"core-ledger/pkg/logging"
 	"core-ledger/pkg/repo"
 	"core-ledger/pkg/utils/fingerprint"
 	"errors"
 	"fmt"
 	"net/http"
-	"os"
 	"runtime/debug"
 	"strings"
 	"time"
 
-	"github.com/sirupsen/logrus"
-
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/mssola/user_agent"
@@ -97,49 +95,63 @@ func (m *middleware) ExtractFingerprint(c *gin.Context) {
 // AuthMiddleware creates a new Gin middleware for JWT authentication.
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenString, err := extractTokenFromHeader(c)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			c.Abort()
+		if !authenticate(c) {
 			return
 		}
+		c.Next()
+	}
+}
 
-		claims := &dto.Claims{}
+// authenticate kiểm tra JWT và set userID/isEmployee vào context, trả về false (đã abort) nếu token không hợp lệ.
+// Không gọi c.Next() để các middleware khác có thể kiểm tra thêm trước khi chạy handler.
+func authenticate(c *gin.Context) bool {
+	tokenString, err := extractTokenFromHeader(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		c.Abort()
+		return false
+	}
 
-		jwtSecret := config.GetConfig().JWT.Secret
-		if jwtSecret == "" {
-			jwtSecret = "default-secret-key"
-		}
+	claims := &dto.Claims{}
 
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
+	// không có secret mặc định: thiếu JWT_SECRET (chỉ được phép ở MODE=development) thì từ chối mọi token
+	jwtSecret := config.GetConfig().JWT.Secret
+	if jwtSecret == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "JWT_SECRET is not configured"})
+		c.Abort()
+		return false
+	}
 
-		if err != nil {
-			if errors.Is(err, jwt.ErrSignatureInvalid) {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token signature"})
-				c.Abort()
-				return
-			}
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Bad request"})
-			c.Abort()
-			return
-		}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(jwtSecret), nil
+	}, jwtValidMethods)
 
-		if !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+	if err != nil {
+		if errors.Is(err, jwt.ErrSignatureInvalid) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token signature"})
 			c.Abort()
-			return
+			return false
 		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		c.Abort()
+		return false
+	}
 
-		// Set user information in the context for downstream handlers
-		c.Set("userID", claims.ID)
-		c.Set("isEmployee", claims.IsEmployee)
-
-		c.Next()
+	if !token.Valid {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		c.Abort()
+		return false
 	}
+
+	// Set user information in the context for downstream handlers
+	c.Set("userID", claims.ID)
+	c.Set("isEmployee", claims.IsEmployee)
+	return true
 }
 
+// jwtValidMethods chỉ nhận token ký HMAC bằng JWT_SECRET
+var jwtValidMethods = jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodHS384.Alg(), jwt.SigningMethodHS512.Alg()})
+
 func extractTokenFromHeader(c *gin.Context) (string, error) {
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
@@ -172,24 +184,29 @@ func GetUserIDFromContext(c *gin.Context) (int64, error) {
 // EmployeeAuthMiddleware checks for a valid JWT and ensures the user is an employee.
 func EmployeeAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// First, run the standard authentication check.
-		AuthMiddleware()(c)
-
-		// If the standard auth middleware aborted, c.IsAborted() will be true.
-		if c.IsAborted() {
+		if !AuthenticateEmployee(c) {
 			return
 		}
+		c.Next()
+	}
+}
 
-		// Now, check if the user is an employee.
-		isEmployee, exists := c.Get("isEmployee")
-		if !exists || !isEmployee.(bool) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Employee access required"})
-			c.Abort()
-			return
-		}
+// AuthenticateEmployee kiểm tra JWT và yêu cầu user là nhân viên, trả về false (đã abort) nếu không hợp lệ.
+// Không gọi c.Next(), dùng khi cần kết hợp với cách xác thực khác (VD: X-API-Key).
+func AuthenticateEmployee(c *gin.Context) bool {
+	// First, run the standard authentication check.
+	if !authenticate(c) {
+		return false
+	}
 
-		c.Next()
+	// Now, check if the user is an employee.
+	isEmployee, exists := c.Get("isEmployee")
+	if !exists || !isEmployee.(bool) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Forbidden: Employee access required"})
+		c.Abort()
+		return false
 	}
+	return true
 }
 
 type CustomResponseWriter struct {
@@ -228,14 +245,18 @@ func (m *middleware) Authorize(c *gin.Context) {
 			ginhp.RespondError(c, http.StatusUnauthorized, "invalid token paths")
 			return
 		}
+		// cùng cấu hình với authenticate: thiếu JWT_SECRET thì từ chối, không ký/kiểm tra bằng secret rỗng
+		jwtSecret := config.GetConfig().JWT.Secret
+		if jwtSecret == "" {
+			ginhp.RespondError(c, http.StatusUnauthorized, "JWT_SECRET is not configured")
+			return
+		}
 		claims := &dto.Claims{}
-		token, err := jwt.ParseWithClaims(parts[1], claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(os.Getenv("JWT_SECRET")), nil
-		})
-		logrus.Info(*claims)
-		logrus.Info(token)
+		_, err := jwt.ParseWithClaims(parts[1], claims, func(token *jwt.Token) (interface{}, error) {
+			return []byte(jwtSecret), nil
+		}, jwtValidMethods)
 		if err != nil {
-			logrus.Info(err)
+			logging.FromContext(c).Infow("invalid bearer token", "error", err)
 			ginhp.RespondError(c, http.StatusUnauthorized, "Unauthorized")
 			return
 		}
@@ -40,7 +40,7 @@ func LogRequest(c *gin.Context) {
 	sugaredLogger := logging.From(ctx).Named("http")
 
 	// Attach logger to context (minimal overhead)
-	c.Request = c.Request.WithContext(logging.WithLogger(ctx, sugaredLogger.With("request_id", requestID)))
+	c.Request = c.Request.WithContext(logging.WithLogger(ctx, sugaredLogger.With(logging.FieldRequestID, requestID)))
 
 	c.Next()
 
@@ -91,7 +91,7 @@ func LogRequest(c *gin.Context) {
 
 	// Add optional fields only if they exist
 	if requestID != "" {
-		fields = append(fields, zap.String("request_id", requestID))
+		fields = append(fields, zap.String(logging.FieldRequestID, requestID))
 	}
 	if ip := c.ClientIP(); ip != "" {
 		fields = append(fields, zap.String("remote_ip", ip))
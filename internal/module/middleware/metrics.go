@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	config "core-ledger/configs"
+	"core-ledger/pkg/metrics"
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute label route cho request không khớp route nào (404), tránh dùng path thật làm label
+const unmatchedRoute = "unmatched"
+
+// Metrics ghi nhận latency/status của request theo route template của gin
+func Metrics(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+
+	route := c.FullPath()
+	if route == "" {
+		route = unmatchedRoute
+	}
+	metrics.ObserveHTTP(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+}
+
+// MetricsHandler endpoint Prometheus, bắt buộc bearer token METRICS_TOKEN (token rỗng thì luôn 401)
+func MetricsHandler(cfg *config.MetricsConfig) gin.HandlerFunc {
+	handler := metrics.Handler()
+	expected := []byte("Bearer " + cfg.Token)
+	return func(c *gin.Context) {
+		if cfg.Token == "" || subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), expected) != 1 {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(c.Writer, c.Request)
+	}
+}
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	config "core-ledger/configs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// Tracing mở span server cho mỗi request (tên span là route template của gin), nối tiếp traceparent của client.
+// Bỏ qua health check và endpoint metrics để không sinh trace rác.
+func Tracing(cfg *config.TracingConfig) gin.HandlerFunc {
+	metricsPath := config.GetMetricsConfig().Path
+	return otelgin.Middleware(cfg.ServiceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return r.URL.Path != "/health" && r.URL.Path != metricsPath
+	}))
+}
@@ -4,11 +4,10 @@ import (
 	"bytes"
 	config "core-ledger/configs"
 	"core-ledger/model/dto"
+	"core-ledger/pkg/logging"
 	"encoding/json"
 	"time"
 
-	log "github.com/sirupsen/logrus"
-
 	"github.com/gin-gonic/gin"
 )
 
@@ -24,7 +23,7 @@ func WrapResponse(c *gin.Context) {
 	var originalArrInterface []any
 	var message string
 	if err := json.Unmarshal(blw.body.Bytes(), &original); err != nil {
-		log.Error(err)
+		logging.FromContext(c).Errorw("wrap response: decode body", "error", err)
 	}
 	if original == nil {
 		_ = json.Unmarshal(blw.body.Bytes(), &originalArrInterface)
@@ -0,0 +1,85 @@
+package queuemonitor
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/module/rbac"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/queue"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hibiken/asynq"
+	"github.com/hibiken/asynqmon"
+	"go.uber.org/fx"
+)
+
+const (
+	// DashboardPath đường dẫn asynqmon trên router chính (ngoài /api/v2 vì là giao diện web)
+	DashboardPath = "/admin/queues"
+	// SessionCookie cookie giữ JWT nhân viên cho dashboard, trình duyệt không tự gửi header Authorization
+	SessionCookie = "ledger_queues_token"
+)
+
+// Dashboard asynqmon dùng Redis của QueueConfig: bản đầy đủ cho người có queue.manage,
+// bản read-only (ẩn nút xoá/chạy lại, chặn request ghi) cho người chỉ có queue.read
+type Dashboard struct {
+	full     *asynqmon.HTTPHandler
+	readOnly *asynqmon.HTTPHandler
+}
+
+// NewDashboard tạo dashboard khi QUEUE_DRIVER=asynq, driver sync/memory không có Redis để xem
+func NewDashboard(lc fx.Lifecycle, cfg *config.QueueConfig) *Dashboard {
+	if cfg.Driver != queue.DriverAsynq {
+		return &Dashboard{}
+	}
+	redisOpt := asynq.RedisClientOpt{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	}
+	d := &Dashboard{
+		full:     asynqmon.New(asynqmon.Options{RootPath: DashboardPath, RedisConnOpt: redisOpt}),
+		readOnly: asynqmon.New(asynqmon.Options{RootPath: DashboardPath, RedisConnOpt: redisOpt, ReadOnly: true}),
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(_ context.Context) error {
+			return errors.Join(d.full.Close(), d.readOnly.Close())
+		},
+	})
+	return d
+}
+
+// Serve chuyển request cho asynqmon theo permission đã nạp ở middleware xác thực
+func (d *Dashboard) Serve(c *gin.Context) {
+	if d.full == nil {
+		ginhp.RespondError(c, http.StatusServiceUnavailable, "queue dashboard requires QUEUE_DRIVER=asynq")
+		return
+	}
+	handler := d.readOnly
+	if rbac.GetPermissions(c).Has(rbac.PermQueueManage) {
+		handler = d.full
+	}
+	handler.ServeHTTP(c.Writer, c.Request)
+}
+
+// SessionToken dùng JWT trong SessionCookie khi request không có Authorization/X-API-Key, chạy trước middleware xác thực
+func SessionToken(c *gin.Context) {
+	if c.GetHeader("Authorization") == "" && c.GetHeader(rbac.HeaderApiKey) == "" {
+		if token, err := c.Cookie(SessionCookie); err == nil && token != "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	c.Next()
+}
+
+// requireManageForWrites request ghi (xoá, chạy lại, tạm dừng queue...) cần queue.manage
+func requireManageForWrites(c *gin.Context) {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead:
+		c.Next()
+	default:
+		rbac.Require(rbac.PermQueueManage)(c)
+	}
+}
@@ -0,0 +1,64 @@
+package queuemonitor
+
+import (
+	config "core-ledger/configs"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QueueMonitorHandler struct {
+	logger    logger.CustomLogger
+	service   *QueueMonitorService
+	dashboard *Dashboard
+}
+
+func NewQueueMonitorHandler(service *QueueMonitorService, dashboard *Dashboard) *QueueMonitorHandler {
+	return &QueueMonitorHandler{
+		logger:    logger.NewSystemLog("QueueMonitorHandler"),
+		service:   service,
+		dashboard: dashboard,
+	}
+}
+
+// Overview số task theo queue, backlog outbox, số job lỗi và link tới dashboard/API quản lý
+func (h *QueueMonitorHandler) Overview(c *gin.Context) {
+	res, err := h.service.Overview(c)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// CreateSession lưu JWT của request vào cookie HttpOnly (chỉ gửi kèm DashboardPath) để mở dashboard trên trình duyệt
+func (h *QueueMonitorHandler) CreateSession(c *gin.Context) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		ginhp.RespondError(c, http.StatusBadRequest, "dashboard session requires an employee bearer token")
+		return
+	}
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(SessionCookie, token, 0, DashboardPath, "", !config.GetConfig().Common.IsDevelopment(), true)
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: SessionResponse{Dashboard: DashboardPath},
+	})
+}
+
+// DeleteSession xoá cookie dashboard
+func (h *QueueMonitorHandler) DeleteSession(c *gin.Context) {
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(SessionCookie, "", -1, DashboardPath, "", !config.GetConfig().Common.IsDevelopment(), true)
+	c.Status(http.StatusNoContent)
+}
+
+// Dashboard giao diện asynqmon
+func (h *QueueMonitorHandler) Dashboard(c *gin.Context) {
+	h.dashboard.Serve(c)
+}
@@ -0,0 +1,51 @@
+package queuemonitor
+
+import "time"
+
+// QueueStats số task theo trạng thái của một queue asynq
+type QueueStats struct {
+	Queue     string `json:"queue"`
+	Size      int    `json:"size"`
+	Pending   int    `json:"pending"`
+	Active    int    `json:"active"`
+	Scheduled int    `json:"scheduled"`
+	Retry     int    `json:"retry"`
+	Archived  int    `json:"archived"`
+	Completed int    `json:"completed"`
+	Paused    bool   `json:"paused"`
+	// LatencySeconds tuổi của task pending cũ nhất
+	LatencySeconds float64 `json:"latency_seconds"`
+}
+
+// OutboxBacklogResponse event outbox (transaction_logs) chưa publish theo status
+type OutboxBacklogResponse struct {
+	Status           string     `json:"status"`
+	Total            int64      `json:"total"`
+	OldestAt         *time.Time `json:"oldest_at,omitempty"`
+	OldestAgeSeconds float64    `json:"oldest_age_seconds"`
+}
+
+// OverviewLinks đường dẫn tới dashboard và các API quản lý queue/outbox
+type OverviewLinks struct {
+	Dashboard  string `json:"dashboard"`
+	Session    string `json:"session"`
+	FailedJobs string `json:"failed_jobs"`
+	Schedules  string `json:"schedules"`
+	Jobs       string `json:"jobs"`
+}
+
+// OverviewResponse tổng quan queue + outbox + job lỗi, trang chính của phần vận hành queue
+type OverviewResponse struct {
+	Driver string       `json:"driver"`
+	Queues []QueueStats `json:"queues"`
+	// QueueError lỗi đọc Redis, các phần đọc từ DB vẫn được trả về
+	QueueError string                  `json:"queue_error,omitempty"`
+	Outbox     []OutboxBacklogResponse `json:"outbox"`
+	FailedJobs map[string]int64        `json:"failed_jobs"`
+	Links      OverviewLinks           `json:"links"`
+}
+
+// SessionResponse kết quả tạo session cho dashboard
+type SessionResponse struct {
+	Dashboard string `json:"dashboard"`
+}
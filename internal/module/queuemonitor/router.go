@@ -0,0 +1,33 @@
+package queuemonitor
+
+import (
+	"core-ledger/internal/module/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerAPIRoutes(r *gin.RouterGroup, h *QueueMonitorHandler, middleware ...gin.HandlerFunc) {
+	// Apply middleware to the group if provided
+	tx := r.Group("admin/queues", middleware...)
+	{
+		tx.GET("/overview", rbac.RequireAny(rbac.PermQueueRead, rbac.PermQueueManage), h.Overview)
+		tx.POST("/session", rbac.RequireAny(rbac.PermQueueRead, rbac.PermQueueManage), h.CreateSession)
+		tx.DELETE("/session", h.DeleteSession)
+	}
+}
+
+// SetupRoutes registers queue overview and dashboard session routes with optional middleware
+func SetupRoutes(rg *gin.RouterGroup, h *QueueMonitorHandler, middleware ...gin.HandlerFunc) {
+	registerAPIRoutes(rg, h, middleware...)
+}
+
+// SetupDashboardRoutes mounts asynqmon at DashboardPath; middleware must authenticate and load permissions
+func SetupDashboardRoutes(r *gin.Engine, h *QueueMonitorHandler, middleware ...gin.HandlerFunc) {
+	handlers := append([]gin.HandlerFunc{SessionToken}, middleware...)
+	handlers = append(handlers,
+		rbac.RequireAny(rbac.PermQueueRead, rbac.PermQueueManage),
+		requireManageForWrites,
+		h.Dashboard,
+	)
+	r.Any(DashboardPath+"/*path", handlers...)
+}
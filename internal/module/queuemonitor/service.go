@@ -0,0 +1,104 @@
+package queuemonitor
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/repo"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// apiPrefix prefix của các API link tới trong overview, khớp group /api/v2 ở router chính
+const apiPrefix = "/api/v2"
+
+// QueueMonitorService số liệu tổng quan cho trang vận hành queue: queue asynq, backlog outbox, job lỗi
+type QueueMonitorService struct {
+	transactionLogRepo repo.TransactionLogRepo
+	failedJobRepo      repo.FailedJobRepo
+	inspector          *asynq.Inspector
+	cfg                *config.QueueConfig
+	logger             logger.CustomLogger
+}
+
+func NewQueueMonitorService(transactionLogRepo repo.TransactionLogRepo, failedJobRepo repo.FailedJobRepo, inspector *asynq.Inspector, cfg *config.QueueConfig) *QueueMonitorService {
+	return &QueueMonitorService{
+		transactionLogRepo: transactionLogRepo,
+		failedJobRepo:      failedJobRepo,
+		inspector:          inspector,
+		cfg:                cfg,
+		logger:             logger.NewSystemLog("QueueMonitorService"),
+	}
+}
+
+func (s *QueueMonitorService) Overview(ctx context.Context) (*OverviewResponse, error) {
+	res := &OverviewResponse{
+		Driver: s.cfg.Driver,
+		Queues: []QueueStats{},
+		Links: OverviewLinks{
+			Dashboard:  DashboardPath,
+			Session:    apiPrefix + DashboardPath + "/session",
+			FailedJobs: apiPrefix + "/admin/failed-jobs",
+			Schedules:  apiPrefix + "/admin/schedules",
+			Jobs:       apiPrefix + "/jobs/:id",
+		},
+	}
+	if s.cfg.Driver == queue.DriverAsynq {
+		queues, err := s.queueStats()
+		if err != nil {
+			s.logger.Warn("read asynq queues: %v", err)
+			res.QueueError = err.Error()
+		}
+		res.Queues = append(res.Queues, queues...)
+	}
+
+	backlog, err := s.transactionLogRepo.Backlog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	res.Outbox = make([]OutboxBacklogResponse, 0, len(backlog))
+	for _, b := range backlog {
+		item := OutboxBacklogResponse{Status: b.Status, Total: b.Total}
+		if !b.OldestAt.IsZero() {
+			oldestAt := b.OldestAt
+			item.OldestAt = &oldestAt
+			item.OldestAgeSeconds = now.Sub(oldestAt).Seconds()
+		}
+		res.Outbox = append(res.Outbox, item)
+	}
+
+	if res.FailedJobs, err = s.failedJobRepo.CountByStatus(ctx); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *QueueMonitorService) queueStats() ([]QueueStats, error) {
+	names, err := s.inspector.Queues()
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]QueueStats, 0, len(names))
+	for _, name := range names {
+		info, err := s.inspector.GetQueueInfo(name)
+		if err != nil {
+			return stats, err
+		}
+		stats = append(stats, QueueStats{
+			Queue:          info.Queue,
+			Size:           info.Size,
+			Pending:        info.Pending,
+			Active:         info.Active,
+			Scheduled:      info.Scheduled,
+			Retry:          info.Retry,
+			Archived:       info.Archived,
+			Completed:      info.Completed,
+			Paused:         info.Paused,
+			LatencySeconds: info.Latency.Seconds(),
+		})
+	}
+	return stats, nil
+}
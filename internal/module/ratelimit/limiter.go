@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript token bucket trong Redis: nạp lại rate token/ms, tối đa burst token, mỗi request tốn 1 token.
+// Thời gian lấy từ TIME của Redis nên lệch đồng hồ giữa các replica API không ảnh hưởng quota
+// (Redis >= 5 replicate script theo effect nên ghi sau TIME vẫn hợp lệ).
+// Trả về {allowed, remaining, retry_after_ms, reset_ms}.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+local reset = math.ceil((burst - tokens) / rate)
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', now)
+redis.call('PEXPIRE', KEYS[1], reset + 1000)
+return {allowed, math.floor(tokens), retry, reset}
+`)
+
+// Result kết quả kiểm tra quota của một request
+type Result struct {
+	Allowed bool
+	// Limit số request tối đa liên tiếp (dung lượng bucket = Burst), không phải tốc độ nạp lại
+	Limit     int
+	Remaining int
+	// RetryAfter thời gian chờ tới khi có token tiếp theo (chỉ có khi bị từ chối)
+	RetryAfter time.Duration
+	// Reset thời gian tới khi bucket đầy lại
+	Reset time.Duration
+}
+
+// Limiter kiểm tra và trừ quota cho key theo rule
+type Limiter interface {
+	Allow(ctx context.Context, key string, rule config.RateLimitRule, window time.Duration) (*Result, error)
+}
+
+type redisLimiter struct {
+	client *redis.Client
+}
+
+// NewRedisLimiter limiter dùng chung giữa các replica API qua Redis
+func NewRedisLimiter(client *redis.Client) Limiter {
+	return &redisLimiter{client: client}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, rule config.RateLimitRule, window time.Duration) (*Result, error) {
+	rate := float64(rule.Limit) / float64(window.Milliseconds())
+	res, err := tokenBucketScript.Run(ctx, l.client, []string{key},
+		strconv.FormatFloat(rate, 'f', -1, 64), rule.Burst,
+	).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Allowed:    res[0] == 1,
+		Limit:      rule.Burst,
+		Remaining:  int(res[1]),
+		RetryAfter: time.Duration(res[2]) * time.Millisecond,
+		Reset:      time.Duration(res[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package ratelimit
+
+import (
+	config "core-ledger/configs"
+	"core-ledger/internal/module/rbac"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderLimit     = "X-RateLimit-Limit"
+	HeaderRemaining = "X-RateLimit-Remaining"
+	HeaderReset     = "X-RateLimit-Reset"
+)
+
+// RateLimiter giới hạn request theo principal (API key/nhân viên) hoặc tenant, quota riêng cho từng nhóm route:
+// reports (RATE_LIMIT_REPORT_ROUTES), write (POST/PUT/PATCH/DELETE), default (còn lại).
+// Redis lỗi thì cho request đi qua để không làm gián đoạn ghi sổ.
+type RateLimiter struct {
+	limiter Limiter
+	cfg     *config.RateLimitConfig
+	logger  logger.CustomLogger
+}
+
+func NewRateLimiter() *RateLimiter {
+	return NewRateLimiterWith(NewRedisLimiter(config.NewRedisClient()), config.GetRateLimitConfig())
+}
+
+func NewRateLimiterWith(limiter Limiter, cfg *config.RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		limiter: limiter,
+		cfg:     cfg,
+		logger:  logger.NewSystemLog("RateLimiter"),
+	}
+}
+
+// Handler chạy sau middleware xác thực để lấy principal
+func (l *RateLimiter) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !l.cfg.Enabled {
+			c.Next()
+			return
+		}
+		group := l.group(c)
+		rule, ok := l.cfg.Groups[group]
+		if !ok || rule.Limit <= 0 || rule.Burst <= 0 {
+			c.Next()
+			return
+		}
+
+		res, err := l.limiter.Allow(c, fmt.Sprintf("ratelimit:%s:%s", group, l.subject(c)), rule, l.cfg.Window)
+		if err != nil {
+			l.logger.Error(fmt.Sprintf("rate limit %s: %v", group, err))
+			c.Next()
+			return
+		}
+
+		c.Header(HeaderLimit, strconv.Itoa(res.Limit))
+		c.Header(HeaderRemaining, strconv.Itoa(res.Remaining))
+		c.Header(HeaderReset, strconv.Itoa(int(math.Ceil(res.Reset.Seconds()))))
+		if !res.Allowed {
+			c.Header("Retry-After", strconv.Itoa(seconds(res.RetryAfter)))
+			ginhp.RespondError(c, http.StatusTooManyRequests, "Too many requests, please try again later.")
+			return
+		}
+		c.Next()
+	}
+}
+
+func (l *RateLimiter) group(c *gin.Context) string {
+	path := c.FullPath()
+	for _, prefix := range l.cfg.ReportRoutes {
+		if strings.HasPrefix(path, prefix) {
+			return config.RateLimitGroupReports
+		}
+	}
+	switch c.Request.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return config.RateLimitGroupWrite
+	}
+	return config.RateLimitGroupDefault
+}
+
+// subject quota theo tenant của API key khi bật RATE_LIMIT_BY_TENANT, ngược lại theo principal
+func (l *RateLimiter) subject(c *gin.Context) string {
+	if l.cfg.ByTenant {
+		if apiKey := rbac.GetApiKey(c); apiKey != nil && apiKey.TenantID != nil && *apiKey.TenantID != "" {
+			return "tenant:" + *apiKey.TenantID
+		}
+	}
+	principal := rbac.Principal(c)
+	if principal == "anonymous" {
+		return "ip:" + c.ClientIP()
+	}
+	return principal
+}
+
+// seconds làm tròn lên, tối thiểu 1 giây (Retry-After)
+func seconds(d time.Duration) int {
+	s := int(math.Ceil(d.Seconds()))
+	if s < 1 {
+		return 1
+	}
+	return s
+}
@@ -0,0 +1,127 @@
+package ratelimit
+
+import (
+	config "core-ledger/configs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRouter(t *testing.T, cfg *config.RateLimitConfig) (*gin.Engine, *miniredis.Miniredis) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	limiter := NewRateLimiterWith(NewRedisLimiter(redis.NewClient(&redis.Options{Addr: mr.Addr()})), cfg)
+
+	r := gin.New()
+	api := r.Group("/api/v2", func(c *gin.Context) {
+		if id, err := strconv.ParseInt(c.GetHeader("X-Test-User"), 10, 64); err == nil {
+			c.Set("userID", id)
+		}
+		c.Next()
+	}, limiter.Handler())
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) }
+	api.GET("/journals/:id", ok)
+	api.POST("/journals", ok)
+	api.GET("/exports", ok)
+	return r, mr
+}
+
+func testConfig() *config.RateLimitConfig {
+	return &config.RateLimitConfig{
+		Enabled:      true,
+		Window:       time.Minute,
+		ReportRoutes: []string{"/api/v2/exports"},
+		Groups: map[string]config.RateLimitRule{
+			config.RateLimitGroupDefault: {Limit: 60, Burst: 5},
+			config.RateLimitGroupWrite:   {Limit: 60, Burst: 3},
+			config.RateLimitGroupReports: {Limit: 60, Burst: 1},
+		},
+	}
+}
+
+func do(r *gin.Engine, method, path, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitBurstPerGroup(t *testing.T) {
+	r, _ := newTestRouter(t, testConfig())
+
+	for i := 0; i < 3; i++ {
+		if w := do(r, http.MethodPost, "/api/v2/journals", "1"); w.Code != http.StatusOK {
+			t.Fatalf("write %d: expected 200, got %d", i, w.Code)
+		}
+	}
+	w := do(r, http.MethodPost, "/api/v2/journals", "1")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after write burst, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" || w.Header().Get(HeaderRemaining) != "0" {
+		t.Fatalf("expected Retry-After and %s=0, got %v", HeaderRemaining, w.Header())
+	}
+
+	// nhóm default và reports có quota riêng
+	if w := do(r, http.MethodGet, "/api/v2/journals/1", "1"); w.Code != http.StatusOK {
+		t.Fatalf("read: expected 200, got %d", w.Code)
+	}
+	if w := do(r, http.MethodGet, "/api/v2/exports", "1"); w.Code != http.StatusOK {
+		t.Fatalf("report: expected 200, got %d", w.Code)
+	}
+	if w := do(r, http.MethodGet, "/api/v2/exports", "1"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("report: expected 429 after burst 1, got %d", w.Code)
+	}
+}
+
+func TestRateLimitHeadersAndPrincipalIsolation(t *testing.T) {
+	r, _ := newTestRouter(t, testConfig())
+
+	w := do(r, http.MethodGet, "/api/v2/journals/1", "1")
+	// X-RateLimit-Limit là Burst (số request dùng được liền), không phải Limit mỗi Window
+	if w.Header().Get(HeaderLimit) != "5" || w.Header().Get(HeaderRemaining) != "4" {
+		t.Fatalf("unexpected headers %v", w.Header())
+	}
+
+	do(r, http.MethodGet, "/api/v2/exports", "1")
+	if w := do(r, http.MethodGet, "/api/v2/exports", "2"); w.Code != http.StatusOK {
+		t.Fatalf("other employee should have its own quota, got %d", w.Code)
+	}
+}
+
+func TestRateLimitRefillsOverTime(t *testing.T) {
+	cfg := testConfig()
+	cfg.Window = time.Second
+	cfg.Groups[config.RateLimitGroupReports] = config.RateLimitRule{Limit: 20, Burst: 1}
+	r, _ := newTestRouter(t, cfg)
+
+	do(r, http.MethodGet, "/api/v2/exports", "1")
+	if w := do(r, http.MethodGet, "/api/v2/exports", "1"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", w.Code)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if w := do(r, http.MethodGet, "/api/v2/exports", "1"); w.Code != http.StatusOK {
+		t.Fatalf("expected token refilled after 100ms, got %d", w.Code)
+	}
+}
+
+func TestRateLimitFailsOpenWhenRedisDown(t *testing.T) {
+	r, mr := newTestRouter(t, testConfig())
+	mr.Close()
+
+	for i := 0; i < 5; i++ {
+		if w := do(r, http.MethodPost, "/api/v2/journals", "1"); w.Code != http.StatusOK {
+			t.Fatalf("expected request to pass when Redis is down, got %d", w.Code)
+		}
+	}
+}
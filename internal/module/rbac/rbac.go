@@ -0,0 +1,354 @@
+package rbac
+
+import (
+	"bytes"
+	"context"
+	"core-ledger/internal/module/middleware"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logging"
+	"core-ledger/pkg/repo"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Permission = permissions.name, gán cho nhân viên qua employee_permissions.
+// "*" cấp toàn quyền, "ledger.*" cấp mọi permission bắt đầu bằng "ledger.".
+const (
+	PermLedgerJournalRead    = "ledger.journal.read"
+	PermLedgerJournalPost    = "ledger.journal.post"
+	PermLedgerJournalReverse = "ledger.journal.reverse"
+	PermLedgerHoldRead       = "ledger.hold.read"
+	PermLedgerHoldWrite      = "ledger.hold.write"
+	PermCoaRead              = "coa.read"
+	PermCoaWrite             = "coa.write"
+	PermReportsRead          = "reports.read"
+	PermReconciliationRun    = "reconciliation.run"
+	PermConfigRead           = "config.read"
+	PermConfigWrite          = "config.write"
+	PermPeriodClose          = "period.close"
+	PermApiKeysManage        = "apikeys.manage"
+	PermWebhooksManage       = "webhooks.manage"
+	PermQueueRead            = "queue.read"
+	PermQueueManage          = "queue.manage"
+	PermJobsRead             = "jobs.read"
+)
+
+// KnownPermissions danh sách permission hệ thống khai báo ở route, dùng để kiểm tra scope của API key
+var KnownPermissions = []string{
+	PermLedgerJournalRead, PermLedgerJournalPost, PermLedgerJournalReverse,
+	PermLedgerHoldRead, PermLedgerHoldWrite,
+	PermCoaRead, PermCoaWrite,
+	PermReportsRead, PermReconciliationRun,
+	PermConfigRead, PermConfigWrite,
+	PermPeriodClose, PermApiKeysManage, PermWebhooksManage,
+	PermQueueRead, PermQueueManage, PermJobsRead,
+}
+
+// IsKnownPermission kiểm tra permission (hoặc wildcard "*", "ledger.*") khớp ít nhất một permission hệ thống
+func IsKnownPermission(permission string) bool {
+	pattern := PermissionSet{permission: true}
+	for _, p := range KnownPermissions {
+		if pattern.Has(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingPermissions permission hệ thống mà scope (có thể là wildcard) bao phủ nhưng granted không có
+func MissingPermissions(scope string, granted PermissionSet) []string {
+	pattern := PermissionSet{scope: true}
+	var missing []string
+	for _, p := range KnownPermissions {
+		if pattern.Has(p) && !granted.Has(p) {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+// HeaderApiKey header chứa API key khi service nội bộ gọi ledger
+const HeaderApiKey = "X-API-Key"
+
+// permissionCacheTTL thời gian giữ quyền của nhân viên trong bộ nhớ, thu hồi quyền có hiệu lực sau tối đa TTL
+const permissionCacheTTL = 30 * time.Second
+
+// permissionCacheSweepSize số nhân viên trong cache mà từ đó mỗi lần nạp sẽ dọn các mục đã quá TTL
+const permissionCacheSweepSize = 1024
+
+// PermissionSet tập permission của nhân viên đang đăng nhập
+type PermissionSet map[string]bool
+
+// Has kiểm tra permission, hỗ trợ "*" và wildcard theo tiền tố ("ledger.*")
+func (s PermissionSet) Has(permission string) bool {
+	if s["*"] || s[permission] {
+		return true
+	}
+	for p := range s {
+		if strings.HasSuffix(p, ".*") && strings.HasPrefix(permission, strings.TrimSuffix(p, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewPermissionSet tạo PermissionSet từ danh sách permission/scope
+func NewPermissionSet(permissions []string) PermissionSet {
+	set := make(PermissionSet, len(permissions))
+	for _, p := range permissions {
+		set[strings.TrimSpace(p)] = true
+	}
+	return set
+}
+
+// List danh sách permission đã sắp xếp
+func (s PermissionSet) List() []string {
+	list := make([]string, 0, len(s))
+	for p := range s {
+		list = append(list, p)
+	}
+	sort.Strings(list)
+	return list
+}
+
+type cachedPermissions struct {
+	set      PermissionSet
+	loadedAt time.Time
+}
+
+// PermissionResolver đọc permission của nhân viên từ permissions/employee_permissions (có cache ngắn hạn)
+type PermissionResolver struct {
+	permissionRepo repo.PermissionRepo
+
+	mu    sync.RWMutex
+	cache map[int64]cachedPermissions
+}
+
+func NewPermissionResolver(permissionRepo repo.PermissionRepo) *PermissionResolver {
+	return &PermissionResolver{
+		permissionRepo: permissionRepo,
+		cache:          map[int64]cachedPermissions{},
+	}
+}
+
+func (r *PermissionResolver) Resolve(ctx context.Context, employeeID int64) (PermissionSet, error) {
+	r.mu.RLock()
+	cached, ok := r.cache[employeeID]
+	r.mu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < permissionCacheTTL {
+		return cached.set, nil
+	}
+
+	names, err := r.permissionRepo.ListNamesByEmployee(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	set := NewPermissionSet(names)
+	now := time.Now()
+	r.mu.Lock()
+	r.cache[employeeID] = cachedPermissions{set: set, loadedAt: now}
+	if len(r.cache) > permissionCacheSweepSize {
+		for id, c := range r.cache {
+			if now.Sub(c.loadedAt) >= permissionCacheTTL {
+				delete(r.cache, id)
+			}
+		}
+	}
+	r.mu.Unlock()
+	return set, nil
+}
+
+// ApiKeyAuthenticator xác thực X-API-Key của service nội bộ (wallet, VA, card...)
+type ApiKeyAuthenticator interface {
+	AuthenticateApiKey(ctx context.Context, key string) (*model.ApiKey, error)
+}
+
+// Authenticate xác thực request và nạp permission vào context:
+//   - có header X-API-Key: xác thực API key, permission = scopes của key; key có tenant thì chỉ nhận tenant_id của tenant đó
+//   - ngược lại: JWT nhân viên, permission = permissions/employee_permissions
+func (r *PermissionResolver) Authenticate(apiKeys ApiKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := c.GetHeader(HeaderApiKey); key != "" {
+			apiKey, err := apiKeys.AuthenticateApiKey(c, key)
+			if err != nil {
+				ginhp.RespondError(c, http.StatusUnauthorized, err.Error())
+				return
+			}
+			c.Set(ginhp.ContextKeyApiKey.String(), apiKey)
+			c.Set(ginhp.ContextKeyPermissions.String(), NewPermissionSet(apiKey.Scopes))
+			if apiKey.TenantID != nil && !checkTenant(c, *apiKey.TenantID) {
+				return
+			}
+			attachLogFields(c)
+			c.Next()
+			return
+		}
+
+		if !middleware.AuthenticateEmployee(c) {
+			return
+		}
+		if !r.loadPermissions(c) {
+			return
+		}
+		attachLogFields(c)
+		c.Next()
+	}
+}
+
+// LoadPermissions nạp permission của nhân viên vào context, chạy sau middleware.EmployeeAuthMiddleware
+func (r *PermissionResolver) LoadPermissions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !r.loadPermissions(c) {
+			return
+		}
+		attachLogFields(c)
+		c.Next()
+	}
+}
+
+// attachLogFields gắn principal (và tenant của API key nếu có) vào logger của request
+func attachLogFields(c *gin.Context) {
+	fields := []any{logging.FieldPrincipal, Principal(c)}
+	if apiKey := GetApiKey(c); apiKey != nil && apiKey.TenantID != nil {
+		fields = append(fields, logging.FieldTenantID, *apiKey.TenantID)
+	}
+	logging.AddFields(c, fields...)
+}
+
+// FieldTenantID tên field tenant trên query/body của các API ghi sổ (journal, batch, hold)
+const FieldTenantID = "tenant_id"
+
+// checkTenant từ chối (403) request của API key gắn tenant nếu tenant_id trên query hoặc trong body JSON
+// (kể cả journal lồng trong batch) khác tenant của key. Body được đọc rồi trả lại cho handler.
+func checkTenant(c *gin.Context, tenantID string) bool {
+	for _, v := range c.QueryArray(FieldTenantID) {
+		if v != tenantID {
+			ginhp.RespondError(c, http.StatusForbidden, "Forbidden: tenant_id does not match API key tenant")
+			return false
+		}
+	}
+	if c.Request.Body == nil {
+		return true
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	var payload any
+	// body không phải JSON để handler tự trả lỗi binding
+	if len(body) == 0 || json.Unmarshal(body, &payload) != nil {
+		return true
+	}
+	if !tenantMatches(payload, tenantID) {
+		ginhp.RespondError(c, http.StatusForbidden, "Forbidden: tenant_id does not match API key tenant")
+		return false
+	}
+	return true
+}
+
+// tenantMatches mọi field tenant_id khác null trong payload đều bằng tenantID
+func tenantMatches(payload any, tenantID string) bool {
+	switch v := payload.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if key == FieldTenantID && value != nil && value != tenantID {
+				return false
+			}
+			if !tenantMatches(value, tenantID) {
+				return false
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if !tenantMatches(item, tenantID) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (r *PermissionResolver) loadPermissions(c *gin.Context) bool {
+	employeeID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusUnauthorized, err.Error())
+		return false
+	}
+	set, err := r.Resolve(c, employeeID)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	c.Set(ginhp.ContextKeyPermissions.String(), set)
+	return true
+}
+
+// Require khai báo permission cần có cho từng route (cần tất cả permission truyền vào)
+func Require(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		set := GetPermissions(c)
+		for _, p := range permissions {
+			if !set.Has(p) {
+				ginhp.RespondError(c, http.StatusForbidden, fmt.Sprintf("Forbidden: missing permission %s", p))
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireAny cần ít nhất một trong các permission truyền vào (VD quyền xem hoặc quyền quản lý)
+func RequireAny(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		set := GetPermissions(c)
+		for _, p := range permissions {
+			if set.Has(p) {
+				c.Next()
+				return
+			}
+		}
+		ginhp.RespondError(c, http.StatusForbidden, fmt.Sprintf("Forbidden: missing permission %s", strings.Join(permissions, " or ")))
+	}
+}
+
+// GetApiKey API key đã xác thực request, nil nếu request dùng JWT nhân viên
+func GetApiKey(c *gin.Context) *model.ApiKey {
+	if v, ok := c.Get(ginhp.ContextKeyApiKey.String()); ok {
+		if apiKey, ok := v.(*model.ApiKey); ok {
+			return apiKey
+		}
+	}
+	return nil
+}
+
+// Principal định danh chủ thể gửi request ("api_key:<id>", "employee:<id>"), "anonymous" nếu chưa xác thực
+func Principal(c *gin.Context) string {
+	if apiKey := GetApiKey(c); apiKey != nil {
+		return fmt.Sprintf("api_key:%d", apiKey.ID)
+	}
+	if employeeID, err := middleware.GetUserIDFromContext(c); err == nil {
+		return fmt.Sprintf("employee:%d", employeeID)
+	}
+	return "anonymous"
+}
+
+// GetPermissions permission đã nạp bởi Authenticate/LoadPermissions, rỗng nếu chưa nạp
+func GetPermissions(c *gin.Context) PermissionSet {
+	if v, ok := c.Get(ginhp.ContextKeyPermissions.String()); ok {
+		if set, ok := v.(PermissionSet); ok {
+			return set
+		}
+	}
+	return PermissionSet{}
+}
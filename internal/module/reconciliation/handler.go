@@ -0,0 +1,105 @@
+package reconciliation
+
+import (
+	"core-ledger/internal/module/validate"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/repo"
+	"core-ledger/pkg/utils"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReconciliationHandler struct {
+	logger             logger.CustomLogger
+	service            *ReconciliationService
+	reconciliationRepo repo.ProviderReconciliationRepo
+}
+
+func NewReconciliationHandler(service *ReconciliationService, reconciliationRepo repo.ProviderReconciliationRepo) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		logger:             logger.NewSystemLog("ReconciliationHandler"),
+		service:            service,
+		reconciliationRepo: reconciliationRepo,
+	}
+}
+
+func (h *ReconciliationHandler) List(c *gin.Context) {
+	q := &dto.ListProviderReconciliationFilter{}
+	if err := c.ShouldBindQuery(&q); err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.reconciliationRepo.PaginateWithScopes(c, q)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+func (h *ReconciliationHandler) Detail(c *gin.Context) {
+	id, err := utils.ParseIntIdParam(c.Param("id"))
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "Invalid id")
+		return
+	}
+	res, err := h.reconciliationRepo.GetByID(c, id)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusNotFound, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// Run chạy lại đối soát cho một cutoff (bất đồng bộ qua queue)
+func (h *ReconciliationHandler) Run(c *gin.Context) {
+	var req RunReconciliationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	cutoff, err := h.service.ParseCutoffDate(req.Cutoff)
+	if err != nil {
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", map[string]string{"cutoff": "must be YYYY-MM-DD"})
+		return
+	}
+	jobID, err := h.service.DispatchRun(c, &cutoff)
+	if err != nil {
+		h.logger.Error("Failed to dispatch reconciliation job", err)
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ginhp.RespondOK(c, gin.H{"cutoff_at": cutoff, "job_id": jobID})
+}
+
+// Export tải báo cáo chênh lệch (Excel) của một ngày cutoff
+func (h *ReconciliationHandler) Export(c *gin.Context) {
+	cutoff, err := h.service.ParseCutoffDate(c.Query("cutoff"))
+	if err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, "cutoff must be YYYY-MM-DD")
+		return
+	}
+	buf, err := h.service.ExportVarianceReport(c, cutoff)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	downloadName := fmt.Sprintf("Provider-Reconciliation-%s.xlsx", cutoff.Format("02-01-2006"))
+	c.Header("Content-Description", "File Transfer")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", downloadName))
+	c.Header("Content-Transfer-Encoding", "binary")
+	c.Header("Expires", "0")
+	c.Header("Cache-Control", "must-revalidate")
+	c.Header("Pragma", "public")
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+}
@@ -0,0 +1,6 @@
+package reconciliation
+
+type RunReconciliationRequest struct {
+	// Cutoff ngày chốt số liệu dạng YYYY-MM-DD (00:00 giờ Việt Nam)
+	Cutoff string `json:"cutoff" binding:"required"`
+}
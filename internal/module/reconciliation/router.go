@@ -0,0 +1,23 @@
+package reconciliation
+
+import (
+	"core-ledger/internal/module/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerAPIRoutes(r *gin.RouterGroup, h *ReconciliationHandler, middleware ...gin.HandlerFunc) {
+	// Apply middleware to the group if provided
+	tx := r.Group("reconciliations", middleware...)
+	{
+		tx.GET("/list", rbac.Require(rbac.PermReportsRead), h.List)
+		tx.GET("/export", rbac.Require(rbac.PermReportsRead), h.Export)
+		tx.POST("/run", rbac.Require(rbac.PermReconciliationRun), h.Run)
+		tx.GET("/:id", rbac.Require(rbac.PermReportsRead), h.Detail)
+	}
+}
+
+// SetupRoutes registers reconciliation routes with optional middleware
+func SetupRoutes(rg *gin.RouterGroup, h *ReconciliationHandler, middleware ...gin.HandlerFunc) {
+	registerAPIRoutes(rg, h, middleware...)
+}
@@ -0,0 +1,295 @@
+package reconciliation
+
+import (
+	"bytes"
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/module/currencies"
+	model "core-ledger/model/core-ledger"
+	wealify "core-ledger/model/wealify"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/queue/jobs"
+	"core-ledger/pkg/repo"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+// ProviderTxnCodeMetaKey là key trong journal.meta dùng để liên kết bút toán với giao dịch phía nhà cung cấp
+const ProviderTxnCodeMetaKey = "provider_txn_code"
+
+// settledProviderStatuses các trạng thái phía nhà cung cấp được coi là đã thay đổi số dư
+var settledProviderStatuses = map[string]bool{
+	"SUCCESS":   true,
+	"SUCCEEDED": true,
+	"COMPLETED": true,
+	"APPROVED":  true,
+}
+
+type ReconciliationService struct {
+	db                 *gorm.DB
+	cfg                *config.ReconciliationConfig
+	coAccountRepo      repo.CoAccountRepo
+	entriesRepo        repo.EnTriesRepo
+	systemPaymentRepo  repo.SystemPaymentRepo
+	reconciliationRepo repo.ProviderReconciliationRepo
+	currencyService    *currencies.CurrencyService
+	logger             logger.CustomLogger
+	dispatcher         queue.Dispatcher
+}
+
+func NewReconciliationService(
+	dispatcher queue.Dispatcher,
+	db *gorm.DB,
+	coAccountRepo repo.CoAccountRepo,
+	entriesRepo repo.EnTriesRepo,
+	systemPaymentRepo repo.SystemPaymentRepo,
+	reconciliationRepo repo.ProviderReconciliationRepo,
+	currencyService *currencies.CurrencyService,
+) *ReconciliationService {
+	return &ReconciliationService{
+		db:                 db,
+		cfg:                config.GetReconciliationConfig(),
+		coAccountRepo:      coAccountRepo,
+		entriesRepo:        entriesRepo,
+		systemPaymentRepo:  systemPaymentRepo,
+		reconciliationRepo: reconciliationRepo,
+		currencyService:    currencyService,
+		logger:             logger.NewSystemLog("ReconciliationService"),
+		dispatcher:         dispatcher,
+	}
+}
+
+// DefaultCutoff trả về 00:00 của ngày hiện tại theo timezone cấu hình
+func (s *ReconciliationService) DefaultCutoff(now time.Time) time.Time {
+	local := now.In(s.cfg.Location())
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+}
+
+// ParseCutoffDate chuyển ngày dạng YYYY-MM-DD thành cutoff 00:00 theo timezone cấu hình
+func (s *ReconciliationService) ParseCutoffDate(date string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", date, s.cfg.Location())
+}
+
+// DispatchRun đẩy job đối soát vào queue, trả về task ID
+func (s *ReconciliationService) DispatchRun(ctx context.Context, cutoff *time.Time) (string, error) {
+	return s.dispatcher.DispatchContext(ctx, jobs.NewReconcileProviderBalance(cutoff))
+}
+
+// Run đối soát số dư từng system payment (theo currency) với tài khoản CoA tương ứng tại cutoff.
+// Số dư phía nhà cung cấp tại cutoff = số dư hiện tại - biến động đã settle sau cutoff.
+func (s *ReconciliationService) Run(ctx context.Context, cutoff time.Time) ([]*model.ProviderReconciliation, error) {
+	balances, err := s.systemPaymentRepo.ListBalances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list provider balances: %w", err)
+	}
+
+	now := time.Now()
+	windowFrom := cutoff.Add(-s.cfg.Lookback)
+	reports := make([]*model.ProviderReconciliation, 0, len(balances))
+
+	for _, b := range balances {
+		currency := b.CurrencySymbol
+
+		afterCutoff, err := s.systemPaymentRepo.ListTransactions(ctx, b.SystemPaymentID, currency, cutoff, now)
+		if err != nil {
+			return nil, fmt.Errorf("list provider transactions after cutoff: %w", err)
+		}
+		movementAfter := netSettledMovement(afterCutoff)
+		providerBalance := decimal.NewFromFloat(b.Balance).Sub(movementAfter)
+
+		network := b.PaymentType
+		report := &model.ProviderReconciliation{
+			CutoffAt:        cutoff,
+			SystemPaymentID: b.SystemPaymentID,
+			Provider:        b.Provider,
+			Network:         &network,
+			Currency:        currency,
+			ProviderBalance: providerBalance,
+			LedgerBalance:   decimal.Zero,
+			Meta: map[string]any{
+				"reported_balance":       b.Balance,
+				"reported_at":            b.UpdatedAt,
+				"movement_after_cutoff":  movementAfter.String(),
+				"unmatched_window_start": windowFrom,
+			},
+		}
+
+		window, err := s.systemPaymentRepo.ListTransactions(ctx, b.SystemPaymentID, currency, windowFrom, cutoff)
+		if err != nil {
+			return nil, fmt.Errorf("list provider transactions in window: %w", err)
+		}
+		settled := make([]*wealify.SystemPaymentTransaction, 0, len(window))
+		for _, t := range window {
+			if isSettled(t.ProviderStatus) {
+				settled = append(settled, t)
+			}
+		}
+
+		account, err := s.coAccountRepo.FindByProviderNetwork(ctx, b.Provider, network, currency)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("find coa account: %w", err)
+			}
+			s.logger.WithContext(ctx).Warn("No CoA account for provider", b.Provider, network, currency)
+			report.Status = model.ReconciliationStatusNoAccount
+			report.Variance = providerBalance
+			report.Items = toReconciliationItems(settled)
+			report.UnmatchedCount = len(report.Items)
+			reports = append(reports, report)
+			continue
+		}
+
+		report.AccountID = &account.ID
+		report.AccountCode = &account.Code
+
+		debit, credit, err := s.entriesRepo.SumByAccountAsOf(ctx, account.ID, cutoff)
+		if err != nil {
+			return nil, fmt.Errorf("sum ledger balance: %w", err)
+		}
+		// Tài khoản ASSET: số dư = Nợ - Có
+		report.LedgerBalance = debit.Sub(credit)
+		report.Variance = providerBalance.Sub(report.LedgerBalance)
+
+		codes := make([]string, 0, len(settled))
+		for _, t := range settled {
+			codes = append(codes, t.Code)
+		}
+		matched, err := s.entriesRepo.MatchedProviderTxnCodes(ctx, account.ID, codes)
+		if err != nil {
+			return nil, fmt.Errorf("match provider transactions: %w", err)
+		}
+		unmatched := make([]*wealify.SystemPaymentTransaction, 0)
+		for _, t := range settled {
+			if !matched[t.Code] {
+				unmatched = append(unmatched, t)
+			}
+		}
+		report.Items = toReconciliationItems(unmatched)
+		report.UnmatchedCount = len(report.Items)
+
+		report.Status = model.ReconciliationStatusMatched
+		if report.Variance.Abs().GreaterThan(s.cfg.Tolerance) || report.UnmatchedCount > 0 {
+			report.Status = model.ReconciliationStatusVariance
+		}
+		reports = append(reports, report)
+	}
+
+	if err := s.reconciliationRepo.ReplaceForCutoff(ctx, cutoff, reports); err != nil {
+		return nil, fmt.Errorf("save reconciliation reports: %w", err)
+	}
+	s.logger.WithContext(ctx).Info("Provider reconciliation completed", cutoff, len(reports))
+	return reports, nil
+}
+
+// ExportVarianceReport xuất báo cáo chênh lệch của một cutoff ra file Excel (2 sheet: Variance, Unmatched)
+func (s *ReconciliationService) ExportVarianceReport(ctx context.Context, cutoff time.Time) (*bytes.Buffer, error) {
+	reports, err := s.reconciliationRepo.ListByCutoff(ctx, cutoff)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	const varianceSheet = "Variance"
+	const unmatchedSheet = "Unmatched"
+	_ = f.SetSheetName("Sheet1", varianceSheet)
+	if _, err := f.NewSheet(unmatchedSheet); err != nil {
+		return nil, err
+	}
+
+	varianceHeaders := []any{"Provider", "Network", "Currency", "System payment", "Account code", "Provider balance", "Ledger balance", "Variance", "Unmatched", "Status"}
+	unmatchedHeaders := []any{"Provider", "Currency", "Account code", "Transaction code", "Direction", "Provider status", "Amount", "Transaction at"}
+	if err := f.SetSheetRow(varianceSheet, "A1", &varianceHeaders); err != nil {
+		return nil, err
+	}
+	if err := f.SetSheetRow(unmatchedSheet, "A1", &unmatchedHeaders); err != nil {
+		return nil, err
+	}
+
+	loc := s.cfg.Location()
+	unmatchedRow := 2
+	for i, r := range reports {
+		// số tiền hiển thị theo scale của currency trong ledger_currencies
+		format := func(amount decimal.Decimal) string {
+			return s.currencyService.Format(ctx, r.Currency, amount)
+		}
+		row := []any{
+			r.Provider, derefString(r.Network), r.Currency, r.SystemPaymentID, derefString(r.AccountCode),
+			format(r.ProviderBalance), format(r.LedgerBalance), format(r.Variance), r.UnmatchedCount, r.Status,
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := f.SetSheetRow(varianceSheet, cell, &row); err != nil {
+			return nil, err
+		}
+		for _, item := range r.Items {
+			itemRow := []any{
+				r.Provider, item.Currency, derefString(r.AccountCode), item.TransactionCode, item.Direction,
+				item.ProviderStatus, s.currencyService.Format(ctx, item.Currency, item.Amount), item.TransactionAt.In(loc).Format("2006-01-02 15:04:05"),
+			}
+			cell, _ := excelize.CoordinatesToCellName(1, unmatchedRow)
+			if err := f.SetSheetRow(unmatchedSheet, cell, &itemRow); err != nil {
+				return nil, err
+			}
+			unmatchedRow++
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+// netSettledMovement tổng biến động số dư (IN cộng, OUT trừ) của các giao dịch đã settle
+func netSettledMovement(transactions []*wealify.SystemPaymentTransaction) decimal.Decimal {
+	net := decimal.Zero
+	for _, t := range transactions {
+		if !isSettled(t.ProviderStatus) {
+			continue
+		}
+		amount := decimal.NewFromFloat(t.Amount)
+		switch strings.ToUpper(t.Direction) {
+		case "IN":
+			net = net.Add(amount)
+		case "OUT":
+			net = net.Sub(amount)
+		}
+	}
+	return net
+}
+
+func isSettled(providerStatus string) bool {
+	return settledProviderStatuses[strings.ToUpper(strings.TrimSpace(providerStatus))]
+}
+
+func toReconciliationItems(transactions []*wealify.SystemPaymentTransaction) []model.ProviderReconciliationItem {
+	items := make([]model.ProviderReconciliationItem, 0, len(transactions))
+	for _, t := range transactions {
+		items = append(items, model.ProviderReconciliationItem{
+			TransactionID:   t.ID,
+			TransactionCode: t.Code,
+			Direction:       t.Direction,
+			ProviderStatus:  t.ProviderStatus,
+			Amount:          decimal.NewFromFloat(t.Amount),
+			Currency:        t.CurrencySymbol,
+			TransactionAt:   t.CreatedAt,
+		})
+	}
+	return items
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
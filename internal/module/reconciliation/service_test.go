@@ -0,0 +1,249 @@
+package reconciliation
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/module/currencies"
+	model "core-ledger/model/core-ledger"
+	wealify "core-ledger/model/wealify"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/repo"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+type fakeSystemPayments struct {
+	balances     []*repo.SystemPaymentBalanceView
+	transactions []*wealify.SystemPaymentTransaction
+}
+
+func (f *fakeSystemPayments) ListBalances(context.Context) ([]*repo.SystemPaymentBalanceView, error) {
+	return f.balances, nil
+}
+
+func (f *fakeSystemPayments) ListTransactions(_ context.Context, systemPaymentID, currency string, from, to time.Time) ([]*wealify.SystemPaymentTransaction, error) {
+	var out []*wealify.SystemPaymentTransaction
+	for _, t := range f.transactions {
+		if t.SystemPaymentID == systemPaymentID && t.CurrencySymbol == currency && !t.CreatedAt.Before(from) && t.CreatedAt.Before(to) {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+type fakeCoAccounts struct {
+	repo.CoAccountRepo
+	accounts map[string]*model.CoaAccount
+}
+
+func (f *fakeCoAccounts) FindByProviderNetwork(_ context.Context, provider, network, currency string) (*model.CoaAccount, error) {
+	if a, ok := f.accounts[provider+"/"+network+"/"+currency]; ok {
+		return a, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fakeEntries tổng Nợ/Có theo tài khoản và mã giao dịch nhà cung cấp đã ghi sổ
+type fakeEntries struct {
+	repo.EnTriesRepo
+	sums    map[uint64][2]string
+	matched map[string]bool
+}
+
+func (f *fakeEntries) SumByAccountAsOf(_ context.Context, accountID uint64, _ time.Time) (decimal.Decimal, decimal.Decimal, error) {
+	sum := f.sums[accountID]
+	return decimal.RequireFromString(sum[0]), decimal.RequireFromString(sum[1]), nil
+}
+
+func (f *fakeEntries) MatchedProviderTxnCodes(_ context.Context, _ uint64, codes []string) (map[string]bool, error) {
+	out := map[string]bool{}
+	for _, code := range codes {
+		out[code] = f.matched[code]
+	}
+	return out, nil
+}
+
+type fakeReports struct {
+	repo.ProviderReconciliationRepo
+	saved []*model.ProviderReconciliation
+}
+
+func (f *fakeReports) ReplaceForCutoff(_ context.Context, _ time.Time, reports []*model.ProviderReconciliation) error {
+	f.saved = reports
+	return nil
+}
+
+func (f *fakeReports) ListByCutoff(context.Context, time.Time) ([]*model.ProviderReconciliation, error) {
+	return f.saved, nil
+}
+
+type fakeCurrencies struct {
+	repo.LedgerCurrencyRepo
+}
+
+func (fakeCurrencies) List(context.Context) ([]*model.LedgerCurrency, error) {
+	return []*model.LedgerCurrency{{Code: "USD", Scale: 2, Active: true}}, nil
+}
+
+func balanceView(systemPaymentID, provider, network string, balance float64) *repo.SystemPaymentBalanceView {
+	return &repo.SystemPaymentBalanceView{
+		SystemPaymentBalance: wealify.SystemPaymentBalance{SystemPaymentID: systemPaymentID, CurrencySymbol: "USD", Balance: balance},
+		Provider:             provider,
+		PaymentType:          network,
+	}
+}
+
+func providerTxn(systemPaymentID, code, direction, status string, amount float64, at time.Time) *wealify.SystemPaymentTransaction {
+	return &wealify.SystemPaymentTransaction{
+		ID: "id-" + code, Code: code, SystemPaymentID: systemPaymentID, CurrencySymbol: "USD",
+		Direction: direction, ProviderStatus: status, Amount: amount, CreatedAt: at,
+	}
+}
+
+func newTestService(t *testing.T) (*ReconciliationService, time.Time, *fakeReports) {
+	t.Helper()
+	cfg := &config.ReconciliationConfig{Timezone: "Asia/Ho_Chi_Minh", Lookback: 72 * time.Hour, Tolerance: decimal.Zero}
+	cutoff := time.Date(2026, 10, 1, 0, 0, 0, 0, cfg.Location())
+	payments := &fakeSystemPayments{
+		balances: []*repo.SystemPaymentBalanceView{
+			balanceView("sp-wise", "WISE", "BANK", 150),
+			balanceView("sp-card", "STRIPE", "CARD", 80),
+			balanceView("sp-new", "NEWPAY", "BANK", 10),
+		},
+		transactions: []*wealify.SystemPaymentTransaction{
+			// sau cutoff: IN 50 đã settle được trừ ra, OUT đang chờ bỏ qua → số dư tại cutoff 100
+			providerTxn("sp-wise", "W-AFTER", "IN", "SUCCESS", 50, cutoff.Add(time.Hour)),
+			providerTxn("sp-wise", "W-PENDING", "OUT", "PENDING", 20, cutoff.Add(2*time.Hour)),
+			providerTxn("sp-wise", "W-1", "IN", "completed", 30, cutoff.Add(-time.Hour)),
+			providerTxn("sp-wise", "W-FAILED", "IN", "FAILED", 5, cutoff.Add(-time.Hour)),
+			providerTxn("sp-card", "C-1", "IN", "SUCCESS", 20, cutoff.Add(-3*time.Hour)),
+			providerTxn("sp-card", "C-2", "IN", "SUCCESS", 5, cutoff.Add(-2*time.Hour)),
+			// ngoài cửa sổ lookback
+			providerTxn("sp-card", "C-OLD", "IN", "SUCCESS", 1, cutoff.Add(-100*time.Hour)),
+			providerTxn("sp-new", "N-1", "IN", "SUCCESS", 10, cutoff.Add(-time.Hour)),
+		},
+	}
+	accounts := &fakeCoAccounts{accounts: map[string]*model.CoaAccount{
+		"WISE/BANK/USD":   {ID: 1, Code: "ASSET.WISE.USD"},
+		"STRIPE/CARD/USD": {ID: 2, Code: "ASSET.STRIPE.USD"},
+	}}
+	entries := &fakeEntries{
+		sums:    map[uint64][2]string{1: {"130", "30"}, 2: {"100", "25"}},
+		matched: map[string]bool{"W-1": true, "C-1": true},
+	}
+	reports := &fakeReports{}
+	return &ReconciliationService{
+		cfg:                cfg,
+		coAccountRepo:      accounts,
+		entriesRepo:        entries,
+		systemPaymentRepo:  payments,
+		reconciliationRepo: reports,
+		currencyService:    currencies.NewCurrencyService(fakeCurrencies{}),
+		logger:             logger.NewSystemLog("ReconciliationService"),
+	}, cutoff, reports
+}
+
+func TestReconciliationRun(t *testing.T) {
+	service, cutoff, saved := newTestService(t)
+	reports, err := service.Run(context.Background(), cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 3 || len(saved.saved) != 3 {
+		t.Fatalf("reports = %d saved = %d", len(reports), len(saved.saved))
+	}
+	cases := []struct {
+		provider, status              string
+		providerBalance, ledger, diff string
+		unmatched                     []string
+	}{
+		{"WISE", model.ReconciliationStatusMatched, "100", "100", "0", nil},
+		{"STRIPE", model.ReconciliationStatusVariance, "80", "75", "5", []string{"C-2"}},
+		{"NEWPAY", model.ReconciliationStatusNoAccount, "10", "0", "10", []string{"N-1"}},
+	}
+	for i, tc := range cases {
+		r := reports[i]
+		if r.Provider != tc.provider || r.Status != tc.status || !r.CutoffAt.Equal(cutoff) {
+			t.Errorf("%s: report = %+v", tc.provider, r)
+		}
+		if !r.ProviderBalance.Equal(decimal.RequireFromString(tc.providerBalance)) ||
+			!r.LedgerBalance.Equal(decimal.RequireFromString(tc.ledger)) ||
+			!r.Variance.Equal(decimal.RequireFromString(tc.diff)) {
+			t.Errorf("%s: provider = %s ledger = %s variance = %s", tc.provider, r.ProviderBalance, r.LedgerBalance, r.Variance)
+		}
+		codes := make([]string, 0, len(r.Items))
+		for _, item := range r.Items {
+			codes = append(codes, item.TransactionCode)
+		}
+		if r.UnmatchedCount != len(tc.unmatched) || fmt.Sprint(codes) != fmt.Sprint(tc.unmatched) {
+			t.Errorf("%s: unmatched = %v (%d)", tc.provider, codes, r.UnmatchedCount)
+		}
+	}
+	item := reports[1].Items[0]
+	if item.TransactionID != "id-C-2" || item.Direction != "IN" || !item.Amount.Equal(decimal.NewFromInt(5)) || item.Currency != "USD" {
+		t.Errorf("item = %+v", item)
+	}
+}
+
+func TestReconciliationVarianceWithinTolerance(t *testing.T) {
+	service, cutoff, _ := newTestService(t)
+	service.cfg.Tolerance = decimal.NewFromInt(5)
+	service.entriesRepo.(*fakeEntries).matched["C-2"] = true
+	reports, err := service.Run(context.Background(), cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := reports[1]; r.Status != model.ReconciliationStatusMatched || r.UnmatchedCount != 0 {
+		t.Fatalf("report = %+v", r)
+	}
+}
+
+func TestReconciliationExport(t *testing.T) {
+	service, cutoff, _ := newTestService(t)
+	if _, err := service.Run(context.Background(), cutoff); err != nil {
+		t.Fatal(err)
+	}
+	buf, err := service.ExportVarianceReport(context.Background(), cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	variance, err := f.GetRows("Variance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"[Provider Network Currency System payment Account code Provider balance Ledger balance Variance Unmatched Status]",
+		"[WISE BANK USD sp-wise ASSET.WISE.USD 100.00 100.00 0.00 0 MATCHED]",
+		"[STRIPE CARD USD sp-card ASSET.STRIPE.USD 80.00 75.00 5.00 1 VARIANCE]",
+		"[NEWPAY BANK USD sp-new  10.00 0.00 10.00 1 NO_ACCOUNT]",
+	}
+	if len(variance) != len(want) {
+		t.Fatalf("variance rows = %v", variance)
+	}
+	for i, row := range variance {
+		if fmt.Sprint(row) != want[i] {
+			t.Errorf("variance row %d = %v, want %s", i, row, want[i])
+		}
+	}
+
+	unmatched, err := f.GetRows("Unmatched")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unmatched) != 3 ||
+		fmt.Sprint(unmatched[1]) != "[STRIPE USD ASSET.STRIPE.USD C-2 IN SUCCESS 5.00 2026-09-30 22:00:00]" ||
+		fmt.Sprint(unmatched[2]) != "[NEWPAY USD  N-1 IN SUCCESS 10.00 2026-09-30 23:00:00]" {
+		t.Fatalf("unmatched rows = %v", unmatched)
+	}
+}
@@ -1,6 +1,8 @@
 package ruleCategory
 
 import (
+	"core-ledger/internal/module/rbac"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -8,7 +10,7 @@ func registerAPIRoutes(r *gin.RouterGroup, h *RuleCategoryHandler, middleware ..
 	// Apply middleware to the group if provided
 	tx := r.Group("rule-category", middleware...)
 	{
-		tx.GET("/list", h.List)
+		tx.GET("/list", rbac.Require(rbac.PermConfigRead), h.List)
 
 		// Add more routes here
 		// tx.POST("", h.Create)
@@ -1,6 +1,8 @@
 package ruleValue
 
 import (
+	"core-ledger/internal/module/rbac"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -8,10 +10,10 @@ func registerAPIRoutes(r *gin.RouterGroup, h *RuleValueHandler, middleware ...gi
 	// Apply middleware to the group if provided
 	tx := r.Group("rule-value", middleware...)
 	{
-		tx.GET("/list", h.List)
+		tx.GET("/list", rbac.Require(rbac.PermConfigRead), h.List)
 
 		// Add more routes here
-		tx.POST("", h.Create)
+		tx.POST("", rbac.Require(rbac.PermConfigWrite), h.Create)
 		// tx.GET("/:id", h.GetByID)
 		// tx.PUT("/:id", h.Update)
 		// tx.DELETE("/:id", h.Delete)
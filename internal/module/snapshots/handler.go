@@ -0,0 +1,51 @@
+package snapshots
+
+import (
+	"core-ledger/internal/module/rbac"
+	"core-ledger/internal/module/validate"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SnapshotHandler struct {
+	logger  logger.CustomLogger
+	service *SnapshotService
+}
+
+func NewSnapshotHandler(service *SnapshotService) *SnapshotHandler {
+	return &SnapshotHandler{
+		logger:  logger.NewSystemLog("SnapshotHandler"),
+		service: service,
+	}
+}
+
+// CloseDay chốt số dư cuối ngày bằng tay (bất đồng bộ qua queue), ngày đã chốt thì job bỏ qua
+func (h *SnapshotHandler) CloseDay(c *gin.Context) {
+	var req CloseDayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	var asOf *time.Time
+	if req.AsOfDate != "" {
+		date, err := h.service.ParseAsOfDate(req.AsOfDate)
+		if err != nil {
+			ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", map[string]string{"as_of_date": "must be YYYY-MM-DD"})
+			return
+		}
+		asOf = &date
+	}
+	jobID, err := h.service.DispatchEOD(c, asOf)
+	if err != nil {
+		h.logger.Error("Failed to dispatch EOD snapshot job", err)
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	h.logger.WithContext(c).Info("EOD snapshot close requested", req.AsOfDate, rbac.Principal(c), jobID)
+	ginhp.RespondOK(c, gin.H{"as_of_date": req.AsOfDate, "job_id": jobID})
+}
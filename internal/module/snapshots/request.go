@@ -0,0 +1,6 @@
+package snapshots
+
+type CloseDayRequest struct {
+	// AsOfDate ngày cần chốt dạng YYYY-MM-DD (theo timezone scheduler), để trống thì chốt ngày hôm trước
+	AsOfDate string `json:"as_of_date"`
+}
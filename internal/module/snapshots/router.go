@@ -0,0 +1,21 @@
+package snapshots
+
+import (
+	"core-ledger/internal/module/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerAPIRoutes(r *gin.RouterGroup, h *SnapshotHandler, middleware ...gin.HandlerFunc) {
+	// Apply middleware to the group if provided
+	tx := r.Group("snapshots", middleware...)
+	{
+		// chốt sổ (snapshot LOCKED) không sửa lại được nên cần quyền riêng period.close
+		tx.POST("/eod", rbac.Require(rbac.PermPeriodClose), h.CloseDay)
+	}
+}
+
+// SetupRoutes registers snapshot routes with optional middleware
+func SetupRoutes(rg *gin.RouterGroup, h *SnapshotHandler, middleware ...gin.HandlerFunc) {
+	registerAPIRoutes(rg, h, middleware...)
+}
@@ -0,0 +1,208 @@
+package snapshots
+
+import (
+	"context"
+	config "core-ledger/configs"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/queue/jobs"
+	"core-ledger/pkg/repo"
+	"core-ledger/pkg/tracing"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// snapshotCreatedBy người tạo ghi vào snapshot do job EOD sinh ra
+const snapshotCreatedBy = "system:eod"
+
+// EODResult kết quả chốt số dư một ngày
+type EODResult struct {
+	AsOfDate string `json:"as_of_date"`
+	Accounts int    `json:"accounts"`
+	Created  int64  `json:"created"`
+	// Skipped ngày đã được chốt trước đó, không tạo thêm snapshot/event
+	Skipped bool `json:"skipped"`
+}
+
+type SnapshotService struct {
+	db           *gorm.DB
+	cfg          *config.SchedulerConfig
+	snapshotRepo repo.SnapshotRepo
+	entriesRepo  repo.EnTriesRepo
+	logger       logger.CustomLogger
+	dispatcher   queue.Dispatcher
+}
+
+func NewSnapshotService(dispatcher queue.Dispatcher, db *gorm.DB, snapshotRepo repo.SnapshotRepo, entriesRepo repo.EnTriesRepo) *SnapshotService {
+	return &SnapshotService{
+		db:           db,
+		cfg:          config.GetSchedulerConfig(),
+		snapshotRepo: snapshotRepo,
+		entriesRepo:  entriesRepo,
+		logger:       logger.NewSystemLog("SnapshotService"),
+		dispatcher:   dispatcher,
+	}
+}
+
+// ParseAsOfDate chuyển ngày dạng YYYY-MM-DD thành 00:00 theo timezone scheduler
+func (s *SnapshotService) ParseAsOfDate(date string) (time.Time, error) {
+	return time.ParseInLocation("2006-01-02", date, s.cfg.Location())
+}
+
+// DispatchEOD đẩy job chốt số dư vào queue, asOf = nil để chốt ngày hôm trước; trả về task ID
+func (s *SnapshotService) DispatchEOD(ctx context.Context, asOf *time.Time) (string, error) {
+	return s.dispatcher.DispatchContext(ctx, jobs.NewSnapshotEOD(asOf))
+}
+
+// PreviousBusinessDate ngày cần chốt khi job chạy lúc now (ngày hôm trước theo timezone scheduler)
+func (s *SnapshotService) PreviousBusinessDate(now time.Time) time.Time {
+	local := now.In(s.cfg.Location())
+	return time.Date(local.Year(), local.Month(), local.Day()-1, 0, 0, 0, 0, local.Location())
+}
+
+// RunEOD chốt số dư cuối ngày asOf cho toàn bộ tài khoản: mỗi tài khoản một snapshot LOCKED,
+// số dư đầu kỳ lấy từ snapshot ngày trước (không có thì cộng dồn từ đầu), hash nối chuỗi với snapshot ngày trước.
+// Ngày đã chốt thì bỏ qua nên job chạy lại an toàn.
+func (s *SnapshotService) RunEOD(ctx context.Context, asOf time.Time) (*EODResult, error) {
+	loc := s.cfg.Location()
+	local := asOf.In(loc)
+	from := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	to := from.AddDate(0, 0, 1)
+	result := &EODResult{AsOfDate: from.Format("2006-01-02")}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// chặn hai job chốt cùng lúc (VD chạy tay trùng lịch)
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('snapshots:eod'))").Error; err != nil {
+			return err
+		}
+		snapshotRepo := s.snapshotRepo.WithTx(tx)
+		entriesRepo := s.entriesRepo.WithTx(tx)
+
+		existing, err := snapshotRepo.ListByDate(ctx, from)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			result.Skipped = true
+			return nil
+		}
+
+		var accounts []*model.CoaAccount
+		if err := tx.WithContext(ctx).Order("id").Find(&accounts).Error; err != nil {
+			return err
+		}
+		previous, err := snapshotRepo.ListByDate(ctx, from.AddDate(0, 0, -1))
+		if err != nil {
+			return err
+		}
+		previousByAccount := make(map[uint64]*model.Snapshot, len(previous))
+		for _, p := range previous {
+			previousByAccount[p.AccountID] = p
+		}
+		movements, err := entriesRepo.SumByAccountBetween(ctx, &from, to)
+		if err != nil {
+			return err
+		}
+		// chỉ cộng dồn từ đầu khi có tài khoản chưa có snapshot ngày trước (lần chạy đầu, tài khoản mới)
+		var cumulative map[uint64]repo.AccountTotals
+		if len(previousByAccount) < len(accounts) {
+			if cumulative, err = entriesRepo.SumByAccountBetween(ctx, nil, from); err != nil {
+				return err
+			}
+		}
+
+		createdBy := snapshotCreatedBy
+		// cột date: gửi 00:00 UTC để Postgres không đổi ngày theo timezone của session
+		asOfDate := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+		snapshots := make([]*model.Snapshot, 0, len(accounts))
+		// chạy trong job thì báo tiến độ theo số tài khoản đã tính, gọi trực tiếp thì reporter nil
+		reporter := queue.ReporterFromContext(ctx)
+		for i, account := range accounts {
+			reporter.Progress(ctx, int64(i), int64(len(accounts)), "computing snapshots")
+			var opening decimal.Decimal
+			prevHash := ""
+			if p, ok := previousByAccount[account.ID]; ok {
+				opening = p.ClosingBalance
+				prevHash = p.Hash
+			} else {
+				t := cumulative[account.ID]
+				opening = account.NormalBalance(t.Debit, t.Credit)
+			}
+			m := movements[account.ID]
+			movement := account.NormalBalance(m.Debit, m.Credit)
+			snapshot := &model.Snapshot{
+				AsOfDate:       asOfDate,
+				AccountID:      account.ID,
+				AccountCode:    account.Code,
+				Currency:       account.Currency,
+				OpeningBalance: opening,
+				DebitTotal:     m.Debit,
+				CreditTotal:    m.Credit,
+				Movement:       movement,
+				ClosingBalance: opening.Add(movement),
+				EntryCount:     m.EntryCount,
+				Status:         "LOCKED",
+				CreatedBy:      &createdBy,
+			}
+			snapshot.Hash = snapshotHash(prevHash, snapshot)
+			snapshots = append(snapshots, snapshot)
+		}
+
+		reporter.Progress(ctx, int64(len(accounts)), int64(len(accounts)), "saving snapshots")
+		created, err := snapshotRepo.CreateIgnoreConflict(ctx, snapshots)
+		if err != nil {
+			return err
+		}
+		result.Accounts = len(accounts)
+		result.Created = created
+
+		dateKey, _ := strconv.ParseUint(from.Format("20060102"), 10, 64)
+		event := &model.TransactionLog{
+			AggregateType: model.AggregateTypeSnapshot,
+			AggregateID:   dateKey,
+			EventType:     model.EventSnapshotLocked,
+			EventKey:      fmt.Sprintf("%s:%s", model.EventSnapshotLocked, result.AsOfDate),
+			PartitionKey:  model.AggregateTypeSnapshot,
+			Payload: map[string]any{
+				"as_of_date": result.AsOfDate,
+				"timezone":   loc.String(),
+				"accounts":   len(accounts),
+				"created":    created,
+			},
+			Headers: tracing.Headers(ctx),
+			Status:  model.TransactionLogStatusPending,
+		}
+		return tx.Create(event).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !result.Skipped {
+		s.logger.WithContext(ctx).Info("Locked EOD snapshots", result.AsOfDate, result.Created)
+	}
+	return result, nil
+}
+
+// snapshotHash sha256 của các số liệu snapshot nối với hash snapshot ngày trước của cùng tài khoản
+func snapshotHash(prevHash string, snapshot *model.Snapshot) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d|%s|%s|%s|%s|%s|%s|%d",
+		prevHash,
+		snapshot.AsOfDate.Format("2006-01-02"),
+		snapshot.AccountID,
+		snapshot.AccountCode,
+		snapshot.Currency,
+		snapshot.OpeningBalance.String(),
+		snapshot.DebitTotal.String(),
+		snapshot.CreditTotal.String(),
+		snapshot.ClosingBalance.String(),
+		snapshot.EntryCount,
+	)))
+	return hex.EncodeToString(sum[:])
+}
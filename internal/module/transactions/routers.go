@@ -1,6 +1,8 @@
 package transactions
 
 import (
+	"core-ledger/internal/module/rbac"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -8,7 +10,7 @@ func registerAPIRoutes(r *gin.RouterGroup, h *TransactionHandler, middleware ...
 	// Apply middleware to the group if provided
 	tx := r.Group("transactions", middleware...)
 	{
-		tx.GET("", h.GetList)
+		tx.GET("", rbac.Require(rbac.PermReportsRead), h.GetList)
 		// Add more routes here
 		// tx.POST("", h.Create)
 		// tx.GET("/:id", h.GetByID)
@@ -31,7 +31,7 @@ func (s *transactionService) ListTransaction(ctx context.Context) ([]model.Trans
 	job.SetBackoff([]int{2, 5, 10}) // Custom backoff: 2s, 5s, 10s
 	job.SetRetry(3)                 // Cho phép retry 3 lần
 
-	if err := s.dispatcher.Dispatch(job, queue.Timeout(1*time.Second)); err != nil {
+	if _, err := s.dispatcher.DispatchContext(ctx, job, queue.Timeout(1*time.Second)); err != nil {
 		log.Printf("❌ Failed to dispatch data job: %v", err)
 		return nil, err
 	}
@@ -0,0 +1,211 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"core-ledger/internal/core"
+	model "core-ledger/model/core-ledger"
+	wealify "core-ledger/model/wealify"
+	"core-ledger/pkg/constants"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/queue/jobs"
+	"core-ledger/pkg/tracing"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+const (
+	HeaderSignature = "X-Signature"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderEvent     = "X-Webhook-Event"
+	// HeaderMessageID id event, giữ nguyên khi gửi lại để consumer chống xử lý trùng
+	HeaderMessageID = "X-Webhook-Id"
+
+	// maxResponseBody số byte response tối đa lưu vào webhook_logs
+	maxResponseBody = 64 << 10
+	// maxRelayBackoff khoảng chờ tối đa giữa 2 lần relay lỗi của cùng một event
+	maxRelayBackoff = 10 * time.Minute
+)
+
+// EventMessage body gửi tới consumer
+type EventMessage struct {
+	ID         string         `json:"id"`
+	Event      string         `json:"event"`
+	CreatedAt  time.Time      `json:"created_at"`
+	TenantID   string         `json:"tenant_id,omitempty"`
+	LedgerCode *string        `json:"ledger_code,omitempty"`
+	Data       map[string]any `json:"data"`
+}
+
+// RelayOutbox lấy event PENDING trong transaction_logs (FOR UPDATE SKIP LOCKED để nhiều worker chạy song song)
+// và tạo job gửi cho từng webhook đăng ký event đó. Job có task id theo (webhook, event) nên relay lại sau lỗi
+// không enqueue trùng; event lỗi relay được thử lại với backoff và chuyển DEAD khi quá WEBHOOK_RELAY_MAX_ATTEMPTS.
+func (s *WebhookService) RelayOutbox(ctx context.Context) (int, error) {
+	relayed := 0
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		transactionLogRepo := s.transactionLogRepo.WithTx(tx)
+		events, err := transactionLogRepo.ClaimPending(ctx, now, s.cfg.RelayBatchSize)
+		if err != nil {
+			return err
+		}
+		subscribers := map[string][]*wealify.WebhookConfiguration{}
+		for _, event := range events {
+			webhooks, ok := subscribers[event.EventType]
+			if !ok {
+				if webhooks, err = s.webhookRepo.ListSubscribers(ctx, LedgerOwnerID, event.EventType); err != nil {
+					return err
+				}
+				subscribers[event.EventType] = webhooks
+			}
+
+			if err := s.publish(tracing.ContextFromHeaders(ctx, event.Headers), event, webhooks); err != nil {
+				attempts := event.Attempts + 1
+				status := model.TransactionLogStatusPending
+				if attempts >= s.cfg.RelayMaxAttempts {
+					status = model.TransactionLogStatusDead
+				}
+				s.logger.WithContext(ctx).Error(fmt.Sprintf("relay %s (attempt %d): %v", event.EventKey, attempts, err))
+				if err := transactionLogRepo.MarkRetry(ctx, event.ID, status, now.Add(relayBackoff(attempts)), now, err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := transactionLogRepo.MarkPublished(ctx, event.ID, now); err != nil {
+				return err
+			}
+			relayed++
+		}
+		return nil
+	})
+	return relayed, err
+}
+
+// publish đẩy job gửi webhook, nối tiếp trace của request đã ghi event (TransactionLog.Headers)
+func (s *WebhookService) publish(ctx context.Context, event *model.TransactionLog, webhooks []*wealify.WebhookConfiguration) error {
+	if len(webhooks) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(&EventMessage{
+		ID:         event.EventKey,
+		Event:      event.EventType,
+		CreatedAt:  event.CreatedAt,
+		TenantID:   event.TenantID,
+		LedgerCode: event.LedgerCode,
+		Data:       event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+	for _, webhook := range webhooks {
+		job := jobs.NewDeliverWebhook(webhook.ID, event.EventType, event.EventKey, body, s.cfg.DeliveryRetry)
+		_, err := s.dispatcher.DispatchContext(ctx, job, queue.TaskID(fmt.Sprintf("webhook:%s:%s", webhook.ID, event.EventKey)))
+		if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deliver gửi một event tới webhook: ký ed25519 trên timestamp + body, lưu kết quả vào webhook_logs.
+// Trả về lỗi khi không nhận được 2xx để asynq gửi lại theo backoff của job.
+func (s *WebhookService) Deliver(ctx context.Context, job *jobs.DeliverWebhook) error {
+	webhook, err := s.webhookRepo.GetConfig(ctx, job.WebhookID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.WithContext(ctx).Warn(fmt.Sprintf("webhook %s không còn tồn tại, bỏ qua %s", job.WebhookID, job.MessageID))
+			return nil
+		}
+		return err
+	}
+	if !webhook.IsActive {
+		s.logger.WithContext(ctx).Warn(fmt.Sprintf("webhook %s đang tắt, bỏ qua %s", webhook.ID, job.MessageID))
+		return nil
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature, err := core.SignEd25519(webhook.PrivateKey, append([]byte(timestamp), job.Body...))
+	if err != nil {
+		return fmt.Errorf("sign webhook %s: %w", webhook.ID, err)
+	}
+
+	delivery := &wealify.WebhookLog{
+		EventName:        job.EventName,
+		WebhookConfigID:  webhook.ID,
+		HeaderXTimestamp: timestamp,
+		HeaderXSignature: signature,
+		Request:          job.Body,
+		MessageID:        job.MessageID,
+	}
+	sendErr := s.send(ctx, webhook, job, timestamp, signature, delivery)
+	if err := s.webhookRepo.CreateLog(context.WithoutCancel(ctx), delivery); err != nil {
+		s.logger.WithContext(ctx).Error(fmt.Sprintf("save webhook log %s: %v", job.MessageID, err))
+	}
+	return sendErr
+}
+
+// send gọi endpoint và ghi status/response vào delivery
+func (s *WebhookService) send(ctx context.Context, webhook *wealify.WebhookConfiguration, job *jobs.DeliverWebhook, timestamp, signature string, delivery *wealify.WebhookLog) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.TargetURL, bytes.NewReader(job.Body))
+	if err != nil {
+		delivery.Status = constants.WebhookSentErrorStatus
+		delivery.ErrorMsg = err.Error()
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderSignature, signature)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderEvent, job.EventName)
+	req.Header.Set(HeaderMessageID, job.MessageID)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		delivery.Status = constants.WebhookSentErrorStatus
+		delivery.ErrorMsg = err.Error()
+		return fmt.Errorf("deliver %s to webhook %s: %w", job.MessageID, webhook.ID, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+
+	delivery.HttpResponseCode = resp.StatusCode
+	delivery.Response = toJSON(body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		delivery.Status = constants.WebhookSentFailedStatus
+		delivery.ErrorMsg = resp.Status
+		return fmt.Errorf("deliver %s to webhook %s: %s", job.MessageID, webhook.ID, resp.Status)
+	}
+	delivery.Status = constants.WebhookSentSuccessStatus
+	return nil
+}
+
+// toJSON response không phải JSON được lưu dưới dạng chuỗi JSON
+func toJSON(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return body
+	}
+	quoted, _ := json.Marshal(string(body))
+	return quoted
+}
+
+// relayBackoff 5s, 10s, 20s... tối đa maxRelayBackoff
+func relayBackoff(attempts int) time.Duration {
+	backoff := 5 * time.Second
+	for i := 1; i < attempts && backoff < maxRelayBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRelayBackoff {
+		return maxRelayBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,92 @@
+package webhooks
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/core"
+	wealify "core-ledger/model/wealify"
+	"core-ledger/pkg/constants"
+	"core-ledger/pkg/queue/jobs"
+	"core-ledger/pkg/repo"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeWebhookRepo chỉ implement phần Deliver dùng tới
+type fakeWebhookRepo struct {
+	repo.WebhookRepo
+	webhook *wealify.WebhookConfiguration
+	logs    []*wealify.WebhookLog
+}
+
+func (r *fakeWebhookRepo) GetConfig(context.Context, string) (*wealify.WebhookConfiguration, error) {
+	return r.webhook, nil
+}
+
+func (r *fakeWebhookRepo) CreateLog(_ context.Context, log *wealify.WebhookLog) error {
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+func newTestService(t *testing.T, handler http.HandlerFunc) (*WebhookService, *fakeWebhookRepo) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	privateKey, publicKey, err := core.GenerateEd25519Hex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	webhookRepo := &fakeWebhookRepo{webhook: &wealify.WebhookConfiguration{
+		ID: "wh-1", TargetURL: server.URL, PrivateKey: privateKey, PublicKey: publicKey, IsActive: true,
+	}}
+	return &WebhookService{
+		cfg:         &config.WebhookConfig{Timeout: time.Second},
+		webhookRepo: webhookRepo,
+		client:      server.Client(),
+	}, webhookRepo
+}
+
+func TestDeliverSignsTimestampAndBody(t *testing.T) {
+	body := []byte(`{"id":"ledger.posted:1","event":"ledger.posted"}`)
+	var verified bool
+	var publicKey string
+	service, webhookRepo := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		got, _ := io.ReadAll(r.Body)
+		msg := append([]byte(r.Header.Get(HeaderTimestamp)), got...)
+		verified, _ = core.VerifyEd25519Hex(publicKey, msg, r.Header.Get(HeaderSignature))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	})
+	publicKey = webhookRepo.webhook.PublicKey
+
+	err := service.Deliver(context.Background(), jobs.NewDeliverWebhook("wh-1", "ledger.posted", "ledger.posted:1", body, 8))
+	if err != nil {
+		t.Fatalf("expected delivery to succeed, got %v", err)
+	}
+	if !verified {
+		t.Fatal("expected signature over timestamp+body to verify with the webhook public key")
+	}
+	if len(webhookRepo.logs) != 1 || webhookRepo.logs[0].Status != constants.WebhookSentSuccessStatus || webhookRepo.logs[0].HttpResponseCode != http.StatusOK {
+		t.Fatalf("expected one SUCCESS log, got %+v", webhookRepo.logs)
+	}
+}
+
+func TestDeliverNon2xxIsLoggedAndRetried(t *testing.T) {
+	service, webhookRepo := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	err := service.Deliver(context.Background(), jobs.NewDeliverWebhook("wh-1", "ledger.posted", "ledger.posted:1", []byte(`{}`), 8))
+	if err == nil {
+		t.Fatal("expected error so the queue retries the delivery")
+	}
+	if len(webhookRepo.logs) != 1 || webhookRepo.logs[0].Status != constants.WebhookSentFailedStatus {
+		t.Fatalf("expected one FAILED log, got %+v", webhookRepo.logs)
+	}
+	if string(webhookRepo.logs[0].Response) != `"unavailable\n"` {
+		t.Fatalf("expected non-JSON response stored as JSON string, got %s", webhookRepo.logs[0].Response)
+	}
+}
@@ -0,0 +1,116 @@
+package webhooks
+
+import (
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/validate"
+	"core-ledger/model/dto"
+	"core-ledger/pkg/ginhp"
+	"core-ledger/pkg/logger"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	logger  logger.CustomLogger
+	service *WebhookService
+}
+
+func NewWebhookHandler(service *WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		logger:  logger.NewSystemLog("WebhookHandler"),
+		service: service,
+	}
+}
+
+func (h *WebhookHandler) List(c *gin.Context) {
+	res, err := h.service.List(c)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// Events danh sách ledger event có thể đăng ký
+func (h *WebhookHandler) Events(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: SupportedEvents,
+	})
+}
+
+// Create đăng ký webhook, response có public_key để consumer kiểm tra X-Signature
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	res, err := h.service.Create(c, &req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+func (h *WebhookHandler) Update(c *gin.Context) {
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		out := validate.FormatErrorMessage(req, err)
+		ginhp.RespondErrorValidate(c, http.StatusUnprocessableEntity, "Invalid input", out)
+		return
+	}
+	res, err := h.service.Update(c, c.Param("id"), &req)
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	q := &dto.ListWebhookDeliveryFilter{}
+	if err := c.ShouldBindQuery(&q); err != nil {
+		ginhp.RespondError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	res, err := h.service.ListDeliveries(c, q)
+	if err != nil {
+		ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// Redeliver đưa lại một lần gửi vào hàng đợi, kết quả xem qua danh sách deliveries
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	res, err := h.service.Redeliver(c, c.Param("id"))
+	if err != nil {
+		respondServiceError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, dto.PreResponse{
+		Data: res,
+	})
+}
+
+// respondServiceError: AppError trả về theo chuẩn RespondOKWithError, lỗi hệ thống trả 500
+func respondServiceError(c *gin.Context, err error) {
+	var appErr *core.AppError
+	if errors.As(err, &appErr) {
+		ginhp.RespondOKWithError(c, appErr)
+		return
+	}
+	ginhp.RespondError(c, http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,57 @@
+package webhooks
+
+import (
+	wealify "core-ledger/model/wealify"
+	"time"
+)
+
+type CreateWebhookRequest struct {
+	Name      string   `json:"name" binding:"required,max=128"`
+	TargetURL string   `json:"target_url" binding:"required,url,max=512"`
+	Events    []string `json:"events" binding:"required,min=1,dive,required,max=64"`
+}
+
+// UpdateWebhookRequest chỉ cập nhật field được truyền, events (nếu có) thay toàn bộ danh sách đăng ký
+type UpdateWebhookRequest struct {
+	Name      *string  `json:"name,omitempty" binding:"omitempty,max=128"`
+	TargetURL *string  `json:"target_url,omitempty" binding:"omitempty,url,max=512"`
+	Events    []string `json:"events,omitempty" binding:"omitempty,min=1,dive,required,max=64"`
+	IsActive  *bool    `json:"is_active,omitempty"`
+}
+
+// WebhookResponse không trả private key, consumer dùng public_key để kiểm tra X-Signature
+type WebhookResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	TargetURL string    `json:"target_url"`
+	PublicKey string    `json:"public_key"`
+	IsActive  bool      `json:"is_active"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newWebhookResponse(config *wealify.WebhookConfiguration, events []string) *WebhookResponse {
+	if events == nil {
+		events = []string{}
+	}
+	return &WebhookResponse{
+		ID:        config.ID,
+		Name:      config.Name,
+		TargetURL: config.TargetURL,
+		PublicKey: config.PublicKey,
+		IsActive:  config.IsActive,
+		Events:    events,
+		CreatedAt: config.CreatedAt,
+		UpdatedAt: config.UpdatedAt,
+	}
+}
+
+type RedeliverResponse struct {
+	JobID      string `json:"job_id"`
+	DeliveryID string `json:"delivery_id"`
+	WebhookID  string `json:"webhook_id"`
+	EventName  string `json:"event_name"`
+	MessageID  string `json:"message_id"`
+	Queued     bool   `json:"queued"`
+}
@@ -0,0 +1,25 @@
+package webhooks
+
+import (
+	"core-ledger/internal/module/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+func registerAPIRoutes(r *gin.RouterGroup, h *WebhookHandler, middleware ...gin.HandlerFunc) {
+	// Apply middleware to the group if provided
+	tx := r.Group("webhooks", middleware...)
+	{
+		tx.GET("", rbac.Require(rbac.PermWebhooksManage), h.List)
+		tx.POST("", rbac.Require(rbac.PermWebhooksManage), h.Create)
+		tx.GET("/events", rbac.Require(rbac.PermWebhooksManage), h.Events)
+		tx.GET("/deliveries", rbac.Require(rbac.PermWebhooksManage), h.ListDeliveries)
+		tx.POST("/deliveries/:id/redeliver", rbac.Require(rbac.PermWebhooksManage), h.Redeliver)
+		tx.PUT("/:id", rbac.Require(rbac.PermWebhooksManage), h.Update)
+	}
+}
+
+// SetupRoutes registers ledger webhook subscription and delivery routes with optional middleware
+func SetupRoutes(rg *gin.RouterGroup, h *WebhookHandler, middleware ...gin.HandlerFunc) {
+	registerAPIRoutes(rg, h, middleware...)
+}
@@ -0,0 +1,254 @@
+package webhooks
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/core"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	wealify "core-ledger/model/wealify"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/queue/jobs"
+	"core-ledger/pkg/repo"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LedgerOwnerID owner_id của webhook do ledger quản lý trong bảng webhook_configuration dùng chung với wealify
+const LedgerOwnerID int64 = 0
+
+// SupportedEvents các ledger event consumer có thể đăng ký
+var SupportedEvents = []string{
+	model.EventLedgerPosted,
+	model.EventLedgerReversed,
+	model.EventSnapshotLocked,
+	model.EventBalanceThresholdCrossed,
+}
+
+// WebhookService quản lý webhook đăng ký ledger event, relay outbox sang hàng đợi và gửi webhook có chữ ký ed25519
+type WebhookService struct {
+	db                 *gorm.DB
+	cfg                *config.WebhookConfig
+	webhookRepo        repo.WebhookRepo
+	transactionLogRepo repo.TransactionLogRepo
+	dispatcher         queue.Dispatcher
+	client             *http.Client
+	logger             logger.CustomLogger
+}
+
+func NewWebhookService(db *gorm.DB, dispatcher queue.Dispatcher, webhookRepo repo.WebhookRepo, transactionLogRepo repo.TransactionLogRepo) *WebhookService {
+	cfg := config.GetWebhookConfig()
+	return &WebhookService{
+		db:                 db,
+		cfg:                cfg,
+		webhookRepo:        webhookRepo,
+		transactionLogRepo: transactionLogRepo,
+		dispatcher:         dispatcher,
+		client:             &http.Client{Timeout: cfg.Timeout},
+		logger:             logger.NewSystemLog("WebhookService"),
+	}
+}
+
+func (s *WebhookService) List(ctx context.Context) ([]*WebhookResponse, error) {
+	configs, err := s.webhookRepo.ListConfigs(ctx, LedgerOwnerID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(configs))
+	for _, c := range configs {
+		ids = append(ids, c.ID)
+	}
+	registered, err := s.webhookRepo.ListEvents(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	events := map[string][]string{}
+	for _, e := range registered {
+		events[e.WebhookID] = append(events[e.WebhookID], e.EventName)
+	}
+	res := make([]*WebhookResponse, 0, len(configs))
+	for _, c := range configs {
+		res = append(res, newWebhookResponse(c, events[c.ID]))
+	}
+	return res, nil
+}
+
+// Create đăng ký webhook mới và sinh cặp khoá ed25519, public key trả về để consumer kiểm tra chữ ký
+func (s *WebhookService) Create(ctx context.Context, req *CreateWebhookRequest) (*WebhookResponse, error) {
+	events, err := normalizeEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, publicKey, err := core.GenerateEd25519Hex()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	webhook := &wealify.WebhookConfiguration{
+		ID:         uuid.NewString(),
+		OwnerID:    LedgerOwnerID,
+		Name:       req.Name,
+		TargetURL:  req.TargetURL,
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
+		IsActive:   true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		webhookRepo := s.webhookRepo.WithTx(tx)
+		if err := webhookRepo.CreateConfig(ctx, webhook); err != nil {
+			return err
+		}
+		return webhookRepo.ReplaceEvents(ctx, webhook.ID, events, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newWebhookResponse(webhook, events), nil
+}
+
+func (s *WebhookService) Update(ctx context.Context, id string, req *UpdateWebhookRequest) (*WebhookResponse, error) {
+	var events []string
+	if req.Events != nil {
+		var err error
+		if events, err = normalizeEvents(req.Events); err != nil {
+			return nil, err
+		}
+	}
+	webhook, err := s.getLedgerWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	fields := map[string]interface{}{"updated_at": now}
+	if req.Name != nil {
+		webhook.Name = *req.Name
+		fields["name"] = webhook.Name
+	}
+	if req.TargetURL != nil {
+		webhook.TargetURL = *req.TargetURL
+		fields["target_url"] = webhook.TargetURL
+	}
+	if req.IsActive != nil {
+		webhook.IsActive = *req.IsActive
+		fields["is_active"] = webhook.IsActive
+	}
+	webhook.UpdatedAt = now
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		webhookRepo := s.webhookRepo.WithTx(tx)
+		if err := webhookRepo.UpdateConfig(ctx, webhook.ID, fields); err != nil {
+			return err
+		}
+		if events != nil {
+			return webhookRepo.ReplaceEvents(ctx, webhook.ID, events, now)
+		}
+		registered, err := webhookRepo.ListEvents(ctx, []string{webhook.ID})
+		if err != nil {
+			return err
+		}
+		for _, e := range registered {
+			events = append(events, e.EventName)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newWebhookResponse(webhook, events), nil
+}
+
+// ListDeliveries lịch sử gửi (mỗi lần thử một bản ghi) của các webhook ledger
+func (s *WebhookService) ListDeliveries(ctx context.Context, filter *dto.ListWebhookDeliveryFilter) (*dto.PaginationResponse[*wealify.WebhookLog], error) {
+	configs, err := s.webhookRepo.ListConfigs(ctx, LedgerOwnerID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(configs))
+	for _, c := range configs {
+		ids = append(ids, c.ID)
+	}
+	return s.webhookRepo.PaginateLogs(ctx, ids, filter)
+}
+
+// Redeliver đưa lại body của một lần gửi vào hàng đợi, chữ ký và timestamp được tạo mới khi gửi
+func (s *WebhookService) Redeliver(ctx context.Context, deliveryID string) (*RedeliverResponse, error) {
+	delivery, err := s.webhookRepo.GetLog(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.NewError(core.ErrCodeLedgerWebhookLogNotFound, fmt.Sprintf("delivery %s", deliveryID))
+		}
+		return nil, err
+	}
+	webhook, err := s.getLedgerWebhook(ctx, delivery.WebhookConfigID)
+	if err != nil {
+		return nil, err
+	}
+	if !webhook.IsActive {
+		return nil, core.NewError(core.ErrCodeLedgerWebhookInactive, fmt.Sprintf("webhook %s", webhook.ID))
+	}
+	job := jobs.NewDeliverWebhook(webhook.ID, delivery.EventName, delivery.MessageID, delivery.Request, s.cfg.DeliveryRetry)
+	jobID, err := s.dispatcher.DispatchContext(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	return &RedeliverResponse{
+		JobID:      jobID,
+		DeliveryID: delivery.ID,
+		WebhookID:  webhook.ID,
+		EventName:  delivery.EventName,
+		MessageID:  delivery.MessageID,
+		Queued:     true,
+	}, nil
+}
+
+// getLedgerWebhook webhook của owner khác (wealify) coi như không tồn tại
+func (s *WebhookService) getLedgerWebhook(ctx context.Context, id string) (*wealify.WebhookConfiguration, error) {
+	webhook, err := s.webhookRepo.GetConfig(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, core.NewError(core.ErrCodeLedgerWebhookNotFound, fmt.Sprintf("webhook %s", id))
+		}
+		return nil, err
+	}
+	if webhook.OwnerID != LedgerOwnerID {
+		return nil, core.NewError(core.ErrCodeLedgerWebhookNotFound, fmt.Sprintf("webhook %s", id))
+	}
+	return webhook, nil
+}
+
+// normalizeEvents bỏ trùng và kiểm tra event nằm trong SupportedEvents
+func normalizeEvents(events []string) ([]string, error) {
+	seen := map[string]bool{}
+	res := make([]string, 0, len(events))
+	for _, e := range events {
+		e = strings.TrimSpace(e)
+		if seen[e] {
+			continue
+		}
+		if !isSupportedEvent(e) {
+			return nil, core.NewError(core.ErrCodeLedgerWebhookInvalidEvent, fmt.Sprintf("event %q không được hỗ trợ", e))
+		}
+		seen[e] = true
+		res = append(res, e)
+	}
+	return res, nil
+}
+
+func isSupportedEvent(event string) bool {
+	for _, e := range SupportedEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
@@ -11,8 +11,9 @@ import (
 
 func main() {
 	fx.New(
-		app.FXProviders,     // gom tất cả module Provider vào
-		fx.Invoke(StartApp), // lifecycle của app
+		app.FXProviders,        // gom tất cả module Provider vào
+		app.LocalQueueOption(), // QUEUE_DRIVER=sync/memory: chạy job ngay trong process
+		fx.Invoke(StartApp),    // lifecycle của app
 	).Run()
 }
 
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AccountBalance số dư tổng hợp của tài khoản, cập nhật cùng transaction với việc ghi entries.
+// Version dùng cho optimistic locking: mọi thay đổi số dư (và đặt hold) đều tăng version.
+type AccountBalance struct {
+	AccountID   uint64          `gorm:"primaryKey;autoIncrement:false;column:account_id" json:"account_id"`
+	Currency    string          `gorm:"type:char(8);not null" json:"currency"`
+	DebitTotal  decimal.Decimal `gorm:"type:numeric(28,8);not null;default:0" json:"debit_total"`
+	CreditTotal decimal.Decimal `gorm:"type:numeric(28,8);not null;default:0" json:"credit_total"`
+	Balance     decimal.Decimal `gorm:"type:numeric(28,8);not null;default:0" json:"balance"`
+	Version     int64           `gorm:"not null;default:0" json:"version"`
+	LastEntryID *uint64         `json:"last_entry_id,omitempty"`
+	UpdatedAt   time.Time       `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (AccountBalance) TableName() string {
+	return "account_balances"
+}
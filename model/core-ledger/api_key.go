@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// ApiKey khoá API cho service nội bộ (wallet, VA, card...) gọi ledger machine-to-machine qua header X-API-Key.
+// Chỉ lưu HMAC của key, plaintext chỉ trả về một lần khi tạo/rotate.
+// TenantID gắn vào log, khoá rate limit; key có tenant chỉ được gửi tenant_id của tenant đó (rbac.Authenticate).
+type ApiKey struct {
+	ID            uint64                      `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	Name          string                      `gorm:"type:varchar(128);not null" json:"name"`
+	Prefix        string                      `gorm:"type:varchar(16);not null" json:"prefix"`
+	KeyHash       string                      `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	OwnerService  string                      `gorm:"type:varchar(64);not null;index" json:"owner_service"`
+	TenantID      *string                     `gorm:"type:varchar(36)" json:"tenant_id,omitempty"`
+	Scopes        datatypes.JSONSlice[string] `gorm:"type:jsonb;not null" json:"scopes"`
+	ExpiresAt     *time.Time                  `json:"expires_at,omitempty"`
+	LastUsedAt    *time.Time                  `json:"last_used_at,omitempty"`
+	RevokedAt     *time.Time                  `json:"revoked_at,omitempty"`
+	RotatedFromID *uint64                     `json:"rotated_from_id,omitempty"`
+	CreatedBy     *string                     `gorm:"type:varchar(64)" json:"created_by,omitempty"`
+	CreatedAt     time.Time                   `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time                   `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+const (
+	ApiKeyStatusActive  = "ACTIVE"
+	ApiKeyStatusExpired = "EXPIRED"
+	ApiKeyStatusRevoked = "REVOKED"
+)
+
+func (ApiKey) TableName() string {
+	return "api_keys"
+}
+
+// Status trạng thái hiện tại của key tại thời điểm now
+func (k *ApiKey) Status(now time.Time) string {
+	switch {
+	case k.RevokedAt != nil:
+		return ApiKeyStatusRevoked
+	case k.ExpiresAt != nil && !k.ExpiresAt.After(now):
+		return ApiKeyStatusExpired
+	default:
+		return ApiKeyStatusActive
+	}
+}
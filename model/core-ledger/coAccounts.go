@@ -4,6 +4,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
@@ -22,8 +23,14 @@ type CoaAccount struct {
 	Network   *string         `gorm:"type:varchar(32)" json:"network,omitempty"`
 	Tags      map[string]any  `gorm:"type:jsonb" json:"tags,omitempty"`
 	Metadata  *datatypes.JSON `gorm:"type:jsonb" json:"metadata,omitempty"`
-	CreatedAt time.Time       `gorm:"column:created_at;autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time       `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	// Chính sách số dư: AllowNegative bỏ qua giới hạn, ngược lại số dư sau ghi sổ >= MinBalance - OverdraftLimit
+	AllowNegative  bool             `gorm:"not null;default:false" json:"allow_negative"`
+	MinBalance     *decimal.Decimal `gorm:"type:numeric(28,8)" json:"min_balance,omitempty"`
+	OverdraftLimit *decimal.Decimal `gorm:"type:numeric(28,8)" json:"overdraft_limit,omitempty"`
+	// AlertThreshold số dư cảnh báo: ghi sổ làm số dư đi qua ngưỡng này sinh event balance.threshold_crossed
+	AlertThreshold *decimal.Decimal `gorm:"type:numeric(28,8)" json:"alert_threshold,omitempty"`
+	CreatedAt      time.Time        `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time        `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 
 	// Quan hệ
 	Parent   *CoaAccount  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
@@ -37,6 +44,41 @@ func (c *CoaAccount) TableName() string {
 	return "coa_accounts"
 }
 
+// IsDebitNormal tài khoản có số dư bên Nợ (ASSET, EXP), các loại còn lại số dư bên Có
+func (c *CoaAccount) IsDebitNormal() bool {
+	return c.Type == "ASSET" || c.Type == "EXP"
+}
+
+// NormalBalance quy đổi tổng phát sinh Nợ/Có thành số dư theo tính chất tài khoản
+func (c *CoaAccount) NormalBalance(debit, credit decimal.Decimal) decimal.Decimal {
+	if c.IsDebitNormal() {
+		return debit.Sub(credit)
+	}
+	return credit.Sub(debit)
+}
+
+// BalanceFloor số dư thấp nhất được phép sau khi ghi sổ, limited = false nếu tài khoản cho phép âm
+func (c *CoaAccount) BalanceFloor() (floor decimal.Decimal, limited bool) {
+	if c.AllowNegative {
+		return decimal.Zero, false
+	}
+	floor = decimal.Zero
+	if c.MinBalance != nil {
+		floor = *c.MinBalance
+	}
+	if c.OverdraftLimit != nil {
+		floor = floor.Sub(*c.OverdraftLimit)
+	}
+	return floor, true
+}
+
+// ApplyDefaultBalancePolicy mặc định chính sách số dư theo loại tài khoản như migration 20251203010000: chỉ LIAB (công nợ khách hàng)
+// bị chặn âm. Tài khoản khác được phép âm, trừ khi đã đặt MinBalance/OverdraftLimit (VD MinBalance = 0 để chặn âm).
+// Chỉ gọi ở luồng tạo không mang chính sách số dư (import Excel, seeder, provider tự tạo) để không đè allow_negative = false tường minh.
+func (c *CoaAccount) ApplyDefaultBalancePolicy() {
+	c.AllowNegative = c.Type != "LIAB" && c.MinBalance == nil && c.OverdraftLimit == nil
+}
+
 func (c *CoaAccount) ScopeSearch(search string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		if strings.TrimSpace(search) == "" {
@@ -80,3 +122,27 @@ func (c *CoaAccount) ScopeProviders(providers []string) func(db *gorm.DB) *gorm.
 func (c *CoaAccount) ScopeSort(sortStr string) func(db *gorm.DB) *gorm.DB {
 	return c.Entity.ScopeSort(sortStr, CoaAccount{})
 }
+
+// FilterFields whitelist filter của CoA (ngoài các ScopeXxx), networks/providers nhận list như status
+func (c *CoaAccount) FilterFields() FilterFields {
+	return commonFilterFields("created_at", FilterFields{
+		"code":            {Column: "code", Kind: FilterString},
+		"account_no":      {Column: "account_no", Kind: FilterString},
+		"name":            {Column: "name", Kind: FilterString},
+		"type":            {Column: "type", Kind: FilterString},
+		"currency":        {Column: "currency", Kind: FilterString},
+		"status":          {Column: "status", Kind: FilterString},
+		"provider":        {Column: "provider", Kind: FilterString},
+		"network":         {Column: "network", Kind: FilterString},
+		"networks":        {Column: "network", Kind: FilterString, Op: OpIn},
+		"parent_id":       {Column: "parent_id", Kind: FilterInt},
+		"allow_negative":  {Column: "allow_negative", Kind: FilterBool},
+		"min_balance":     {Column: "min_balance", Kind: FilterDecimal},
+		"overdraft_limit": {Column: "overdraft_limit", Kind: FilterDecimal},
+		"alert_threshold": {Column: "alert_threshold", Kind: FilterDecimal},
+		"created_at":      {Column: "created_at", Kind: FilterTime},
+		"updated_at":      {Column: "updated_at", Kind: FilterTime},
+		"tags":            {Column: "tags", Kind: FilterJSON},
+		"metadata":        {Column: "metadata", Kind: FilterJSON},
+	})
+}
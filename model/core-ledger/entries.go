@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -51,5 +52,45 @@ func (e *Entry) BeforeUpdate(tx *gorm.DB) (err error) {
 }
 
 func (c *Entry) ScopeSort(sortStr string) func(db *gorm.DB) *gorm.DB {
-	return c.Entity.ScopeSort(sortStr, CoaAccount{})
+	return c.Entity.ScopeSort(sortStr, Entry{})
+}
+
+func (c *Entry) ScopeSearch(search string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if strings.TrimSpace(search) == "" {
+			return db
+		}
+		return db.Where("entries.memo LIKE ?", "%"+search+"%")
+	}
+}
+
+// ScopeCurrency lọc theo currency của tài khoản (entries không lưu currency)
+func (c *Entry) ScopeCurrency(currency []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(currency) == 0 {
+			return db
+		}
+		return db.Where("entries.account_id IN (?)", db.Session(&gorm.Session{NewDB: true}).
+			Model(&CoaAccount{}).Select("id").Where("currency IN ?", currency))
+	}
+}
+
+// FilterFields whitelist filter của entries, type là cột dc (D/C)
+func (c *Entry) FilterFields() FilterFields {
+	return commonFilterFields("created_at", FilterFields{
+		"journal_id":   {Column: "journal_id", Kind: FilterInt},
+		"line_no":      {Column: "line_no", Kind: FilterInt},
+		"account_id":   {Column: "account_id", Kind: FilterInt},
+		"type":         {Column: "dc", Kind: FilterString},
+		"dc":           {Column: "dc", Kind: FilterString},
+		"amount":       {Column: "amount", Kind: FilterDecimal},
+		"amount_atoms": {Column: "amount_atoms", Kind: FilterInt},
+		"memo":         {Column: "memo", Kind: FilterString},
+		"tenant_id":    {Column: "tenant_id", Kind: FilterString},
+		"ledger_code":  {Column: "ledger_code", Kind: FilterString},
+		"batch_id":     {Column: "batch_id", Kind: FilterString},
+		"created_at":   {Column: "created_at", Kind: FilterTime},
+		"updated_at":   {Column: "updated_at", Kind: FilterTime},
+		"meta":         {Column: "meta", Kind: FilterJSON},
+	})
 }
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	ExportStatusPending   = "PENDING"
+	ExportStatusRunning   = "RUNNING"
+	ExportStatusCompleted = "COMPLETED"
+	ExportStatusFailed    = "FAILED"
+)
+
+// Export một lần export dữ liệu (XLSX/CSV/JSONL) chạy qua queue, file kết quả là bản ghi files (FileID).
+// RequestedBy là principal tạo export ("employee:<id>", "api_key:<id>"), dùng cho lịch sử export của từng người.
+type Export struct {
+	ID          uint64         `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	Resource    string         `gorm:"type:varchar(64);not null" json:"resource"`
+	Format      string         `gorm:"type:varchar(8);not null" json:"format"`
+	Status      string         `gorm:"type:varchar(16);not null;default:'PENDING';check:status IN ('PENDING','RUNNING','COMPLETED','FAILED')" json:"status"`
+	FileName    string         `gorm:"type:varchar(255);not null" json:"file_name"`
+	FileID      *uint64        `gorm:"column:file_id" json:"-"`
+	File        *File          `gorm:"foreignKey:FileID" json:"-"`
+	Columns     datatypes.JSON `gorm:"type:jsonb" json:"columns,omitempty"`
+	Query       datatypes.JSON `gorm:"type:jsonb" json:"query,omitempty"`
+	Rows        int64          `gorm:"not null;default:0" json:"rows"`
+	Size        int64          `gorm:"not null;default:0" json:"size"`
+	Error       *string        `gorm:"type:text" json:"error,omitempty"`
+	JobID       *string        `gorm:"type:varchar(64)" json:"job_id,omitempty"`
+	RequestedBy string         `gorm:"type:varchar(128);not null;index:idx_exports_requested_by" json:"requested_by"`
+	ExpiresAt   *time.Time     `json:"expires_at,omitempty"`
+	StartedAt   *time.Time     `json:"started_at,omitempty"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (Export) TableName() string {
+	return "exports"
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	FailedJobStatusFailed  = "FAILED"
+	FailedJobStatusRetried = "RETRIED"
+)
+
+// FailedJob job hết retry (hoặc SkipRetry) do worker ghi lại, giữ payload để chạy lại kể cả khi asynq đã xoá task.
+// Cùng task_id lỗi lại sau khi retry thì cập nhật dòng cũ, Attempts cộng dồn.
+type FailedJob struct {
+	ID            uint64         `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	TaskID        string         `gorm:"type:varchar(64);not null;uniqueIndex:uq_failed_jobs_task_id" json:"task_id"`
+	Type          string         `gorm:"type:varchar(128);not null;index:idx_failed_jobs_type" json:"type"`
+	Queue         string         `gorm:"type:varchar(64);not null" json:"queue"`
+	Payload       datatypes.JSON `gorm:"type:jsonb;not null" json:"payload"`
+	Error         string         `gorm:"type:text;not null" json:"error"`
+	Attempts      int            `gorm:"not null" json:"attempts"`
+	MaxRetry      int            `gorm:"not null" json:"max_retry"`
+	Handler       string         `gorm:"type:varchar(255)" json:"handler"`
+	Status        string         `gorm:"type:varchar(16);not null;index:idx_failed_jobs_status" json:"status"`
+	RetryCount    int            `gorm:"not null;default:0" json:"retry_count"`
+	FirstFailedAt time.Time      `gorm:"not null" json:"first_failed_at"`
+	LastFailedAt  time.Time      `gorm:"not null" json:"last_failed_at"`
+	RetriedAt     *time.Time     `json:"retried_at,omitempty"`
+	CreatedAt     time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (FailedJob) TableName() string {
+	return "failed_jobs"
+}
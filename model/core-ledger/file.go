@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	FilePurposeExport = "exports"
+	FilePurposeImport = "imports"
+)
+
+// File metadata của một file trong storage (upload, export). StorageKey duy nhất theo driver,
+// SHA256 là hex SHA-256 của nội dung để đối chiếu/kiểm tra trùng file.
+type File struct {
+	ID           uint64    `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	StorageKey   string    `gorm:"type:varchar(512);not null;uniqueIndex:uq_files_storage_key" json:"-"`
+	Driver       string    `gorm:"type:varchar(16);not null" json:"driver"`
+	OriginalName string    `gorm:"type:varchar(255);not null" json:"original_name"`
+	ContentType  string    `gorm:"type:varchar(255)" json:"content_type"`
+	Size         int64     `gorm:"not null;default:0" json:"size"`
+	SHA256       string    `gorm:"column:sha256;type:varchar(64);not null;index:idx_files_sha256" json:"sha256"`
+	Purpose      string    `gorm:"type:varchar(32);not null" json:"purpose"`
+	CreatedBy    *string   `gorm:"type:varchar(128)" json:"created_by,omitempty"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (File) TableName() string {
+	return "files"
+}
@@ -0,0 +1,297 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidFilter filter không có trong whitelist của model hoặc giá trị sai kiểu
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// FilterKind kiểu giá trị của field lọc, quyết định cách parse và toán tử dùng được
+type FilterKind int
+
+const (
+	FilterString FilterKind = iota
+	FilterInt
+	FilterDecimal
+	FilterTime
+	FilterBool
+	// FilterJSON cột jsonb, lọc theo path: meta.provider=wise
+	FilterJSON
+)
+
+// FilterOp toán tử, lấy từ hậu tố của key (amount_gte) hoặc cố định ở FilterField.Op
+type FilterOp string
+
+const (
+	OpEq    FilterOp = "eq"
+	OpNe    FilterOp = "ne"
+	OpIn    FilterOp = "in"
+	OpNotIn FilterOp = "not_in"
+	OpGt    FilterOp = "gt"
+	OpGte   FilterOp = "gte"
+	OpLt    FilterOp = "lt"
+	OpLte   FilterOp = "lte"
+	OpFrom  FilterOp = "from"
+	OpTo    FilterOp = "to"
+	OpNull  FilterOp = "null"
+)
+
+// filterSuffixes hậu tố nhận được, dài trước để "_not_in" không bị đọc thành "_in"
+var filterSuffixes = []struct {
+	suffix string
+	op     FilterOp
+}{
+	{"_not_in", OpNotIn},
+	{"_null", OpNull},
+	{"_from", OpFrom},
+	{"_gte", OpGte},
+	{"_lte", OpLte},
+	{"_nin", OpNotIn},
+	{"_in", OpIn},
+	{"_ne", OpNe},
+	{"_gt", OpGt},
+	{"_lt", OpLt},
+	{"_to", OpTo},
+}
+
+var jsonPathSegment = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// FilterField một field lọc được: Column là cột DB, Op cố định dùng cho alias (start_date = created_at_from)
+type FilterField struct {
+	Column string
+	Kind   FilterKind
+	Op     FilterOp
+}
+
+// FilterFields whitelist filter của một model, key là tên dùng trong query
+type FilterFields map[string]FilterField
+
+// Filterable model khai báo whitelist filter; key không có ScopeXxx và không khớp whitelist là lỗi
+type Filterable interface {
+	FilterFields() FilterFields
+}
+
+// Scope dựng điều kiện cho một key query:
+//   - "currency=USD", "status=A,B" (list → IN), "status_ne", "status_in", "status_not_in"
+//   - "amount_gte", "amount_lt"..., "ts_from"/"ts_to" (YYYY-MM-DD lấy trọn ngày, giờ Việt Nam, hoặc RFC3339)
+//   - "memo_null=true|false"
+//   - "meta.provider=wise", "meta.card.brand_in=visa,master" (jsonb, so sánh dạng text)
+func (f FilterFields) Scope(key string, value any) (func(db *gorm.DB) *gorm.DB, error) {
+	field, op, path, err := f.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	expr, err := field.expression(op, path, filterValues(value))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidFilter, key, err)
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(expr)
+	}, nil
+}
+
+// lookup tách key thành field, toán tử và path jsonb (nếu có)
+func (f FilterFields) lookup(key string) (FilterField, FilterOp, []string, error) {
+	if field, ok := f[key]; ok {
+		return field, field.opOrEq(), nil, nil
+	}
+	name, op := key, OpEq
+	for _, s := range filterSuffixes {
+		if base := strings.TrimSuffix(key, s.suffix); base != key {
+			name, op = base, s.op
+			break
+		}
+	}
+	if field, ok := f[name]; ok && field.Op == "" && (field.Kind != FilterJSON || op == OpNull) {
+		return field, op, nil, nil
+	}
+
+	// jsonb: <field>.<path...>[_op]
+	parts := strings.Split(name, ".")
+	if field, ok := f[parts[0]]; ok && field.Kind == FilterJSON && len(parts) > 1 {
+		for _, seg := range parts[1:] {
+			if !jsonPathSegment.MatchString(seg) {
+				return FilterField{}, "", nil, fmt.Errorf("%w: %s: invalid json path", ErrInvalidFilter, key)
+			}
+		}
+		return field, op, parts[1:], nil
+	}
+	return FilterField{}, "", nil, fmt.Errorf("%w: unknown filter %q", ErrInvalidFilter, key)
+}
+
+func (f FilterField) opOrEq() FilterOp {
+	if f.Op == "" {
+		return OpEq
+	}
+	return f.Op
+}
+
+func (f FilterField) expression(op FilterOp, path []string, raw []string) (clause.Expression, error) {
+	var column any = clause.Column{Table: clause.CurrentTable, Name: f.Column}
+	kind := f.Kind
+	if kind == FilterJSON {
+		if len(path) == 0 {
+			if op != OpNull {
+				return nil, errors.New("json filter needs a path")
+			}
+		} else {
+			// path đã kiểm tra ký tự, nhúng thẳng vào SQL để Postgres hiểu là text[]
+			column = clause.Expr{SQL: "(? #>> '{" + strings.Join(path, ",") + "}')", Vars: []any{column}}
+			kind = FilterString
+		}
+	}
+	if len(raw) == 0 {
+		return nil, errors.New("missing value")
+	}
+
+	switch op {
+	case OpNull:
+		isNull, err := strconv.ParseBool(raw[0])
+		if err != nil {
+			return nil, err
+		}
+		if isNull {
+			return clause.Expr{SQL: "? IS NULL", Vars: []any{column}}, nil
+		}
+		return clause.Expr{SQL: "? IS NOT NULL", Vars: []any{column}}, nil
+
+	case OpEq, OpNe, OpIn, OpNotIn:
+		if op == OpIn || op == OpNotIn {
+			raw = splitFilterList(raw)
+			if len(raw) == 0 {
+				return nil, errors.New("missing value")
+			}
+		}
+		values := make([]any, len(raw))
+		for i, s := range raw {
+			v, _, err := parseFilterValue(kind, s)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		negate := op == OpNe || op == OpNotIn
+		if len(values) == 1 && (op == OpEq || op == OpNe) {
+			if negate {
+				return clause.Expr{SQL: "? <> ?", Vars: []any{column, values[0]}}, nil
+			}
+			return clause.Expr{SQL: "? = ?", Vars: []any{column, values[0]}}, nil
+		}
+		if negate {
+			return clause.Expr{SQL: "? NOT IN ?", Vars: []any{column, values}}, nil
+		}
+		return clause.Expr{SQL: "? IN ?", Vars: []any{column, values}}, nil
+
+	case OpGt, OpGte, OpLt, OpLte, OpFrom, OpTo:
+		if kind != FilterInt && kind != FilterDecimal && kind != FilterTime {
+			return nil, fmt.Errorf("operator %s not supported", op)
+		}
+		if (op == OpFrom || op == OpTo) && kind != FilterTime {
+			return nil, fmt.Errorf("operator %s only for time fields", op)
+		}
+		v, dateOnly, err := parseFilterValue(kind, raw[0])
+		if err != nil {
+			return nil, err
+		}
+		sqlOp := map[FilterOp]string{OpGt: ">", OpGte: ">=", OpFrom: ">=", OpLt: "<", OpLte: "<=", OpTo: "<="}[op]
+		// chỉ có ngày: _to/_lte lấy trọn ngày, _gt tính từ ngày hôm sau
+		if dateOnly {
+			switch op {
+			case OpLte, OpTo:
+				v, sqlOp = v.(time.Time).Add(24*time.Hour), "<"
+			case OpGt:
+				v, sqlOp = v.(time.Time).Add(24*time.Hour), ">="
+			}
+		}
+		return clause.Expr{SQL: "? " + sqlOp + " ?", Vars: []any{column, v}}, nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", op)
+}
+
+// filterValues đưa giá trị từ query/JSON/struct về chuỗi
+func filterValues(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		out := make([]string, len(v))
+		for i, item := range v {
+			out[i] = fmt.Sprint(item)
+		}
+		return out
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// splitFilterList "a,b" → [a b] cho IN/NOT IN
+func splitFilterList(raw []string) []string {
+	var out []string
+	for _, s := range raw {
+		for _, part := range strings.Split(s, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+func parseFilterValue(kind FilterKind, s string) (any, bool, error) {
+	switch kind {
+	case FilterInt:
+		v, err := strconv.ParseInt(s, 10, 64)
+		return v, false, err
+	case FilterDecimal:
+		v, err := decimal.NewFromString(s)
+		return v, false, err
+	case FilterBool:
+		v, err := strconv.ParseBool(s)
+		return v, false, err
+	case FilterTime:
+		return parseFilterTime(s)
+	}
+	return s, false, nil
+}
+
+// parseFilterTime nhận RFC3339, "YYYY-MM-DD HH:MM:SS" hoặc "YYYY-MM-DD" (giờ Việt Nam); dateOnly khi chỉ có ngày
+func parseFilterTime(s string) (time.Time, bool, error) {
+	loc, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+	if err != nil {
+		loc = time.FixedZone("UTC+7", 7*60*60)
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", s, loc); err == nil {
+		return t, false, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %q", s)
+	}
+	return t, true, nil
+}
+
+// commonFilterFields id/ids và start_date/end_date (YYYY-MM-DD) theo dateColumn, dùng chung cho các model
+func commonFilterFields(dateColumn string, fields FilterFields) FilterFields {
+	fields["id"] = FilterField{Column: "id", Kind: FilterInt}
+	fields["ids"] = FilterField{Column: "id", Kind: FilterInt, Op: OpIn}
+	fields["start_date"] = FilterField{Column: dateColumn, Kind: FilterTime, Op: OpFrom}
+	fields["end_date"] = FilterField{Column: dateColumn, Kind: FilterTime, Op: OpTo}
+	return fields
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	HoldStatusPending  = "PENDING"
+	HoldStatusCaptured = "CAPTURED"
+	HoldStatusReleased = "RELEASED"
+	HoldStatusExpired  = "EXPIRED"
+)
+
+// Hold tạm giữ một phần số dư của tài khoản trước khi thanh toán được settle.
+// Số tiền còn giữ = Amount - CapturedAmount, chỉ tính khi Status = PENDING.
+type Hold struct {
+	ID             uint64          `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	AccountID      uint64          `gorm:"not null;index:idx_holds_account_status" json:"account_id"`
+	Amount         decimal.Decimal `gorm:"type:numeric(28,8);not null;check:amount>0" json:"amount"`
+	CapturedAmount decimal.Decimal `gorm:"type:numeric(28,8);not null;default:0" json:"captured_amount"`
+	Currency       string          `gorm:"type:char(8);not null" json:"currency"`
+	Reference      string          `gorm:"type:varchar(191);unique;not null" json:"reference"`
+	Status         string          `gorm:"type:varchar(16);not null;default:'PENDING';check:status IN ('PENDING','CAPTURED','RELEASED','EXPIRED');index:idx_holds_account_status" json:"status"`
+	ExpiresAt      time.Time       `gorm:"not null;index:idx_holds_expires_at" json:"expires_at"`
+	Memo           *string         `gorm:"type:varchar(256)" json:"memo,omitempty"`
+	Meta           map[string]any  `gorm:"type:jsonb" json:"meta,omitempty"`
+	CapturedAt     *time.Time      `json:"captured_at,omitempty"`
+	ReleasedAt     *time.Time      `json:"released_at,omitempty"`
+	TenantID       *string         `gorm:"type:varchar(36)" json:"tenant_id,omitempty"`
+	LedgerCode     *string         `gorm:"type:varchar(32)" json:"ledger_code,omitempty"`
+	CreatedAt      time.Time       `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time       `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Quan hệ
+	Account *CoaAccount `gorm:"foreignKey:AccountID" json:"account,omitempty"`
+}
+
+func (Hold) TableName() string {
+	return "holds"
+}
+
+// Remaining số tiền còn đang giữ
+func (h *Hold) Remaining() decimal.Decimal {
+	return h.Amount.Sub(h.CapturedAmount)
+}
+
+func (h *Hold) ScopeAccountId(accountID uint64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if accountID == 0 {
+			return db
+		}
+		return db.Where("account_id = ?", accountID)
+	}
+}
+
+func (h *Hold) ScopeStatus(status []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(status) == 0 {
+			return db
+		}
+		return db.Where("status IN ?", status)
+	}
+}
+
+func (h *Hold) ScopeReference(reference string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if reference == "" {
+			return db
+		}
+		return db.Where("reference = ?", reference)
+	}
+}
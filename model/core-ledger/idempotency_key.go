@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+const (
+	IdempotencyStatusInProgress = "IN_PROGRESS"
+	IdempotencyStatusCompleted  = "COMPLETED"
+)
+
+// IdempotencyKey kết quả request ghi sổ theo (principal, idempotency_key, route) để replay khi client gửi lại.
+// LockToken đánh dấu request đang giữ key, chỉ request đó được ghi kết quả/giải phóng key.
+type IdempotencyKey struct {
+	ID           uint64     `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	Principal    string     `gorm:"type:varchar(64);not null;uniqueIndex:uq_idempotency_keys_scope" json:"principal"`
+	Key          string     `gorm:"column:idempotency_key;type:varchar(128);not null;uniqueIndex:uq_idempotency_keys_scope" json:"idempotency_key"`
+	Route        string     `gorm:"type:varchar(255);not null;uniqueIndex:uq_idempotency_keys_scope" json:"route"`
+	RequestHash  string     `gorm:"type:varchar(64);not null" json:"request_hash"`
+	Status       string     `gorm:"type:varchar(16);not null" json:"status"`
+	ResponseCode int        `json:"response_code"`
+	ResponseBody []byte     `gorm:"type:bytea" json:"-"`
+	ContentType  string     `gorm:"type:varchar(128)" json:"content_type"`
+	LockToken    *string    `gorm:"type:varchar(36)" json:"-"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty"`
+	ExpiresAt    time.Time  `gorm:"not null;index" json:"expires_at"`
+	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
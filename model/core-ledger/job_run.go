@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// JobRun bản sao tiến độ/kết quả của task do handler báo qua queue.Reporter,
+// giữ lại để GET /jobs/:id vẫn đọc được khi asynq đã hết retention.
+type JobRun struct {
+	ID         uint64         `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	TaskID     string         `gorm:"type:varchar(64);not null;uniqueIndex:uq_job_runs_task_id" json:"task_id"`
+	Type       string         `gorm:"type:varchar(128);not null;index:idx_job_runs_type" json:"type"`
+	Queue      string         `gorm:"type:varchar(64);not null" json:"queue"`
+	State      string         `gorm:"type:varchar(16);not null" json:"state"`
+	Processed  int64          `gorm:"not null;default:0" json:"processed"`
+	Total      int64          `gorm:"not null;default:0" json:"total"`
+	Message    string         `gorm:"type:text" json:"message"`
+	Result     datatypes.JSON `gorm:"type:jsonb" json:"result,omitempty"`
+	Error      string         `gorm:"type:text" json:"error"`
+	Attempts   int            `gorm:"not null;default:0" json:"attempts"`
+	MaxRetry   int            `gorm:"not null;default:0" json:"max_retry"`
+	StartedAt  time.Time      `gorm:"not null" json:"started_at"`
+	FinishedAt *time.Time     `json:"finished_at,omitempty"`
+	CreatedAt  time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (JobRun) TableName() string {
+	return "job_runs"
+}
@@ -23,9 +23,39 @@ type Journal struct {
 	TenantID       *string        `gorm:"type:varchar(36)" json:"tenant_id,omitempty"`
 	LedgerCode     *string        `gorm:"type:varchar(32)" json:"ledger_code,omitempty"`
 	BatchID        *string        `gorm:"type:varchar(36)" json:"batch_id,omitempty"`
+
+	// Quan hệ
+	Entries []Entry `gorm:"foreignKey:JournalID" json:"entries,omitempty"`
 }
 
+const (
+	JournalStatusDraft    = "DRAFT"
+	JournalStatusPosted   = "POSTED"
+	JournalStatusReversed = "REVERSED"
+)
+
 // TableName đặt tên bảng rõ ràng
 func (Journal) TableName() string {
 	return "journals"
 }
+
+// FilterFields whitelist filter của journals, start_date/end_date theo ts
+func (Journal) FilterFields() FilterFields {
+	return commonFilterFields("ts", FilterFields{
+		"ts":              {Column: "ts", Kind: FilterTime},
+		"status":          {Column: "status", Kind: FilterString},
+		"idempotency_key": {Column: "idempotency_key", Kind: FilterString},
+		"currency":        {Column: "currency", Kind: FilterString},
+		"source":          {Column: "source", Kind: FilterString},
+		"memo":            {Column: "memo", Kind: FilterString},
+		"reversal_of":     {Column: "reversal_of", Kind: FilterInt},
+		"posted_by":       {Column: "posted_by", Kind: FilterString},
+		"posted_at":       {Column: "posted_at", Kind: FilterTime},
+		"tenant_id":       {Column: "tenant_id", Kind: FilterString},
+		"ledger_code":     {Column: "ledger_code", Kind: FilterString},
+		"batch_id":        {Column: "batch_id", Kind: FilterString},
+		"created_at":      {Column: "created_at", Kind: FilterTime},
+		"updated_at":      {Column: "updated_at", Kind: FilterTime},
+		"meta":            {Column: "meta", Kind: FilterJSON},
+	})
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	JournalBatchModeAtomic     = "ATOMIC"
+	JournalBatchModeBestEffort = "BEST_EFFORT"
+
+	JournalBatchStatusProcessing = "PROCESSING"
+	JournalBatchStatusPosted     = "POSTED"
+	JournalBatchStatusPartial    = "PARTIAL"
+	JournalBatchStatusFailed     = "FAILED"
+	JournalBatchStatusReversed   = "REVERSED"
+)
+
+// JournalBatch nhóm các journal ghi sổ cùng một lần (VD: file settlement cuối ngày).
+// Journal/entries thuộc batch mang batch_id = ID.
+type JournalBatch struct {
+	ID          string         `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	Mode        string         `gorm:"type:varchar(16);not null;check:mode IN ('ATOMIC','BEST_EFFORT')" json:"mode"`
+	Status      string         `gorm:"type:varchar(16);not null;check:status IN ('PROCESSING','POSTED','PARTIAL','FAILED','REVERSED')" json:"status"`
+	Source      string         `gorm:"type:varchar(64);not null" json:"source"`
+	Memo        *string        `gorm:"type:varchar(256)" json:"memo,omitempty"`
+	Meta        map[string]any `gorm:"type:jsonb" json:"meta,omitempty"`
+	TotalItems  int            `gorm:"not null;default:0" json:"total_items"`
+	PostedCount int            `gorm:"not null;default:0" json:"posted_count"`
+	FailedCount int            `gorm:"not null;default:0" json:"failed_count"`
+	Error       *string        `gorm:"type:text" json:"error,omitempty"`
+	CreatedBy   *string        `gorm:"type:varchar(64)" json:"created_by,omitempty"`
+	ReversedBy  *string        `gorm:"type:varchar(64)" json:"reversed_by,omitempty"`
+	ReversedAt  *time.Time     `json:"reversed_at,omitempty"`
+	TenantID    *string        `gorm:"type:varchar(36)" json:"tenant_id,omitempty"`
+	LedgerCode  *string        `gorm:"type:varchar(32)" json:"ledger_code,omitempty"`
+	CreatedAt   time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (JournalBatch) TableName() string {
+	return "journal_batches"
+}
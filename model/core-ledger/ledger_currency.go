@@ -0,0 +1,68 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxCurrencyScale số chữ số thập phân tối đa, khớp numeric(28,8) của entries.amount
+const MaxCurrencyScale = 8
+
+var (
+	ErrAmountPrecision = errors.New("amount has more decimal places than the currency allows")
+	ErrAmountOverflow  = errors.New("amount exceeds amount_atoms range")
+)
+
+// LedgerCurrency đăng ký loại tiền dùng trong sổ cái và số chữ số thập phân của đơn vị nhỏ nhất (VND 0, USD 2, USDT 6).
+// amount_atoms = amount * 10^scale.
+type LedgerCurrency struct {
+	Code      string    `gorm:"primaryKey;type:varchar(8)" json:"code"`
+	Name      string    `gorm:"type:varchar(64);not null" json:"name"`
+	Symbol    *string   `gorm:"type:varchar(16)" json:"symbol,omitempty"`
+	Scale     int32     `gorm:"not null;check:scale BETWEEN 0 AND 8" json:"scale"`
+	Active    bool      `gorm:"not null;default:true" json:"active"`
+	Source    string    `gorm:"type:varchar(32);not null" json:"source"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+const (
+	CurrencySourceDefault      = "DEFAULT"
+	CurrencySourceRuleCategory = "RULE_CATEGORY"
+	CurrencySourceWealify      = "WEALIFY"
+	CurrencySourceManual       = "MANUAL"
+)
+
+func (LedgerCurrency) TableName() string {
+	return "ledger_currencies"
+}
+
+// ToAtoms đổi amount sang số nguyên đơn vị nhỏ nhất, lỗi nếu amount có nhiều chữ số thập phân hơn scale
+func (c *LedgerCurrency) ToAtoms(amount decimal.Decimal) (int64, error) {
+	shifted := amount.Shift(c.Scale)
+	if !shifted.Equal(shifted.Truncate(0)) {
+		return 0, ErrAmountPrecision
+	}
+	if shifted.Abs().GreaterThan(decimal.NewFromInt(math.MaxInt64)) {
+		return 0, ErrAmountOverflow
+	}
+	return shifted.IntPart(), nil
+}
+
+// FromAtoms đổi số nguyên đơn vị nhỏ nhất về amount
+func (c *LedgerCurrency) FromAtoms(atoms int64) decimal.Decimal {
+	return decimal.New(atoms, -c.Scale)
+}
+
+// Round làm tròn amount về scale của currency (dùng cho số tiền tính ra như phí)
+func (c *LedgerCurrency) Round(amount decimal.Decimal) decimal.Decimal {
+	return amount.Round(c.Scale)
+}
+
+// Format hiển thị amount đúng số chữ số thập phân của currency
+func (c *LedgerCurrency) Format(amount decimal.Decimal) string {
+	return amount.StringFixed(c.Scale)
+}
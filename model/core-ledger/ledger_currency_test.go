@@ -0,0 +1,69 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestLedgerCurrencyAtoms(t *testing.T) {
+	cases := []struct {
+		scale  int32
+		amount string
+		atoms  int64
+		err    error
+	}{
+		{0, "1500", 1500, nil},
+		{0, "1500.0", 1500, nil},
+		{0, "0.5", 0, ErrAmountPrecision},
+		{2, "10.25", 1025, nil},
+		{2, "-10.25", -1025, nil},
+		{2, "0.01", 1, nil},
+		{2, "10.255", 0, ErrAmountPrecision},
+		{6, "1.123456", 1123456, nil},
+		{6, "0.000001", 1, nil},
+		{6, "1.1234567", 0, ErrAmountPrecision},
+		{0, "9223372036854775807", math.MaxInt64, nil},
+		{0, "9223372036854775808", 0, ErrAmountOverflow},
+		{2, "92233720368547758.08", 0, ErrAmountOverflow},
+		{6, "-9223372036854.775808", 0, ErrAmountOverflow},
+	}
+	for _, tc := range cases {
+		currency := &LedgerCurrency{Code: "TST", Scale: tc.scale}
+		atoms, err := currency.ToAtoms(decimal.RequireFromString(tc.amount))
+		if !errors.Is(err, tc.err) || (tc.err == nil && atoms != tc.atoms) {
+			t.Errorf("scale %d %s: atoms = %d err = %v", tc.scale, tc.amount, atoms, err)
+			continue
+		}
+		if tc.err != nil {
+			continue
+		}
+		// đổi ngược lại không mất chữ số
+		if back := currency.FromAtoms(atoms); !back.Equal(decimal.RequireFromString(tc.amount)) {
+			t.Errorf("scale %d %s: FromAtoms(%d) = %s", tc.scale, tc.amount, atoms, back)
+		}
+	}
+}
+
+func TestLedgerCurrencyFromAtoms(t *testing.T) {
+	cases := []struct {
+		scale  int32
+		atoms  int64
+		format string
+	}{
+		{0, 1500, "1500"},
+		{2, 1025, "10.25"},
+		{2, 5, "0.05"},
+		{2, -1, "-0.01"},
+		{6, 1, "0.000001"},
+		{6, 1500000, "1.500000"},
+	}
+	for _, tc := range cases {
+		currency := &LedgerCurrency{Code: "TST", Scale: tc.scale}
+		if got := currency.Format(currency.FromAtoms(tc.atoms)); got != tc.format {
+			t.Errorf("scale %d %d: %s, want %s", tc.scale, tc.atoms, got, tc.format)
+		}
+	}
+}
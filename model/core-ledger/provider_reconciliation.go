@@ -0,0 +1,92 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	ReconciliationStatusMatched   = "MATCHED"
+	ReconciliationStatusVariance  = "VARIANCE"
+	ReconciliationStatusNoAccount = "NO_ACCOUNT"
+)
+
+// ProviderReconciliation lưu kết quả đối soát số dư giữa nhà cung cấp thanh toán và sổ cái
+// tại một thời điểm cutoff (mỗi system payment + currency một dòng).
+type ProviderReconciliation struct {
+	ID              uint64          `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	CutoffAt        time.Time       `gorm:"column:cutoff_at;not null;index:idx_provider_reconciliations_cutoff" json:"cutoff_at"`
+	SystemPaymentID string          `gorm:"type:varchar(64);not null" json:"system_payment_id"`
+	Provider        string          `gorm:"type:varchar(64);not null" json:"provider"`
+	Network         *string         `gorm:"type:varchar(32)" json:"network,omitempty"`
+	Currency        string          `gorm:"type:char(8);not null" json:"currency"`
+	AccountID       *uint64         `gorm:"column:account_id" json:"account_id,omitempty"`
+	AccountCode     *string         `gorm:"type:varchar(128)" json:"account_code,omitempty"`
+	ProviderBalance decimal.Decimal `gorm:"type:numeric(28,8);not null" json:"provider_balance"`
+	LedgerBalance   decimal.Decimal `gorm:"type:numeric(28,8);not null" json:"ledger_balance"`
+	Variance        decimal.Decimal `gorm:"type:numeric(28,8);not null" json:"variance"`
+	UnmatchedCount  int             `gorm:"not null;default:0" json:"unmatched_count"`
+	Status          string          `gorm:"type:varchar(16);not null;check:status IN ('MATCHED','VARIANCE','NO_ACCOUNT')" json:"status"`
+	Meta            map[string]any  `gorm:"type:jsonb" json:"meta,omitempty"`
+	CreatedAt       time.Time       `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+
+	// Quan hệ
+	Items []ProviderReconciliationItem `gorm:"foreignKey:ReconciliationID" json:"items,omitempty"`
+}
+
+func (ProviderReconciliation) TableName() string {
+	return "provider_reconciliations"
+}
+
+// ScopeCutoff lọc theo ngày cutoff (YYYY-MM-DD, giờ Việt Nam)
+func (r *ProviderReconciliation) ScopeCutoff(cutoff string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		loc, err := time.LoadLocation("Asia/Ho_Chi_Minh")
+		if err != nil {
+			loc = time.FixedZone("UTC+7", 7*60*60)
+		}
+		day, err := time.ParseInLocation("2006-01-02", cutoff, loc)
+		if err != nil {
+			return db
+		}
+		return db.Where("cutoff_at >= ? AND cutoff_at < ?", day, day.AddDate(0, 0, 1))
+	}
+}
+
+func (r *ProviderReconciliation) ScopeStatus(status []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(status) == 0 {
+			return db
+		}
+		return db.Where("status IN ?", status)
+	}
+}
+
+func (r *ProviderReconciliation) ScopeProviders(providers []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(providers) == 0 {
+			return db
+		}
+		return db.Where("provider IN ?", providers)
+	}
+}
+
+// ProviderReconciliationItem là giao dịch phía nhà cung cấp chưa khớp với bút toán nào trong sổ cái
+type ProviderReconciliationItem struct {
+	ID               uint64          `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
+	ReconciliationID uint64          `gorm:"not null;index:idx_provider_reconciliation_items_reconciliation" json:"reconciliation_id"`
+	TransactionID    string          `gorm:"type:varchar(64);not null" json:"transaction_id"`
+	TransactionCode  string          `gorm:"type:varchar(191)" json:"transaction_code"`
+	Direction        string          `gorm:"type:varchar(10)" json:"direction"`
+	ProviderStatus   string          `gorm:"type:varchar(100)" json:"provider_status"`
+	Amount           decimal.Decimal `gorm:"type:numeric(28,8);not null" json:"amount"`
+	Currency         string          `gorm:"type:char(8);not null" json:"currency"`
+	TransactionAt    time.Time       `gorm:"column:transaction_at;not null" json:"transaction_at"`
+	CreatedAt        time.Time       `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (ProviderReconciliationItem) TableName() string {
+	return "provider_reconciliation_items"
+}
@@ -35,6 +35,28 @@ func (Snapshot) TableName() string {
 	return "snapshots"
 }
 
+// FilterFields whitelist filter của snapshots, start_date/end_date theo as_of_date
+func (Snapshot) FilterFields() FilterFields {
+	return commonFilterFields("as_of_date", FilterFields{
+		"as_of_date":      {Column: "as_of_date", Kind: FilterTime},
+		"account_id":      {Column: "account_id", Kind: FilterInt},
+		"account_code":    {Column: "account_code", Kind: FilterString},
+		"currency":        {Column: "currency", Kind: FilterString},
+		"opening_balance": {Column: "opening_balance", Kind: FilterDecimal},
+		"debit_total":     {Column: "debit_total", Kind: FilterDecimal},
+		"credit_total":    {Column: "credit_total", Kind: FilterDecimal},
+		"movement":        {Column: "movement", Kind: FilterDecimal},
+		"closing_balance": {Column: "closing_balance", Kind: FilterDecimal},
+		"entry_count":     {Column: "entry_count", Kind: FilterInt},
+		"ledger_code":     {Column: "ledger_code", Kind: FilterString},
+		"tenant_id":       {Column: "tenant_id", Kind: FilterString},
+		"status":          {Column: "status", Kind: FilterString},
+		"created_at":      {Column: "created_at", Kind: FilterTime},
+		"created_by":      {Column: "created_by", Kind: FilterString},
+		"meta":            {Column: "meta", Kind: FilterJSON},
+	})
+}
+
 // Hooks
 func (s *Snapshot) BeforeCreate(tx *gorm.DB) (err error) {
 	s.CreatedAt = time.Now()
@@ -6,6 +6,26 @@ import (
 	"gorm.io/gorm"
 )
 
+// Loại event ghi vào outbox (transaction_logs)
+const (
+	AggregateTypeJournal  = "journal"
+	AggregateTypeAccount  = "account"
+	AggregateTypeSnapshot = "snapshot"
+
+	EventLedgerPosted            = "ledger.posted"
+	EventLedgerReversed          = "ledger.reversed"
+	EventSnapshotLocked          = "snapshot.locked"
+	EventBalanceThresholdCrossed = "balance.threshold_crossed"
+)
+
+// Trạng thái outbox: PENDING chờ relay, PUBLISHED đã chuyển sang hàng đợi webhook, DEAD quá số lần thử
+const (
+	TransactionLogStatusPending   = "PENDING"
+	TransactionLogStatusPublished = "PUBLISHED"
+	TransactionLogStatusFailed    = "FAILED"
+	TransactionLogStatusDead      = "DEAD"
+)
+
 type TransactionLog struct {
 	ID            uint64         `gorm:"primaryKey;autoIncrement;column:id" json:"id"`
 	AggregateType string         `gorm:"type:varchar(32);not null;index:idx_transaction_logs_aggregate" json:"aggregate_type"`
@@ -31,6 +51,29 @@ func (TransactionLog) TableName() string {
 	return "transaction_logs"
 }
 
+// FilterFields whitelist filter của transaction_logs, start_date/end_date theo created_at
+func (TransactionLog) FilterFields() FilterFields {
+	return commonFilterFields("created_at", FilterFields{
+		"aggregate_type":  {Column: "aggregate_type", Kind: FilterString},
+		"aggregate_id":    {Column: "aggregate_id", Kind: FilterInt},
+		"event_type":      {Column: "event_type", Kind: FilterString},
+		"event_key":       {Column: "event_key", Kind: FilterString},
+		"partition_key":   {Column: "partition_key", Kind: FilterString},
+		"status":          {Column: "status", Kind: FilterString},
+		"attempts":        {Column: "attempts", Kind: FilterInt},
+		"next_attempt_at": {Column: "next_attempt_at", Kind: FilterTime},
+		"last_attempt_at": {Column: "last_attempt_at", Kind: FilterTime},
+		"published_at":    {Column: "published_at", Kind: FilterTime},
+		"error_last":      {Column: "error_last", Kind: FilterString},
+		"tenant_id":       {Column: "tenant_id", Kind: FilterString},
+		"ledger_code":     {Column: "ledger_code", Kind: FilterString},
+		"seq":             {Column: "seq", Kind: FilterInt},
+		"created_at":      {Column: "created_at", Kind: FilterTime},
+		"payload":         {Column: "payload", Kind: FilterJSON},
+		"headers":         {Column: "headers", Kind: FilterJSON},
+	})
+}
+
 // Hooks
 func (t *TransactionLog) BeforeCreate(tx *gorm.DB) (err error) {
 	t.CreatedAt = time.Now()
@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+// AccountBalanceResponse số dư sổ cái và số dư khả dụng (= sổ cái - các hold đang PENDING),
+// số tiền định dạng theo scale của currency (VD USD "10.50")
+type AccountBalanceResponse struct {
+	AccountID        uint64    `json:"account_id"`
+	Code             string    `json:"code"`
+	Type             string    `json:"type"`
+	Currency         string    `json:"currency"`
+	LedgerBalance    string    `json:"ledger_balance"`
+	HeldAmount       string    `json:"held_amount"`
+	AvailableBalance string    `json:"available_balance"`
+	AsOf             time.Time `json:"as_of"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+// ListExportFilter lịch sử export, RequestedBy do server gán theo principal đang đăng nhập
+type ListExportFilter struct {
+	BasePaginationQuery
+	Resource    *string `json:"resource,omitempty" form:"resource"`
+	Status      *string `json:"status,omitempty" form:"status"`
+	RequestedBy string  `json:"-" form:"-"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// ListFailedJobFilter lọc job lỗi (failed_jobs)
+type ListFailedJobFilter struct {
+	BasePaginationQuery
+	Type   *string `json:"type,omitempty" form:"type"`
+	Queue  *string `json:"queue,omitempty" form:"queue"`
+	Status *string `json:"status,omitempty" form:"status"`
+}
+
+// PurgeFailedJobFilter điều kiện xoá job lỗi, Before theo last_failed_at (RFC3339)
+type PurgeFailedJobFilter struct {
+	Type   *string    `json:"type,omitempty" form:"type"`
+	Status *string    `json:"status,omitempty" form:"status"`
+	Before *time.Time `json:"before,omitempty" form:"before" time_format:"2006-01-02T15:04:05Z07:00"`
+}
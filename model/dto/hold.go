@@ -0,0 +1,8 @@
+package dto
+
+type ListHoldFilter struct {
+	BasePaginationQuery
+	AccountID *uint64  `json:"account_id,omitempty" form:"account_id"`
+	Status    []string `json:"status,omitempty" form:"status[]"`
+	Reference *string  `json:"reference,omitempty" form:"reference"`
+}
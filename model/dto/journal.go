@@ -0,0 +1,9 @@
+package dto
+
+// ListJournalFilter lọc bút toán, StartDate/EndDate (YYYY-MM-DD) theo ts
+type ListJournalFilter struct {
+	BasePaginationQuery
+	Status   []string `json:"status,omitempty" form:"status[]"`
+	Currency []string `json:"currency,omitempty" form:"currency[]"`
+	Source   *string  `json:"source,omitempty" form:"source"`
+}
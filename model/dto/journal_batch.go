@@ -0,0 +1,14 @@
+package dto
+
+import (
+	"github.com/shopspring/decimal"
+)
+
+// JournalBatchTotal tổng hợp journal gốc của batch theo currency (không tính journal đảo)
+type JournalBatchTotal struct {
+	Currency      string          `json:"currency"`
+	JournalCount  int64           `json:"journal_count"`
+	ReversedCount int64           `json:"reversed_count"`
+	TotalDebit    decimal.Decimal `json:"total_debit"`
+	TotalCredit   decimal.Decimal `json:"total_credit"`
+}
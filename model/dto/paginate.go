@@ -9,8 +9,16 @@ type BasePaginationQuery struct {
 	IsDeleted *bool    `form:"is_deleted" json:"is_deleted,omitempty"`
 	Order     *any     `form:"order" json:"order,omitempty"`
 	Cursor    *string  `form:"cursor" json:"cursor,omitempty"`
+	WithTotal *bool    `form:"with_total" json:"with_total,omitempty"`
 	StartDate *string  `form:"start_date" json:"start_date,omitempty"`
 	EndDate   *string  `form:"end_date" json:"end_date,omitempty"`
+	// Filters filter DSL ngoài các field của struct (amount_gte, meta.provider...), kiểm tra theo whitelist của model
+	Filters map[string]any `form:"-" json:"-"`
+}
+
+// SetFilters gán filter DSL, dùng khi decode filter generic (VD query của export)
+func (q *BasePaginationQuery) SetFilters(filters map[string]any) {
+	q.Filters = filters
 }
 
 type PaginationResponse[T any] struct {
@@ -22,3 +30,12 @@ type PaginationResponse[T any] struct {
 	NextPage  *int64 `json:"next_page"`
 	PrevPage  *int64 `json:"prev_page"`
 }
+
+// CursorPaginationResponse kết quả phân trang keyset (cursor=), Total chỉ có khi with_total=true
+type CursorPaginationResponse[T any] struct {
+	Items      []T     `json:"items"`
+	Limit      int64   `json:"limit"`
+	Total      *int64  `json:"total,omitempty"`
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+type ListProviderReconciliationFilter struct {
+	BasePaginationQuery
+	Cutoff    *string  `json:"cutoff,omitempty" form:"cutoff"`
+	Status    []string `json:"status,omitempty" form:"status[]"`
+	Providers []string `json:"providers,omitempty" form:"providers[]"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+// ListSnapshotFilter lọc snapshot số dư, StartDate/EndDate (YYYY-MM-DD) theo as_of_date
+type ListSnapshotFilter struct {
+	BasePaginationQuery
+	AccountID *uint64  `json:"account_id,omitempty" form:"account_id"`
+	Currency  []string `json:"currency,omitempty" form:"currency[]"`
+	Status    []string `json:"status,omitempty" form:"status[]"`
+}
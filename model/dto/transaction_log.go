@@ -0,0 +1,9 @@
+package dto
+
+// ListTransactionLogFilter lọc event outbox (transaction_logs), StartDate/EndDate (YYYY-MM-DD) theo created_at
+type ListTransactionLogFilter struct {
+	BasePaginationQuery
+	AggregateType *string  `json:"aggregate_type,omitempty" form:"aggregate_type"`
+	EventType     *string  `json:"event_type,omitempty" form:"event_type"`
+	Status        []string `json:"status,omitempty" form:"status[]"`
+}
@@ -61,3 +61,12 @@ type ListWebhookLogResponse struct {
 	Limit     int         `json:"limit"`
 	Items     interface{} `json:"items"`
 }
+
+// ListWebhookDeliveryFilter lọc lịch sử gửi webhook ledger event (webhook_logs)
+type ListWebhookDeliveryFilter struct {
+	BasePaginationQuery
+	WebhookConfigID *string `json:"webhook_config_id,omitempty" form:"webhook_config_id"`
+	EventName       *string `json:"event_name,omitempty" form:"event_name"`
+	Status          *string `json:"status,omitempty" form:"status"`
+	MessageID       *string `json:"message_id,omitempty" form:"message_id"`
+}
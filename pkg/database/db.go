@@ -2,6 +2,7 @@ package database
 
 import (
 	config "core-ledger/configs"
+	"core-ledger/pkg/tracing"
 	"fmt"
 	"sync"
 	"time"
@@ -25,6 +26,9 @@ func connect(dsn string) (*gorm.DB, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := db.Use(tracing.GormPlugin()); err != nil {
+		return nil, err
+	}
 	return db, nil
 }
 
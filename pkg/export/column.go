@@ -0,0 +1,67 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Field cột của file: Key dùng làm khoá JSONL và tham số select, Header là tiêu đề XLSX/CSV
+type Field struct {
+	Key    string
+	Header string
+}
+
+// Column cột export của resource kiểu T
+type Column[T any] struct {
+	Key    string
+	Header string
+	Value  func(item T) any
+	// index cột số thứ tự, giá trị do Run điền
+	index bool
+}
+
+// Col khai báo cột lấy giá trị từ bản ghi
+func Col[T any](key, header string, value func(item T) any) Column[T] {
+	return Column[T]{Key: key, Header: header, Value: value}
+}
+
+// IndexCol cột số thứ tự (1, 2, 3...) theo thứ tự dòng trong file
+func IndexCol[T any](key, header string) Column[T] {
+	return Column[T]{Key: key, Header: header, index: true}
+}
+
+// Columns danh sách cột đăng ký cho một resource
+type Columns[T any] []Column[T]
+
+// Select chọn cột theo key và theo đúng thứ tự truyền vào, không truyền key nào thì lấy tất cả
+func (c Columns[T]) Select(keys []string) (Columns[T], error) {
+	if len(keys) == 0 {
+		return c, nil
+	}
+	byKey := make(map[string]Column[T], len(c))
+	for _, col := range c {
+		byKey[col.Key] = col
+	}
+	selected := make(Columns[T], 0, len(keys))
+	var unknown []string
+	for _, key := range keys {
+		col, ok := byKey[key]
+		if !ok {
+			unknown = append(unknown, key)
+			continue
+		}
+		selected = append(selected, col)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownColumn, strings.Join(unknown, ", "))
+	}
+	return selected, nil
+}
+
+func (c Columns[T]) Fields() []Field {
+	fields := make([]Field, len(c))
+	for i, col := range c {
+		fields[i] = Field{Key: col.Key, Header: col.Header}
+	}
+	return fields
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrUnknownColumn key cột không có trong registry của resource
+	ErrUnknownColumn = errors.New("unknown export column")
+	// ErrTooManyRows số dòng vượt Options.MaxRows
+	ErrTooManyRows = errors.New("export exceeds max rows")
+)
+
+// Page đọc một trang dữ liệu (page bắt đầu từ 1), total là tổng số bản ghi khớp filter
+type Page[T any] func(ctx context.Context, page, limit int64) (items []T, total int64, err error)
+
+// Options giới hạn khi stream dữ liệu
+type Options struct {
+	// PageSize số bản ghi mỗi lần gọi Page, mặc định 1000
+	PageSize int64
+	// MaxRows > 0 thì trả ErrTooManyRows trước khi ghi nếu tổng số bản ghi vượt quá
+	MaxRows int64
+	// Progress gọi sau mỗi trang đã ghi
+	Progress func(processed, total int64)
+}
+
+// Run ghi header, stream lần lượt từng trang qua columns rồi Close writer. Trả về số dòng đã ghi
+func Run[T any](ctx context.Context, w Writer, columns Columns[T], fetch Page[T], opts Options) (int64, error) {
+	if opts.PageSize <= 0 {
+		opts.PageSize = 1000
+	}
+	if err := w.WriteHeader(columns.Fields()); err != nil {
+		return 0, err
+	}
+
+	var processed int64
+	values := make([]any, len(columns))
+	for page := int64(1); ; page++ {
+		if err := ctx.Err(); err != nil {
+			return processed, err
+		}
+		items, total, err := fetch(ctx, page, opts.PageSize)
+		if err != nil {
+			return processed, err
+		}
+		if opts.MaxRows > 0 && total > opts.MaxRows {
+			return processed, fmt.Errorf("%w: %d > %d", ErrTooManyRows, total, opts.MaxRows)
+		}
+		for _, item := range items {
+			processed++
+			for i, col := range columns {
+				if col.index {
+					values[i] = processed
+					continue
+				}
+				values[i] = col.Value(item)
+			}
+			if err := w.WriteRow(values); err != nil {
+				return processed, err
+			}
+		}
+		if opts.Progress != nil {
+			opts.Progress(processed, total)
+		}
+		if int64(len(items)) < opts.PageSize || processed >= total {
+			break
+		}
+	}
+	return processed, w.Close()
+}
@@ -0,0 +1,133 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/xuri/excelize/v2"
+)
+
+type row struct {
+	ID     int64
+	Code   string
+	Amount decimal.Decimal
+	Parent *string
+	At     time.Time
+}
+
+func testColumns() Columns[row] {
+	return Columns[row]{
+		IndexCol[row]("no", "STT"),
+		Col("code", "Mã", func(r row) any { return r.Code }),
+		Col("amount", "Số tiền", func(r row) any { return r.Amount }),
+		Col("parent", "Cha", func(r row) any { return r.Parent }),
+		Col("at", "Thời gian", func(r row) any { return r.At }),
+	}
+}
+
+func pager(rows []row) Page[row] {
+	return func(_ context.Context, page, limit int64) ([]row, int64, error) {
+		start := (page - 1) * limit
+		if start >= int64(len(rows)) {
+			return nil, int64(len(rows)), nil
+		}
+		end := min(start+limit, int64(len(rows)))
+		return rows[start:end], int64(len(rows)), nil
+	}
+}
+
+func testRows(n int) []row {
+	parent := "P"
+	rows := make([]row, n)
+	for i := range rows {
+		rows[i] = row{ID: int64(i + 1), Code: "C" + string(rune('a'+i)), Amount: decimal.RequireFromString("1000.5")}
+	}
+	rows[0].Parent = &parent
+	rows[0].At = time.Date(2025, 12, 1, 17, 0, 0, 0, time.UTC)
+	return rows
+}
+
+func TestRunCSVPagesAndProgress(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatCSV, &buf, time.FixedZone("ICT", 7*3600))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls int
+	n, err := Run(context.Background(), w, testColumns(), pager(testRows(5)), Options{
+		PageSize: 2,
+		Progress: func(processed, total int64) { calls++ },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 5 || calls != 3 {
+		t.Fatalf("rows=%d progress calls=%d", n, calls)
+	}
+	lines := strings.Split(strings.TrimSpace(strings.TrimPrefix(buf.String(), "\ufeff")), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("want header + 5 rows, got %d lines", len(lines))
+	}
+	if lines[0] != "STT,Mã,Số tiền,Cha,Thời gian" {
+		t.Fatalf("header = %q", lines[0])
+	}
+	if lines[1] != "1,Ca,1000.5,P,2025-12-02 00:00:00" || lines[5] != "5,Ce,1000.5,," {
+		t.Fatalf("rows = %q / %q", lines[1], lines[5])
+	}
+}
+
+func TestRunJSONLSelectedColumns(t *testing.T) {
+	cols, err := testColumns().Select([]string{"code", "parent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w, _ := NewWriter(FormatJSONL, &buf, nil)
+	if _, err := Run(context.Background(), w, cols, pager(testRows(2)), Options{}); err != nil {
+		t.Fatal(err)
+	}
+	want := "{\"code\":\"Ca\",\"parent\":\"P\"}\n{\"code\":\"Cb\",\"parent\":null}\n"
+	if buf.String() != want {
+		t.Fatalf("got %q", buf.String())
+	}
+}
+
+func TestRunXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewWriter(FormatXLSX, &buf, time.UTC)
+	if _, err := Run(context.Background(), w, testColumns(), pager(testRows(3)), Options{PageSize: 2}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := f.GetRows("Sheet1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || rows[0][1] != "Mã" || rows[3][1] != "Cc" {
+		t.Fatalf("rows = %v", rows)
+	}
+}
+
+func TestSelectUnknownColumn(t *testing.T) {
+	if _, err := testColumns().Select([]string{"code", "nope"}); !errors.Is(err, ErrUnknownColumn) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestRunMaxRows(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewWriter(FormatCSV, &buf, nil)
+	_, err := Run(context.Background(), w, testColumns(), pager(testRows(3)), Options{MaxRows: 2})
+	if !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format định dạng file export
+type Format string
+
+const (
+	FormatXLSX  Format = "xlsx"
+	FormatCSV   Format = "csv"
+	FormatJSONL Format = "jsonl"
+)
+
+// ParseFormat đọc định dạng từ request, rỗng thì mặc định xlsx
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case "":
+		return FormatXLSX, nil
+	case FormatXLSX, FormatCSV, FormatJSONL:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q", s)
+	}
+}
+
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSONL:
+		return "application/x-ndjson"
+	default:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+}
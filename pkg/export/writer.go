@@ -0,0 +1,223 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/xuri/excelize/v2"
+)
+
+// timeLayout định dạng thời gian trong XLSX/CSV, JSONL giữ RFC3339
+const timeLayout = "2006-01-02 15:04:05"
+
+// Writer ghi từng dòng ra file, Close ghi phần còn lại (XLSX ghi toàn bộ file khi Close)
+type Writer interface {
+	WriteHeader(fields []Field) error
+	WriteRow(values []any) error
+	Close() error
+}
+
+// NewWriter writer theo định dạng, thời gian trong XLSX/CSV hiển thị theo loc
+func NewWriter(format Format, w io.Writer, loc *time.Location) (Writer, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	switch format {
+	case FormatXLSX:
+		return newXLSXWriter(w, loc)
+	case FormatCSV:
+		return &csvWriter{w: w, csv: csv.NewWriter(w), loc: loc}, nil
+	case FormatJSONL:
+		return &jsonlWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+type xlsxWriter struct {
+	w      io.Writer
+	loc    *time.Location
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+// newXLSXWriter dùng StreamWriter của excelize: dòng đã ghi được đẩy ra file tạm, không giữ cả sheet trong RAM
+func newXLSXWriter(w io.Writer, loc *time.Location) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	stream, err := f.NewStreamWriter("Sheet1")
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &xlsxWriter{w: w, loc: loc, file: f, stream: stream}, nil
+}
+
+func (x *xlsxWriter) WriteHeader(fields []Field) error {
+	style, err := x.file.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#D3D3D3"}},
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+	})
+	if err != nil {
+		return err
+	}
+	// độ rộng cột phải đặt trước khi ghi dòng đầu tiên
+	if len(fields) > 0 {
+		if err := x.stream.SetColWidth(1, len(fields), 20); err != nil {
+			return err
+		}
+	}
+	cells := make([]any, len(fields))
+	for i, f := range fields {
+		cells[i] = excelize.Cell{StyleID: style, Value: f.Header}
+	}
+	return x.writeCells(cells)
+}
+
+func (x *xlsxWriter) WriteRow(values []any) error {
+	cells := make([]any, len(values))
+	for i, v := range values {
+		cells[i] = cellValue(v, x.loc)
+	}
+	return x.writeCells(cells)
+}
+
+func (x *xlsxWriter) writeCells(cells []any) error {
+	x.row++
+	cell, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.stream.SetRow(cell, cells)
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.w)
+}
+
+type csvWriter struct {
+	w   io.Writer
+	csv *csv.Writer
+	loc *time.Location
+}
+
+func (c *csvWriter) WriteHeader(fields []Field) error {
+	// BOM để Excel mở đúng UTF-8 (tiêu đề tiếng Việt)
+	if _, err := io.WriteString(c.w, "\ufeff"); err != nil {
+		return err
+	}
+	headers := make([]string, len(fields))
+	for i, f := range fields {
+		headers[i] = f.Header
+	}
+	return c.csv.Write(headers)
+}
+
+func (c *csvWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = fmt.Sprint(cellValue(v, c.loc))
+	}
+	return c.csv.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.csv.Flush()
+	return c.csv.Error()
+}
+
+// jsonlWriter mỗi dòng một object, key theo Field.Key và giữ thứ tự cột
+type jsonlWriter struct {
+	w    io.Writer
+	keys [][]byte
+	buf  bytes.Buffer
+}
+
+func (j *jsonlWriter) WriteHeader(fields []Field) error {
+	j.keys = make([][]byte, len(fields))
+	for i, f := range fields {
+		key, err := json.Marshal(f.Key)
+		if err != nil {
+			return err
+		}
+		j.keys[i] = key
+	}
+	return nil
+}
+
+func (j *jsonlWriter) WriteRow(values []any) error {
+	j.buf.Reset()
+	j.buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			j.buf.WriteByte(',')
+		}
+		j.buf.Write(j.keys[i])
+		j.buf.WriteByte(':')
+		data, err := json.Marshal(deref(v))
+		if err != nil {
+			return err
+		}
+		j.buf.Write(data)
+	}
+	j.buf.WriteString("}\n")
+	_, err := j.w.Write(j.buf.Bytes())
+	return err
+}
+
+func (j *jsonlWriter) Close() error {
+	return nil
+}
+
+// cellValue giá trị hiển thị trong XLSX/CSV: nil → "", thời gian theo loc, decimal giữ nguyên chữ số, map/slice/struct → JSON
+func cellValue(v any, loc *time.Location) any {
+	v = deref(v)
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return val
+	case time.Time:
+		if val.IsZero() {
+			return ""
+		}
+		return val.In(loc).Format(timeLayout)
+	case decimal.Decimal:
+		return val.String()
+	case json.RawMessage:
+		return string(val)
+	case fmt.Stringer:
+		return val.String()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// deref bỏ con trỏ, con trỏ/map/slice nil thành nil
+func deref(v any) any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || (rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice) && rv.IsNil() {
+		return nil
+	}
+	return rv.Interface()
+}
@@ -11,6 +11,8 @@ const (
 	ContextKeyCustomerRequest ContextKey = "customer_request"
 	ContextKeyEmployeeRequest ContextKey = "employee_request"
 	ContextKeyFingerprint     ContextKey = "Fingerprint"
+	ContextKeyPermissions     ContextKey = "permissions"
+	ContextKeyApiKey          ContextKey = "api_key"
 )
 
 func (t ContextKey) String() string {
@@ -119,8 +119,7 @@ func RespondOKWithError(c *gin.Context, err error) {
 	var appErr *core.AppError
 	if !errors.As(err, &appErr) {
 		appErr = &core.AppError{
-			Message:     appErr.Error(),
-			Description: appErr.Description,
+			Message: err.Error(),
 		}
 	}
 	c.AbortWithStatusJSON(http.StatusOK, Response{
@@ -1,142 +1,83 @@
+// Package logger giữ interface CustomLogger cho các service/handler hiện có,
+// ghi log qua logger dùng chung của pkg/logging (cùng level, redaction và field theo context).
 package logger
 
 import (
+	"context"
+	"core-ledger/pkg/logging"
 	"fmt"
-	"os"
-	"path"
-	"regexp"
-	"strconv"
+	"strings"
 
-	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
-var sensitiveKeys = []string{
-	"password",
-	"key",
-	"token",
-	"secret",
-	"credential",
-	"private",
-	"api_key",
-	"access_token",
-}
-
-func maskSensitiveString(msg string) string {
-	for _, key := range sensitiveKeys {
-		patterns := []string{
-			key + `=\S+`,
-			key + `:\s*\S+`,
-			key + `\s+\S+`,
-		}
-		for _, pattern := range patterns {
-			re := regexp.MustCompile(`(?i)` + pattern)
-			msg = re.ReplaceAllString(msg, key+"=********")
-		}
-	}
-	return msg
-}
-
-func maskSensitiveValue(v any) any {
-	if s, ok := v.(string); ok {
-		return maskSensitiveString(s)
-	}
-	return v
-}
-
-const (
-	ColorReset  = "\033[0m"
-	ColorRed    = "\033[31m"
-	ColorGreen  = "\033[32m"
-	ColorYellow = "\033[33m"
-	ColorBlue   = "\033[34m"
-	ColorCyan   = "\033[36m"
-)
-
-func init() {
-	logrus.SetReportCaller(true)
-	logrus.SetFormatter(&customLog{})
-	logrus.SetOutput(os.Stdout)
-}
-
 type CustomLogger interface {
 	Debug(msg ...any)
 	Error(msg ...any)
 	Info(msg ...any)
 	Warn(msg ...any)
+	// WithContext trả về logger gắn ctx, log sẽ kèm request_id/principal/tenant_id/trace_id đã gắn vào ctx
+	WithContext(ctx context.Context) CustomLogger
 }
 
 type customLog struct {
-	name   string
-	logger *logrus.Entry
+	name string
+	ctx  context.Context
 }
 
-func (l *customLog) Format(entry *logrus.Entry) ([]byte, error) {
-	// Mask toàn bộ dữ liệu nhạy cảm
-	entry.Message = maskSensitiveString(entry.Message)
-	for k, v := range entry.Data {
-		entry.Data[k] = maskSensitiveValue(v)
-	}
-
-	timestamp := fmt.Sprintf("%s[%s]%s", ColorCyan, entry.Time.Format("2006-01-02 15:04:05.000"), ColorReset)
-
-	var levelColor string
-	switch entry.Level {
-	case logrus.InfoLevel:
-		levelColor = ColorGreen
-	case logrus.WarnLevel:
-		levelColor = ColorYellow
-	case logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel:
-		levelColor = ColorRed
-	default:
-		levelColor = ColorBlue
-	}
-	level := fmt.Sprintf("%s[%s]%s", levelColor, entry.Level.String(), ColorReset)
-
-	provider := ""
-	if p, ok := entry.Data["provider"]; ok {
-		provider = fmt.Sprintf("%s[%s]%s", ColorBlue, p, ColorReset)
-		delete(entry.Data, "provider")
+func NewSystemLog(name string) CustomLogger {
+	return &customLog{
+		name: name,
 	}
+}
 
-	var fields string
-	for k, v := range entry.Data {
-		fields += fmt.Sprintf(" %s=%v", k, v)
+func (l *customLog) WithContext(ctx context.Context) CustomLogger {
+	return &customLog{
+		name: l.name,
+		ctx:  ctx,
 	}
+}
 
-	caller := ""
-	if entry.Caller != nil {
-		caller += path.Base(entry.Caller.File) + ":" + strconv.Itoa(entry.Caller.Line)
+func (l *customLog) sugar() *zap.SugaredLogger {
+	ctx := l.ctx
+	if ctx == nil {
+		ctx = context.Background()
 	}
-
-	msg := fmt.Sprintf("%s%s[%v] %s %s%s\n", level, timestamp, caller, provider, entry.Message, fields)
-	return []byte(msg), nil
+	// bỏ qua 2 frame (log + Info/Warn... của customLog) để caller là nơi gọi logger
+	return logging.FromContext(ctx).Named(l.name).WithOptions(zap.AddCallerSkip(2))
 }
 
-func NewSystemLog(name string) CustomLogger {
-	logger := logrus.New()
-	logger.SetOutput(os.Stdout)
-	logger.SetReportCaller(true)
-	logEntry := logrus.NewEntry(logger)
-	ct := &customLog{
-		name:   name,
-		logger: logEntry,
+// format ghép các tham số thành message; tham số đầu có verb (%d, %s...) thì dùng như format string
+func format(msg []any) string {
+	if len(msg) > 1 {
+		if f, ok := msg[0].(string); ok && strings.Contains(f, "%") {
+			return fmt.Sprintf(f, msg[1:]...)
+		}
 	}
-	logger.SetFormatter(ct)
-	return ct
+	return strings.TrimSuffix(fmt.Sprintln(msg...), "\n")
 }
 
 func (l *customLog) Debug(msg ...any) {
-	logrus.Debug("[", l.name, "] ", msg)
+	l.log(zap.DebugLevel, msg)
 }
 
 func (l *customLog) Error(msg ...any) {
-	logrus.Error("[", l.name, "] ", msg)
+	l.log(zap.ErrorLevel, msg)
 }
 
 func (l *customLog) Info(msg ...any) {
-	logrus.Info("[", l.name, "] ", msg)
+	l.log(zap.InfoLevel, msg)
 }
 
 func (l *customLog) Warn(msg ...any) {
-	logrus.Warn("[", l.name, "] ", msg)
+	l.log(zap.WarnLevel, msg)
+}
+
+func (l *customLog) log(level zapcore.Level, msg []any) {
+	s := l.sugar()
+	if !s.Level().Enabled(level) {
+		return
+	}
+	s.Log(level, format(msg))
 }
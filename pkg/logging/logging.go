@@ -3,38 +3,54 @@ package logging
 
 import (
 	"context"
-	"os"
+	config "core-ledger/configs"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	//gin
 	"github.com/gin-gonic/gin"
 )
 
-var (
-	defaultLogLevel = "INFO"       // Default log level if not set in environment
-	defaultLogMode  = "production" // Default log mode if not set in environment
-)
-
 // contextKey is a private string type to prevent collisions in the context map.
 type contextKey string
 
 const loggerKey = contextKey("logger")
 
+// Field names attached to the request/job logger by middleware and the queue worker.
+const (
+	FieldRequestID = "request_id"
+	FieldPrincipal = "principal"
+	FieldTenantID  = "tenant_id"
+	FieldTraceID   = "trace_id"
+	FieldSpanID    = "span_id"
+	FieldJobType   = "job_type"
+	FieldJobID     = "job_id"
+)
+
 var (
 	defaultLogger     *zap.SugaredLogger
 	defaultLoggerOnce sync.Once
+
+	// level is shared by the default logger and every logger derived from it,
+	// so SetLevel takes effect at runtime without rebuilding loggers.
+	level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
 )
 
 // NewLogger creates a new logger with the given configuration.
 func NewLogger(level string, development bool) *zap.SugaredLogger {
+	return newLogger(zap.NewAtomicLevelAt(levelToZapLevel(level)), development, nil)
+}
+
+func newLogger(atomicLevel zap.AtomicLevel, development bool, redactFields []string) *zap.SugaredLogger {
 	var config *zap.Config
 	if development {
 		config = &zap.Config{
-			Level:            zap.NewAtomicLevelAt(levelToZapLevel(level)),
+			Level:            atomicLevel,
 			Development:      true,
 			Encoding:         encodingConsole,
 			EncoderConfig:    developmentEncoderConfig,
@@ -43,7 +59,7 @@ func NewLogger(level string, development bool) *zap.SugaredLogger {
 		}
 	} else {
 		config = &zap.Config{
-			Level:            zap.NewAtomicLevelAt(levelToZapLevel(level)),
+			Level:            atomicLevel,
 			Encoding:         encodingJSON,
 			EncoderConfig:    productionEncoderConfig,
 			OutputPaths:      outputStderr,
@@ -51,7 +67,9 @@ func NewLogger(level string, development bool) *zap.SugaredLogger {
 		}
 	}
 
-	logger, err := config.Build()
+	logger, err := config.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return newRedactCore(core, redactFields)
+	}))
 	if err != nil {
 		logger = zap.NewNop()
 	}
@@ -60,15 +78,31 @@ func NewLogger(level string, development bool) *zap.SugaredLogger {
 }
 
 // NewLoggerFromEnv creates a new logger from the environment. It consumes
-// LOG_LEVEL for determining the level and LOG_MODE for determining the output
-// parameters.
+// LOG_LEVEL for determining the level, LOG_MODE for determining the output
+// parameters and LOG_REDACT_FIELDS for the redacted field names.
+// The logger uses the shared runtime level (see SetLevel).
 func NewLoggerFromEnv() *zap.SugaredLogger {
-	level := os.Getenv("LOG_LEVEL")
-	if level == "" {
-		level = defaultLogLevel
+	cfg := config.GetLoggingConfig()
+	level.SetLevel(levelToZapLevel(cfg.Level))
+	development := strings.ToLower(strings.TrimSpace(cfg.Mode)) == "development"
+	return newLogger(level, development, cfg.RedactFields)
+}
+
+// SetLevel changes the level of the default logger and all loggers derived from it.
+func SetLevel(name string) error {
+	l, ok := parseLevel(name)
+	if !ok {
+		return fmt.Errorf("unknown log level %q", name)
 	}
-	development := strings.ToLower(strings.TrimSpace(os.Getenv("LOG_MODE"))) == "development"
-	return NewLogger(level, development)
+	DefaultLogger() // make sure LOG_LEVEL does not override the new level later
+	level.SetLevel(l)
+	return nil
+}
+
+// Level returns the current level of the default logger (DEBUG, INFO, WARNING...).
+func Level() string {
+	DefaultLogger()
+	return levelName(level.Level())
 }
 
 // DefaultLogger returns the default logger for the package.
@@ -83,6 +117,18 @@ func DefaultLogger() *zap.SugaredLogger {
 func WithLogger(ctx context.Context, logger *zap.SugaredLogger) context.Context {
 	return context.WithValue(ctx, loggerKey, logger)
 }
+
+// WithFields returns a context whose logger carries the given key/value pairs
+// in addition to the fields already attached to ctx.
+func WithFields(ctx context.Context, keysAndValues ...any) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(keysAndValues...))
+}
+
+// AddFields attaches key/value pairs to the logger of the request, so that
+// handlers, services and repos reading the request context log them.
+func AddFields(c *gin.Context, keysAndValues ...any) {
+	c.Request = c.Request.WithContext(WithFields(c.Request.Context(), keysAndValues...))
+}
 func From(ctx context.Context) *zap.SugaredLogger {
 	return FromContext(ctx)
 }
@@ -101,6 +147,10 @@ func FromContext(ctx context.Context) *zap.SugaredLogger {
 	if logger, ok := ctx.Value(loggerKey).(*zap.SugaredLogger); ok {
 		return logger
 	}
+	// ctx có span (otelgin, job) thì gắn trace_id/span_id để log đối chiếu được với trace
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return DefaultLogger().With(FieldTraceID, sc.TraceID().String(), FieldSpanID, sc.SpanID().String())
+	}
 	return DefaultLogger()
 }
 
@@ -159,24 +209,53 @@ var developmentEncoderConfig = zapcore.EncoderConfig{
 // levelToZapLevel converts the given string to the appropriate zap level
 // value.
 func levelToZapLevel(s string) zapcore.Level {
+	if l, ok := parseLevel(s); ok {
+		return l
+	}
+	return zapcore.WarnLevel
+}
+
+// parseLevel converts a level name (DEBUG, INFO, WARNING...) to a zap level,
+// ok is false for unknown names.
+func parseLevel(s string) (zapcore.Level, bool) {
 	switch strings.ToUpper(strings.TrimSpace(s)) {
 	case levelDebug:
-		return zapcore.DebugLevel
+		return zapcore.DebugLevel, true
 	case levelInfo:
-		return zapcore.InfoLevel
+		return zapcore.InfoLevel, true
 	case levelWarning:
-		return zapcore.WarnLevel
+		return zapcore.WarnLevel, true
 	case levelError:
-		return zapcore.ErrorLevel
+		return zapcore.ErrorLevel, true
 	case levelCritical:
-		return zapcore.DPanicLevel
+		return zapcore.DPanicLevel, true
 	case levelAlert:
-		return zapcore.PanicLevel
+		return zapcore.PanicLevel, true
 	case levelEmergency:
-		return zapcore.FatalLevel
+		return zapcore.FatalLevel, true
 	}
+	return zapcore.InfoLevel, false
+}
 
-	return zapcore.WarnLevel
+// levelName converts a zap level back to the level name used by LOG_LEVEL.
+func levelName(l zapcore.Level) string {
+	switch l {
+	case zapcore.DebugLevel:
+		return levelDebug
+	case zapcore.InfoLevel:
+		return levelInfo
+	case zapcore.WarnLevel:
+		return levelWarning
+	case zapcore.ErrorLevel:
+		return levelError
+	case zapcore.DPanicLevel:
+		return levelCritical
+	case zapcore.PanicLevel:
+		return levelAlert
+	case zapcore.FatalLevel:
+		return levelEmergency
+	}
+	return levelDebug
 }
 
 // levelEncoder transforms a zap level to the associated stackdriver level.
@@ -0,0 +1,55 @@
+package logging
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRedactCore(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(newRedactCore(core, []string{"password", "api_key"})).Sugar()
+
+	logger.With("client_api_key", "k-123").Infow("login password=hunter2 ok", "Password", "hunter2", "note", "api_key: abc", "user", "alice")
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries", len(entries))
+	}
+	entry := entries[0]
+	if entry.Message != "login password=******** ok" {
+		t.Fatalf("message = %q", entry.Message)
+	}
+	fields := entry.ContextMap()
+	want := map[string]string{
+		"client_api_key": redactedValue,
+		"Password":       redactedValue,
+		"note":           "api_key=" + redactedValue,
+		"user":           "alice",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Fatalf("field %s = %v, want %v", k, fields[k], v)
+		}
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	previous := Level()
+	defer func() { _ = SetLevel(previous) }()
+
+	if err := SetLevel("debug"); err != nil {
+		t.Fatal(err)
+	}
+	if Level() != levelDebug || !DefaultLogger().Desugar().Core().Enabled(zapcore.DebugLevel) {
+		t.Fatalf("level = %s, want DEBUG", Level())
+	}
+	if err := SetLevel("verbose"); err == nil {
+		t.Fatal("expected error for unknown level")
+	}
+	if Level() != levelDebug {
+		t.Fatalf("level changed to %s after invalid input", Level())
+	}
+}
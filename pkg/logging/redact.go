@@ -0,0 +1,109 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedValue replaces the value of redacted fields.
+const redactedValue = "********"
+
+// redactCore masks sensitive data before it reaches the encoder:
+//   - fields whose name matches a configured name (or ends with "_<name>") are replaced entirely
+//   - "<name>=value", "<name>: value", "x_<name>": "value" inside messages and string fields are masked
+type redactCore struct {
+	zapcore.Core
+	fields  []string
+	pattern *regexp.Regexp
+}
+
+func newRedactCore(core zapcore.Core, fields []string) zapcore.Core {
+	if len(fields) == 0 {
+		return core
+	}
+	names := make([]string, 0, len(fields))
+	quoted := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		names = append(names, f)
+		quoted = append(quoted, regexp.QuoteMeta(f))
+	}
+	if len(names) == 0 {
+		return core
+	}
+	return &redactCore{
+		Core:    core,
+		fields:  names,
+		pattern: regexp.MustCompile(`(?i)\b([\w-]*(?:` + strings.Join(quoted, "|") + `))["']?\s*[=:]\s*["']?[^\s,;&"']+`),
+	}
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{
+		Core:    c.Core.With(c.redact(fields)),
+		fields:  c.fields,
+		pattern: c.pattern,
+	}
+}
+
+func (c *redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = c.mask(entry.Message)
+	return c.Core.Write(entry, c.redact(fields))
+}
+
+func (c *redactCore) redact(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		var replaced zapcore.Field
+		switch {
+		case c.sensitive(f.Key):
+			replaced = zapcore.Field{Key: f.Key, Type: zapcore.StringType, String: redactedValue}
+		case f.Type == zapcore.StringType:
+			masked := c.mask(f.String)
+			if masked == f.String {
+				continue
+			}
+			replaced = f
+			replaced.String = masked
+		default:
+			continue
+		}
+		if out == nil {
+			out = append(make([]zapcore.Field, 0, len(fields)), fields...)
+		}
+		out[i] = replaced
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func (c *redactCore) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, name := range c.fields {
+		if key == name || strings.HasSuffix(key, "_"+name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *redactCore) mask(s string) string {
+	if s == "" {
+		return s
+	}
+	return c.pattern.ReplaceAllString(s, "${1}="+redactedValue)
+}
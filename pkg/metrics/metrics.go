@@ -0,0 +1,116 @@
+// Package metrics khai báo Prometheus metrics của core-ledger (HTTP, ghi sổ, queue, outbox, DB pool).
+// Danh sách metric và label xem docs/metrics.md; label chỉ nhận tập giá trị hữu hạn (route template, mã lỗi...).
+package metrics
+
+import (
+	"core-ledger/internal/core"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "core_ledger"
+
+// Kết quả ghi sổ / xử lý job
+const (
+	ResultSuccess  = "success"
+	ResultRejected = "rejected"
+	ResultError    = "error"
+	ResultFailure  = "failure"
+	ResultRetry    = "retry"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status class.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status class.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	postings = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "journal_postings_total",
+		Help:      "Journal posting operations by operation and result (success, rejected, error).",
+	}, []string{"operation", "result"})
+
+	postingDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "journal_posting_duration_seconds",
+		Help:      "Journal posting latency by operation.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation"})
+
+	postingRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "journal_rejections_total",
+		Help:      "Journal postings rejected by business validation, by operation and reason (error scope).",
+	}, []string{"operation", "reason"})
+
+	jobs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "queue_jobs_total",
+		Help:      "Queue jobs processed by queue, job type and result (success, retry, failure).",
+	}, []string{"queue", "type", "result"})
+
+	jobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "queue_job_duration_seconds",
+		Help:      "Queue job processing time by queue and job type.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300},
+	}, []string{"queue", "type"})
+)
+
+// Handler endpoint /metrics
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveHTTP ghi nhận một request, route là template của gin (VD /api/v2/journals/:id)
+func ObserveHTTP(method, route string, status int, duration time.Duration) {
+	class := strconv.Itoa(status/100) + "xx"
+	httpRequests.WithLabelValues(method, route, class).Inc()
+	httpDuration.WithLabelValues(method, route, class).Observe(duration.Seconds())
+}
+
+// ObservePosting ghi nhận một lần ghi sổ: AppError là bị từ chối (reason = scope của lỗi), lỗi khác là lỗi hệ thống
+func ObservePosting(operation string, start time.Time, err error) {
+	postingDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	if err == nil {
+		postings.WithLabelValues(operation, ResultSuccess).Inc()
+		return
+	}
+	var appErr *core.AppError
+	if errors.As(err, &appErr) {
+		ObserveRejection(operation, appErr)
+		return
+	}
+	postings.WithLabelValues(operation, ResultError).Inc()
+}
+
+// ObserveRejection ghi nhận ghi sổ bị từ chối khi lỗi không trả về cho caller (VD batch ATOMIC lưu FAILED)
+func ObserveRejection(operation string, appErr *core.AppError) {
+	reason := appErr.Scope
+	if reason == "" {
+		reason = string(appErr.Code)
+	}
+	postings.WithLabelValues(operation, ResultRejected).Inc()
+	postingRejections.WithLabelValues(operation, reason).Inc()
+}
+
+// ObserveJob ghi nhận kết quả xử lý một job
+func ObserveJob(queue, jobType, result string, duration time.Duration) {
+	jobs.WithLabelValues(queue, jobType, result).Inc()
+	jobDuration.WithLabelValues(queue, jobType).Observe(duration.Seconds())
+}
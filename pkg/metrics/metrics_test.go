@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"core-ledger/internal/core"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObservePostingClassifiesResult(t *testing.T) {
+	start := time.Now()
+	ObservePosting("test", start, nil)
+	ObservePosting("test", start, core.NewError(core.ErrCodeLedgerJournalUnbalanced))
+	ObservePosting("test", start, errors.New("connection reset"))
+
+	for result, want := range map[string]float64{ResultSuccess: 1, ResultRejected: 1, ResultError: 1} {
+		if got := testutil.ToFloat64(postings.WithLabelValues("test", result)); got != want {
+			t.Fatalf("%s: expected %v, got %v", result, want, got)
+		}
+	}
+	reason := core.MapCodeToScope[core.ErrCodeLedgerJournalUnbalanced]
+	if got := testutil.ToFloat64(postingRejections.WithLabelValues("test", reason)); got != 1 {
+		t.Fatalf("expected rejection with reason %s, got %v", reason, got)
+	}
+}
+
+func TestObserveHTTPGroupsStatusClass(t *testing.T) {
+	ObserveHTTP("GET", "/api/v2/journals/:id", 404, time.Millisecond)
+	ObserveHTTP("GET", "/api/v2/journals/:id", 422, time.Millisecond)
+
+	if got := testutil.ToFloat64(httpRequests.WithLabelValues("GET", "/api/v2/journals/:id", "4xx")); got != 2 {
+		t.Fatalf("expected 2 requests in 4xx, got %v", got)
+	}
+}
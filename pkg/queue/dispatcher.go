@@ -1,38 +1,94 @@
 package queue
 
 import (
+	"context"
+	"core-ledger/pkg/tracing"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// Dispatcher interface để gửi job (thay thế globals)
+// Dispatcher interface để gửi job (thay thế globals).
+// Các hàm Dispatch* trả về task ID để tra trạng thái/tiến độ qua GET /jobs/:id
 type Dispatcher interface {
-	Dispatch(job Job, options ...DispatchOption) error
-	DispatchLater(job Job, delay time.Duration, options ...DispatchOption) error
-	DispatchAt(job Job, processAt time.Time, options ...DispatchOption) error
-	DispatchOnQueue(job Job, queueName string, options ...DispatchOption) error
+	Dispatch(job Job, options ...DispatchOption) (string, error)
+	// DispatchContext giống Dispatch, lan truyền trace context của ctx sang job
+	DispatchContext(ctx context.Context, job Job, options ...DispatchOption) (string, error)
+	DispatchLater(job Job, delay time.Duration, options ...DispatchOption) (string, error)
+	DispatchAt(job Job, processAt time.Time, options ...DispatchOption) (string, error)
+	DispatchOnQueue(job Job, queueName string, options ...DispatchOption) (string, error)
+	// DispatchWorkflow enqueue chain/batch, trả về id workflow để tra trạng thái bằng GetWorkflow
+	DispatchWorkflow(ctx context.Context, wf *Workflow) (string, error)
 }
 
+// ErrDuplicateJob job có UniqueKey đã được enqueue và khoá chống trùng còn hiệu lực
+var ErrDuplicateJob = asynq.ErrDuplicateTask
+
 type asynqDispatcher struct {
 	client *asynq.Client
+	// rdb lưu trạng thái workflow, nil thì DispatchWorkflow trả lỗi
+	rdb redis.UniversalClient
 }
 
 func NewDispatcher(client *asynq.Client) Dispatcher {
 	return &asynqDispatcher{client: client}
 }
 
-func (d *asynqDispatcher) Dispatch(job Job, options ...DispatchOption) error {
+// NewDispatcherWithRedis dispatcher hỗ trợ cả workflow (chain/batch)
+func NewDispatcherWithRedis(client *asynq.Client, rdb redis.UniversalClient) Dispatcher {
+	return &asynqDispatcher{client: client, rdb: rdb}
+}
+
+func (d *asynqDispatcher) Dispatch(job Job, options ...DispatchOption) (string, error) {
+	return d.DispatchContext(context.Background(), job, options...)
+}
+
+func (d *asynqDispatcher) DispatchContext(ctx context.Context, job Job, options ...DispatchOption) (string, error) {
+	info, err := d.enqueue(ctx, job, nil, options...)
+	if err != nil {
+		return "", err
+	}
+	return info.ID, nil
+}
+
+// enqueue tạo task (kèm thông tin workflow nếu có) và đẩy vào asynq
+func (d *asynqDispatcher) enqueue(ctx context.Context, job Job, workflow *WorkflowMeta, options ...DispatchOption) (info *asynq.TaskInfo, err error) {
 	if d.client == nil {
-		return fmt.Errorf("queue client not initialized")
+		return nil, fmt.Errorf("queue client not initialized")
 	}
 
-	task, err := CreateTask(job)
+	ctx, span := tracing.Tracer().Start(ctx, "queue.dispatch "+job.GetType(),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "asynq"),
+			attribute.String("messaging.destination.name", job.GetQueue()),
+			attribute.String("queue.job_type", job.GetType()),
+		),
+	)
+	defer func() {
+		if err != nil && !errors.Is(err, ErrDuplicateJob) {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	task, err := createTask(ctx, job, workflow)
 	if err != nil {
-		return fmt.Errorf("failed to create task: %v", err)
+		return nil, fmt.Errorf("failed to create task: %v", err)
 	}
 
+	return d.client.EnqueueContext(ctx, task, taskOptions(job, task, options...)...)
+}
+
+// taskOptions option enqueue lấy từ metadata của job, DispatchOption truyền vào được áp dụng sau cùng
+func taskOptions(job Job, task *asynq.Task, options ...DispatchOption) []asynq.Option {
 	var asynqOpts []asynq.Option
 
 	queueName := job.GetQueue()
@@ -48,25 +104,30 @@ func (d *asynqDispatcher) Dispatch(job Job, options ...DispatchOption) error {
 		asynqOpts = append(asynqOpts, asynq.ProcessIn(delay))
 	}
 
+	if job.GetUniqueKey() != "" {
+		asynqOpts = append(asynqOpts, asynq.Unique(job.GetUniqueTTL()))
+	}
+
+	// giữ task đã xong để đọc kết quả/tiến độ qua Inspector
+	asynqOpts = append(asynqOpts, asynq.Retention(DefaultResultRetention))
+
 	for _, option := range options {
 		asynqOpts = append(asynqOpts, option(task))
 	}
-
-	_, err = d.client.Enqueue(task, asynqOpts...)
-	return err
+	return asynqOpts
 }
 
-func (d *asynqDispatcher) DispatchLater(job Job, delay time.Duration, options ...DispatchOption) error {
+func (d *asynqDispatcher) DispatchLater(job Job, delay time.Duration, options ...DispatchOption) (string, error) {
 	job.SetDelay(delay)
 	return d.Dispatch(job, options...)
 }
 
-func (d *asynqDispatcher) DispatchAt(job Job, processAt time.Time, options ...DispatchOption) error {
+func (d *asynqDispatcher) DispatchAt(job Job, processAt time.Time, options ...DispatchOption) (string, error) {
 	options = append(options, ProcessAt(processAt))
 	return d.Dispatch(job, options...)
 }
 
-func (d *asynqDispatcher) DispatchOnQueue(job Job, queueName string, options ...DispatchOption) error {
+func (d *asynqDispatcher) DispatchOnQueue(job Job, queueName string, options ...DispatchOption) (string, error) {
 	job.SetQueue(queueName)
 	return d.Dispatch(job, options...)
 }
@@ -2,17 +2,21 @@ package handlers
 
 import (
 	"context"
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/files"
 	model "core-ledger/model/core-ledger"
 	"core-ledger/pkg/logger"
 	"core-ledger/pkg/queue"
 	"core-ledger/pkg/queue/jobs"
 	"core-ledger/pkg/repo"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strings"
 
+	"github.com/hibiken/asynq"
 	"github.com/xuri/excelize/v2"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
@@ -22,14 +26,24 @@ import (
 type ImportCoaAccountHandler struct {
 	db            *gorm.DB
 	coAccountRepo repo.CoAccountRepo
+	fileService   *files.FileService
 	logger        logger.CustomLogger
 	// thêm dependency nếu cần (ví dụ: services, repos)
 }
 
-func NewImportCoaAccountHandler(db *gorm.DB, coAccountRepo repo.CoAccountRepo) *ImportCoaAccountHandler {
+// ImportCoaAccountResult kết quả import ghi qua Reporter, đọc lại ở GET /jobs/:id
+type ImportCoaAccountResult struct {
+	Rows         int64    `json:"rows"`
+	Imported     int64    `json:"imported"`
+	Skipped      int64    `json:"skipped"`
+	FailedSheets []string `json:"failed_sheets,omitempty"`
+}
+
+func NewImportCoaAccountHandler(db *gorm.DB, coAccountRepo repo.CoAccountRepo, fileService *files.FileService) *ImportCoaAccountHandler {
 	return &ImportCoaAccountHandler{
 		db:            db,
 		coAccountRepo: coAccountRepo,
+		fileService:   fileService,
 		logger:        logger.NewSystemLog("ImportCoaAccountHandler"),
 	}
 }
@@ -50,22 +64,34 @@ func (h *ImportCoaAccountHandler) Handle(ctx context.Context, j queue.Job) error
 		return fmt.Errorf("invalid job type, expect *ImportCoaAccount")
 	}
 	data := job.Data
-	h.logger.Info("Data", data.TmpFile)
-	if data.TmpFile == "" {
-		log.Fatal("File path is empty")
-	}
-	f, err := excelize.OpenFile(data.TmpFile)
-
+	f, err := h.openWorkbook(ctx, data)
 	if err != nil {
 		return err
 	}
-	var accounts []*model.CoaAccount
+	defer f.Close()
+	// đọc trước mọi sheet để biết tổng số dòng khi báo tiến độ
 	sheets := f.GetSheetList()
+	sheetRows := make(map[string][][]string, len(sheets))
+	var total int64
 	for _, sheet := range sheets {
 		rows, _ := f.GetRows(sheet)
 		if len(rows) < 2 {
 			continue
 		}
+		sheetRows[sheet] = rows
+		total += int64(len(rows) - 1)
+	}
+
+	reporter := queue.ReporterFromContext(ctx)
+	result := ImportCoaAccountResult{Rows: total}
+	var processed int64
+	for _, sheet := range sheets {
+		rows, ok := sheetRows[sheet]
+		if !ok {
+			continue
+		}
+		var accounts []*model.CoaAccount
+		var skipped int64
 		err := h.db.Transaction(func(tx *gorm.DB) error {
 			headers := rows[0]
 			h.logger.Info("Importing sheet %s with headers %v", sheet, headers)
@@ -98,6 +124,7 @@ func (h *ImportCoaAccountHandler) Handle(ctx context.Context, j queue.Job) error
 				}
 				if code == "" {
 					h.logger.Warn("Skipping empty code at row %d in sheet %s", i+2, sheet)
+					skipped++
 					continue
 				}
 
@@ -126,22 +153,52 @@ func (h *ImportCoaAccountHandler) Handle(ctx context.Context, j queue.Job) error
 					Metadata: metadataJSON, // TODO: parse JSON nếu cần
 
 				}
+				// file import không có cột chính sách số dư: lấy mặc định theo type (chỉ áp dụng khi tạo mới, upsert không ghi đè allow_negative)
+				account.ApplyDefaultBalancePolicy()
 
 				accounts = append(accounts, account)
 				// h.logger.Info("Importing co-account %s with data %v", code, accounts)
 
 			}
-			h.coAccountRepo.Upsert(accounts, []string{})
-			return nil
+			return h.coAccountRepo.Upsert(accounts, []string{})
 		})
+		processed += int64(len(rows) - 1)
 		if err != nil {
 			fmt.Printf("Sheet %s failed: %v\n", sheet, err)
+			result.FailedSheets = append(result.FailedSheets, sheet)
+			reporter.Progress(ctx, processed, total, "sheet "+sheet+" failed")
 			continue // tiếp tục sheet khác
 		}
+		result.Imported += int64(len(accounts))
+		result.Skipped += skipped
+		reporter.Progress(ctx, processed, total, "sheet "+sheet+" imported")
 		fmt.Printf("Sheet %s imported successfully\n", sheet)
 	}
-	defer os.Remove(data.TmpFile)
-	return nil // trả về error để asynq retry nếu cần
+	if data.TmpFile != "" {
+		defer os.Remove(data.TmpFile)
+	}
+	return reporter.SetResult(ctx, result) // trả về error để asynq retry nếu cần
+}
+
+// openWorkbook đọc file upload từ storage; job enqueue trước khi có storage vẫn đọc TmpFile trên máy worker
+func (h *ImportCoaAccountHandler) openWorkbook(ctx context.Context, data jobs.DataImportCoaAccount) (*excelize.File, error) {
+	if data.FileID == 0 {
+		if data.TmpFile == "" {
+			return nil, fmt.Errorf("import file is empty: %w", asynq.SkipRetry)
+		}
+		return excelize.OpenFile(data.TmpFile)
+	}
+	rc, file, err := h.fileService.Open(ctx, data.FileID)
+	if err != nil {
+		var appErr *core.AppError
+		if errors.As(err, &appErr) {
+			return nil, fmt.Errorf("%s: %w", appErr.Description, asynq.SkipRetry)
+		}
+		return nil, err
+	}
+	defer rc.Close()
+	h.logger.Info("Importing co-accounts from file %d (%s)", file.ID, file.OriginalName)
+	return excelize.OpenReader(rc)
 }
 
 // Failed: hook được gọi khi job đã hết retry hoặc timeout
@@ -152,6 +209,5 @@ func (h *ImportCoaAccountHandler) Failed(ctx context.Context, j queue.Job, err e
 	} else {
 		log.Printf("[FAILED] DataProcessJob Error=%v", err)
 	}
-	// TODO: Có thể ghi log vào DB, tạo transaction_log, hoặc đẩy sang channel cảnh báo...
-	_ = h // giữ chỗ nếu sau này cần dùng repo để lưu DB
+	// worker đã ghi job vào failed_jobs (FailedJobRecorder), chạy lại qua /admin/failed-jobs
 }
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/module/idempotency"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/queue/jobs"
+	"time"
+)
+
+// CleanupIdempotencyKeysHandler xử lý job xoá idempotency key hết hạn
+type CleanupIdempotencyKeysHandler struct {
+	store  idempotency.Store
+	logger logger.CustomLogger
+}
+
+func NewCleanupIdempotencyKeysHandler(store idempotency.Store) *CleanupIdempotencyKeysHandler {
+	return &CleanupIdempotencyKeysHandler{
+		store:  store,
+		logger: logger.NewSystemLog("CleanupIdempotencyKeysHandler"),
+	}
+}
+
+// NewCleanupIdempotencyKeysRegistration: provider đăng ký job/handler vào group "queue-registrations"
+func NewCleanupIdempotencyKeysRegistration(h *CleanupIdempotencyKeysHandler) queue.Registration {
+	return queue.Registration{
+		Type:     jobs.CleanupIdempotencyKeysJobType,
+		Template: &jobs.CleanupIdempotencyKeys{},
+		Handler:  h,
+	}
+}
+
+// NewCleanupIdempotencyKeysSchedule: provider lịch dọn idempotency key vào group "queue-schedules"
+func NewCleanupIdempotencyKeysSchedule() queue.Schedule {
+	return queue.Schedule{
+		Name:        "cleanup-idempotency-keys",
+		Description: "Xoá idempotency key hết hạn trong Postgres",
+		Cron:        config.GetIdempotencyConfig().CleanupCron,
+		Job:         jobs.NewCleanupIdempotencyKeys(),
+		Unique:      time.Minute,
+	}
+}
+
+func (h *CleanupIdempotencyKeysHandler) Handle(ctx context.Context, j queue.Job) error {
+	n, err := h.store.PurgeExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		h.logger.Info("Purged expired idempotency keys", n)
+	}
+	return nil
+}
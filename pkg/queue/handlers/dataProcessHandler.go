@@ -86,7 +86,7 @@ func (h *DataProcessHandler) Handle(ctx context.Context, j queue.Job) error {
 		"max_records":     1000,
 	})
 	dataJob.SetQueue("critical")
-	if err := h.dispatcher.Dispatch(dataJob, queue.Timeout(1*time.Second)); err != nil {
+	if _, err := h.dispatcher.DispatchContext(ctx, dataJob, queue.Timeout(1*time.Second)); err != nil {
 		log.Printf("❌ Failed to dispatch data job: %v", err)
 		return err
 	}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"context"
+	"core-ledger/internal/module/webhooks"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/queue/jobs"
+	"fmt"
+)
+
+// DeliverWebhookHandler xử lý job gửi ledger event tới webhook
+type DeliverWebhookHandler struct {
+	service *webhooks.WebhookService
+	logger  logger.CustomLogger
+}
+
+func NewDeliverWebhookHandler(service *webhooks.WebhookService) *DeliverWebhookHandler {
+	return &DeliverWebhookHandler{
+		service: service,
+		logger:  logger.NewSystemLog("DeliverWebhookHandler"),
+	}
+}
+
+// NewDeliverWebhookRegistration: provider đăng ký job/handler vào group "queue-registrations"
+func NewDeliverWebhookRegistration(h *DeliverWebhookHandler) queue.Registration {
+	return queue.Registration{
+		Type:     jobs.DeliverWebhookJobType,
+		Template: &jobs.DeliverWebhook{},
+		Handler:  h,
+	}
+}
+
+func (h *DeliverWebhookHandler) Handle(ctx context.Context, j queue.Job) error {
+	job, ok := j.(*jobs.DeliverWebhook)
+	if !ok {
+		return fmt.Errorf("invalid job type, expect *DeliverWebhook")
+	}
+	return h.service.Deliver(ctx, job)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/module/holds"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/queue/jobs"
+	"time"
+)
+
+// ExpireHoldsHandler xử lý job hết hạn hold
+type ExpireHoldsHandler struct {
+	service *holds.HoldService
+	logger  logger.CustomLogger
+}
+
+func NewExpireHoldsHandler(service *holds.HoldService) *ExpireHoldsHandler {
+	return &ExpireHoldsHandler{
+		service: service,
+		logger:  logger.NewSystemLog("ExpireHoldsHandler"),
+	}
+}
+
+// NewExpireHoldsRegistration: provider đăng ký job/handler vào group "queue-registrations"
+func NewExpireHoldsRegistration(h *ExpireHoldsHandler) queue.Registration {
+	return queue.Registration{
+		Type:     jobs.ExpireHoldsJobType,
+		Template: &jobs.ExpireHolds{},
+		Handler:  h,
+	}
+}
+
+// NewExpireHoldsSchedule: provider lịch hết hạn hold vào group "queue-schedules"
+func NewExpireHoldsSchedule() queue.Schedule {
+	return queue.Schedule{
+		Name:        "expire-holds",
+		Description: "Chuyển hold PENDING quá hạn sang EXPIRED",
+		Cron:        config.GetHoldConfig().ExpiryCron,
+		Job:         jobs.NewExpireHolds(),
+		Unique:      time.Minute,
+	}
+}
+
+func (h *ExpireHoldsHandler) Handle(ctx context.Context, j queue.Job) error {
+	n, err := h.service.ExpireStale(ctx)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		h.logger.Info("Expired stale holds", n)
+	}
+	return nil
+}
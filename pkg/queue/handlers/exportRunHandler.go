@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"core-ledger/internal/core"
+	"core-ledger/internal/module/exports"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/queue/jobs"
+	"errors"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+)
+
+// ExportRunHandler ghi file export ra storage
+type ExportRunHandler struct {
+	service *exports.ExportService
+	logger  logger.CustomLogger
+}
+
+func NewExportRunHandler(service *exports.ExportService) *ExportRunHandler {
+	return &ExportRunHandler{
+		service: service,
+		logger:  logger.NewSystemLog("ExportRunHandler"),
+	}
+}
+
+// NewExportRunRegistration: provider đăng ký job/handler vào group "queue-registrations"
+func NewExportRunRegistration(h *ExportRunHandler) queue.Registration {
+	return queue.Registration{
+		Type:     jobs.ExportRunJobType,
+		Template: &jobs.ExportRun{},
+		Handler:  h,
+	}
+}
+
+func (h *ExportRunHandler) Handle(ctx context.Context, j queue.Job) error {
+	job, ok := j.(*jobs.ExportRun)
+	if !ok {
+		return fmt.Errorf("invalid job type, expect *ExportRun")
+	}
+	result, err := h.service.Run(ctx, job.ExportID)
+	if err != nil {
+		// lỗi do request (cột, query, quá số dòng) chạy lại cũng không qua
+		var appErr *core.AppError
+		if errors.As(err, &appErr) {
+			return fmt.Errorf("%s: %w", appErr.Description, asynq.SkipRetry)
+		}
+		return err
+	}
+	// URL tải trong kết quả có hạn ngắn, hết hạn thì lấy lại ở GET /exports/:id
+	return queue.ReporterFromContext(ctx).SetResult(ctx, result)
+}
+
+// Failed: hook được gọi khi job đã hết retry
+func (h *ExportRunHandler) Failed(ctx context.Context, j queue.Job, err error) {
+	job, ok := j.(*jobs.ExportRun)
+	// SkipRetry: service đã đánh dấu FAILED kèm lỗi gốc
+	if !ok || errors.Is(err, asynq.SkipRetry) {
+		return
+	}
+	if markErr := h.service.MarkFailed(ctx, job.ExportID, err); markErr != nil {
+		h.logger.Error("mark export failed", markErr)
+	}
+}
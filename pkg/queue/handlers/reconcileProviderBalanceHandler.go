@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/module/reconciliation"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/queue/jobs"
+	"fmt"
+	"time"
+)
+
+// ReconcileProviderBalanceHandler xử lý job đối soát số dư nhà cung cấp
+type ReconcileProviderBalanceHandler struct {
+	service *reconciliation.ReconciliationService
+	logger  logger.CustomLogger
+}
+
+func NewReconcileProviderBalanceHandler(service *reconciliation.ReconciliationService) *ReconcileProviderBalanceHandler {
+	return &ReconcileProviderBalanceHandler{
+		service: service,
+		logger:  logger.NewSystemLog("ReconcileProviderBalanceHandler"),
+	}
+}
+
+// NewReconcileProviderBalanceRegistration: provider đăng ký job/handler vào group "queue-registrations"
+func NewReconcileProviderBalanceRegistration(h *ReconcileProviderBalanceHandler) queue.Registration {
+	return queue.Registration{
+		Type:     jobs.ReconcileProviderBalanceJobType,
+		Template: &jobs.ReconcileProviderBalance{},
+		Handler:  h,
+	}
+}
+
+// NewReconcileProviderBalanceSchedule: provider lịch đối soát hằng ngày vào group "queue-schedules"
+func NewReconcileProviderBalanceSchedule() queue.Schedule {
+	rc := config.GetReconciliationConfig()
+	return queue.Schedule{
+		Name:        "reconcile-provider-balance",
+		Description: "Đối soát số dư nhà cung cấp thanh toán với sổ cái",
+		Cron:        rc.Cron,
+		Timezone:    rc.Location().String(),
+		Job:         jobs.NewReconcileProviderBalance(nil),
+		Unique:      time.Hour,
+	}
+}
+
+func (h *ReconcileProviderBalanceHandler) Handle(ctx context.Context, j queue.Job) error {
+	job, ok := j.(*jobs.ReconcileProviderBalance)
+	if !ok {
+		return fmt.Errorf("invalid job type, expect *ReconcileProviderBalance")
+	}
+	cutoff := h.service.DefaultCutoff(time.Now())
+	if job.CutoffAt != nil {
+		cutoff = *job.CutoffAt
+	}
+	h.logger.Info("Running provider reconciliation", cutoff)
+	_, err := h.service.Run(ctx, cutoff)
+	return err
+}
+
+// Failed: hook được gọi khi job đã hết retry
+func (h *ReconcileProviderBalanceHandler) Failed(ctx context.Context, j queue.Job, err error) {
+	h.logger.Error("Provider reconciliation failed", err)
+}
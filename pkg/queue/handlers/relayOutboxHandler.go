@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/module/webhooks"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/queue/jobs"
+	"time"
+)
+
+// RelayOutboxHandler xử lý job relay outbox sang hàng đợi gửi webhook
+type RelayOutboxHandler struct {
+	service *webhooks.WebhookService
+	logger  logger.CustomLogger
+}
+
+func NewRelayOutboxHandler(service *webhooks.WebhookService) *RelayOutboxHandler {
+	return &RelayOutboxHandler{
+		service: service,
+		logger:  logger.NewSystemLog("RelayOutboxHandler"),
+	}
+}
+
+// NewRelayOutboxRegistration: provider đăng ký job/handler vào group "queue-registrations"
+func NewRelayOutboxRegistration(h *RelayOutboxHandler) queue.Registration {
+	return queue.Registration{
+		Type:     jobs.RelayOutboxJobType,
+		Template: &jobs.RelayOutbox{},
+		Handler:  h,
+	}
+}
+
+// NewRelayOutboxSchedule: provider lịch relay outbox vào group "queue-schedules"
+func NewRelayOutboxSchedule() queue.Schedule {
+	return queue.Schedule{
+		Name:        "relay-outbox",
+		Description: "Chuyển event PENDING trong outbox sang hàng đợi gửi webhook",
+		Cron:        config.GetWebhookConfig().RelayCron,
+		Job:         jobs.NewRelayOutbox(),
+		Unique:      5 * time.Second,
+		// không retry: lần relay kế tiếp theo lịch sẽ xử lý lại event còn PENDING
+		Options: []queue.DispatchOption{queue.Retry(0)},
+	}
+}
+
+func (h *RelayOutboxHandler) Handle(ctx context.Context, j queue.Job) error {
+	n, err := h.service.RelayOutbox(ctx)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		h.logger.Info("Relayed outbox events", n)
+	}
+	return nil
+}
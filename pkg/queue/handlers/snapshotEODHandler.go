@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"core-ledger/internal/module/snapshots"
+	"core-ledger/pkg/logger"
+	"core-ledger/pkg/queue"
+	"core-ledger/pkg/queue/jobs"
+	"fmt"
+	"time"
+)
+
+// SnapshotEODHandler xử lý job chốt số dư cuối ngày
+type SnapshotEODHandler struct {
+	service *snapshots.SnapshotService
+	logger  logger.CustomLogger
+}
+
+func NewSnapshotEODHandler(service *snapshots.SnapshotService) *SnapshotEODHandler {
+	return &SnapshotEODHandler{
+		service: service,
+		logger:  logger.NewSystemLog("SnapshotEODHandler"),
+	}
+}
+
+// NewSnapshotEODRegistration: provider đăng ký job/handler vào group "queue-registrations"
+func NewSnapshotEODRegistration(h *SnapshotEODHandler) queue.Registration {
+	return queue.Registration{
+		Type:     jobs.SnapshotEODJobType,
+		Template: &jobs.SnapshotEOD{},
+		Handler:  h,
+	}
+}
+
+// NewSnapshotEODSchedule: provider lịch chốt số dư hằng ngày vào group "queue-schedules"
+func NewSnapshotEODSchedule() queue.Schedule {
+	return queue.Schedule{
+		Name:        "snapshot-eod",
+		Description: "Chốt số dư cuối ngày hôm trước cho toàn bộ tài khoản",
+		Cron:        config.GetSchedulerConfig().SnapshotCron,
+		Job:         jobs.NewSnapshotEOD(nil),
+		Unique:      time.Hour,
+	}
+}
+
+func (h *SnapshotEODHandler) Handle(ctx context.Context, j queue.Job) error {
+	job, ok := j.(*jobs.SnapshotEOD)
+	if !ok {
+		return fmt.Errorf("invalid job type, expect *SnapshotEOD")
+	}
+	asOf := h.service.PreviousBusinessDate(time.Now())
+	if job.AsOfDate != nil {
+		asOf = *job.AsOfDate
+	}
+	result, err := h.service.RunEOD(ctx, asOf)
+	if err != nil {
+		return err
+	}
+	// kết quả chốt sổ đọc lại qua GET /jobs/:id
+	return queue.ReporterFromContext(ctx).SetResult(ctx, result)
+}
+
+// Failed: hook được gọi khi job đã hết retry
+func (h *SnapshotEODHandler) Failed(ctx context.Context, j queue.Job, err error) {
+	h.logger.Error("EOD snapshot failed", err)
+}
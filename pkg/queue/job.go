@@ -2,6 +2,7 @@ package queue
 
 import (
 	"context"
+	"core-ledger/pkg/tracing"
 	"encoding/json"
 	"time"
 
@@ -20,6 +21,10 @@ type Job interface {
 	SetDelay(time.Duration)
 	SetRetry(int)
 	SetBackoff([]int)
+	// GetUniqueKey/GetUniqueTTL: job có unique key không được enqueue trùng trong TTL (asynq.Unique)
+	GetUniqueKey() string
+	GetUniqueTTL() time.Duration
+	SetUnique(key string, ttl time.Duration)
 }
 
 // JobHandler interface tách riêng phần xử lý
@@ -40,8 +45,14 @@ type BaseJob struct {
 	Delay   time.Duration `json:"delay,omitempty"`
 	Retry   int           `json:"retry,omitempty"`
 	Backoff []int         `json:"backoff,omitempty"` // Mảng các giá trị backoff (giây), ví dụ: [1, 2, 4, 8]
+	// UniqueKey khoá chống trùng, VD "import:<file_id>"; UniqueTTL = 0 dùng DefaultUniqueTTL
+	UniqueKey string        `json:"unique_key,omitempty"`
+	UniqueTTL time.Duration `json:"unique_ttl,omitempty"`
 }
 
+// DefaultUniqueTTL thời gian giữ khoá chống trùng khi job có UniqueKey nhưng không đặt UniqueTTL
+const DefaultUniqueTTL = time.Hour
+
 // GetQueue trả về tên queue, mặc định là "default"
 func (b *BaseJob) GetQueue() string {
 	if b.Queue == "" {
@@ -88,19 +99,59 @@ func (b *BaseJob) SetBackoff(backoff []int) {
 	b.Backoff = backoff
 }
 
+// GetUniqueKey trả về khoá chống trùng, rỗng = không chống trùng
+func (b *BaseJob) GetUniqueKey() string {
+	return b.UniqueKey
+}
+
+// GetUniqueTTL trả về thời gian giữ khoá chống trùng
+func (b *BaseJob) GetUniqueTTL() time.Duration {
+	if b.UniqueKey != "" && b.UniqueTTL <= 0 {
+		return DefaultUniqueTTL
+	}
+	return b.UniqueTTL
+}
+
+// SetUnique set khoá chống trùng và TTL
+func (b *BaseJob) SetUnique(key string, ttl time.Duration) {
+	b.UniqueKey = key
+	b.UniqueTTL = ttl
+}
+
 // JobPayload wraps job data for serialization
 type JobPayload struct {
 	Type    string      `json:"type"`
 	Data    interface{} `json:"data"`
 	Backoff []int       `json:"backoff,omitempty"` // Lưu backoff để dùng trong RetryDelayFunc
+	// TraceContext trace context (traceparent) của nơi dispatch để span xử lý job nối tiếp trace
+	TraceContext map[string]string `json:"trace_context,omitempty"`
+	// Schedule tên schedule đã enqueue job (rỗng nếu dispatch trực tiếp)
+	Schedule string `json:"schedule,omitempty"`
+	// Workflow chain/batch mà job là một bước
+	Workflow *WorkflowMeta `json:"workflow,omitempty"`
 }
 
 // CreateTask tạo asynq.Task từ Job
 func CreateTask(job Job) (*asynq.Task, error) {
+	return CreateTaskContext(context.Background(), job)
+}
+
+// CreateTaskContext tạo asynq.Task từ Job, kèm trace context của ctx
+func CreateTaskContext(ctx context.Context, job Job) (*asynq.Task, error) {
+	return createTask(ctx, job, nil)
+}
+
+func createTask(ctx context.Context, job Job, workflow *WorkflowMeta) (*asynq.Task, error) {
 	payload := JobPayload{
-		Type:    job.GetType(),
-		Data:    job.GetPayload(),
-		Backoff: job.GetBackoff(), // Lưu backoff vào payload
+		Type:     job.GetType(),
+		Data:     job.GetPayload(),
+		Backoff:  job.GetBackoff(), // Lưu backoff vào payload
+		Workflow: workflow,
+	}
+	// asynq.Unique so trùng theo type + queue + payload, trace context khác nhau mỗi request
+	// nên job có UniqueKey không mang trace context (span dispatch vẫn được ghi)
+	if job.GetUniqueKey() == "" {
+		payload.TraceContext = tracing.Inject(ctx)
 	}
 
 	data, err := json.Marshal(payload)
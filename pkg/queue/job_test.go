@@ -0,0 +1,52 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type traceTestJob struct {
+	BaseJob
+	Value string `json:"value"`
+}
+
+func (j *traceTestJob) GetPayload() interface{} { return j }
+func (j *traceTestJob) GetType() string         { return "trace_test" }
+
+func TestCreateTaskContextCarriesTraceContext(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	task, err := CreateTaskContext(ctx, &traceTestJob{Value: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload JobPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	if got := payload.TraceContext["traceparent"]; got != want {
+		t.Fatalf("traceparent = %q, want %q", got, want)
+	}
+
+	task, err = CreateTask(&traceTestJob{Value: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload = JobPayload{}
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.TraceContext != nil {
+		t.Fatalf("trace context without span = %v, want nil", payload.TraceContext)
+	}
+}
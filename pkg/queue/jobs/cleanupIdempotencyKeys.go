@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"core-ledger/pkg/queue"
+)
+
+const CleanupIdempotencyKeysJobType = "cleanup_idempotency_keys:job"
+
+// CleanupIdempotencyKeys job xoá idempotency key đã hết hạn (store Postgres)
+type CleanupIdempotencyKeys struct {
+	queue.BaseJob
+}
+
+// GetPayload trả về payload của job
+func (j *CleanupIdempotencyKeys) GetPayload() interface{} {
+	return j
+}
+
+// GetType trả về loại job
+func (j *CleanupIdempotencyKeys) GetType() string {
+	return CleanupIdempotencyKeysJobType
+}
+
+func NewCleanupIdempotencyKeys() *CleanupIdempotencyKeys {
+	return &CleanupIdempotencyKeys{
+		BaseJob: queue.BaseJob{
+			Queue: "low",
+			Retry: 3,
+		},
+	}
+}
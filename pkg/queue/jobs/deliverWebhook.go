@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"encoding/json"
+
+	"core-ledger/pkg/queue"
+)
+
+const DeliverWebhookJobType = "deliver_webhook:job"
+
+// webhookBackoff khoảng chờ (giây) giữa các lần gửi lại webhook: 10s → 2h
+var webhookBackoff = []int{10, 30, 60, 300, 900, 1800, 3600, 7200}
+
+// DeliverWebhook job gửi một ledger event tới một webhook, mỗi lần thử được lưu vào webhook_logs
+type DeliverWebhook struct {
+	queue.BaseJob
+	WebhookID string `json:"webhook_id"`
+	EventName string `json:"event_name"`
+	// MessageID id event (event_key trong outbox), giữ nguyên qua các lần gửi lại để consumer chống trùng
+	MessageID string          `json:"message_id"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// GetPayload trả về payload của job
+func (j *DeliverWebhook) GetPayload() interface{} {
+	return j
+}
+
+// GetType trả về loại job
+func (j *DeliverWebhook) GetType() string {
+	return DeliverWebhookJobType
+}
+
+// NewDeliverWebhook tạo job gửi webhook, retry = số lần gửi lại khi endpoint lỗi
+func NewDeliverWebhook(webhookID, eventName, messageID string, body json.RawMessage, retry int) *DeliverWebhook {
+	return &DeliverWebhook{
+		BaseJob: queue.BaseJob{
+			Queue:   "default",
+			Retry:   retry,
+			Backoff: webhookBackoff,
+		},
+		WebhookID: webhookID,
+		EventName: eventName,
+		MessageID: messageID,
+		Body:      body,
+	}
+}
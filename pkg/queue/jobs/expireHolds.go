@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"core-ledger/pkg/queue"
+)
+
+const ExpireHoldsJobType = "expire_holds:job"
+
+// ExpireHolds job chuyển các hold PENDING đã quá hạn sang EXPIRED
+type ExpireHolds struct {
+	queue.BaseJob
+}
+
+// GetPayload trả về payload của job
+func (j *ExpireHolds) GetPayload() interface{} {
+	return j
+}
+
+// GetType trả về loại job
+func (j *ExpireHolds) GetType() string {
+	return ExpireHoldsJobType
+}
+
+func NewExpireHolds() *ExpireHolds {
+	return &ExpireHolds{
+		BaseJob: queue.BaseJob{
+			Queue: "default",
+			Retry: 3,
+		},
+	}
+}
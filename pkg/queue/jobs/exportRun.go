@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"core-ledger/pkg/queue"
+)
+
+const ExportRunJobType = "export_run:job"
+
+// ExportRun job ghi file export (bản ghi exports) ra storage
+type ExportRun struct {
+	queue.BaseJob
+	ExportID uint64 `json:"export_id"`
+}
+
+// GetPayload trả về payload của job
+func (j *ExportRun) GetPayload() interface{} {
+	return j
+}
+
+// GetType trả về loại job
+func (j *ExportRun) GetType() string {
+	return ExportRunJobType
+}
+
+// NewExportRun tạo job export cho bản ghi exports đã tạo ở trạng thái PENDING
+func NewExportRun(exportID uint64) *ExportRun {
+	return &ExportRun{
+		BaseJob: queue.BaseJob{
+			Queue:   "default",
+			Retry:   2,
+			Backoff: []int{30, 120},
+		},
+		ExportID: exportID,
+	}
+}
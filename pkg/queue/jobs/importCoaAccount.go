@@ -15,8 +15,10 @@ type ImportCoaAccount struct {
 	Options     map[string]interface{} `json:"options,omitempty"`
 }
 
+// DataImportCoaAccount FileID là bản ghi files của file upload; TmpFile chỉ còn cho job enqueue trước khi có storage
 type DataImportCoaAccount struct {
-	TmpFile string `json:"tmp_file"`
+	FileID  uint64 `json:"file_id,omitempty"`
+	TmpFile string `json:"tmp_file,omitempty"`
 }
 
 // GetPayload trả về payload của job
@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"time"
+
+	"core-ledger/pkg/queue"
+)
+
+const ReconcileProviderBalanceJobType = "reconcile_provider_balance:job"
+
+// ReconcileProviderBalance job đối soát số dư nhà cung cấp thanh toán với sổ cái
+type ReconcileProviderBalance struct {
+	queue.BaseJob
+	// CutoffAt thời điểm chốt số liệu, để trống thì lấy 00:00 ngày hiện tại theo timezone cấu hình
+	CutoffAt *time.Time `json:"cutoff_at,omitempty"`
+}
+
+// GetPayload trả về payload của job
+func (j *ReconcileProviderBalance) GetPayload() interface{} {
+	return j
+}
+
+// GetType trả về loại job
+func (j *ReconcileProviderBalance) GetType() string {
+	return ReconcileProviderBalanceJobType
+}
+
+// NewReconcileProviderBalance tạo job đối soát, cutoffAt = nil để dùng cutoff mặc định
+func NewReconcileProviderBalance(cutoffAt *time.Time) *ReconcileProviderBalance {
+	return &ReconcileProviderBalance{
+		BaseJob: queue.BaseJob{
+			Queue:   "default",
+			Retry:   3,
+			Backoff: []int{60, 300, 900},
+		},
+		CutoffAt: cutoffAt,
+	}
+}
@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"core-ledger/pkg/queue"
+)
+
+const RelayOutboxJobType = "relay_outbox:job"
+
+// RelayOutbox job chuyển event PENDING trong outbox (transaction_logs) thành job gửi webhook cho từng subscriber
+type RelayOutbox struct {
+	queue.BaseJob
+}
+
+// GetPayload trả về payload của job
+func (j *RelayOutbox) GetPayload() interface{} {
+	return j
+}
+
+// GetType trả về loại job
+func (j *RelayOutbox) GetType() string {
+	return RelayOutboxJobType
+}
+
+func NewRelayOutbox() *RelayOutbox {
+	return &RelayOutbox{
+		BaseJob: queue.BaseJob{
+			Queue: "default",
+			Retry: 0,
+		},
+	}
+}
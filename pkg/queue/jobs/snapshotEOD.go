@@ -0,0 +1,38 @@
+package jobs
+
+import (
+	"time"
+
+	"core-ledger/pkg/queue"
+)
+
+const SnapshotEODJobType = "snapshot_eod:job"
+
+// SnapshotEOD job chốt số dư cuối ngày (snapshot LOCKED) cho toàn bộ tài khoản
+type SnapshotEOD struct {
+	queue.BaseJob
+	// AsOfDate ngày cần chốt, để trống thì lấy ngày hôm trước theo timezone scheduler
+	AsOfDate *time.Time `json:"as_of_date,omitempty"`
+}
+
+// GetPayload trả về payload của job
+func (j *SnapshotEOD) GetPayload() interface{} {
+	return j
+}
+
+// GetType trả về loại job
+func (j *SnapshotEOD) GetType() string {
+	return SnapshotEODJobType
+}
+
+// NewSnapshotEOD tạo job chốt số dư, asOfDate = nil để chốt ngày hôm trước
+func NewSnapshotEOD(asOfDate *time.Time) *SnapshotEOD {
+	return &SnapshotEOD{
+		BaseJob: queue.BaseJob{
+			Queue:   "default",
+			Retry:   3,
+			Backoff: []int{60, 300, 900},
+		},
+		AsOfDate: asOfDate,
+	}
+}
@@ -0,0 +1,603 @@
+package queue
+
+import (
+	"context"
+	"core-ledger/pkg/logging"
+	"core-ledger/pkg/metrics"
+	"core-ledger/pkg/tracing"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Driver chọn nơi chạy job của Dispatcher (QUEUE_DRIVER)
+const (
+	// DriverAsynq enqueue vào Redis, worker (cmd/queue_worker) xử lý
+	DriverAsynq = "asynq"
+	// DriverSync chạy handler ngay trong lời gọi Dispatch, dùng cho test
+	DriverSync = "sync"
+	// DriverMemory chạy handler trên goroutine trong process, dùng cho chế độ một binary
+	DriverMemory = "memory"
+)
+
+const (
+	// localDefaultMaxRetry giống mặc định của asynq khi job không đặt MaxRetry
+	localDefaultMaxRetry = 25
+	// localSyncMaxRetry trần số lần retry của sync driver: retry chạy ngay trong Dispatch nên không để chặn request lâu
+	localSyncMaxRetry = 3
+	// localQueueBuffer số task memory driver giữ trong channel trước khi phải chờ worker
+	localQueueBuffer = 1024
+)
+
+// LocalOptions cấu hình LocalDispatcher
+type LocalOptions struct {
+	// Concurrency số goroutine xử lý của memory driver, mặc định 1
+	Concurrency int
+	// RetryDelay thời gian chờ trước lần retry thứ n, mặc định giống Worker (Backoff của job hoặc exponential);
+	// sync driver mặc định không chờ
+	RetryDelay func(n int, e error, t *asynq.Task) time.Duration
+}
+
+// LocalDispatcher Dispatcher chạy job ngay trong process, không cần Redis.
+// Payload, retry/backoff, chống trùng, hook Failed, FailedJobRecorder và workflow giống Worker;
+// sync driver chạy job (kể cả retry, tối đa localSyncMaxRetry lần, mặc định không chờ backoff) trong lời gọi Dispatch,
+// memory driver chạy trên goroutine.
+type LocalDispatcher struct {
+	registry
+	driver      string
+	concurrency int
+	retryDelay  func(n int, e error, t *asynq.Task) time.Duration
+	recorder    FailedJobRecorder
+	jobRuns     JobRunStore
+
+	mu sync.Mutex
+	// pending số job đã dispatch chưa có kết quả cuối (kể cả đang chờ delay/retry)
+	pending   int
+	taskIDs   map[string]bool
+	unique    map[string]time.Time
+	workflows map[string]*localWorkflow
+
+	tasks  chan *localTask
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// localTask task đang nằm trong LocalDispatcher, tương ứng một task asynq
+type localTask struct {
+	id        string
+	task      *asynq.Task
+	queue     string
+	maxRetry  int
+	retried   int
+	timeout   time.Duration
+	deadline  time.Time
+	uniqueKey string
+	workflow  *WorkflowMeta
+}
+
+var _ Dispatcher = (*LocalDispatcher)(nil)
+
+type localWorkflow struct {
+	state          WorkflowState
+	then           *WorkflowStep
+	thenDispatched bool
+}
+
+// NewSyncDispatcher chạy handler ngay trong lời gọi Dispatch
+func NewSyncDispatcher(opts LocalOptions) *LocalDispatcher {
+	return newLocalDispatcher(DriverSync, opts)
+}
+
+// NewMemoryDispatcher chạy handler trên goroutine, job chỉ được xử lý sau khi gọi Start
+func NewMemoryDispatcher(opts LocalOptions) *LocalDispatcher {
+	return newLocalDispatcher(DriverMemory, opts)
+}
+
+func newLocalDispatcher(driver string, opts LocalOptions) *LocalDispatcher {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.RetryDelay == nil {
+		opts.RetryDelay = retryDelayFuncWithJobBackoff
+		if driver == DriverSync {
+			opts.RetryDelay = func(int, error, *asynq.Task) time.Duration { return 0 }
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &LocalDispatcher{
+		registry:    newRegistry(),
+		driver:      driver,
+		concurrency: opts.Concurrency,
+		retryDelay:  opts.RetryDelay,
+		taskIDs:     make(map[string]bool),
+		unique:      make(map[string]time.Time),
+		workflows:   make(map[string]*localWorkflow),
+		tasks:       make(chan *localTask, localQueueBuffer),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// RegisterJob đăng ký job template và handler, giống Worker.RegisterJob
+func (d *LocalDispatcher) RegisterJob(jobType string, jobTemplate Job, handler JobHandler) {
+	d.register(jobType, jobTemplate, handler)
+}
+
+// SetFailedJobRecorder đăng ký nơi lưu job lỗi lần cuối
+func (d *LocalDispatcher) SetFailedJobRecorder(recorder FailedJobRecorder) {
+	d.recorder = recorder
+}
+
+// SetJobRunStore đăng ký nơi lưu tiến độ/kết quả job, không có ResultWriter nên đây là nơi lưu duy nhất
+func (d *LocalDispatcher) SetJobRunStore(store JobRunStore) {
+	d.jobRuns = store
+}
+
+// Start chạy goroutine xử lý của memory driver, sync driver không cần Start
+func (d *LocalDispatcher) Start() error {
+	if d.driver != DriverMemory {
+		return nil
+	}
+	for i := 0; i < d.concurrency; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for {
+				select {
+				case <-d.ctx.Done():
+					return
+				case lt := <-d.tasks:
+					d.run(lt)
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+// Stop dừng nhận job mới vào xử lý, chờ job đang chạy xong. Job còn chờ trong hàng đợi bị bỏ
+func (d *LocalDispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+// Wait chờ tới khi mọi job đã dispatch (kể cả job sinh ra trong lúc chạy) có kết quả cuối
+func (d *LocalDispatcher) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(5 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		d.mu.Lock()
+		pending := d.pending
+		d.mu.Unlock()
+		if pending == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Workflow trạng thái workflow đã dispatch qua LocalDispatcher
+func (d *LocalDispatcher) Workflow(id string) (*WorkflowState, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	lw, ok := d.workflows[id]
+	if !ok {
+		return nil, false
+	}
+	state := lw.state
+	return &state, true
+}
+
+func (d *LocalDispatcher) Dispatch(job Job, options ...DispatchOption) (string, error) {
+	return d.DispatchContext(context.Background(), job, options...)
+}
+
+func (d *LocalDispatcher) DispatchContext(ctx context.Context, job Job, options ...DispatchOption) (string, error) {
+	return d.enqueue(ctx, job, nil, options...)
+}
+
+func (d *LocalDispatcher) DispatchLater(job Job, delay time.Duration, options ...DispatchOption) (string, error) {
+	job.SetDelay(delay)
+	return d.Dispatch(job, options...)
+}
+
+func (d *LocalDispatcher) DispatchAt(job Job, processAt time.Time, options ...DispatchOption) (string, error) {
+	options = append(options, ProcessAt(processAt))
+	return d.Dispatch(job, options...)
+}
+
+func (d *LocalDispatcher) DispatchOnQueue(job Job, queueName string, options ...DispatchOption) (string, error) {
+	job.SetQueue(queueName)
+	return d.Dispatch(job, options...)
+}
+
+// enqueue dựng task giống asynqDispatcher rồi chạy ngay (sync) hoặc đẩy vào hàng đợi trong process (memory)
+func (d *LocalDispatcher) enqueue(ctx context.Context, job Job, workflow *WorkflowMeta, options ...DispatchOption) (string, error) {
+	task, err := createTask(ctx, job, workflow)
+	if err != nil {
+		return "", fmt.Errorf("failed to create task: %v", err)
+	}
+	lt := &localTask{id: uuid.NewString(), task: task, queue: "default", maxRetry: localDefaultMaxRetry, workflow: workflow}
+	var uniqueTTL time.Duration
+	var processAt time.Time
+	for _, opt := range taskOptions(job, task, options...) {
+		switch opt.Type() {
+		case asynq.QueueOpt:
+			lt.queue = opt.Value().(string)
+		case asynq.MaxRetryOpt:
+			lt.maxRetry = max(opt.Value().(int), 0)
+		case asynq.TimeoutOpt:
+			lt.timeout = opt.Value().(time.Duration)
+		case asynq.DeadlineOpt:
+			lt.deadline = opt.Value().(time.Time)
+		case asynq.UniqueOpt:
+			uniqueTTL = opt.Value().(time.Duration)
+		case asynq.ProcessAtOpt:
+			processAt = opt.Value().(time.Time)
+		case asynq.ProcessInOpt:
+			processAt = time.Now().Add(opt.Value().(time.Duration))
+		case asynq.TaskIDOpt:
+			lt.id = opt.Value().(string)
+		}
+	}
+
+	if d.driver == DriverSync {
+		lt.maxRetry = min(lt.maxRetry, localSyncMaxRetry)
+	}
+
+	now := time.Now()
+	d.mu.Lock()
+	if d.taskIDs[lt.id] {
+		d.mu.Unlock()
+		return "", asynq.ErrTaskIDConflict
+	}
+	if uniqueTTL > 0 {
+		// asynq so trùng theo queue + type + payload
+		lt.uniqueKey = lt.queue + ":" + task.Type() + ":" + string(task.Payload())
+		if until, ok := d.unique[lt.uniqueKey]; ok && now.Before(until) {
+			d.mu.Unlock()
+			return "", ErrDuplicateJob
+		}
+		d.unique[lt.uniqueKey] = now.Add(uniqueTTL)
+	}
+	d.taskIDs[lt.id] = true
+	d.pending++
+	d.mu.Unlock()
+
+	if d.driver == DriverSync {
+		d.run(lt)
+		return lt.id, nil
+	}
+	d.schedule(lt, time.Until(processAt))
+	return lt.id, nil
+}
+
+// schedule đẩy task vào hàng đợi của memory driver sau delay
+func (d *LocalDispatcher) schedule(lt *localTask, delay time.Duration) {
+	if delay > 0 {
+		time.AfterFunc(delay, func() { d.push(lt) })
+		return
+	}
+	d.push(lt)
+}
+
+func (d *LocalDispatcher) push(lt *localTask) {
+	select {
+	case d.tasks <- lt:
+		return
+	default:
+	}
+	// channel đầy: không chặn handler đang dispatch job con
+	go func() {
+		select {
+		case d.tasks <- lt:
+		case <-d.ctx.Done():
+		}
+	}()
+}
+
+// run chạy task tới khi có kết quả cuối; memory driver đưa lần retry trở lại hàng đợi sau backoff
+func (d *LocalDispatcher) run(lt *localTask) {
+	for {
+		ctx, result, err := d.attempt(lt)
+		if result == metrics.ResultRetry {
+			delay := d.retryDelay(lt.retried, err, lt.task)
+			lt.retried++
+			if d.driver == DriverMemory {
+				d.schedule(lt, delay)
+				return
+			}
+			time.Sleep(delay)
+			continue
+		}
+
+		if result == metrics.ResultFailure {
+			d.handleFailure(ctx, d.recorder, FailedJob{
+				TaskID:   lt.id,
+				Type:     lt.task.Type(),
+				Queue:    lt.queue,
+				Payload:  lt.task.Payload(),
+				Attempts: lt.retried + 1,
+				MaxRetry: lt.maxRetry,
+				FailedAt: time.Now(),
+			}, err)
+		}
+		if lt.workflow != nil {
+			d.advanceWorkflow(ctx, lt.task.Type(), lt.workflow, err)
+		}
+		d.finish(lt, result == metrics.ResultSuccess)
+		return
+	}
+}
+
+// attempt một lần chạy handler, trả về context (span + logger của job) để gọi hook sau đó
+func (d *LocalDispatcher) attempt(lt *localTask) (ctx context.Context, result string, err error) {
+	start := time.Now()
+	jobType := lt.task.Type()
+	ctx = context.Background()
+	var payload JobPayload
+	if errPayload := json.Unmarshal(lt.task.Payload(), &payload); errPayload == nil {
+		ctx = tracing.Extract(ctx, payload.TraceContext)
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "queue.process "+jobType,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", d.driver),
+			attribute.String("messaging.destination.name", lt.queue),
+			attribute.String("queue.job_type", jobType),
+			attribute.Int("queue.retry_count", lt.retried),
+		),
+	)
+	defer span.End()
+	if lt.workflow != nil {
+		span.SetAttributes(attribute.String("queue.workflow_id", lt.workflow.ID))
+	}
+	ctx = logging.WithFields(ctx, logging.FieldJobType, jobType, logging.FieldJobID, lt.id, "queue", lt.queue)
+	reporter := newReporter(JobRun{
+		TaskID:    lt.id,
+		Type:      jobType,
+		Queue:     lt.queue,
+		State:     JobStateActive,
+		Attempts:  lt.retried + 1,
+		MaxRetry:  lt.maxRetry,
+		StartedAt: start,
+	}, nil, d.jobRuns)
+	ctx = withReporter(ctx, reporter)
+
+	runCtx := ctx
+	if lt.timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, lt.timeout)
+		defer cancel()
+	}
+	if !lt.deadline.IsZero() {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithDeadline(runCtx, lt.deadline)
+		defer cancel()
+	}
+	err = d.process(runCtx, lt.task)
+
+	result = localJobResult(err, lt.retried, lt.maxRetry)
+	reporter.finish(ctx, result, err)
+	span.SetAttributes(attribute.String("queue.result", result))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	metrics.ObserveJob(lt.queue, jobType, result, time.Since(start))
+	return ctx, result, err
+}
+
+// process gọi handler như asynq: panic của handler được chuyển thành lỗi
+func (d *LocalDispatcher) process(ctx context.Context, t *asynq.Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("panic in job %s: %v", t.Type(), r)
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return d.processHandler(t.Type())(ctx, t)
+}
+
+// localJobResult giống jobResult nhưng đọc số lần retry từ task thay vì context của asynq
+func localJobResult(err error, retried, maxRetry int) string {
+	if err == nil {
+		return metrics.ResultSuccess
+	}
+	if errors.Is(err, asynq.SkipRetry) || retried >= maxRetry {
+		return metrics.ResultFailure
+	}
+	return metrics.ResultRetry
+}
+
+// finish giải phóng task ID, nhả khoá chống trùng khi job thành công (giống asynq)
+func (d *LocalDispatcher) finish(lt *localTask, succeeded bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.taskIDs, lt.id)
+	if succeeded && lt.uniqueKey != "" {
+		delete(d.unique, lt.uniqueKey)
+	}
+	d.pending--
+}
+
+func (d *LocalDispatcher) DispatchWorkflow(ctx context.Context, wf *Workflow) (string, error) {
+	if wf == nil || (len(wf.jobs) == 0 && wf.then == nil) {
+		return "", fmt.Errorf("workflow has no jobs")
+	}
+	id := uuid.NewString()
+	lw := &localWorkflow{state: WorkflowState{
+		ID:        id,
+		Kind:      wf.kind,
+		Status:    WorkflowStatusRunning,
+		Total:     len(wf.jobs),
+		Pending:   len(wf.jobs),
+		CreatedAt: time.Now(),
+	}}
+
+	if wf.kind == WorkflowChain {
+		next := make([]WorkflowStep, 0, len(wf.jobs)-1)
+		for _, job := range wf.jobs[1:] {
+			step, err := encodeStep(job)
+			if err != nil {
+				return "", err
+			}
+			next = append(next, step)
+		}
+		d.saveWorkflow(lw)
+		if _, err := d.enqueue(ctx, wf.jobs[0], &WorkflowMeta{ID: id, Kind: WorkflowChain, Next: next}); err != nil {
+			d.markWorkflowFailed(id, err)
+			return id, err
+		}
+		return id, nil
+	}
+
+	if wf.then != nil {
+		step, err := encodeStep(wf.then)
+		if err != nil {
+			return "", err
+		}
+		lw.then = &step
+		lw.state.Total = len(wf.jobs) + 1
+	}
+	d.saveWorkflow(lw)
+	if len(wf.jobs) == 0 {
+		_, err := d.enqueue(ctx, wf.then, &WorkflowMeta{ID: id, Kind: WorkflowBatch, Final: true})
+		if err != nil {
+			d.markWorkflowFailed(id, err)
+		}
+		return id, err
+	}
+	for _, job := range wf.jobs {
+		// job đã enqueue trước đó vẫn chạy, nhưng workflow đã FAILED nên Then không được enqueue
+		if _, err := d.enqueue(ctx, job, &WorkflowMeta{ID: id, Kind: WorkflowBatch}); err != nil {
+			d.markWorkflowFailed(id, err)
+			return id, err
+		}
+	}
+	return id, nil
+}
+
+// saveWorkflow lưu trạng thái workflow, workflow đã kết thúc quá workflowStateTTL được xoá như key Redis hết hạn
+func (d *LocalDispatcher) saveWorkflow(lw *localWorkflow) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for id, old := range d.workflows {
+		if old.state.FinishedAt != nil && time.Since(*old.state.FinishedAt) > workflowStateTTL {
+			delete(d.workflows, id)
+		}
+	}
+	d.workflows[lw.state.ID] = lw
+}
+
+// markWorkflowFailed đánh dấu FAILED, trả về true nếu đây là lần đánh dấu đầu tiên
+func (d *LocalDispatcher) markWorkflowFailed(id string, cause error) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	lw, ok := d.workflows[id]
+	if !ok || lw.state.Error != "" {
+		return false
+	}
+	now := time.Now()
+	lw.state.Error = cause.Error()
+	lw.state.Status = WorkflowStatusFailed
+	lw.state.FinishedAt = &now
+	return true
+}
+
+func (d *LocalDispatcher) finishWorkflow(lw *localWorkflow) {
+	now := time.Now()
+	lw.state.Status = WorkflowStatusSucceeded
+	lw.state.Pending = 0
+	lw.state.FinishedAt = &now
+}
+
+// advanceWorkflow giống Worker.advanceWorkflow, trạng thái lưu trong process thay vì Redis
+func (d *LocalDispatcher) advanceWorkflow(ctx context.Context, jobType string, meta *WorkflowMeta, jobErr error) {
+	if jobErr != nil {
+		cause := fmt.Errorf("%w: %s step %s: %v", ErrWorkflowFailed, meta.ID, jobType, jobErr)
+		d.failWorkflow(ctx, meta, cause)
+		return
+	}
+
+	d.mu.Lock()
+	lw, ok := d.workflows[meta.ID]
+	if !ok {
+		d.mu.Unlock()
+		return
+	}
+	var next WorkflowStep
+	switch {
+	case meta.Kind == WorkflowChain && len(meta.Next) > 0:
+		if lw.state.Status != WorkflowStatusRunning {
+			d.mu.Unlock()
+			return
+		}
+		lw.state.Pending--
+		next = meta.Next[0]
+	case meta.Kind == WorkflowChain || meta.Final:
+		d.finishWorkflow(lw)
+		d.mu.Unlock()
+		return
+	default:
+		lw.state.Pending--
+		if lw.state.Pending > 0 || lw.state.Status != WorkflowStatusRunning {
+			d.mu.Unlock()
+			return
+		}
+		if lw.then == nil {
+			d.finishWorkflow(lw)
+			d.mu.Unlock()
+			return
+		}
+		// Then chỉ được enqueue một lần
+		if lw.thenDispatched {
+			d.mu.Unlock()
+			return
+		}
+		lw.thenDispatched = true
+		next = *lw.then
+	}
+	d.mu.Unlock()
+
+	nextMeta := &WorkflowMeta{ID: meta.ID, Kind: WorkflowBatch, Final: true}
+	if meta.Kind == WorkflowChain {
+		nextMeta = &WorkflowMeta{ID: meta.ID, Kind: WorkflowChain, Next: meta.Next[1:]}
+	}
+	job, err := d.decodeStep(next)
+	if err == nil {
+		_, err = d.enqueue(ctx, job, nextMeta)
+	}
+	if err != nil {
+		d.failWorkflow(ctx, meta, fmt.Errorf("%w: %s: enqueue %s: %v", ErrWorkflowFailed, meta.ID, next.Type, err))
+	}
+}
+
+func (d *LocalDispatcher) failWorkflow(ctx context.Context, meta *WorkflowMeta, cause error) {
+	if !d.markWorkflowFailed(meta.ID, cause) {
+		return
+	}
+	skipped := meta.Next
+	if meta.Kind == WorkflowBatch && !meta.Final {
+		d.mu.Lock()
+		if lw, ok := d.workflows[meta.ID]; ok && lw.then != nil {
+			skipped = []WorkflowStep{*lw.then}
+		}
+		d.mu.Unlock()
+	}
+	d.failSkipped(ctx, skipped, cause)
+}
@@ -0,0 +1,178 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+type flakyJob struct {
+	BaseJob
+	FailTimes int `json:"fail_times"`
+}
+
+func (j *flakyJob) GetPayload() interface{} { return j }
+func (j *flakyJob) GetType() string         { return "flaky" }
+
+// flakyHandler lỗi FailTimes lần đầu rồi thành công
+type flakyHandler struct {
+	mu     sync.Mutex
+	calls  int
+	failed []error
+}
+
+func (h *flakyHandler) Handle(_ context.Context, j Job) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls++
+	if h.calls <= j.(*flakyJob).FailTimes {
+		return fmt.Errorf("attempt %d failed", h.calls)
+	}
+	return nil
+}
+
+func (h *flakyHandler) Failed(_ context.Context, _ Job, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.failed = append(h.failed, err)
+}
+
+func newTestLocal(t *testing.T, newDispatcher func(LocalOptions) *LocalDispatcher) (*LocalDispatcher, *recordingHandler, *memoryRecorder) {
+	t.Helper()
+	d := newDispatcher(LocalOptions{Concurrency: 2, RetryDelay: func(int, error, *asynq.Task) time.Duration { return 0 }})
+	h := &recordingHandler{}
+	rec := &memoryRecorder{}
+	d.RegisterJob("workflow_step", &stepJob{}, h)
+	d.RegisterJob("workflow_then", &thenJob{}, h)
+	d.SetFailedJobRecorder(rec)
+	if err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(d.Stop)
+	return d, h, rec
+}
+
+func waitLocal(t *testing.T, d *LocalDispatcher) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncDispatcherRetriesUntilSuccess(t *testing.T) {
+	d, _, rec := newTestLocal(t, NewSyncDispatcher)
+	h := &flakyHandler{}
+	d.RegisterJob("flaky", &flakyJob{}, h)
+	if _, err := d.Dispatch(&flakyJob{FailTimes: 2, BaseJob: BaseJob{Retry: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	// sync driver: job đã chạy xong khi Dispatch trả về
+	if h.calls != 3 || len(h.failed) != 0 || len(rec.jobs) != 0 {
+		t.Fatalf("calls = %d, failed = %v, recorded = %d", h.calls, h.failed, len(rec.jobs))
+	}
+}
+
+func TestSyncDispatcherExhaustedRetriesCallsFailed(t *testing.T) {
+	d, _, rec := newTestLocal(t, NewSyncDispatcher)
+	h := &flakyHandler{}
+	d.RegisterJob("flaky", &flakyJob{}, h)
+	if _, err := d.Dispatch(&flakyJob{FailTimes: 10, BaseJob: BaseJob{Retry: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if h.calls != 3 || len(h.failed) != 1 {
+		t.Fatalf("calls = %d, failed = %v", h.calls, h.failed)
+	}
+	if len(rec.jobs) != 1 || rec.jobs[0].Attempts != 3 || rec.jobs[0].MaxRetry != 2 || rec.jobs[0].Handler != "*queue.flakyHandler" {
+		t.Fatalf("recorded = %+v", rec.jobs)
+	}
+}
+
+func TestSyncDispatcherFailingJobReturnsPromptly(t *testing.T) {
+	// không truyền RetryDelay như queue_client_module: sync driver không được sleep theo backoff giữa các lần retry
+	d := NewSyncDispatcher(LocalOptions{})
+	h := &flakyHandler{}
+	d.RegisterJob("flaky", &flakyJob{}, h)
+
+	start := time.Now()
+	if _, err := d.Dispatch(&flakyJob{FailTimes: 100}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Dispatch blocked for %s", elapsed)
+	}
+	// MaxRetry mặc định (25) bị giới hạn ở localSyncMaxRetry
+	if h.calls != localSyncMaxRetry+1 || len(h.failed) != 1 {
+		t.Fatalf("calls = %d, failed = %v", h.calls, h.failed)
+	}
+}
+
+func TestSyncDispatcherChain(t *testing.T) {
+	d, h, _ := newTestLocal(t, NewSyncDispatcher)
+	id, err := d.DispatchWorkflow(context.Background(), Chain(&stepJob{Name: "a"}, &stepJob{Name: "b"}).Then(&thenJob{Name: "c"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, ok := d.Workflow(id)
+	if !ok || state.Status != WorkflowStatusSucceeded {
+		t.Fatalf("state = %+v", state)
+	}
+	ran, _ := h.snapshot()
+	if fmt.Sprint(ran) != "[a b c]" {
+		t.Fatalf("ran = %v", ran)
+	}
+}
+
+func TestMemoryDispatcherBatchFailureSkipsThen(t *testing.T) {
+	d, h, rec := newTestLocal(t, NewMemoryDispatcher)
+	id, err := d.DispatchWorkflow(context.Background(), Batch(&stepJob{Name: "a"}, &stepJob{Name: "b", Fail: true}).Then(&thenJob{Name: "lock"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitLocal(t, d)
+	state, _ := d.Workflow(id)
+	if state.Status != WorkflowStatusFailed {
+		t.Fatalf("status = %s", state.Status)
+	}
+	ran, failed := h.snapshot()
+	if len(ran) != 2 || len(failed) != 1 || !errors.Is(failed[0], ErrWorkflowFailed) {
+		t.Fatalf("ran = %v, failed = %v", ran, failed)
+	}
+	if len(rec.jobs) != 1 || rec.jobs[0].Type != "workflow_step" {
+		t.Fatalf("recorded = %+v", rec.jobs)
+	}
+}
+
+func TestMemoryDispatcherBatchThen(t *testing.T) {
+	d, h, _ := newTestLocal(t, NewMemoryDispatcher)
+	id, err := d.DispatchWorkflow(context.Background(), Batch(&stepJob{Name: "a"}, &stepJob{Name: "b"}).Then(&thenJob{Name: "lock"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitLocal(t, d)
+	state, _ := d.Workflow(id)
+	ran, _ := h.snapshot()
+	if state.Status != WorkflowStatusSucceeded || len(ran) != 3 || ran[2] != "lock" {
+		t.Fatalf("state = %+v, ran = %v", state, ran)
+	}
+}
+
+func TestLocalDispatcherUniqueKey(t *testing.T) {
+	d := NewMemoryDispatcher(LocalOptions{})
+	d.RegisterJob("workflow_step", &stepJob{}, &recordingHandler{})
+	// chưa Start nên job đầu còn giữ khoá
+	first := &stepJob{Name: "a", BaseJob: BaseJob{UniqueKey: "import:1"}}
+	if _, err := d.Dispatch(first); err != nil {
+		t.Fatal(err)
+	}
+	second := &stepJob{Name: "a", BaseJob: BaseJob{UniqueKey: "import:1"}}
+	if _, err := d.Dispatch(second); !errors.Is(err, ErrDuplicateJob) {
+		t.Fatalf("err = %v, want ErrDuplicateJob", err)
+	}
+}
@@ -44,4 +44,9 @@ func Timeout(timeout time.Duration) DispatchOption {
 	}
 }
 
-
+// TaskID: set id cho task, enqueue trùng id trả về asynq.ErrTaskIDConflict
+func TaskID(id string) DispatchOption {
+	return func(task *asynq.Task) asynq.Option {
+		return asynq.TaskID(id)
+	}
+}
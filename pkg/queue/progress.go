@@ -0,0 +1,167 @@
+package queue
+
+import (
+	"context"
+	"core-ledger/pkg/logging"
+	"core-ledger/pkg/metrics"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// Trạng thái job lưu ở JobRun
+const (
+	JobStateActive    = "active"
+	JobStateRetry     = "retry"
+	JobStateCompleted = "completed"
+	JobStateFailed    = "failed"
+)
+
+const (
+	// progressFlushInterval khoảng cách tối thiểu giữa hai lần lưu tiến độ, lần báo đủ total luôn được lưu
+	progressFlushInterval = 500 * time.Millisecond
+	// DefaultResultRetention thời gian asynq giữ task đã xong để đọc kết quả qua Inspector
+	DefaultResultRetention = 24 * time.Hour
+)
+
+// Progress tiến độ handler báo qua Reporter
+type Progress struct {
+	Processed int64  `json:"processed"`
+	Total     int64  `json:"total"`
+	Message   string `json:"message,omitempty"`
+}
+
+// JobOutput nội dung ghi vào ResultWriter của asynq (TaskInfo.Result)
+type JobOutput struct {
+	Progress *Progress       `json:"progress,omitempty"`
+	Result   json.RawMessage `json:"result,omitempty"`
+}
+
+// JobRun trạng thái một task đã báo tiến độ/kết quả, lưu qua JobRunStore
+type JobRun struct {
+	TaskID     string
+	Type       string
+	Queue      string
+	State      string
+	Attempts   int
+	MaxRetry   int
+	Progress   Progress
+	Result     json.RawMessage
+	Error      string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+}
+
+// JobRunStore lưu bản sao JobRun (DB) để đọc được sau khi asynq xoá task
+type JobRunStore interface {
+	SaveJobRun(ctx context.Context, run JobRun) error
+}
+
+// Reporter handler lấy bằng ReporterFromContext để báo tiến độ và ghi kết quả.
+// Chỉ job có gọi Progress/SetResult mới được lưu vào JobRunStore.
+type Reporter struct {
+	mu        sync.Mutex
+	run       JobRun
+	writer    *asynq.ResultWriter
+	store     JobRunStore
+	touched   bool
+	lastFlush time.Time
+}
+
+type reporterKey struct{}
+
+func newReporter(run JobRun, writer *asynq.ResultWriter, store JobRunStore) *Reporter {
+	return &Reporter{run: run, writer: writer, store: store}
+}
+
+func withReporter(ctx context.Context, r *Reporter) context.Context {
+	return context.WithValue(ctx, reporterKey{}, r)
+}
+
+// ReporterFromContext reporter của job đang chạy, nil (các method không làm gì) nếu ctx không thuộc job
+func ReporterFromContext(ctx context.Context) *Reporter {
+	r, _ := ctx.Value(reporterKey{}).(*Reporter)
+	return r
+}
+
+// Progress báo đã xử lý processed/total, lưu tối đa mỗi progressFlushInterval
+func (r *Reporter) Progress(ctx context.Context, processed, total int64, message string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.Progress = Progress{Processed: processed, Total: total, Message: message}
+	r.touched = true
+	if processed < total && time.Since(r.lastFlush) < progressFlushInterval {
+		return
+	}
+	r.flush(ctx)
+}
+
+// SetResult ghi kết quả của job (JSON), đọc lại qua GET /jobs/:id
+func (r *Reporter) SetResult(ctx context.Context, v any) error {
+	if r == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.run.Result = data
+	r.touched = true
+	r.flush(ctx)
+	return nil
+}
+
+// finish lưu trạng thái cuối của lần chạy theo kết quả success/retry/failure
+func (r *Reporter) finish(ctx context.Context, result string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.touched {
+		return
+	}
+	switch result {
+	case metrics.ResultSuccess:
+		r.run.State = JobStateCompleted
+	case metrics.ResultRetry:
+		r.run.State = JobStateRetry
+	default:
+		r.run.State = JobStateFailed
+	}
+	if err != nil {
+		r.run.Error = err.Error()
+	}
+	if result != metrics.ResultRetry {
+		now := time.Now()
+		r.run.FinishedAt = &now
+	}
+	// job timeout thì ctx đã huỷ nhưng vẫn cần lưu trạng thái cuối
+	r.flush(context.WithoutCancel(ctx))
+}
+
+// flush ghi ResultWriter (nếu chạy trên asynq) và JobRunStore, gọi khi đang giữ mu
+func (r *Reporter) flush(ctx context.Context) {
+	r.lastFlush = time.Now()
+	if r.writer != nil {
+		output := JobOutput{Result: r.run.Result}
+		if r.run.Progress != (Progress{}) {
+			progress := r.run.Progress
+			output.Progress = &progress
+		}
+		if data, err := json.Marshal(output); err == nil {
+			if _, err := r.writer.Write(data); err != nil {
+				logging.FromContext(ctx).Warnw("write job result", "task_id", r.run.TaskID, "error", err)
+			}
+		}
+	}
+	if r.store != nil {
+		if err := r.store.SaveJobRun(ctx, r.run); err != nil {
+			logging.FromContext(ctx).Warnw("save job run", "task_id", r.run.TaskID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+type progressJob struct {
+	BaseJob
+	Items int `json:"items"`
+}
+
+func (j *progressJob) GetPayload() interface{} { return j }
+func (j *progressJob) GetType() string         { return "progress" }
+
+// progressHandler báo tiến độ từng item rồi ghi số item làm kết quả
+type progressHandler struct{}
+
+func (progressHandler) Handle(ctx context.Context, j Job) error {
+	items := int64(j.(*progressJob).Items)
+	reporter := ReporterFromContext(ctx)
+	for i := int64(1); i <= items; i++ {
+		reporter.Progress(ctx, i, items, "processing")
+	}
+	return reporter.SetResult(ctx, map[string]int64{"items": items})
+}
+
+func (progressHandler) Failed(context.Context, Job, error) {}
+
+type memoryJobRuns struct {
+	mu   sync.Mutex
+	runs map[string]JobRun
+}
+
+func (s *memoryJobRuns) SaveJobRun(_ context.Context, run JobRun) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runs[run.TaskID] = run
+	return nil
+}
+
+func TestReporterSavesProgressAndResult(t *testing.T) {
+	d, h, _ := newTestLocal(t, NewSyncDispatcher)
+	store := &memoryJobRuns{runs: map[string]JobRun{}}
+	d.SetJobRunStore(store)
+	d.RegisterJob("progress", &progressJob{}, progressHandler{})
+
+	id, err := d.Dispatch(&progressJob{Items: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run, ok := store.runs[id]
+	if !ok {
+		t.Fatalf("job run %q not saved", id)
+	}
+	if run.State != JobStateCompleted || run.Progress.Processed != 3 || run.Progress.Total != 3 || run.FinishedAt == nil {
+		t.Fatalf("run = %+v", run)
+	}
+	var result map[string]int64
+	if err := json.Unmarshal(run.Result, &result); err != nil || result["items"] != 3 {
+		t.Fatalf("result = %s, err = %v", run.Result, err)
+	}
+
+	// job không dùng Reporter thì không lưu vào store
+	if _, err := d.Dispatch(&stepJob{Name: "quiet"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.runs) != 1 || len(h.ran) != 1 {
+		t.Fatalf("runs = %d, ran = %v", len(store.runs), h.ran)
+	}
+}
+
+func TestReporterFromContextWithoutJobIsNoop(t *testing.T) {
+	ctx := context.Background()
+	reporter := ReporterFromContext(ctx)
+	reporter.Progress(ctx, 1, 2, "")
+	if err := reporter.SetResult(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,377 @@
+package queue
+
+import (
+	"context"
+	"core-ledger/pkg/logging"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// DefaultScheduleTimezone timezone mặc định của lịch chạy định kỳ
+const DefaultScheduleTimezone = "Asia/Ho_Chi_Minh"
+
+const (
+	// schedulerLeaderKey khoá Redis giữ id của replica đang là leader (chỉ leader chạy asynq.Scheduler)
+	schedulerLeaderKey = "core-ledger:scheduler:leader"
+	// schedulerRunsKey hash lần chạy gần nhất theo tên schedule
+	schedulerRunsKey = "core-ledger:scheduler:runs"
+
+	defaultLeaderTTL = 15 * time.Second
+)
+
+var (
+	renewLeaderScript   = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("PEXPIRE", KEYS[1], ARGV[2]) else return 0 end`)
+	releaseLeaderScript = redis.NewScript(`if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`)
+)
+
+// Schedule một job chạy định kỳ theo cron
+type Schedule struct {
+	// Name định danh duy nhất, dùng để lưu lần chạy gần nhất và hiển thị ở /admin/schedules
+	Name        string
+	Description string
+	// Cron biểu thức 5 trường ("0 6 * * *") hoặc descriptor ("@every 10s", "@daily")
+	Cron string
+	// Timezone tên IANA, để trống thì dùng timezone của Scheduler
+	Timezone string
+	// Job mẫu được enqueue mỗi lần tới lịch (queue/retry/backoff lấy từ BaseJob)
+	Job Job
+	// Unique chặn enqueue trùng trong khoảng thời gian (phòng trường hợp đổi leader giữa chừng)
+	Unique time.Duration
+	// Options ghi đè option mặc định lấy từ Job, VD Retry(0)
+	Options []DispatchOption
+}
+
+// ScheduleInfo trạng thái của một schedule
+type ScheduleInfo struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Cron        string     `json:"cron"`
+	Timezone    string     `json:"timezone"`
+	JobType     string     `json:"job_type"`
+	Queue       string     `json:"queue"`
+	NextRunAt   *time.Time `json:"next_run_at,omitempty"`
+	LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+	LastTaskID  string     `json:"last_task_id,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+}
+
+// SchedulerOptions cấu hình Scheduler
+type SchedulerOptions struct {
+	// Location timezone mặc định, nil = Asia/Ho_Chi_Minh
+	Location *time.Location
+	// LeaderTTL thời gian giữ quyền leader, leader gia hạn mỗi TTL/3; replica khác chỉ thay thế sau khi hết TTL
+	LeaderTTL time.Duration
+}
+
+type scheduleEntry struct {
+	Schedule
+	timezone string
+	spec     string
+	cron     cron.Schedule
+}
+
+type scheduleRun struct {
+	At     time.Time `json:"at"`
+	TaskID string    `json:"task_id,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Scheduler đăng ký Job theo cron trên asynq.Scheduler.
+// Mọi replica đều chạy Scheduler nhưng chỉ replica giữ khoá leader trên Redis mới enqueue,
+// nên mỗi lần tới lịch job chỉ được bắn một lần.
+type Scheduler struct {
+	redisOpt  asynq.RedisClientOpt
+	rdb       redis.UniversalClient
+	location  *time.Location
+	leaderTTL time.Duration
+	id        string
+	entries   []*scheduleEntry
+	logger    *zap.SugaredLogger
+
+	mu      sync.Mutex
+	running *asynq.Scheduler
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewScheduler kiểm tra cron/timezone của các schedule, chưa kết nối Redis cho tới khi Start/Schedules
+func NewScheduler(redisOpt asynq.RedisClientOpt, opts SchedulerOptions, schedules ...Schedule) (*Scheduler, error) {
+	location := opts.Location
+	if location == nil {
+		loc, err := time.LoadLocation(DefaultScheduleTimezone)
+		if err != nil {
+			return nil, err
+		}
+		location = loc
+	}
+	leaderTTL := opts.LeaderTTL
+	if leaderTTL <= 0 {
+		leaderTTL = defaultLeaderTTL
+	}
+	hostname, _ := os.Hostname()
+	s := &Scheduler{
+		redisOpt:  redisOpt,
+		location:  location,
+		leaderTTL: leaderTTL,
+		id:        fmt.Sprintf("%s:%d:%s", hostname, os.Getpid(), uuid.NewString()[:8]),
+		logger:    logging.DefaultLogger().Named("scheduler"),
+	}
+
+	names := make(map[string]bool, len(schedules))
+	for _, sc := range schedules {
+		if sc.Name == "" || sc.Job == nil {
+			return nil, fmt.Errorf("schedule requires name and job")
+		}
+		if names[sc.Name] {
+			return nil, fmt.Errorf("duplicate schedule %q", sc.Name)
+		}
+		names[sc.Name] = true
+
+		timezone := sc.Timezone
+		if timezone == "" {
+			timezone = location.String()
+		} else if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, fmt.Errorf("schedule %s: %w", sc.Name, err)
+		}
+		spec := fmt.Sprintf("CRON_TZ=%s %s", timezone, sc.Cron)
+		parsed, err := cron.ParseStandard(spec)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %s: invalid cron %q: %w", sc.Name, sc.Cron, err)
+		}
+		s.entries = append(s.entries, &scheduleEntry{Schedule: sc, timezone: timezone, spec: spec, cron: parsed})
+	}
+	return s, nil
+}
+
+func (s *Scheduler) client() redis.UniversalClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rdb == nil {
+		s.rdb = s.redisOpt.MakeRedisClient().(redis.UniversalClient)
+	}
+	return s.rdb
+}
+
+// Start chạy vòng tranh cử leader, replica thắng sẽ khởi động asynq.Scheduler
+func (s *Scheduler) Start() error {
+	if len(s.entries) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.loop(ctx)
+	return nil
+}
+
+// Stop dừng enqueue, nhả khoá leader để replica khác tiếp quản ngay và đóng kết nối Redis
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+		<-s.done
+		s.stepDown()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if err := releaseLeaderScript.Run(ctx, s.client(), []string{schedulerLeaderKey}, s.id).Err(); err != nil {
+			s.logger.Warnw("release scheduler leadership", "error", err)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rdb != nil {
+		_ = s.rdb.Close()
+		s.rdb = nil
+	}
+}
+
+// IsLeader replica hiện tại có đang enqueue job theo lịch hay không
+func (s *Scheduler) IsLeader() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running != nil
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	defer close(s.done)
+	ticker := time.NewTicker(s.leaderTTL / 3)
+	defer ticker.Stop()
+	for {
+		s.campaign(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign gia hạn khoá nếu đang là leader, ngược lại thử giành khoá
+func (s *Scheduler) campaign(ctx context.Context) {
+	rdb := s.client()
+	if s.IsLeader() {
+		renewed, err := renewLeaderScript.Run(ctx, rdb, []string{schedulerLeaderKey}, s.id, s.leaderTTL.Milliseconds()).Int()
+		if err != nil || renewed == 0 {
+			// không chắc còn giữ khoá thì dừng enqueue, tránh hai replica cùng bắn
+			s.logger.Warnw("lost scheduler leadership", "id", s.id, "error", err)
+			s.stepDown()
+		}
+		return
+	}
+	acquired, err := rdb.SetNX(ctx, schedulerLeaderKey, s.id, s.leaderTTL).Result()
+	if err != nil || !acquired {
+		return
+	}
+	if err := s.lead(); err != nil {
+		s.logger.Errorw("start scheduler", "error", err)
+		_ = releaseLeaderScript.Run(ctx, rdb, []string{schedulerLeaderKey}, s.id).Err()
+		return
+	}
+	s.logger.Infow("acquired scheduler leadership", "id", s.id, "schedules", len(s.entries))
+}
+
+func (s *Scheduler) lead() error {
+	scheduler := asynq.NewScheduler(s.redisOpt, &asynq.SchedulerOpts{
+		Location:        s.location,
+		PostEnqueueFunc: s.recordEnqueued,
+		// PostEnqueueFunc không có task khi enqueue lỗi, cần handler này để biết schedule nào lỗi
+		EnqueueErrorHandler: s.recordEnqueueError,
+	})
+	for _, e := range s.entries {
+		task, opts, err := e.task()
+		if err != nil {
+			return err
+		}
+		if _, err := scheduler.Register(e.spec, task, opts...); err != nil {
+			return fmt.Errorf("register schedule %s: %w", e.Name, err)
+		}
+	}
+	if err := scheduler.Start(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.running = scheduler
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Scheduler) stepDown() {
+	s.mu.Lock()
+	running := s.running
+	s.running = nil
+	s.mu.Unlock()
+	if running != nil {
+		running.Shutdown()
+	}
+}
+
+// task tạo asynq.Task của schedule, payload ghi tên schedule để ghi nhận lần chạy
+func (e *scheduleEntry) task() (*asynq.Task, []asynq.Option, error) {
+	data, err := json.Marshal(JobPayload{
+		Type:     e.Job.GetType(),
+		Data:     e.Job.GetPayload(),
+		Backoff:  e.Job.GetBackoff(),
+		Schedule: e.Name,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	task := asynq.NewTask(e.Job.GetType(), data)
+	opts := []asynq.Option{asynq.Queue(e.Job.GetQueue())}
+	if retry := e.Job.GetRetry(); retry > 0 {
+		opts = append(opts, asynq.MaxRetry(retry))
+	}
+	if e.Unique > 0 {
+		opts = append(opts, asynq.Unique(e.Unique))
+	}
+	for _, option := range e.Options {
+		opts = append(opts, option(task))
+	}
+	return task, opts, nil
+}
+
+func (s *Scheduler) recordEnqueued(info *asynq.TaskInfo, err error) {
+	if err != nil || info == nil {
+		return
+	}
+	s.recordRun(info.Payload, scheduleRun{At: time.Now(), TaskID: info.ID})
+}
+
+func (s *Scheduler) recordEnqueueError(task *asynq.Task, _ []asynq.Option, err error) {
+	if errors.Is(err, asynq.ErrDuplicateTask) {
+		// replica trước vừa enqueue trong khoảng Unique, không tính là lỗi
+		return
+	}
+	s.logger.Errorw("enqueue scheduled job", "type", task.Type(), "error", err)
+	s.recordRun(task.Payload(), scheduleRun{At: time.Now(), Error: err.Error()})
+}
+
+func (s *Scheduler) recordRun(payload []byte, run scheduleRun) {
+	var p JobPayload
+	if err := json.Unmarshal(payload, &p); err != nil || p.Schedule == "" {
+		return
+	}
+	data, err := json.Marshal(run)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.client().HSet(ctx, schedulerRunsKey, p.Schedule, data).Err(); err != nil {
+		s.logger.Warnw("record schedule run", "schedule", p.Schedule, "error", err)
+	}
+}
+
+// Leader id của replica đang giữ quyền leader, rỗng nếu chưa có
+func (s *Scheduler) Leader(ctx context.Context) (string, error) {
+	leader, err := s.client().Get(ctx, schedulerLeaderKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return leader, err
+}
+
+// Schedules danh sách schedule kèm lần chạy kế tiếp và lần chạy gần nhất (đọc từ Redis, dùng được ở mọi process)
+func (s *Scheduler) Schedules(ctx context.Context) ([]ScheduleInfo, error) {
+	var runs map[string]string
+	if len(s.entries) > 0 {
+		var err error
+		if runs, err = s.client().HGetAll(ctx, schedulerRunsKey).Result(); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	list := make([]ScheduleInfo, 0, len(s.entries))
+	for _, e := range s.entries {
+		next := e.cron.Next(now)
+		info := ScheduleInfo{
+			Name:        e.Name,
+			Description: e.Description,
+			Cron:        e.Cron,
+			Timezone:    e.timezone,
+			JobType:     e.Job.GetType(),
+			Queue:       e.Job.GetQueue(),
+			NextRunAt:   &next,
+		}
+		var run scheduleRun
+		if raw, ok := runs[e.Name]; ok && json.Unmarshal([]byte(raw), &run) == nil {
+			info.LastRunAt = &run.At
+			info.LastTaskID = run.TaskID
+			info.LastError = run.Error
+		}
+		list = append(list, info)
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
@@ -0,0 +1,91 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hibiken/asynq"
+)
+
+func newTestScheduler(t *testing.T, mr *miniredis.Miniredis) *Scheduler {
+	t.Helper()
+	s, err := NewScheduler(asynq.RedisClientOpt{Addr: mr.Addr()}, SchedulerOptions{LeaderTTL: 300 * time.Millisecond},
+		Schedule{Name: "trace-test", Cron: "@every 1s", Job: &traceTestJob{Value: "x"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before timeout")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestSchedulerSingleLeaderAndFailover(t *testing.T) {
+	mr := miniredis.RunT(t)
+	a := newTestScheduler(t, mr)
+	b := newTestScheduler(t, mr)
+	if err := a.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer b.Stop()
+
+	waitFor(t, 2*time.Second, func() bool { return a.IsLeader() || b.IsLeader() })
+	leader, follower := a, b
+	if b.IsLeader() {
+		leader, follower = b, a
+	}
+	time.Sleep(300 * time.Millisecond)
+	if follower.IsLeader() {
+		t.Fatal("both replicas became leader")
+	}
+
+	leader.Stop()
+	waitFor(t, 2*time.Second, follower.IsLeader)
+
+	id, err := follower.Leader(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != follower.id {
+		t.Fatalf("leader = %q, want %q", id, follower.id)
+	}
+}
+
+func TestSchedulerRecordsLastRun(t *testing.T) {
+	mr := miniredis.RunT(t)
+	s := newTestScheduler(t, mr)
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	waitFor(t, 4*time.Second, func() bool {
+		list, err := s.Schedules(context.Background())
+		return err == nil && len(list) == 1 && list[0].LastRunAt != nil
+	})
+	list, _ := s.Schedules(context.Background())
+	if list[0].LastTaskID == "" || list[0].NextRunAt == nil || list[0].Timezone != DefaultScheduleTimezone {
+		t.Fatalf("unexpected schedule info: %+v", list[0])
+	}
+}
+
+func TestNewSchedulerRejectsInvalidCron(t *testing.T) {
+	_, err := NewScheduler(asynq.RedisClientOpt{}, SchedulerOptions{},
+		Schedule{Name: "bad", Cron: "not a cron", Job: &traceTestJob{}})
+	if err == nil {
+		t.Fatal("expected error for invalid cron")
+	}
+}
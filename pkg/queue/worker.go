@@ -2,6 +2,9 @@ package queue
 
 import (
 	"context"
+	"core-ledger/pkg/logging"
+	"core-ledger/pkg/metrics"
+	"core-ledger/pkg/tracing"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +14,10 @@ import (
 	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // JobFactory function để tạo job instance mới
@@ -18,10 +25,41 @@ type JobFactory func() Job
 
 // Worker quản lý việc xử lý jobs
 type Worker struct {
-	server    *asynq.Server
-	mux       *asynq.ServeMux
-	factories map[string]JobFactory
-	handlers  map[string]JobHandler
+	registry
+	server *asynq.Server
+	mux    *asynq.ServeMux
+	// rdb/dispatcher dùng để enqueue bước kế tiếp và cập nhật trạng thái workflow
+	rdb        redis.UniversalClient
+	dispatcher *asynqDispatcher
+	// recorder lưu job lỗi lần cuối (bảng failed_jobs), nil = chỉ gọi hook Failed
+	recorder FailedJobRecorder
+	// jobRuns lưu tiến độ/kết quả handler báo qua Reporter (bảng job_runs)
+	jobRuns JobRunStore
+}
+
+// FailedJob job đã lỗi lần cuối (hết retry hoặc SkipRetry)
+type FailedJob struct {
+	TaskID   string
+	Type     string
+	Queue    string
+	Payload  []byte
+	Error    string
+	Attempts int
+	MaxRetry int
+	// Handler kiểu của handler xử lý job, VD "*handlers.ImportCoaAccountHandler"
+	Handler  string
+	FailedAt time.Time
+}
+
+// FailedJobRecorder lưu job lỗi lần cuối để tra cứu và chạy lại (kể cả khi asynq đã xoá task)
+type FailedJobRecorder interface {
+	RecordFailedJob(ctx context.Context, job FailedJob) error
+}
+
+// newWorkflowDispatcher dispatcher dùng chung kết nối Redis của worker
+func newWorkflowDispatcher(opt asynq.RedisConnOpt) (redis.UniversalClient, *asynqDispatcher) {
+	rdb := opt.MakeRedisClient().(redis.UniversalClient)
+	return rdb, &asynqDispatcher{client: asynq.NewClientFromRedisClient(rdb), rdb: rdb}
 }
 
 // defaultRetryDelayFunc tạo exponential backoff cho retry
@@ -70,48 +108,20 @@ func NewWorker(redisAddr string, concurrency int, queues map[string]int) *Worker
 			Queues:         queues,
 			RetryDelayFunc: retryDelayFuncWithJobBackoff,
 			ErrorHandler: asynq.ErrorHandlerFunc(func(ctx context.Context, t *asynq.Task, err error) {
-				if w == nil {
-					return
-				}
-				jobType := t.Type()
-				handler, ok := w.handlers[jobType]
-				if !ok {
-					return
-				}
-				// chỉ gọi Failed khi hết retry
-				retryCount, okRetry := asynq.GetRetryCount(ctx)
-				maxRetry, okMax := asynq.GetMaxRetry(ctx)
-				// Nếu không lấy được metadata retry, bỏ qua để tránh gọi Failed sai thời điểm
-				if !(okRetry && okMax) {
-					return
-				}
-				if maxRetry > 0 && (retryCount+1) < maxRetry {
-					return
-				}
-				// dựng lại job
-				factory, exists := w.factories[jobType]
-				if !exists {
-					return
-				}
-				job := factory()
-				if job == nil {
-					return
-				}
-				if errPopulate := w.populateJobData(job, t.Payload()); errPopulate != nil {
-					log.Printf("failed to populate job for Failed hook: %v", errPopulate)
-				}
-				if fh, ok := handler.(failableHandler); ok {
-					fh.Failed(ctx, job, err)
+				if w != nil {
+					w.handleError(ctx, t, err)
 				}
 			}),
 		},
 	)
 
+	rdb, dispatcher := newWorkflowDispatcher(asynq.RedisClientOpt{Addr: redisAddr})
 	w = &Worker{
-		server:    srv,
-		mux:       asynq.NewServeMux(),
-		factories: make(map[string]JobFactory),
-		handlers:  make(map[string]JobHandler),
+		registry:   newRegistry(),
+		server:     srv,
+		mux:        asynq.NewServeMux(),
+		rdb:        rdb,
+		dispatcher: dispatcher,
 	}
 	return w
 }
@@ -126,44 +136,19 @@ func NewWorkerWithRedis(opt asynq.RedisClientOpt, concurrency int, queues map[st
 			Queues:         queues,
 			RetryDelayFunc: retryDelayFuncWithJobBackoff,
 			ErrorHandler: asynq.ErrorHandlerFunc(func(ctx context.Context, t *asynq.Task, err error) {
-				if w == nil {
-					return
-				}
-				jobType := t.Type()
-				handler, ok := w.handlers[jobType]
-				if !ok {
-					return
-				}
-				retryCount, okRetry := asynq.GetRetryCount(ctx)
-				maxRetry, okMax := asynq.GetMaxRetry(ctx)
-				if !(okRetry && okMax) {
-					return
-				}
-				if maxRetry > 0 && (retryCount+1) < maxRetry {
-					return
-				}
-				factory, exists := w.factories[jobType]
-				if !exists {
-					return
-				}
-				job := factory()
-				if job == nil {
-					return
-				}
-				if errPopulate := w.populateJobData(job, t.Payload()); errPopulate != nil {
-					log.Printf("failed to populate job for Failed hook: %v", errPopulate)
-				}
-				if fh, ok := handler.(failableHandler); ok {
-					fh.Failed(ctx, job, err)
+				if w != nil {
+					w.handleError(ctx, t, err)
 				}
 			}),
 		},
 	)
+	rdb, dispatcher := newWorkflowDispatcher(opt)
 	w = &Worker{
-		server:    srv,
-		mux:       asynq.NewServeMux(),
-		factories: make(map[string]JobFactory),
-		handlers:  make(map[string]JobHandler),
+		registry:   newRegistry(),
+		server:     srv,
+		mux:        asynq.NewServeMux(),
+		rdb:        rdb,
+		dispatcher: dispatcher,
 	}
 	return w
 }
@@ -172,31 +157,128 @@ func NewWorkerWithRedis(opt asynq.RedisClientOpt, concurrency int, queues map[st
 // jobTemplate: struct mẫu để tạo instance và unmarshal payload
 // handler: đối tượng thực hiện xử lý job (riêng biệt)
 func (w *Worker) RegisterJob(jobType string, jobTemplate Job, handler JobHandler) {
-	// Tạo factory function từ job template
-	w.factories[jobType] = func() Job {
-		// Sử dụng reflection để tạo instance mới
-		jobValue := reflect.ValueOf(jobTemplate)
-		if jobValue.Kind() == reflect.Ptr {
-			jobValue = jobValue.Elem()
+	w.register(jobType, jobTemplate, handler)
+	log.Println("jobType", jobType)
+	log.Printf("Job handler %T", handler)
+	w.mux.HandleFunc(jobType, w.createHandler(jobType))
+}
+
+// createHandler tạo handler function cho asynq, ghi nhận metrics theo queue/job type
+// và mở span consumer nối tiếp trace context lưu trong payload lúc dispatch
+func (w *Worker) createHandler(jobType string) asynq.HandlerFunc {
+	process := w.processHandler(jobType)
+	return func(ctx context.Context, t *asynq.Task) error {
+		start := time.Now()
+		queueName, _ := asynq.GetQueueName(ctx)
+		retryCount, _ := asynq.GetRetryCount(ctx)
+
+		var payload JobPayload
+		if err := json.Unmarshal(t.Payload(), &payload); err == nil {
+			ctx = tracing.Extract(ctx, payload.TraceContext)
 		}
-		newJobValue := reflect.New(jobValue.Type())
-		return newJobValue.Interface().(Job)
+		ctx, span := tracing.Tracer().Start(ctx, "queue.process "+jobType,
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				attribute.String("messaging.system", "asynq"),
+				attribute.String("messaging.destination.name", queueName),
+				attribute.String("queue.job_type", jobType),
+				attribute.Int("queue.retry_count", retryCount),
+			),
+		)
+		defer span.End()
+		if payload.Workflow != nil {
+			span.SetAttributes(attribute.String("queue.workflow_id", payload.Workflow.ID))
+		}
+
+		// logger của job mang job_type/job_id/trace_id cho handler đọc qua context
+		taskID, _ := asynq.GetTaskID(ctx)
+		ctx = logging.WithFields(ctx, logging.FieldJobType, jobType, logging.FieldJobID, taskID, "queue", queueName)
+
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		reporter := newReporter(JobRun{
+			TaskID:    taskID,
+			Type:      jobType,
+			Queue:     queueName,
+			State:     JobStateActive,
+			Attempts:  retryCount + 1,
+			MaxRetry:  maxRetry,
+			StartedAt: start,
+		}, t.ResultWriter(), w.jobRuns)
+		ctx = withReporter(ctx, reporter)
+
+		err := process(ctx, t)
+		result := jobResult(ctx, err)
+		reporter.finish(ctx, result, err)
+		span.SetAttributes(attribute.String("queue.result", result))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		metrics.ObserveJob(queueName, jobType, result, time.Since(start))
+		// retry thì chờ lần chạy sau, chỉ chuyển bước workflow khi job đã có kết quả cuối
+		if payload.Workflow != nil && result != metrics.ResultRetry {
+			w.advanceWorkflow(ctx, payload, err)
+		}
+		return err
 	}
+}
 
-	// Lưu handler (có thể nil nếu muốn fallback dùng method trên job)
-	if handler != nil {
-		w.handlers[jobType] = handler
+// SetFailedJobRecorder đăng ký nơi lưu job lỗi lần cuối, áp dụng cho mọi job type đã đăng ký
+func (w *Worker) SetFailedJobRecorder(recorder FailedJobRecorder) {
+	w.recorder = recorder
+}
+
+// SetJobRunStore đăng ký nơi lưu tiến độ/kết quả job (bản sao của ResultWriter)
+func (w *Worker) SetJobRunStore(store JobRunStore) {
+	w.jobRuns = store
+}
+
+// handleError khi job lỗi lần cuối (hết retry hoặc SkipRetry): lưu vào FailedJobRecorder rồi gọi hook Failed của handler
+func (w *Worker) handleError(ctx context.Context, t *asynq.Task, err error) {
+	jobType := t.Type()
+	// Nếu không lấy được metadata retry, bỏ qua để tránh gọi Failed sai thời điểm
+	retryCount, okRetry := asynq.GetRetryCount(ctx)
+	if !okRetry {
+		return
 	}
-	log.Println("jobType", jobType)
-	log.Printf("Job handler", handler)
-	w.mux.HandleFunc(jobType, w.createHandler(jobType))
+	if jobResult(ctx, err) != metrics.ResultFailure {
+		return
+	}
+	queueName, _ := asynq.GetQueueName(ctx)
+	taskID, _ := asynq.GetTaskID(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	w.handleFailure(ctx, w.recorder, FailedJob{
+		TaskID:   taskID,
+		Type:     jobType,
+		Queue:    queueName,
+		Payload:  t.Payload(),
+		Attempts: retryCount + 1,
+		MaxRetry: maxRetry,
+		FailedAt: time.Now(),
+	}, err)
 }
 
-// createHandler tạo handler function cho asynq
-func (w *Worker) createHandler(jobType string) asynq.HandlerFunc {
+// jobResult success / retry (asynq sẽ chạy lại) / failure (hết retry hoặc SkipRetry)
+func jobResult(ctx context.Context, err error) string {
+	if err == nil {
+		return metrics.ResultSuccess
+	}
+	if errors.Is(err, asynq.SkipRetry) {
+		return metrics.ResultFailure
+	}
+	retryCount, okRetry := asynq.GetRetryCount(ctx)
+	maxRetry, okMax := asynq.GetMaxRetry(ctx)
+	if okRetry && okMax && retryCount < maxRetry {
+		return metrics.ResultRetry
+	}
+	return metrics.ResultFailure
+}
+
+// processHandler dựng job từ payload và gọi handler đã đăng ký
+func (r *registry) processHandler(jobType string) asynq.HandlerFunc {
 	return func(ctx context.Context, t *asynq.Task) error {
 		// Lấy factory từ registry
-		factory, exists := w.factories[jobType]
+		factory, exists := r.factories[jobType]
 		if !exists {
 			return fmt.Errorf("no job factory registered for job type: %s", jobType)
 		}
@@ -208,14 +290,14 @@ func (w *Worker) createHandler(jobType string) asynq.HandlerFunc {
 		}
 
 		// Unmarshal JobPayload vào job instance
-		if err := w.populateJobData(job, t.Payload()); err != nil {
+		if err := r.populateJobData(job, t.Payload()); err != nil {
 			return fmt.Errorf("failed to populate job data: %w", err)
 		}
 
 		var handlerErr error
 
 		// Nếu có handler riêng, gọi handler đó
-		if h, ok := w.handlers[jobType]; ok && h != nil {
+		if h, ok := r.handlers[jobType]; ok && h != nil {
 			log.Printf("Processing job with external handler: %s", jobType)
 			handlerErr = h.Handle(ctx, job)
 		} else if legacyHandler, ok := any(job).(interface {
@@ -251,7 +333,7 @@ func (w *Worker) createHandler(jobType string) asynq.HandlerFunc {
 }
 
 // populateJobData điền data vào job instance từ JobPayload
-func (w *Worker) populateJobData(job Job, payload []byte) error {
+func (r *registry) populateJobData(job Job, payload []byte) error {
 	var jobPayload JobPayload
 	if err := json.Unmarshal(payload, &jobPayload); err != nil {
 		return fmt.Errorf("failed to unmarshal job payload: %w", err)
@@ -267,6 +349,70 @@ func (w *Worker) populateJobData(job Job, payload []byte) error {
 	return json.Unmarshal(data, job)
 }
 
+// registry job template + handler đã đăng ký, dùng chung cho Worker (asynq) và LocalDispatcher
+type registry struct {
+	factories map[string]JobFactory
+	handlers  map[string]JobHandler
+}
+
+func newRegistry() registry {
+	return registry{
+		factories: make(map[string]JobFactory),
+		handlers:  make(map[string]JobHandler),
+	}
+}
+
+// register lưu factory dựng job từ template và handler (nil = fallback dùng method Handle trên job)
+func (r *registry) register(jobType string, jobTemplate Job, handler JobHandler) {
+	// Tạo factory function từ job template
+	r.factories[jobType] = func() Job {
+		// Sử dụng reflection để tạo instance mới
+		jobValue := reflect.ValueOf(jobTemplate)
+		if jobValue.Kind() == reflect.Ptr {
+			jobValue = jobValue.Elem()
+		}
+		newJobValue := reflect.New(jobValue.Type())
+		return newJobValue.Interface().(Job)
+	}
+
+	// Lưu handler (có thể nil nếu muốn fallback dùng method trên job)
+	if handler != nil {
+		r.handlers[jobType] = handler
+	}
+}
+
+// handleFailure job lỗi lần cuối: ghi vào recorder (nếu có) rồi gọi hook Failed của handler
+func (r *registry) handleFailure(ctx context.Context, recorder FailedJobRecorder, failed FailedJob, err error) {
+	handler := r.handlers[failed.Type]
+	if recorder != nil {
+		failed.Error = err.Error()
+		if handler != nil {
+			failed.Handler = reflect.TypeOf(handler).String()
+		}
+		if errRecord := recorder.RecordFailedJob(ctx, failed); errRecord != nil {
+			logging.FromContext(ctx).Errorw("record failed job", "type", failed.Type, "task_id", failed.TaskID, "error", errRecord)
+		}
+	}
+
+	fh, ok := handler.(failableHandler)
+	if !ok {
+		return
+	}
+	// dựng lại job
+	factory, exists := r.factories[failed.Type]
+	if !exists {
+		return
+	}
+	job := factory()
+	if job == nil {
+		return
+	}
+	if errPopulate := r.populateJobData(job, failed.Payload); errPopulate != nil {
+		log.Printf("failed to populate job for Failed hook: %v", errPopulate)
+	}
+	fh.Failed(ctx, job, err)
+}
+
 // failableHandler mô tả handler có hook Failed
 type failableHandler interface {
 	JobHandler
@@ -282,6 +428,9 @@ func (w *Worker) Start() error {
 // Stop dừng worker
 func (w *Worker) Stop() {
 	w.server.Shutdown()
+	if w.rdb != nil {
+		_ = w.rdb.Close()
+	}
 }
 
 // Global worker instance
@@ -0,0 +1,61 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hibiken/asynq"
+)
+
+type memoryRecorder struct {
+	mu   sync.Mutex
+	jobs []FailedJob
+}
+
+func (r *memoryRecorder) RecordFailedJob(_ context.Context, job FailedJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs = append(r.jobs, job)
+	return nil
+}
+
+func TestFailedJobRecorderRecordsFinalFailure(t *testing.T) {
+	mr := miniredis.RunT(t)
+	opt := asynq.RedisClientOpt{Addr: mr.Addr()}
+	h := &recordingHandler{}
+	rec := &memoryRecorder{}
+	w := NewWorkerWithRedis(opt, 1, map[string]int{"default": 1})
+	w.RegisterJob("workflow_step", &stepJob{}, h)
+	w.SetFailedJobRecorder(rec)
+	go func() { _ = w.Start() }()
+	t.Cleanup(w.Stop)
+
+	client := asynq.NewClient(opt)
+	t.Cleanup(func() { _ = client.Close() })
+	d := NewDispatcher(client)
+	if _, err := d.Dispatch(&stepJob{Name: "ok"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Dispatch(&stepJob{Name: "bad", Fail: true}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, 10*time.Second, func() bool {
+		rec.mu.Lock()
+		defer rec.mu.Unlock()
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		return len(rec.jobs) == 1 && len(h.failedSteps) == 1 && len(h.ran) == 2
+	})
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	got := rec.jobs[0]
+	if got.Type != "workflow_step" || got.Queue != "default" || got.TaskID == "" || got.Attempts != 1 {
+		t.Fatalf("recorded = %+v", got)
+	}
+	if got.Handler != "*queue.recordingHandler" {
+		t.Fatalf("handler = %q", got.Handler)
+	}
+}
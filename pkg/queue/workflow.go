@@ -0,0 +1,320 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Loại workflow
+const (
+	WorkflowChain = "chain"
+	WorkflowBatch = "batch"
+)
+
+// Trạng thái workflow lưu trong Redis
+const (
+	WorkflowStatusRunning   = "RUNNING"
+	WorkflowStatusSucceeded = "SUCCEEDED"
+	WorkflowStatusFailed    = "FAILED"
+)
+
+const (
+	workflowKeyPrefix = "core-ledger:queue:workflow:"
+	// workflowStateTTL thời gian giữ trạng thái workflow trong Redis
+	workflowStateTTL = 7 * 24 * time.Hour
+)
+
+// ErrWorkflowFailed truyền vào hook Failed của các bước không được chạy vì một bước trước/song song đã lỗi
+var ErrWorkflowFailed = errors.New("workflow failed")
+
+// Workflow nhóm job chạy theo thứ tự (Chain) hoặc song song rồi chạy tiếp một job (Batch(...).Then(...))
+type Workflow struct {
+	kind string
+	jobs []Job
+	then Job
+}
+
+// Chain job sau chỉ được enqueue khi job trước thành công
+func Chain(jobs ...Job) *Workflow {
+	return &Workflow{kind: WorkflowChain, jobs: jobs}
+}
+
+// Batch các job chạy song song, hoàn thành khi tất cả thành công
+func Batch(jobs ...Job) *Workflow {
+	return &Workflow{kind: WorkflowBatch, jobs: jobs}
+}
+
+// Then job chạy sau khi cả batch thành công; với chain là bước cuối
+func (w *Workflow) Then(job Job) *Workflow {
+	if w.kind == WorkflowChain {
+		w.jobs = append(w.jobs, job)
+		return w
+	}
+	w.then = job
+	return w
+}
+
+// WorkflowStep job chưa enqueue của workflow (type + payload), worker dựng lại bằng job đã đăng ký
+type WorkflowStep struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// WorkflowMeta gắn vào payload của từng job thuộc workflow
+type WorkflowMeta struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	// Next các bước còn lại của chain
+	Next []WorkflowStep `json:"next,omitempty"`
+	// Final job Then của batch, thành công thì workflow hoàn tất
+	Final bool `json:"final,omitempty"`
+}
+
+// WorkflowState trạng thái workflow đọc từ Redis
+type WorkflowState struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`
+	Status     string     `json:"status"`
+	Total      int        `json:"total"`
+	Pending    int        `json:"pending"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+func workflowKey(id string) string {
+	return workflowKeyPrefix + id
+}
+
+// GetWorkflow đọc trạng thái workflow, redis.Nil nếu không tồn tại hoặc đã hết hạn
+func GetWorkflow(ctx context.Context, rdb redis.UniversalClient, id string) (*WorkflowState, error) {
+	fields, err := rdb.HGetAll(ctx, workflowKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, redis.Nil
+	}
+	state := &WorkflowState{
+		ID:     id,
+		Kind:   fields["kind"],
+		Status: fields["status"],
+		Error:  fields["error"],
+	}
+	state.Total, _ = strconv.Atoi(fields["total"])
+	state.Pending, _ = strconv.Atoi(fields["pending"])
+	state.CreatedAt, _ = time.Parse(time.RFC3339Nano, fields["created_at"])
+	if finished, err := time.Parse(time.RFC3339Nano, fields["finished_at"]); err == nil {
+		state.FinishedAt = &finished
+	}
+	return state, nil
+}
+
+func encodeStep(job Job) (WorkflowStep, error) {
+	data, err := json.Marshal(job.GetPayload())
+	if err != nil {
+		return WorkflowStep{}, err
+	}
+	return WorkflowStep{Type: job.GetType(), Data: data}, nil
+}
+
+func (d *asynqDispatcher) DispatchWorkflow(ctx context.Context, wf *Workflow) (string, error) {
+	if d.rdb == nil {
+		return "", fmt.Errorf("workflow requires redis, use NewDispatcherWithRedis")
+	}
+	if wf == nil || (len(wf.jobs) == 0 && wf.then == nil) {
+		return "", fmt.Errorf("workflow has no jobs")
+	}
+	id := uuid.NewString()
+	state := map[string]any{
+		"kind":       wf.kind,
+		"status":     WorkflowStatusRunning,
+		"total":      len(wf.jobs),
+		"pending":    len(wf.jobs),
+		"created_at": time.Now().Format(time.RFC3339Nano),
+	}
+
+	if wf.kind == WorkflowChain {
+		next := make([]WorkflowStep, 0, len(wf.jobs)-1)
+		for _, job := range wf.jobs[1:] {
+			step, err := encodeStep(job)
+			if err != nil {
+				return "", err
+			}
+			next = append(next, step)
+		}
+		if err := d.saveWorkflow(ctx, id, state); err != nil {
+			return "", err
+		}
+		if _, err := d.enqueue(ctx, wf.jobs[0], &WorkflowMeta{ID: id, Kind: WorkflowChain, Next: next}); err != nil {
+			_ = failWorkflow(ctx, d.rdb, id, err)
+			return id, err
+		}
+		return id, nil
+	}
+
+	if wf.then != nil {
+		step, err := encodeStep(wf.then)
+		if err != nil {
+			return "", err
+		}
+		then, _ := json.Marshal(step)
+		state["then"] = string(then)
+		state["total"] = len(wf.jobs) + 1
+	}
+	if err := d.saveWorkflow(ctx, id, state); err != nil {
+		return "", err
+	}
+	if len(wf.jobs) == 0 {
+		_, err := d.enqueue(ctx, wf.then, &WorkflowMeta{ID: id, Kind: WorkflowBatch, Final: true})
+		if err != nil {
+			_ = failWorkflow(ctx, d.rdb, id, err)
+		}
+		return id, err
+	}
+	for _, job := range wf.jobs {
+		// job đã enqueue trước đó vẫn chạy, nhưng workflow đã FAILED nên Then không được enqueue
+		if _, err := d.enqueue(ctx, job, &WorkflowMeta{ID: id, Kind: WorkflowBatch}); err != nil {
+			_ = failWorkflow(ctx, d.rdb, id, err)
+			return id, err
+		}
+	}
+	return id, nil
+}
+
+func (d *asynqDispatcher) saveWorkflow(ctx context.Context, id string, state map[string]any) error {
+	pipe := d.rdb.TxPipeline()
+	pipe.HSet(ctx, workflowKey(id), state)
+	pipe.Expire(ctx, workflowKey(id), workflowStateTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// failWorkflow đánh dấu FAILED, trả về true nếu đây là lần đánh dấu đầu tiên
+func failWorkflow(ctx context.Context, rdb redis.UniversalClient, id string, cause error) bool {
+	key := workflowKey(id)
+	first, err := rdb.HSetNX(ctx, key, "error", cause.Error()).Result()
+	if err != nil || !first {
+		return false
+	}
+	rdb.HSet(ctx, key, "status", WorkflowStatusFailed, "finished_at", time.Now().Format(time.RFC3339Nano))
+	return true
+}
+
+func finishWorkflow(ctx context.Context, rdb redis.UniversalClient, id string) error {
+	return rdb.HSet(ctx, workflowKey(id), "status", WorkflowStatusSucceeded, "pending", 0, "finished_at", time.Now().Format(time.RFC3339Nano)).Err()
+}
+
+// advanceWorkflow được worker gọi sau khi job thuộc workflow kết thúc (thành công hoặc hết retry):
+// thành công thì enqueue bước kế tiếp / Then của batch, lỗi thì đánh dấu workflow FAILED
+// và gọi Failed của các bước sẽ không được chạy.
+func (w *Worker) advanceWorkflow(ctx context.Context, payload JobPayload, jobErr error) {
+	meta := payload.Workflow
+	if jobErr != nil {
+		cause := fmt.Errorf("%w: %s step %s: %v", ErrWorkflowFailed, meta.ID, payload.Type, jobErr)
+		w.failWorkflow(ctx, meta, cause)
+		return
+	}
+
+	switch {
+	case meta.Kind == WorkflowChain && len(meta.Next) > 0:
+		// workflow đã FAILED/SUCCEEDED (VD bước hiện tại bị xử lý lại) thì không enqueue bước kế tiếp
+		fields, err := w.rdb.HMGet(ctx, workflowKey(meta.ID), "status").Result()
+		if err != nil || fields[0] != WorkflowStatusRunning {
+			return
+		}
+		job, err := w.decodeStep(meta.Next[0])
+		if err == nil {
+			w.rdb.HIncrBy(ctx, workflowKey(meta.ID), "pending", -1)
+			_, err = w.dispatcher.enqueue(ctx, job, &WorkflowMeta{ID: meta.ID, Kind: WorkflowChain, Next: meta.Next[1:]})
+		}
+		if err != nil {
+			w.failWorkflow(ctx, meta, fmt.Errorf("%w: %s: enqueue %s: %v", ErrWorkflowFailed, meta.ID, meta.Next[0].Type, err))
+		}
+	case meta.Kind == WorkflowChain || meta.Final:
+		_ = finishWorkflow(ctx, w.rdb, meta.ID)
+	default:
+		key := workflowKey(meta.ID)
+		pending, err := w.rdb.HIncrBy(ctx, key, "pending", -1).Result()
+		if err != nil || pending > 0 {
+			return
+		}
+		fields, err := w.rdb.HMGet(ctx, key, "status", "then").Result()
+		if err != nil || fields[0] != WorkflowStatusRunning {
+			return
+		}
+		then, _ := fields[1].(string)
+		if then == "" {
+			_ = finishWorkflow(ctx, w.rdb, meta.ID)
+			return
+		}
+		// HSETNX đảm bảo Then chỉ được enqueue một lần kể cả khi job cuối được xử lý lại
+		if ok, err := w.rdb.HSetNX(ctx, key, "then_dispatched", 1).Result(); err != nil || !ok {
+			return
+		}
+		var step WorkflowStep
+		job, err := w.decodeStepJSON(then, &step)
+		if err == nil {
+			_, err = w.dispatcher.enqueue(ctx, job, &WorkflowMeta{ID: meta.ID, Kind: WorkflowBatch, Final: true})
+		}
+		if err != nil {
+			w.failWorkflow(ctx, meta, fmt.Errorf("%w: %s: enqueue %s: %v", ErrWorkflowFailed, meta.ID, step.Type, err))
+		}
+	}
+}
+
+func (w *Worker) failWorkflow(ctx context.Context, meta *WorkflowMeta, cause error) {
+	if !failWorkflow(ctx, w.rdb, meta.ID, cause) {
+		return
+	}
+	skipped := meta.Next
+	if meta.Kind == WorkflowBatch && !meta.Final {
+		if then, err := w.rdb.HGet(ctx, workflowKey(meta.ID), "then").Result(); err == nil && then != "" {
+			var step WorkflowStep
+			if json.Unmarshal([]byte(then), &step) == nil {
+				skipped = []WorkflowStep{step}
+			}
+		}
+	}
+	w.failSkipped(ctx, skipped, cause)
+}
+
+// failSkipped gọi Failed của các bước workflow sẽ không được chạy
+func (r *registry) failSkipped(ctx context.Context, steps []WorkflowStep, cause error) {
+	for _, step := range steps {
+		job, err := r.decodeStep(step)
+		if err != nil {
+			continue
+		}
+		if fh, ok := r.handlers[step.Type].(failableHandler); ok {
+			fh.Failed(ctx, job, cause)
+		}
+	}
+}
+
+// decodeStep dựng lại job từ bước workflow bằng job template đã đăng ký
+func (r *registry) decodeStep(step WorkflowStep) (Job, error) {
+	factory, ok := r.factories[step.Type]
+	if !ok {
+		return nil, fmt.Errorf("no job factory registered for job type: %s", step.Type)
+	}
+	job := factory()
+	if err := json.Unmarshal(step.Data, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *registry) decodeStepJSON(raw string, step *WorkflowStep) (Job, error) {
+	if err := json.Unmarshal([]byte(raw), step); err != nil {
+		return nil, err
+	}
+	return r.decodeStep(*step)
+}
@@ -0,0 +1,208 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hibiken/asynq"
+)
+
+type stepJob struct {
+	BaseJob
+	Name string `json:"name"`
+	Fail bool   `json:"fail"`
+	// Wait chặn handler tới khi recordingHandler.release được đóng
+	Wait bool `json:"wait"`
+}
+
+func (j *stepJob) GetPayload() interface{} { return j }
+func (j *stepJob) GetType() string         { return "workflow_step" }
+
+type thenJob struct {
+	BaseJob
+	Name string `json:"name"`
+}
+
+func (j *thenJob) GetPayload() interface{} { return j }
+func (j *thenJob) GetType() string         { return "workflow_then" }
+
+// recordingHandler ghi lại thứ tự job đã chạy và các lần gọi Failed
+type recordingHandler struct {
+	mu          sync.Mutex
+	ran         []string
+	failed      []error
+	failedSteps []string
+	release     chan struct{}
+}
+
+func (h *recordingHandler) Handle(_ context.Context, j Job) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch job := j.(type) {
+	case *stepJob:
+		h.ran = append(h.ran, job.Name)
+		if job.Wait {
+			h.mu.Unlock()
+			<-h.release
+			h.mu.Lock()
+		}
+		if job.Fail {
+			return fmt.Errorf("step %s: %w", job.Name, asynq.SkipRetry)
+		}
+	case *thenJob:
+		h.ran = append(h.ran, job.Name)
+	}
+	return nil
+}
+
+func (h *recordingHandler) Failed(_ context.Context, j Job, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch job := j.(type) {
+	case *thenJob:
+		h.failed = append(h.failed, err)
+	case *stepJob:
+		h.failedSteps = append(h.failedSteps, job.Name)
+	}
+}
+
+func (h *recordingHandler) snapshot() ([]string, []error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.ran...), append([]error(nil), h.failed...)
+}
+
+func startWorkflowWorker(t *testing.T) (*miniredis.Miniredis, Dispatcher, *recordingHandler) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	opt := asynq.RedisClientOpt{Addr: mr.Addr()}
+	h := &recordingHandler{release: make(chan struct{})}
+	w := NewWorkerWithRedis(opt, 1, map[string]int{"default": 1})
+	w.RegisterJob("workflow_step", &stepJob{}, h)
+	w.RegisterJob("workflow_then", &thenJob{}, h)
+	go func() { _ = w.Start() }()
+	t.Cleanup(w.Stop)
+
+	client := asynq.NewClient(opt)
+	t.Cleanup(func() { _ = client.Close() })
+	rdb, _ := newWorkflowDispatcher(opt)
+	return mr, NewDispatcherWithRedis(client, rdb), h
+}
+
+func waitWorkflow(t *testing.T, d Dispatcher, id string) *WorkflowState {
+	t.Helper()
+	rdb := d.(*asynqDispatcher).rdb
+	var state *WorkflowState
+	waitFor(t, 10*time.Second, func() bool {
+		var err error
+		state, err = GetWorkflow(context.Background(), rdb, id)
+		return err == nil && state.Status != WorkflowStatusRunning
+	})
+	return state
+}
+
+func TestChainRunsInOrder(t *testing.T) {
+	_, d, h := startWorkflowWorker(t)
+	id, err := d.DispatchWorkflow(context.Background(), Chain(&stepJob{Name: "a"}, &stepJob{Name: "b"}).Then(&thenJob{Name: "c"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := waitWorkflow(t, d, id)
+	if state.Status != WorkflowStatusSucceeded {
+		t.Fatalf("status = %s (%s)", state.Status, state.Error)
+	}
+	ran, _ := h.snapshot()
+	if fmt.Sprint(ran) != "[a b c]" {
+		t.Fatalf("ran = %v", ran)
+	}
+}
+
+func TestChainStopsWhenWorkflowNotRunning(t *testing.T) {
+	_, d, h := startWorkflowWorker(t)
+	id, err := d.DispatchWorkflow(context.Background(), Chain(&stepJob{Name: "a", Wait: true}, &stepJob{Name: "b"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, 5*time.Second, func() bool {
+		ran, _ := h.snapshot()
+		return len(ran) == 1
+	})
+	// workflow bị đánh dấu FAILED trong lúc bước a đang chạy
+	rdb := d.(*asynqDispatcher).rdb
+	failWorkflow(context.Background(), rdb, id, errors.New("cancelled"))
+	close(h.release)
+
+	time.Sleep(time.Second)
+	if ran, _ := h.snapshot(); fmt.Sprint(ran) != "[a]" {
+		t.Fatalf("ran = %v", ran)
+	}
+	if state := waitWorkflow(t, d, id); state.Status != WorkflowStatusFailed {
+		t.Fatalf("status = %s", state.Status)
+	}
+}
+
+func TestBatchThenRunsAfterAllJobs(t *testing.T) {
+	_, d, h := startWorkflowWorker(t)
+	id, err := d.DispatchWorkflow(context.Background(), Batch(&stepJob{Name: "a"}, &stepJob{Name: "b"}).Then(&thenJob{Name: "lock"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := waitWorkflow(t, d, id)
+	if state.Status != WorkflowStatusSucceeded || state.Total != 3 {
+		t.Fatalf("state = %+v", state)
+	}
+	ran, _ := h.snapshot()
+	if len(ran) != 3 || ran[2] != "lock" {
+		t.Fatalf("ran = %v", ran)
+	}
+}
+
+func TestBatchFailureSkipsThenAndCallsFailed(t *testing.T) {
+	_, d, h := startWorkflowWorker(t)
+	id, err := d.DispatchWorkflow(context.Background(), Batch(&stepJob{Name: "a"}, &stepJob{Name: "b", Fail: true}).Then(&thenJob{Name: "lock"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := waitWorkflow(t, d, id)
+	if state.Status != WorkflowStatusFailed {
+		t.Fatalf("status = %s", state.Status)
+	}
+	waitFor(t, 2*time.Second, func() bool {
+		ran, failed := h.snapshot()
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		return len(ran) == 2 && len(failed) == 1 && len(h.failedSteps) == 1
+	})
+	ran, failed := h.snapshot()
+	for _, name := range ran {
+		if name == "lock" {
+			t.Fatal("then job ran after failed batch")
+		}
+	}
+	if !errors.Is(failed[0], ErrWorkflowFailed) {
+		t.Fatalf("failed err = %v", failed[0])
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if fmt.Sprint(h.failedSteps) != "[b]" {
+		t.Fatalf("failed steps = %v", h.failedSteps)
+	}
+}
+
+func TestUniqueKeyRejectsDuplicate(t *testing.T) {
+	_, d, _ := startWorkflowWorker(t)
+	// job chạy ở queue không có worker để khoá còn giữ
+	first := &stepJob{Name: "a", BaseJob: BaseJob{Queue: "idle", UniqueKey: "import:1"}}
+	if _, err := d.Dispatch(first); err != nil {
+		t.Fatal(err)
+	}
+	second := &stepJob{Name: "a", BaseJob: BaseJob{Queue: "idle", UniqueKey: "import:1"}}
+	if _, err := d.Dispatch(second); !errors.Is(err, ErrDuplicateJob) {
+		t.Fatalf("err = %v, want ErrDuplicateJob", err)
+	}
+}
@@ -0,0 +1,152 @@
+package repo
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AccountBalanceRepo interface {
+	// WithTx trả về repo chạy trên transaction của caller
+	WithTx(tx *gorm.DB) AccountBalanceRepo
+	GetByAccount(ctx context.Context, accountID uint64) (*model.AccountBalance, error)
+	GetOrCreate(ctx context.Context, account *model.CoaAccount, forUpdate bool) (*model.AccountBalance, error)
+	CompareAndSwap(ctx context.Context, balance *model.AccountBalance, version int64) (bool, error)
+	Touch(ctx context.Context, account *model.CoaAccount) (*model.AccountBalance, error)
+	Rebuild(ctx context.Context, accountID *uint64) (int64, error)
+}
+
+type accountBalanceRepo struct {
+	db *gorm.DB
+}
+
+func NewAccountBalanceRepo(db *gorm.DB) AccountBalanceRepo {
+	return &accountBalanceRepo{db: db}
+}
+
+func (r *accountBalanceRepo) WithTx(tx *gorm.DB) AccountBalanceRepo {
+	return &accountBalanceRepo{db: tx}
+}
+
+func (r *accountBalanceRepo) GetByAccount(ctx context.Context, accountID uint64) (*model.AccountBalance, error) {
+	balance := &model.AccountBalance{}
+	return balance, r.db.WithContext(ctx).First(balance, "account_id = ?", accountID).Error
+}
+
+// GetOrCreate đọc số dư của tài khoản, tạo bản ghi 0 nếu chưa có. forUpdate = true để khoá dòng (SELECT ... FOR UPDATE)
+func (r *accountBalanceRepo) GetOrCreate(ctx context.Context, account *model.CoaAccount, forUpdate bool) (*model.AccountBalance, error) {
+	db := r.db.WithContext(ctx)
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.AccountBalance{
+		AccountID: account.ID,
+		Currency:  account.Currency,
+		UpdatedAt: time.Now(),
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	if forUpdate {
+		db = db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	balance := &model.AccountBalance{}
+	return balance, db.First(balance, "account_id = ?", account.ID).Error
+}
+
+// CompareAndSwap ghi số dư mới nếu version trong DB vẫn bằng version đã đọc (optimistic locking).
+// Trả về false nếu đã có giao dịch khác cập nhật trước, caller cần đọc lại và thử lại.
+func (r *accountBalanceRepo) CompareAndSwap(ctx context.Context, balance *model.AccountBalance, version int64) (bool, error) {
+	now := time.Now()
+	res := r.db.WithContext(ctx).
+		Model(&model.AccountBalance{}).
+		Where("account_id = ? AND version = ?", balance.AccountID, version).
+		Updates(map[string]interface{}{
+			"debit_total":   balance.DebitTotal,
+			"credit_total":  balance.CreditTotal,
+			"balance":       balance.Balance,
+			"version":       version + 1,
+			"last_entry_id": balance.LastEntryID,
+			"updated_at":    now,
+		})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return false, nil
+	}
+	balance.Version = version + 1
+	balance.UpdatedAt = now
+	return true, nil
+}
+
+// Touch tăng version (khoá dòng tới hết transaction) để các posting đang đọc số dư cũ phải đọc lại,
+// dùng khi thay đổi ảnh hưởng số dư khả dụng nhưng không đổi số dư sổ cái (VD: đặt hold)
+func (r *accountBalanceRepo) Touch(ctx context.Context, account *model.CoaAccount) (*model.AccountBalance, error) {
+	balance, err := r.GetOrCreate(ctx, account, true)
+	if err != nil {
+		return nil, err
+	}
+	err = r.db.WithContext(ctx).
+		Model(&model.AccountBalance{}).
+		Where("account_id = ?", balance.AccountID).
+		Update("version", gorm.Expr("version + 1")).Error
+	if err != nil {
+		return nil, err
+	}
+	balance.Version++
+	return balance, nil
+}
+
+// Rebuild tính lại account_balances từ entries của các journal đã ghi sổ (accountID = nil: toàn bộ tài khoản).
+// Khoá bảng trong lúc tính để posting đồng thời không bị mất delta.
+func (r *accountBalanceRepo) Rebuild(ctx context.Context, accountID *uint64) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE account_balances IN EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+		// WHERE TRUE tránh nhập nhằng cú pháp INSERT ... SELECT ... ON CONFLICT
+		filter := "WHERE TRUE"
+		args := []interface{}{}
+		if accountID != nil {
+			filter = "WHERE a.id = ?"
+			args = append(args, *accountID)
+		}
+		res := tx.Exec(`
+			INSERT INTO account_balances (account_id, currency, debit_total, credit_total, balance, version, last_entry_id, updated_at)
+			SELECT a.id,
+			       a.currency,
+			       COALESCE(t.debit, 0),
+			       COALESCE(t.credit, 0),
+			       CASE WHEN a.type IN ('ASSET','EXP')
+			            THEN COALESCE(t.debit, 0) - COALESCE(t.credit, 0)
+			            ELSE COALESCE(t.credit, 0) - COALESCE(t.debit, 0)
+			       END,
+			       0,
+			       t.last_entry_id,
+			       NOW()
+			FROM coa_accounts a
+			LEFT JOIN (
+			    SELECT e.account_id,
+			           SUM(e.amount) FILTER (WHERE e.dc = 'D') AS debit,
+			           SUM(e.amount) FILTER (WHERE e.dc = 'C') AS credit,
+			           MAX(e.id) AS last_entry_id
+			    FROM entries e
+			    JOIN journals j ON j.id = e.journal_id AND j.status IN ('POSTED','REVERSED')
+			    GROUP BY e.account_id
+			) t ON t.account_id = a.id
+			`+filter+`
+			ON CONFLICT (account_id) DO UPDATE SET
+			    currency = EXCLUDED.currency,
+			    debit_total = EXCLUDED.debit_total,
+			    credit_total = EXCLUDED.credit_total,
+			    balance = EXCLUDED.balance,
+			    version = account_balances.version + 1,
+			    last_entry_id = EXCLUDED.last_entry_id,
+			    updated_at = EXCLUDED.updated_at`, args...)
+		affected = res.RowsAffected
+		return res.Error
+	})
+	return affected, err
+}
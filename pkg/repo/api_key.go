@@ -0,0 +1,64 @@
+package repo
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ApiKeyRepo interface {
+	// WithTx trả về repo chạy trên transaction của caller
+	WithTx(tx *gorm.DB) ApiKeyRepo
+	Create(apiKey *model.ApiKey) error
+	GetByID(ctx context.Context, id int64) (*model.ApiKey, error)
+	GetByHash(ctx context.Context, keyHash string) (*model.ApiKey, error)
+	List(ctx context.Context, ownerService string) ([]*model.ApiKey, error)
+	UpdateSelectField(entity *model.ApiKey, fields map[string]interface{}) error
+	// TouchLastUsed cập nhật last_used_at, không đổi updated_at
+	TouchLastUsed(ctx context.Context, id uint64, usedAt time.Time) error
+}
+
+type apiKeyRepo struct {
+	db *gorm.DB
+}
+
+func NewApiKeyRepo(db *gorm.DB) ApiKeyRepo {
+	return &apiKeyRepo{db: db}
+}
+
+func (r *apiKeyRepo) WithTx(tx *gorm.DB) ApiKeyRepo {
+	return &apiKeyRepo{db: tx}
+}
+
+func (r *apiKeyRepo) Create(apiKey *model.ApiKey) error {
+	return r.db.Create(apiKey).Error
+}
+
+func (r *apiKeyRepo) GetByID(ctx context.Context, id int64) (*model.ApiKey, error) {
+	apiKey := &model.ApiKey{}
+	return apiKey, r.db.WithContext(ctx).First(apiKey, "id = ?", id).Error
+}
+
+func (r *apiKeyRepo) GetByHash(ctx context.Context, keyHash string) (*model.ApiKey, error) {
+	apiKey := &model.ApiKey{}
+	return apiKey, r.db.WithContext(ctx).First(apiKey, "key_hash = ?", keyHash).Error
+}
+
+func (r *apiKeyRepo) List(ctx context.Context, ownerService string) ([]*model.ApiKey, error) {
+	var apiKeys []*model.ApiKey
+	query := r.db.WithContext(ctx).Order("id DESC")
+	if ownerService != "" {
+		query = query.Where("owner_service = ?", ownerService)
+	}
+	return apiKeys, query.Find(&apiKeys).Error
+}
+
+func (r *apiKeyRepo) UpdateSelectField(entity *model.ApiKey, fields map[string]interface{}) error {
+	return r.db.Model(entity).Updates(fields).Error
+}
+
+func (r *apiKeyRepo) TouchLastUsed(ctx context.Context, id uint64, usedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.ApiKey{}).Where("id = ?", id).UpdateColumn("last_used_at", usedAt).Error
+}
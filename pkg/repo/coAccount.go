@@ -23,7 +23,10 @@ type CoAccountRepo interface {
 	Save(customer *model.CoaAccount) error
 	Upsert(accounts []*model.CoaAccount, updateColumns []string) error
 	GetParentID(ctx context.Context, id string) (*uint64, error)
-	PaginateWithScopes(ctx context.Context, filter *dto.ListCoaAccountFilter) (*dto.PaginationResponse[*model.CoaAccount], error)
+	PaginateWithScopes(ctx context.Context, filter *dto.ListCoaAccountFilter, preloads ...string) (*dto.PaginationResponse[*model.CoaAccount], error)
+	// CursorPaginateWithScopes như PaginateWithScopes nhưng phân trang keyset theo filter.Cursor
+	CursorPaginateWithScopes(ctx context.Context, filter *dto.ListCoaAccountFilter, preloads ...string) (*dto.CursorPaginationResponse[*model.CoaAccount], error)
+	FindByProviderNetwork(ctx context.Context, provider, network, currency string) (*model.CoaAccount, error)
 }
 
 type coAccountRepo struct {
@@ -107,6 +110,21 @@ func (c *coAccountRepo) GetParentID(ctx context.Context, parentCode string) (*ui
 	return &parent.ID, nil
 }
 
+// FindByProviderNetwork tìm tài khoản tài sản (ASSET) đại diện cho số dư tại nhà cung cấp.
+// Ưu tiên tài khoản khớp cả network, nếu không có thì lấy tài khoản không khai báo network.
+func (c *coAccountRepo) FindByProviderNetwork(ctx context.Context, provider, network, currency string) (*model.CoaAccount, error) {
+	account := &model.CoaAccount{}
+	err := c.db.WithContext(ctx).
+		Where("type = ? AND status = ? AND provider = ? AND currency = ?", "ASSET", "ACTIVE", provider, currency).
+		Where("network = ? OR network IS NULL", network).
+		Order("network NULLS LAST, id ASC").
+		First(account).Error
+	if err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
 func (s *coAccountRepo) GetOneByFields(ctx context.Context, fields map[string]interface{}, preloads ...string) (*model.CoaAccount, error) {
 	var coaAccount *model.CoaAccount
 	query := s.db.WithContext(ctx).Model(&model.CoaAccount{})
@@ -192,7 +210,7 @@ func (s *coAccountRepo) Paginate(fields *dto.ListCoaAccountFilter) (*dto.Paginat
 		PrevPage:  prevPage,
 	}, nil
 }
-func (r *coAccountRepo) PaginateWithScopes(ctx context.Context, fields *dto.ListCoaAccountFilter) (*dto.PaginationResponse[*model.CoaAccount], error) {
+func (r *coAccountRepo) PaginateWithScopes(ctx context.Context, fields *dto.ListCoaAccountFilter, preloads ...string) (*dto.PaginationResponse[*model.CoaAccount], error) {
 
 	params := BuildParamsFromFilter(fields)
 
@@ -210,7 +228,11 @@ func (r *coAccountRepo) PaginateWithScopes(ctx context.Context, fields *dto.List
 		page = *fields.Page
 	}
 
-	pagination, err := CustomPaginate(r.db.Model(&model.CoaAccount{}), params, page, limit, &items)
+	query := r.db.WithContext(ctx).Model(&model.CoaAccount{})
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+	pagination, err := CustomPaginate(query, params, page, limit, &items)
 	if err != nil {
 		return nil, err
 	}
@@ -339,3 +361,13 @@ func (r *coAccountRepo) PaginateWithScopes(ctx context.Context, fields *dto.List
 	// 	PrevPage:  prevPage,
 	// }, nil
 }
+
+func (r *coAccountRepo) CursorPaginateWithScopes(ctx context.Context, fields *dto.ListCoaAccountFilter, preloads ...string) (*dto.CursorPaginationResponse[*model.CoaAccount], error) {
+	var items []*model.CoaAccount
+	query := r.db.WithContext(ctx).Model(&model.CoaAccount{})
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+	cursor, limit, withTotal := cursorParams(fields.BasePaginationQuery)
+	return CursorPaginate(query, BuildParamsFromFilter(fields), cursor, limit, withTotal, &items)
+}
@@ -0,0 +1,255 @@
+package repo
+
+import (
+	"context"
+	"core-ledger/model/dto"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidCursor cursor không giải mã được hoặc không khớp sort hiện tại
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// defaultCursorSort sort khi filter không có sort, luôn có id làm khoá phụ
+const defaultCursorSort = "id:1"
+
+// cursorKey một cột trong ORDER BY của keyset
+type cursorKey struct {
+	field *schema.Field
+	desc  bool
+}
+
+// cursorToken nội dung cursor trước khi base64: sort đã chuẩn hoá, giá trị các cột sort của bản ghi mốc
+// và chiều đọc (Prev=true là trang trước bản ghi mốc)
+type cursorToken struct {
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+	Prev   bool              `json:"p,omitempty"`
+}
+
+// CursorPaginate phân trang keyset: WHERE (sort keys, id) > cursor thay cho OFFSET, không COUNT trừ khi withTotal.
+// Sort lấy từ filters["sort"] ("col:1,col:-1" như ScopeSort, mặc định id tăng dần, luôn thêm id làm khoá phụ),
+// chỉ nhận cột NOT NULL của model. cursor rỗng là trang đầu; next_cursor/prev_cursor trả về là chuỗi opaque.
+// Filter khác áp dụng qua ApplyFilterScopeDynamic như CustomPaginate.
+func CursorPaginate[T any](db *gorm.DB, filters map[string]any, cursor string, limit int64, withTotal bool, out *[]*T) (*dto.CursorPaginationResponse[*T], error) {
+	if db == nil {
+		return nil, errors.New("db is nil")
+	}
+	if limit <= 0 {
+		limit = 25
+	}
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	sortStr, _ := filters["sort"].(string)
+	keys := parseCursorSort(sortStr, stmt.Schema)
+
+	res := &dto.CursorPaginationResponse[*T]{Limit: limit}
+	if withTotal {
+		countDB := ApplyFilterScopeDynamic[T](db.Session(&gorm.Session{}), filters, false)
+		var total int64
+		if err := countDB.Count(&total).Error; err != nil {
+			return nil, fmt.Errorf("failed to count: %w", err)
+		}
+		res.Total = &total
+	}
+
+	dataDB := ApplyFilterScopeDynamic[T](db.Session(&gorm.Session{}), filters, false)
+	var token *cursorToken
+	if cursor != "" {
+		var err error
+		token, err = decodeCursor(cursor, keys)
+		if err != nil {
+			return nil, err
+		}
+		values, err := cursorValues(token, keys)
+		if err != nil {
+			return nil, err
+		}
+		dataDB = dataDB.Where(keysetCondition(keys, values, token.Prev))
+	}
+	prev := token != nil && token.Prev
+	// đọc lùi thì đảo ORDER BY rồi đảo lại kết quả
+	for _, k := range keys {
+		dataDB = dataDB.Order(clause.OrderByColumn{
+			Column: clause.Column{Table: clause.CurrentTable, Name: k.field.DBName},
+			Desc:   k.desc != prev,
+		})
+	}
+	if err := dataDB.Limit(int(limit) + 1).Find(out).Error; err != nil {
+		return nil, fmt.Errorf("failed to find: %w", err)
+	}
+
+	items := *out
+	hasMore := int64(len(items)) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+	if prev {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	*out = items
+	res.Items = items
+	if len(items) == 0 {
+		return res, nil
+	}
+
+	ctx := db.Statement.Context
+	first, last := items[0], items[len(items)-1]
+	// trang sau: còn bản ghi khi đọc tới, hoặc đang đọc lùi từ một trang phía sau
+	if hasMore || prev {
+		next, err := encodeCursor(ctx, keys, reflect.ValueOf(last), false)
+		if err != nil {
+			return nil, err
+		}
+		res.NextCursor = &next
+	}
+	// trang trước: đã đi qua ít nhất một cursor, hoặc đọc lùi vẫn còn bản ghi
+	if (token != nil && !prev) || (prev && hasMore) {
+		p, err := encodeCursor(ctx, keys, reflect.ValueOf(first), true)
+		if err != nil {
+			return nil, err
+		}
+		res.PrevCursor = &p
+	}
+	return res, nil
+}
+
+// parseCursorSort đọc sort dạng "col:1,col:-1", bỏ cột không có trong model hoặc nullable, thêm id nếu thiếu
+func parseCursorSort(sortStr string, sch *schema.Schema) []cursorKey {
+	if strings.TrimSpace(sortStr) == "" {
+		sortStr = defaultCursorSort
+	}
+	var keys []cursorKey
+	seen := map[string]bool{}
+	for _, pair := range strings.Split(sortStr, ",") {
+		parts := strings.Split(pair, ":")
+		if len(parts) != 2 {
+			continue
+		}
+		field, ok := sch.FieldsByDBName[strings.TrimSpace(parts[0])]
+		// cột NULL không so sánh được trong keyset
+		if !ok || seen[field.DBName] || field.FieldType.Kind() == reflect.Pointer {
+			continue
+		}
+		seen[field.DBName] = true
+		keys = append(keys, cursorKey{field: field, desc: strings.TrimSpace(parts[1]) == "-1"})
+	}
+	if pk := sch.PrioritizedPrimaryField; pk != nil && !seen[pk.DBName] {
+		keys = append(keys, cursorKey{field: pk})
+	}
+	return keys
+}
+
+// sortSignature sort đã chuẩn hoá, gắn vào cursor để không dùng cursor của sort khác
+func sortSignature(keys []cursorKey) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		dir := "1"
+		if k.desc {
+			dir = "-1"
+		}
+		parts[i] = k.field.DBName + ":" + dir
+	}
+	return strings.Join(parts, ",")
+}
+
+func encodeCursor(ctx context.Context, keys []cursorKey, item reflect.Value, prev bool) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	item = reflect.Indirect(item)
+	token := cursorToken{Sort: sortSignature(keys), Prev: prev}
+	for _, k := range keys {
+		value, _ := k.field.ValueOf(ctx, item)
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		token.Values = append(token.Values, raw)
+	}
+	b, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCursor(cursor string, keys []cursorKey) (*cursorToken, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	token := &cursorToken{}
+	if err := json.Unmarshal(b, token); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if token.Sort != sortSignature(keys) || len(token.Values) != len(keys) {
+		return nil, fmt.Errorf("%w: sort changed", ErrInvalidCursor)
+	}
+	return token, nil
+}
+
+// cursorValues giải mã giá trị mốc theo đúng kiểu field (time.Time, decimal...) trước khi đưa vào query
+func cursorValues(token *cursorToken, keys []cursorKey) ([]any, error) {
+	values := make([]any, len(keys))
+	for i, k := range keys {
+		v := reflect.New(k.field.FieldType)
+		if err := json.Unmarshal(token.Values[i], v.Interface()); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, k.field.DBName)
+		}
+		values[i] = v.Elem().Interface()
+	}
+	return values, nil
+}
+
+// keysetCondition điều kiện lấy bản ghi sau (hoặc trước khi prev) mốc: cùng chiều sort thì dùng so sánh tuple
+// (dùng được index), khác chiều thì mở rộng (k1 > v1) OR (k1 = v1 AND k2 > v2) ...
+func keysetCondition(keys []cursorKey, values []any, prev bool) clause.Expression {
+	op := func(k cursorKey) string {
+		if k.desc != prev {
+			return "<"
+		}
+		return ">"
+	}
+	column := func(k cursorKey) clause.Column {
+		return clause.Column{Table: clause.CurrentTable, Name: k.field.DBName}
+	}
+
+	sameDir := true
+	for _, k := range keys[1:] {
+		sameDir = sameDir && k.desc == keys[0].desc
+	}
+	if sameDir {
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(keys)), ", ")
+		vars := make([]any, 0, 2*len(keys))
+		for _, k := range keys {
+			vars = append(vars, column(k))
+		}
+		vars = append(vars, values...)
+		return clause.Expr{SQL: fmt.Sprintf("(%s) %s (%s)", placeholders, op(keys[0]), placeholders), Vars: vars}
+	}
+
+	ors := make([]clause.Expression, len(keys))
+	for i, k := range keys {
+		ands := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, clause.Eq{Column: column(keys[j]), Value: values[j]})
+		}
+		ands = append(ands, clause.Expr{SQL: "? " + op(k) + " ?", Vars: []any{column(k), values[i]}})
+		ors[i] = clause.And(ands...)
+	}
+	return clause.Or(ors...)
+}
@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type cursorRow struct {
+	ID        uint64    `gorm:"primaryKey"`
+	Code      string    `gorm:"not null"`
+	Ts        time.Time `gorm:"not null"`
+	DeletedAt *time.Time
+}
+
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true, // DryRun không mở transaction thật cho Create/Delete
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func cursorKeys(t *testing.T, db *gorm.DB, sort string) []cursorKey {
+	t.Helper()
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(&cursorRow{}); err != nil {
+		t.Fatal(err)
+	}
+	return parseCursorSort(sort, stmt.Schema)
+}
+
+func TestParseCursorSort(t *testing.T) {
+	db := dryRunDB(t)
+	cases := map[string]string{
+		"":                            "id:1",
+		"ts:-1":                       "ts:-1,id:1",
+		"code:1,id:-1":                "code:1,id:-1",
+		"unknown:1,deleted_at:1,ts:1": "ts:1,id:1", // cột lạ và cột nullable bị bỏ
+	}
+	for in, want := range cases {
+		if got := sortSignature(cursorKeys(t, db, in)); got != want {
+			t.Errorf("sort %q = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	db := dryRunDB(t)
+	keys := cursorKeys(t, db, "ts:-1")
+	ts := time.Date(2025, 12, 1, 10, 30, 0, 123, time.UTC)
+	cursor, err := encodeCursor(nil, keys, reflect.ValueOf(&cursorRow{ID: 42, Code: "A", Ts: ts}), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := decodeCursor(cursor, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := cursorValues(token, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !token.Prev || !values[0].(time.Time).Equal(ts) || values[1].(uint64) != 42 {
+		t.Fatalf("token = %+v values = %v", token, values)
+	}
+
+	// cursor của sort khác hoặc rác đều bị từ chối
+	if _, err := decodeCursor(cursor, cursorKeys(t, db, "ts:1")); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("other sort: err = %v", err)
+	}
+	if _, err := decodeCursor("not-a-cursor", keys); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("garbage: err = %v", err)
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	db := dryRunDB(t)
+	sql := func(sort string, values []any, prev bool) string {
+		var rows []cursorRow
+		stmt := db.Model(&cursorRow{}).Where(keysetCondition(cursorKeys(t, db, sort), values, prev)).Find(&rows).Statement
+		return stmt.SQL.String()
+	}
+
+	// cùng chiều: so sánh tuple
+	if got := sql("ts:1", []any{time.Now(), uint64(1)}, false); !strings.Contains(got, `("cursor_rows"."ts", "cursor_rows"."id") > ($1, $2)`) {
+		t.Errorf("same direction: %s", got)
+	}
+	if got := sql("ts:1", []any{time.Now(), uint64(1)}, true); !strings.Contains(got, `("cursor_rows"."ts", "cursor_rows"."id") < ($1, $2)`) {
+		t.Errorf("same direction prev: %s", got)
+	}
+	// khác chiều: mở rộng OR
+	want := `("cursor_rows"."ts" < $1 OR ("cursor_rows"."ts" = $2 AND "cursor_rows"."id" > $3))`
+	if got := sql("ts:-1", []any{time.Now(), uint64(1)}, false); !strings.Contains(got, want) {
+		t.Errorf("mixed direction: %s", got)
+	}
+}
@@ -4,12 +4,17 @@ import (
 	"context"
 	model "core-ledger/model/core-ledger"
 	"core-ledger/model/dto"
+	"time"
+
+	"github.com/shopspring/decimal"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type EnTriesRepo interface {
+	// WithTx trả về repo chạy trên transaction của caller
+	WithTx(tx *gorm.DB) EnTriesRepo
 	creator[*model.Entry]
 	// reader[*model.Entry, *dto.ListCustomerFilter]
 	getByID[*model.Entry]
@@ -17,7 +22,21 @@ type EnTriesRepo interface {
 	Save(customer *model.Entry) error
 	Upsert(accounts []*model.Entry, updateColumns []string) error
 	GetByAccount(ctx context.Context, id int64) ([]model.Entry, error)
-	PaginateWithScopes(ctx context.Context, filter *dto.ListEntrytFilter) (*dto.PaginationResponse[*model.Entry], error)
+	PaginateWithScopes(ctx context.Context, filter *dto.ListEntrytFilter, preloads ...string) (*dto.PaginationResponse[*model.Entry], error)
+	// CursorPaginateWithScopes như PaginateWithScopes nhưng phân trang keyset theo filter.Cursor
+	CursorPaginateWithScopes(ctx context.Context, filter *dto.ListEntrytFilter, preloads ...string) (*dto.CursorPaginationResponse[*model.Entry], error)
+	SumByAccountAsOf(ctx context.Context, accountID uint64, asOf time.Time) (debit, credit decimal.Decimal, err error)
+	MatchedProviderTxnCodes(ctx context.Context, accountID uint64, codes []string) (map[string]bool, error)
+	// SumByAccountBetween tổng phát sinh theo từng tài khoản của journal có ts trong [from, to), from = nil tính từ đầu
+	SumByAccountBetween(ctx context.Context, from *time.Time, to time.Time) (map[uint64]AccountTotals, error)
+}
+
+// AccountTotals tổng phát sinh Nợ/Có và số bút toán của một tài khoản
+type AccountTotals struct {
+	AccountID  uint64
+	Debit      decimal.Decimal
+	Credit     decimal.Decimal
+	EntryCount int
 }
 
 type enTriesRepo struct {
@@ -29,6 +48,10 @@ func NewEnTriesRepo(db *gorm.DB) EnTriesRepo {
 		db: db,
 	}
 }
+func (c *enTriesRepo) WithTx(tx *gorm.DB) EnTriesRepo {
+	return &enTriesRepo{db: tx}
+}
+
 func (c *enTriesRepo) Save(customer *model.Entry) error {
 	return c.db.Create(&customer).Error
 }
@@ -71,7 +94,7 @@ func (c *enTriesRepo) GetByAccount(context context.Context, id int64) ([]model.E
 	return entries, c.db.WithContext(context).Where("account_id = ?", id).Find(&entries).Error
 }
 
-func (r *enTriesRepo) PaginateWithScopes(ctx context.Context, fields *dto.ListEntrytFilter) (*dto.PaginationResponse[*model.Entry], error) {
+func (r *enTriesRepo) PaginateWithScopes(ctx context.Context, fields *dto.ListEntrytFilter, preloads ...string) (*dto.PaginationResponse[*model.Entry], error) {
 	params := BuildParamsFromFilter(fields)
 
 	var items []*model.Entry
@@ -88,10 +111,98 @@ func (r *enTriesRepo) PaginateWithScopes(ctx context.Context, fields *dto.ListEn
 		page = *fields.Page
 	}
 
-	pagination, err := CustomPaginate(r.db.Model(&model.Entry{}), params, page, limit, &items)
+	query := r.db.WithContext(ctx).Model(&model.Entry{})
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+	pagination, err := CustomPaginate(query, params, page, limit, &items)
 	if err != nil {
 		return nil, err
 	}
 
 	return pagination, nil
 }
+
+func (r *enTriesRepo) CursorPaginateWithScopes(ctx context.Context, fields *dto.ListEntrytFilter, preloads ...string) (*dto.CursorPaginationResponse[*model.Entry], error) {
+	var items []*model.Entry
+	query := r.db.WithContext(ctx).Model(&model.Entry{})
+	for _, preload := range preloads {
+		query = query.Preload(preload)
+	}
+	cursor, limit, withTotal := cursorParams(fields.BasePaginationQuery)
+	return CursorPaginate(query, BuildParamsFromFilter(fields), cursor, limit, withTotal, &items)
+}
+
+// SumByAccountAsOf tổng phát sinh Nợ/Có của tài khoản tính đến thời điểm asOf (bỏ qua journal DRAFT)
+func (r *enTriesRepo) SumByAccountAsOf(ctx context.Context, accountID uint64, asOf time.Time) (decimal.Decimal, decimal.Decimal, error) {
+	return r.sumByAccount(r.postedEntries(ctx, accountID).Where("journals.ts <= ?", asOf))
+}
+
+func (r *enTriesRepo) postedEntries(ctx context.Context, accountID uint64) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&model.Entry{}).
+		Joins("JOIN journals ON journals.id = entries.journal_id").
+		Where("entries.account_id = ?", accountID).
+		Where("journals.status IN ?", []string{"POSTED", "REVERSED"})
+}
+
+func (r *enTriesRepo) sumByAccount(q *gorm.DB) (decimal.Decimal, decimal.Decimal, error) {
+	var row struct {
+		Debit  decimal.Decimal
+		Credit decimal.Decimal
+	}
+	err := q.Select("COALESCE(SUM(CASE WHEN entries.dc = 'D' THEN entries.amount ELSE 0 END), 0) AS debit, " +
+		"COALESCE(SUM(CASE WHEN entries.dc = 'C' THEN entries.amount ELSE 0 END), 0) AS credit").
+		Scan(&row).Error
+	return row.Debit, row.Credit, err
+}
+
+// MatchedProviderTxnCodes trả về các mã giao dịch nhà cung cấp đã được ghi sổ vào tài khoản
+// (journal.meta->>'provider_txn_code')
+func (r *enTriesRepo) MatchedProviderTxnCodes(ctx context.Context, accountID uint64, codes []string) (map[string]bool, error) {
+	matched := make(map[string]bool, len(codes))
+	if len(codes) == 0 {
+		return matched, nil
+	}
+	var found []string
+	err := r.db.WithContext(ctx).
+		Model(&model.Entry{}).
+		Joins("JOIN journals ON journals.id = entries.journal_id").
+		Where("entries.account_id = ?", accountID).
+		Where("journals.status IN ?", []string{"POSTED", "REVERSED"}).
+		Where("journals.meta->>'provider_txn_code' IN ?", codes).
+		Pluck("DISTINCT journals.meta->>'provider_txn_code'", &found).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, code := range found {
+		matched[code] = true
+	}
+	return matched, nil
+}
+
+func (r *enTriesRepo) SumByAccountBetween(ctx context.Context, from *time.Time, to time.Time) (map[uint64]AccountTotals, error) {
+	q := r.db.WithContext(ctx).
+		Model(&model.Entry{}).
+		Joins("JOIN journals ON journals.id = entries.journal_id").
+		Where("journals.status IN ?", []string{"POSTED", "REVERSED"}).
+		Where("journals.ts < ?", to)
+	if from != nil {
+		q = q.Where("journals.ts >= ?", *from)
+	}
+	var rows []AccountTotals
+	err := q.Select("entries.account_id AS account_id, " +
+		"COALESCE(SUM(CASE WHEN entries.dc = 'D' THEN entries.amount ELSE 0 END), 0) AS debit, " +
+		"COALESCE(SUM(CASE WHEN entries.dc = 'C' THEN entries.amount ELSE 0 END), 0) AS credit, " +
+		"COUNT(*) AS entry_count").
+		Group("entries.account_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	totals := make(map[uint64]AccountTotals, len(rows))
+	for _, row := range rows {
+		totals[row.AccountID] = row
+	}
+	return totals, nil
+}
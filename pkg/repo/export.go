@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ExportRepo đọc/ghi lịch sử export (bảng exports)
+type ExportRepo interface {
+	WithTx(tx *gorm.DB) ExportRepo
+	Create(ctx context.Context, export *model.Export) error
+	// GetByID kèm File khi export đã có file kết quả
+	GetByID(ctx context.Context, id uint64) (*model.Export, error)
+	// Paginate export mới nhất trước
+	Paginate(ctx context.Context, filter *dto.ListExportFilter) (*dto.PaginationResponse[*model.Export], error)
+	SetJobID(ctx context.Context, id uint64, jobID string) error
+	MarkRunning(ctx context.Context, id uint64, now time.Time) error
+	MarkCompleted(ctx context.Context, id uint64, fileID uint64, rows, size int64, expiresAt, now time.Time) error
+	MarkFailed(ctx context.Context, id uint64, errMsg string, now time.Time) error
+}
+
+type exportRepo struct {
+	db *gorm.DB
+}
+
+func NewExportRepo(db *gorm.DB) ExportRepo {
+	return &exportRepo{db: db}
+}
+
+// WithTx trả về repo chạy trên transaction của caller
+func (r *exportRepo) WithTx(tx *gorm.DB) ExportRepo {
+	return &exportRepo{db: tx}
+}
+
+func (r *exportRepo) Create(ctx context.Context, export *model.Export) error {
+	return r.db.WithContext(ctx).Create(export).Error
+}
+
+func (r *exportRepo) GetByID(ctx context.Context, id uint64) (*model.Export, error) {
+	export := &model.Export{}
+	return export, r.db.WithContext(ctx).Preload("File").First(export, "id = ?", id).Error
+}
+
+func (r *exportRepo) Paginate(ctx context.Context, fields *dto.ListExportFilter) (*dto.PaginationResponse[*model.Export], error) {
+	query := r.db.WithContext(ctx).Model(&model.Export{}).Where("requested_by = ?", fields.RequestedBy)
+	if fields.Resource != nil {
+		query = query.Where("resource = ?", *fields.Resource)
+	}
+	if fields.Status != nil {
+		query = query.Where("status = ?", *fields.Status)
+	}
+
+	var items []*model.Export
+	page, limit := pageParams(fields.BasePaginationQuery)
+	return CustomPaginate(query.Preload("File").Order("id DESC"), nil, page, limit, &items)
+}
+
+func (r *exportRepo) SetJobID(ctx context.Context, id uint64, jobID string) error {
+	return r.db.WithContext(ctx).Model(&model.Export{}).Where("id = ?", id).Update("job_id", jobID).Error
+}
+
+func (r *exportRepo) MarkRunning(ctx context.Context, id uint64, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.Export{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     model.ExportStatusRunning,
+		"error":      nil,
+		"started_at": now,
+	}).Error
+}
+
+func (r *exportRepo) MarkCompleted(ctx context.Context, id uint64, fileID uint64, rows, size int64, expiresAt, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.Export{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       model.ExportStatusCompleted,
+		"file_id":      fileID,
+		"rows":         rows,
+		"size":         size,
+		"error":        nil,
+		"expires_at":   expiresAt,
+		"completed_at": now,
+	}).Error
+}
+
+func (r *exportRepo) MarkFailed(ctx context.Context, id uint64, errMsg string, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.Export{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       model.ExportStatusFailed,
+		"error":        errMsg,
+		"completed_at": now,
+	}).Error
+}
@@ -0,0 +1,146 @@
+package repo
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPurgeFilterRequired Purge không có điều kiện nào, từ chối để không xoá cả bảng failed_jobs
+var ErrPurgeFilterRequired = errors.New("purge requires at least one of type, status, before")
+
+// FailedJobRepo đọc/ghi job lỗi lần cuối của worker (bảng failed_jobs)
+type FailedJobRepo interface {
+	WithTx(tx *gorm.DB) FailedJobRepo
+	// Record thêm job lỗi, task_id đã có (lỗi lại sau khi chạy lại) thì cộng dồn attempts và chuyển về FAILED
+	Record(ctx context.Context, job *model.FailedJob) error
+	GetByID(ctx context.Context, id uint64) (*model.FailedJob, error)
+	Paginate(ctx context.Context, filter *dto.ListFailedJobFilter) (*dto.PaginationResponse[*model.FailedJob], error)
+	// ListFailedByType job FAILED của type có id > afterID, tăng dần theo id
+	ListFailedByType(ctx context.Context, jobType string, afterID uint64, limit int) ([]*model.FailedJob, error)
+	MarkRetried(ctx context.Context, id uint64, now time.Time) error
+	// Purge xoá job lỗi theo filter, filter rỗng trả ErrPurgeFilterRequired
+	Purge(ctx context.Context, filter *dto.PurgeFailedJobFilter) (int64, error)
+	// CountByStatus số job lỗi theo status (FAILED, RETRIED)
+	CountByStatus(ctx context.Context) (map[string]int64, error)
+}
+
+type failedJobRepo struct {
+	db *gorm.DB
+}
+
+func NewFailedJobRepo(db *gorm.DB) FailedJobRepo {
+	return &failedJobRepo{db: db}
+}
+
+// WithTx trả về repo chạy trên transaction của caller
+func (r *failedJobRepo) WithTx(tx *gorm.DB) FailedJobRepo {
+	return &failedJobRepo{db: tx}
+}
+
+func (r *failedJobRepo) Record(ctx context.Context, job *model.FailedJob) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "task_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"type":           gorm.Expr("EXCLUDED.type"),
+			"queue":          gorm.Expr("EXCLUDED.queue"),
+			"payload":        gorm.Expr("EXCLUDED.payload"),
+			"error":          gorm.Expr("EXCLUDED.error"),
+			"attempts":       gorm.Expr("failed_jobs.attempts + EXCLUDED.attempts"),
+			"max_retry":      gorm.Expr("EXCLUDED.max_retry"),
+			"handler":        gorm.Expr("EXCLUDED.handler"),
+			"status":         model.FailedJobStatusFailed,
+			"last_failed_at": gorm.Expr("EXCLUDED.last_failed_at"),
+			"updated_at":     gorm.Expr("EXCLUDED.updated_at"),
+		}),
+	}).Create(job).Error
+}
+
+func (r *failedJobRepo) GetByID(ctx context.Context, id uint64) (*model.FailedJob, error) {
+	job := &model.FailedJob{}
+	return job, r.db.WithContext(ctx).First(job, "id = ?", id).Error
+}
+
+// Paginate job lỗi gần nhất trước
+func (r *failedJobRepo) Paginate(ctx context.Context, fields *dto.ListFailedJobFilter) (*dto.PaginationResponse[*model.FailedJob], error) {
+	query := r.db.WithContext(ctx).Model(&model.FailedJob{})
+	if fields.Type != nil {
+		query = query.Where("type = ?", *fields.Type)
+	}
+	if fields.Queue != nil {
+		query = query.Where("queue = ?", *fields.Queue)
+	}
+	if fields.Status != nil {
+		query = query.Where("status = ?", *fields.Status)
+	}
+
+	var items []*model.FailedJob
+	limit := int64(25)
+	page := int64(1)
+	if fields.Limit != nil {
+		limit = *fields.Limit
+	}
+	if fields.Page != nil {
+		page = *fields.Page
+	}
+	return CustomPaginate(query.Order("last_failed_at DESC"), nil, page, limit, &items)
+}
+
+func (r *failedJobRepo) ListFailedByType(ctx context.Context, jobType string, afterID uint64, limit int) ([]*model.FailedJob, error) {
+	var jobs []*model.FailedJob
+	err := r.db.WithContext(ctx).
+		Where("type = ? AND status = ? AND id > ?", jobType, model.FailedJobStatusFailed, afterID).
+		Order("id").Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *failedJobRepo) MarkRetried(ctx context.Context, id uint64, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.FailedJob{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":      model.FailedJobStatusRetried,
+		"retry_count": gorm.Expr("retry_count + 1"),
+		"retried_at":  now,
+	}).Error
+}
+
+func (r *failedJobRepo) Purge(ctx context.Context, fields *dto.PurgeFailedJobFilter) (int64, error) {
+	if fields == nil || (fields.Type == nil && fields.Status == nil && fields.Before == nil) {
+		return 0, ErrPurgeFilterRequired
+	}
+	query := r.db.WithContext(ctx)
+	if fields.Type != nil {
+		query = query.Where("type = ?", *fields.Type)
+	}
+	if fields.Status != nil {
+		query = query.Where("status = ?", *fields.Status)
+	}
+	if fields.Before != nil {
+		query = query.Where("last_failed_at < ?", *fields.Before)
+	}
+	res := query.Delete(&model.FailedJob{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *failedJobRepo) CountByStatus(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Total  int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&model.FailedJob{}).
+		Select("status, COUNT(*) AS total").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Total
+	}
+	return counts, nil
+}
@@ -0,0 +1,38 @@
+package repo
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestFailedJobPurgeRequiresFilter(t *testing.T) {
+	db := dryRunDB(t)
+	r := NewFailedJobRepo(db)
+
+	for _, filter := range []*dto.PurgeFailedJobFilter{nil, {}} {
+		if _, err := r.Purge(context.Background(), filter); !errors.Is(err, ErrPurgeFilterRequired) {
+			t.Fatalf("filter %+v: err = %v", filter, err)
+		}
+	}
+
+	// DryRun không chạy SQL, lấy statement qua callback: DELETE phải có WHERE theo status
+	var sql string
+	if err := db.Callback().Delete().After("gorm:delete").Register("test:capture", func(tx *gorm.DB) {
+		sql = tx.Statement.SQL.String()
+	}); err != nil {
+		t.Fatal(err)
+	}
+	status := model.FailedJobStatusRetried
+	if _, err := r.Purge(context.Background(), &dto.PurgeFailedJobFilter{Status: &status}); err != nil {
+		t.Fatalf("status filter: %v", err)
+	}
+	if !strings.Contains(sql, "WHERE status = $1") {
+		t.Fatalf("sql = %s", sql)
+	}
+}
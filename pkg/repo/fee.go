@@ -0,0 +1,71 @@
+package repo
+
+import (
+	"context"
+
+	model "core-ledger/model/wealify"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// FeeQuery điều kiện tìm biểu phí áp dụng cho một giao dịch
+type FeeQuery struct {
+	TransactionType string
+	Currency        string
+	Provider        string
+	Tier            string
+}
+
+type FeeRepo interface {
+	getByUuid[*model.Fee]
+	FindForCustomer(ctx context.Context, customerID int64, q FeeQuery) (*model.Fee, error)
+	FindByTier(ctx context.Context, q FeeQuery) (*model.Fee, error)
+	ListRanges(ctx context.Context, feeID string) ([]*model.FeeRange, error)
+}
+
+type feeRepo struct {
+	db *gorm.DB
+}
+
+func NewFeeRepo(db *gorm.DB) FeeRepo {
+	return &feeRepo{db: db}
+}
+
+func (r *feeRepo) GetByUuid(ctx context.Context, id string) (*model.Fee, error) {
+	fee := &model.Fee{}
+	return fee, r.db.WithContext(ctx).Where("status = 1 AND is_deleted = 0").First(&fee, "id = ?", id).Error
+}
+
+// FindForCustomer tìm biểu phí riêng được gán cho khách hàng (bảng fee-customer)
+func (r *feeRepo) FindForCustomer(ctx context.Context, customerID int64, q FeeQuery) (*model.Fee, error) {
+	fee := &model.Fee{}
+	query := r.active(ctx, q).
+		Joins("JOIN ? fc ON fc.fee_id = fees.id", clause.Table{Name: model.TableNameFeeCustomer}).
+		Where("fc.customer_id = ?", customerID)
+	return fee, query.First(&fee).Error
+}
+
+// FindByTier tìm biểu phí chung theo hạng khách hàng
+func (r *feeRepo) FindByTier(ctx context.Context, q FeeQuery) (*model.Fee, error) {
+	fee := &model.Fee{}
+	return fee, r.active(ctx, q).Where("fees.tier = ?", q.Tier).First(&fee).Error
+}
+
+func (r *feeRepo) ListRanges(ctx context.Context, feeID string) ([]*model.FeeRange, error) {
+	var ranges []*model.FeeRange
+	return ranges, r.db.WithContext(ctx).
+		Where("fee_id = ? AND status = 1 AND is_deleted = 0", feeID).
+		Order("min ASC").
+		Find(&ranges).Error
+}
+
+func (r *feeRepo) active(ctx context.Context, q FeeQuery) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&model.Fee{}).
+		Where("fees.status = 1 AND fees.is_deleted = 0").
+		Where("fees.transaction_type = ? AND fees.currency_symbol = ?", q.TransactionType, q.Currency)
+	if q.Provider != "" {
+		query = query.Where("fees.provider = ?", q.Provider)
+	}
+	return query.Order("fees.updated_at DESC")
+}
@@ -0,0 +1,37 @@
+package repo
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+
+	"gorm.io/gorm"
+)
+
+// FileRepo metadata file trong storage (bảng files)
+type FileRepo interface {
+	WithTx(tx *gorm.DB) FileRepo
+	Create(ctx context.Context, file *model.File) error
+	GetByID(ctx context.Context, id uint64) (*model.File, error)
+}
+
+type fileRepo struct {
+	db *gorm.DB
+}
+
+func NewFileRepo(db *gorm.DB) FileRepo {
+	return &fileRepo{db: db}
+}
+
+// WithTx trả về repo chạy trên transaction của caller
+func (r *fileRepo) WithTx(tx *gorm.DB) FileRepo {
+	return &fileRepo{db: tx}
+}
+
+func (r *fileRepo) Create(ctx context.Context, file *model.File) error {
+	return r.db.WithContext(ctx).Create(file).Error
+}
+
+func (r *fileRepo) GetByID(ctx context.Context, id uint64) (*model.File, error) {
+	file := &model.File{}
+	return file, r.db.WithContext(ctx).First(file, "id = ?", id).Error
+}
@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"bytes"
+	model "core-ledger/model/core-ledger"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// QueryFilters query param không bind vào struct filter (theo form tag) → filter DSL cho BasePaginationQuery.Filters.
+// "x[]" được đọc như "x", nhiều giá trị cùng key thành list
+func QueryFilters(values url.Values, filter any) map[string]any {
+	known := structTagKeys(filter, "form")
+	extra := map[string]any{}
+	for key, vals := range values {
+		if known[key] || len(vals) == 0 {
+			continue
+		}
+		key = strings.TrimSuffix(key, "[]")
+		if len(vals) == 1 {
+			extra[key] = vals[0]
+			continue
+		}
+		list := make([]any, len(vals))
+		for i, v := range vals {
+			list[i] = v
+		}
+		extra[key] = list
+	}
+	return extra
+}
+
+// JSONFilters tách JSON filter (VD query của export): key có trong struct (theo json tag) trả lại để decode,
+// key còn lại là filter DSL. Giá trị DSL phải là chuỗi, số, bool hoặc mảng
+func JSONFilters(raw json.RawMessage, filter any) (json.RawMessage, map[string]any, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, nil, err
+	}
+	known := structTagKeys(filter, "json")
+	extra := map[string]any{}
+	for key, value := range fields {
+		if known[key] {
+			continue
+		}
+		delete(fields, key)
+		dec := json.NewDecoder(bytes.NewReader(value))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, nil, err
+		}
+		if _, isObject := v.(map[string]any); isObject {
+			return nil, nil, fmt.Errorf("%w: %s: object value not supported", model.ErrInvalidFilter, key)
+		}
+		extra[key] = v
+	}
+	rest, err := json.Marshal(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rest, extra, nil
+}
+
+// structTagKeys tên field theo tag (form/json) của struct filter, gồm cả struct nhúng
+func structTagKeys(filter any, tag string) map[string]bool {
+	keys := map[string]bool{}
+	t := reflect.TypeOf(filter)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return keys
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			for k := range structTagKeys(reflect.New(f.Type).Interface(), tag) {
+				keys[k] = true
+			}
+			continue
+		}
+		name := strings.Split(f.Tag.Get(tag), ",")[0]
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
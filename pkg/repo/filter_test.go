@@ -0,0 +1,110 @@
+package repo
+
+import (
+	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func filterSQL[T any](t *testing.T, filters map[string]any) (string, []any, error) {
+	t.Helper()
+	var rows []*T
+	db := ApplyFilterScopeDynamic[T](dryRunDB(t).Model(new(T)), filters, false).Find(&rows)
+	return db.Statement.SQL.String(), db.Statement.Vars, db.Error
+}
+
+func TestFilterDSL(t *testing.T) {
+	cases := []struct {
+		key   string
+		value any
+		want  string
+	}{
+		{"amount_gte", "100.5", `"entries"."amount" >= $1`},
+		{"memo_null", "true", `"entries"."memo" IS NULL`},
+		{"type", "D", `"entries"."dc" = $1`},
+		{"ids", "1,2", `"entries"."id" IN ($1,$2)`},
+		{"meta.provider", "wise", `("entries"."meta" #>> '{provider}') = $1`},
+		{"meta.card.brand_not_in", []any{"visa", "master"}, `("entries"."meta" #>> '{card,brand}') NOT IN ($1,$2)`},
+		{"end_date", "2025-12-01", `"entries"."created_at" < $1`},
+	}
+	for _, tc := range cases {
+		sql, _, err := filterSQL[model.Entry](t, map[string]any{tc.key: tc.value})
+		if err != nil {
+			t.Errorf("%s: %v", tc.key, err)
+			continue
+		}
+		if !strings.Contains(sql, tc.want) {
+			t.Errorf("%s: %s", tc.key, sql)
+		}
+	}
+
+	// YYYY-MM-DD ở _to lấy trọn ngày
+	_, vars, err := filterSQL[model.Journal](t, map[string]any{"ts_to": "2025-12-01", "status_not_in": "FAILED"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vars) != 2 {
+		t.Fatalf("vars = %v", vars)
+	}
+}
+
+func TestFilterDSLInvalid(t *testing.T) {
+	for key, value := range map[string]any{
+		"unknown":         "x",
+		"amount_gte":      "abc",
+		"memo_gte":        "a",
+		"meta":            "x",
+		"meta.a'b":        "x",
+		"created_at_from": "yesterday",
+	} {
+		if _, _, err := filterSQL[model.Entry](t, map[string]any{key: value}); !errors.Is(err, model.ErrInvalidFilter) {
+			t.Errorf("%s: err = %v", key, err)
+		}
+		if err := ValidateFilters[*model.Entry](map[string]any{key: value}); !errors.Is(err, model.ErrInvalidFilter) {
+			t.Errorf("validate %s: err = %v", key, err)
+		}
+	}
+}
+
+func TestFilterDSLSkipsBaseQueryKeys(t *testing.T) {
+	// BuildParamsFromFilter đưa cả field của BasePaginationQuery vào filters
+	order := any("desc")
+	deleted := false
+	keyword := "abc"
+	filters := BuildParamsFromFilter(&dto.ListEntrytFilter{BasePaginationQuery: dto.BasePaginationQuery{
+		Order: &order, IsDeleted: &deleted, Keyword: &keyword, StringIDs: []string{"a"},
+	}})
+	for _, key := range []string{"order", "is_deleted", "keyword", "string_ids"} {
+		if _, ok := filters[key]; !ok {
+			t.Fatalf("filters = %v", filters)
+		}
+	}
+	if _, _, err := filterSQL[model.Entry](t, filters); err != nil {
+		t.Fatalf("entries: %v", err)
+	}
+	if _, _, err := filterSQL[model.CoaAccount](t, filters); err != nil {
+		t.Fatalf("coa accounts: %v", err)
+	}
+	if err := ValidateFilters[*model.Entry](filters); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+}
+
+func TestQueryFilters(t *testing.T) {
+	values, _ := url.ParseQuery("page=2&search=abc&currency[]=USD&amount_gte=10&status_in[]=A&status_in[]=B")
+	got := QueryFilters(values, &dto.ListEntrytFilter{})
+	if len(got) != 2 || got["amount_gte"] != "10" || len(got["status_in"].([]any)) != 2 {
+		t.Fatalf("filters = %v", got)
+	}
+
+	known, extra, err := JSONFilters([]byte(`{"search":"abc","amount_gte":10,"meta.provider":"wise"}`), &dto.ListEntrytFilter{})
+	if err != nil || string(known) != `{"search":"abc"}` || len(extra) != 2 {
+		t.Fatalf("known = %s extra = %v err = %v", known, extra, err)
+	}
+	if _, _, err := JSONFilters([]byte(`{"meta":{"provider":"wise"}}`), &dto.ListEntrytFilter{}); !errors.Is(err, model.ErrInvalidFilter) {
+		t.Fatalf("object value: err = %v", err)
+	}
+}
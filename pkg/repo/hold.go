@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type HoldRepo interface {
+	creator[*model.Hold]
+	getByID[*model.Hold]
+	GetByReference(ctx context.Context, reference string) (*model.Hold, error)
+	SumActiveByAccount(ctx context.Context, accountID uint64) (decimal.Decimal, error)
+	ExpireStale(ctx context.Context, now time.Time, limit int) (int64, error)
+	PaginateWithScopes(ctx context.Context, filter *dto.ListHoldFilter) (*dto.PaginationResponse[*model.Hold], error)
+}
+
+type holdRepo struct {
+	db *gorm.DB
+}
+
+func NewHoldRepo(db *gorm.DB) HoldRepo {
+	return &holdRepo{db: db}
+}
+
+func (r *holdRepo) Create(holds ...*model.Hold) error {
+	return r.db.Create(holds).Error
+}
+
+func (r *holdRepo) GetByID(ctx context.Context, id int64) (*model.Hold, error) {
+	hold := &model.Hold{}
+	return hold, r.db.WithContext(ctx).First(&hold, "id = ?", id).Error
+}
+
+func (r *holdRepo) GetByReference(ctx context.Context, reference string) (*model.Hold, error) {
+	hold := &model.Hold{}
+	return hold, r.db.WithContext(ctx).First(&hold, "reference = ?", reference).Error
+}
+
+// SumActiveByAccount tổng số tiền còn giữ của các hold PENDING chưa hết hạn
+func (r *holdRepo) SumActiveByAccount(ctx context.Context, accountID uint64) (decimal.Decimal, error) {
+	var held decimal.NullDecimal
+	err := r.db.WithContext(ctx).
+		Model(&model.Hold{}).
+		Select("SUM(amount - captured_amount)").
+		Where("account_id = ? AND status = ? AND expires_at > ?", accountID, model.HoldStatusPending, time.Now()).
+		Scan(&held).Error
+	if err != nil || !held.Valid {
+		return decimal.Zero, err
+	}
+	return held.Decimal, nil
+}
+
+// ExpireStale chuyển tối đa limit hold PENDING đã quá hạn sang EXPIRED, trả về số bản ghi đã cập nhật
+func (r *holdRepo) ExpireStale(ctx context.Context, now time.Time, limit int) (int64, error) {
+	stale := r.db.Model(&model.Hold{}).
+		Select("id").
+		Where("status = ? AND expires_at <= ?", model.HoldStatusPending, now).
+		Order("expires_at").
+		Limit(limit)
+	res := r.db.WithContext(ctx).
+		Model(&model.Hold{}).
+		Where("id IN (?) AND status = ?", stale, model.HoldStatusPending).
+		Updates(map[string]interface{}{
+			"status":      model.HoldStatusExpired,
+			"released_at": now,
+			"updated_at":  now,
+		})
+	return res.RowsAffected, res.Error
+}
+
+func (r *holdRepo) PaginateWithScopes(ctx context.Context, fields *dto.ListHoldFilter) (*dto.PaginationResponse[*model.Hold], error) {
+	params := BuildParamsFromFilter(fields)
+
+	var items []*model.Hold
+	limit := int64(25)
+	page := int64(1)
+	if fields.Limit != nil {
+		limit = *fields.Limit
+	}
+	if fields.Page != nil {
+		page = *fields.Page
+	}
+
+	return CustomPaginate(r.db.WithContext(ctx).Model(&model.Hold{}).Order("id DESC"), params, page, limit, &items)
+}
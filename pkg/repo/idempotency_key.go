@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IdempotencyKeyRepo interface {
+	// Acquire giữ key cho request hiện tại: thêm mới, hoặc chiếm lại key đã hết hạn/request IN_PROGRESS bị treo.
+	// Trả về false nếu key đang được giữ hoặc đã có kết quả.
+	Acquire(ctx context.Context, record *model.IdempotencyKey, now time.Time) (bool, error)
+	Get(ctx context.Context, principal, key, route string) (*model.IdempotencyKey, error)
+	// Complete lưu response, chỉ áp dụng khi request vẫn đang giữ key (lock_token khớp)
+	Complete(ctx context.Context, record *model.IdempotencyKey) error
+	// Release xoá key đang giữ để client có thể gửi lại
+	Release(ctx context.Context, record *model.IdempotencyKey) error
+	// DeleteExpired xoá tối đa limit key đã hết hạn trước thời điểm before
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+type idempotencyKeyRepo struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyKeyRepo(db *gorm.DB) IdempotencyKeyRepo {
+	return &idempotencyKeyRepo{db: db}
+}
+
+func (r *idempotencyKeyRepo) Acquire(ctx context.Context, record *model.IdempotencyKey, now time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Exec(`
+		INSERT INTO idempotency_keys (principal, idempotency_key, route, request_hash, status, response_code, lock_token, locked_until, expires_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, 0, ?, ?, ?, ?, ?)
+		ON CONFLICT (principal, idempotency_key, route) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status = EXCLUDED.status,
+			response_code = 0,
+			response_body = NULL,
+			content_type = NULL,
+			lock_token = EXCLUDED.lock_token,
+			locked_until = EXCLUDED.locked_until,
+			expires_at = EXCLUDED.expires_at,
+			created_at = EXCLUDED.created_at,
+			updated_at = EXCLUDED.updated_at
+		WHERE idempotency_keys.expires_at <= ?
+			OR (idempotency_keys.status = ? AND idempotency_keys.locked_until <= ?)`,
+		record.Principal, record.Key, record.Route, record.RequestHash, record.Status,
+		record.LockToken, record.LockedUntil, record.ExpiresAt, now, now,
+		now, model.IdempotencyStatusInProgress, now,
+	)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *idempotencyKeyRepo) Get(ctx context.Context, principal, key, route string) (*model.IdempotencyKey, error) {
+	record := &model.IdempotencyKey{}
+	return record, r.db.WithContext(ctx).
+		Where("principal = ? AND idempotency_key = ? AND route = ?", principal, key, route).
+		First(record).Error
+}
+
+func (r *idempotencyKeyRepo) Complete(ctx context.Context, record *model.IdempotencyKey) error {
+	return r.db.WithContext(ctx).Model(&model.IdempotencyKey{}).
+		Where("principal = ? AND idempotency_key = ? AND route = ? AND lock_token = ?", record.Principal, record.Key, record.Route, record.LockToken).
+		Updates(map[string]interface{}{
+			"status":        model.IdempotencyStatusCompleted,
+			"response_code": record.ResponseCode,
+			"response_body": record.ResponseBody,
+			"content_type":  record.ContentType,
+			"lock_token":    nil,
+			"locked_until":  nil,
+			"expires_at":    record.ExpiresAt,
+		}).Error
+}
+
+func (r *idempotencyKeyRepo) Release(ctx context.Context, record *model.IdempotencyKey) error {
+	return r.db.WithContext(ctx).
+		Where("principal = ? AND idempotency_key = ? AND route = ? AND lock_token = ?", record.Principal, record.Key, record.Route, record.LockToken).
+		Delete(&model.IdempotencyKey{}).Error
+}
+
+func (r *idempotencyKeyRepo) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	res := r.db.WithContext(ctx).Exec(`
+		DELETE FROM idempotency_keys
+		WHERE id IN (SELECT id FROM idempotency_keys WHERE expires_at <= ? ORDER BY expires_at LIMIT ?)`,
+		before, limit,
+	)
+	return res.RowsAffected, res.Error
+}
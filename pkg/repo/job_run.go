@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobRunRepo đọc/ghi tiến độ và kết quả job (bảng job_runs)
+type JobRunRepo interface {
+	WithTx(tx *gorm.DB) JobRunRepo
+	// Save thêm mới hoặc ghi đè trạng thái theo task_id
+	Save(ctx context.Context, run *model.JobRun) error
+	GetByTaskID(ctx context.Context, taskID string) (*model.JobRun, error)
+}
+
+type jobRunRepo struct {
+	db *gorm.DB
+}
+
+func NewJobRunRepo(db *gorm.DB) JobRunRepo {
+	return &jobRunRepo{db: db}
+}
+
+// WithTx trả về repo chạy trên transaction của caller
+func (r *jobRunRepo) WithTx(tx *gorm.DB) JobRunRepo {
+	return &jobRunRepo{db: tx}
+}
+
+func (r *jobRunRepo) Save(ctx context.Context, run *model.JobRun) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "task_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"type", "queue", "state", "processed", "total", "message", "result",
+			"error", "attempts", "max_retry", "started_at", "finished_at", "updated_at",
+		}),
+	}).Create(run).Error
+}
+
+func (r *jobRunRepo) GetByTaskID(ctx context.Context, taskID string) (*model.JobRun, error) {
+	run := &model.JobRun{}
+	return run, r.db.WithContext(ctx).First(run, "task_id = ?", taskID).Error
+}
@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -15,6 +16,13 @@ type JournalRepo interface {
 	updater[*model.Journal]
 	Save(customer *model.Journal) error
 	Upsert(accounts []*model.Journal, updateColumns []string) error
+	GetByIdempotencyKey(ctx context.Context, key string) (*model.Journal, error)
+	GetWithEntries(ctx context.Context, id int64) (*model.Journal, error)
+	// LockWithEntries đọc journal kèm entries và khoá FOR UPDATE, dùng trong transaction (WithTx)
+	LockWithEntries(ctx context.Context, id int64) (*model.Journal, error)
+	WithTx(tx *gorm.DB) JournalRepo
+	// Paginate bút toán tăng dần theo id
+	Paginate(ctx context.Context, filter *dto.ListJournalFilter) (*dto.PaginationResponse[*model.Journal], error)
 }
 
 type journalRepo struct {
@@ -62,3 +70,36 @@ func (c *journalRepo) Upsert(accounts []*model.Journal, updateColumns []string)
 		DoUpdates: clause.AssignmentColumns(updateColumns),
 	}).Create(&accounts).Error
 }
+
+func (c *journalRepo) GetByIdempotencyKey(ctx context.Context, key string) (*model.Journal, error) {
+	journal := &model.Journal{}
+	return journal, c.db.WithContext(ctx).Preload("Entries", func(db *gorm.DB) *gorm.DB {
+		return db.Order("line_no ASC")
+	}).First(&journal, "idempotency_key = ?", key).Error
+}
+
+func (c *journalRepo) GetWithEntries(ctx context.Context, id int64) (*model.Journal, error) {
+	journal := &model.Journal{}
+	return journal, c.db.WithContext(ctx).Preload("Entries", func(db *gorm.DB) *gorm.DB {
+		return db.Order("line_no ASC")
+	}).First(&journal, "id = ?", id).Error
+}
+
+func (c *journalRepo) LockWithEntries(ctx context.Context, id int64) (*model.Journal, error) {
+	journal := &model.Journal{}
+	return journal, c.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Entries", func(db *gorm.DB) *gorm.DB {
+		return db.Order("line_no ASC")
+	}).First(&journal, "id = ?", id).Error
+}
+
+func (c *journalRepo) WithTx(tx *gorm.DB) JournalRepo {
+	return &journalRepo{db: tx}
+}
+
+func (c *journalRepo) Paginate(ctx context.Context, fields *dto.ListJournalFilter) (*dto.PaginationResponse[*model.Journal], error) {
+	// field của filter và filter DSL đều đi qua whitelist FilterFields của model
+	var items []*model.Journal
+	page, limit := pageParams(fields.BasePaginationQuery)
+	query := c.db.WithContext(ctx).Model(&model.Journal{}).Order("id")
+	return CustomPaginate(query, BuildParamsFromFilter(fields), page, limit, &items)
+}
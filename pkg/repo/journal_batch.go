@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JournalBatchRepo interface {
+	// WithTx trả về repo chạy trên transaction của caller
+	WithTx(tx *gorm.DB) JournalBatchRepo
+	Create(batch *model.JournalBatch) error
+	GetByID(ctx context.Context, id string) (*model.JournalBatch, error)
+	// LockByID đọc batch kèm FOR UPDATE
+	LockByID(ctx context.Context, id string) (*model.JournalBatch, error)
+	UpdateSelectField(entity *model.JournalBatch, fields map[string]interface{}) error
+	// ListJournals trả về các journal gốc (không tính journal đảo) của batch kèm entries
+	ListJournals(ctx context.Context, id string) ([]*model.Journal, error)
+	// Totals tổng hợp số journal và tổng Nợ/Có của batch theo currency
+	Totals(ctx context.Context, id string) ([]*dto.JournalBatchTotal, error)
+}
+
+type journalBatchRepo struct {
+	db *gorm.DB
+}
+
+func NewJournalBatchRepo(db *gorm.DB) JournalBatchRepo {
+	return &journalBatchRepo{db: db}
+}
+
+func (r *journalBatchRepo) WithTx(tx *gorm.DB) JournalBatchRepo {
+	return &journalBatchRepo{db: tx}
+}
+
+func (r *journalBatchRepo) Create(batch *model.JournalBatch) error {
+	return r.db.Create(batch).Error
+}
+
+func (r *journalBatchRepo) GetByID(ctx context.Context, id string) (*model.JournalBatch, error) {
+	batch := &model.JournalBatch{}
+	return batch, r.db.WithContext(ctx).First(batch, "id = ?", id).Error
+}
+
+func (r *journalBatchRepo) LockByID(ctx context.Context, id string) (*model.JournalBatch, error) {
+	batch := &model.JournalBatch{}
+	return batch, r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(batch, "id = ?", id).Error
+}
+
+func (r *journalBatchRepo) UpdateSelectField(entity *model.JournalBatch, fields map[string]interface{}) error {
+	return r.db.Model(entity).Updates(fields).Error
+}
+
+func (r *journalBatchRepo) ListJournals(ctx context.Context, id string) ([]*model.Journal, error) {
+	var journals []*model.Journal
+	err := r.db.WithContext(ctx).
+		Preload("Entries", func(db *gorm.DB) *gorm.DB {
+			return db.Order("line_no ASC")
+		}).
+		Where("batch_id = ? AND reversal_of IS NULL", id).
+		Order("id ASC").
+		Find(&journals).Error
+	return journals, err
+}
+
+func (r *journalBatchRepo) Totals(ctx context.Context, id string) ([]*dto.JournalBatchTotal, error) {
+	var totals []*dto.JournalBatchTotal
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT TRIM(j.currency) AS currency,
+		       COUNT(DISTINCT j.id) FILTER (WHERE j.reversal_of IS NULL) AS journal_count,
+		       COUNT(DISTINCT j.id) FILTER (WHERE j.reversal_of IS NULL AND j.status = 'REVERSED') AS reversed_count,
+		       COALESCE(SUM(e.amount) FILTER (WHERE j.reversal_of IS NULL AND e.dc = 'D'), 0) AS total_debit,
+		       COALESCE(SUM(e.amount) FILTER (WHERE j.reversal_of IS NULL AND e.dc = 'C'), 0) AS total_credit
+		FROM journals j
+		JOIN entries e ON e.journal_id = j.id
+		WHERE j.batch_id = ?
+		GROUP BY TRIM(j.currency)
+		ORDER BY 1`, id).Scan(&totals).Error
+	return totals, err
+}
@@ -0,0 +1,52 @@
+package repo
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LedgerCurrencyRepo interface {
+	Create(currency *model.LedgerCurrency) error
+	UpdateSelectField(entity *model.LedgerCurrency, fields map[string]interface{}) error
+	GetByCode(ctx context.Context, code string) (*model.LedgerCurrency, error)
+	List(ctx context.Context) ([]*model.LedgerCurrency, error)
+	// InsertMissing thêm các currency chưa có, bỏ qua code đã tồn tại (không ghi đè scale)
+	InsertMissing(ctx context.Context, currencies []*model.LedgerCurrency) (int64, error)
+}
+
+type ledgerCurrencyRepo struct {
+	db *gorm.DB
+}
+
+func NewLedgerCurrencyRepo(db *gorm.DB) LedgerCurrencyRepo {
+	return &ledgerCurrencyRepo{db: db}
+}
+
+func (r *ledgerCurrencyRepo) Create(currency *model.LedgerCurrency) error {
+	return r.db.Create(currency).Error
+}
+
+func (r *ledgerCurrencyRepo) UpdateSelectField(entity *model.LedgerCurrency, fields map[string]interface{}) error {
+	return r.db.Model(entity).Updates(fields).Error
+}
+
+func (r *ledgerCurrencyRepo) GetByCode(ctx context.Context, code string) (*model.LedgerCurrency, error) {
+	currency := &model.LedgerCurrency{}
+	return currency, r.db.WithContext(ctx).First(currency, "code = ?", code).Error
+}
+
+func (r *ledgerCurrencyRepo) List(ctx context.Context) ([]*model.LedgerCurrency, error) {
+	var currencies []*model.LedgerCurrency
+	return currencies, r.db.WithContext(ctx).Order("code ASC").Find(&currencies).Error
+}
+
+func (r *ledgerCurrencyRepo) InsertMissing(ctx context.Context, currencies []*model.LedgerCurrency) (int64, error) {
+	if len(currencies) == 0 {
+		return 0, nil
+	}
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&currencies)
+	return res.RowsAffected, res.Error
+}
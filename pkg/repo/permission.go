@@ -0,0 +1,39 @@
+package repo
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type PermissionRepo interface {
+	// ListNamesByEmployee trả về tên các permission đang hiệu lực của nhân viên,
+	// gồm cả permission con của permission được gán (permissions.parent_id)
+	ListNamesByEmployee(ctx context.Context, employeeID int64) ([]string, error)
+}
+
+type permissionRepo struct {
+	db *gorm.DB
+}
+
+func NewPermissionRepo(db *gorm.DB) PermissionRepo {
+	return &permissionRepo{db: db}
+}
+
+func (r *permissionRepo) ListNamesByEmployee(ctx context.Context, employeeID int64) ([]string, error) {
+	var names []string
+	err := r.db.WithContext(ctx).Raw(`
+		WITH RECURSIVE granted AS (
+		    SELECT p.id, p.name
+		    FROM permissions p
+		    JOIN employee_permissions ep ON ep.permission_id = p.id
+		    WHERE ep.employee_id = ? AND p.status = TRUE AND p.is_deleted = FALSE
+		    UNION
+		    SELECT c.id, c.name
+		    FROM permissions c
+		    JOIN granted g ON c.parent_id = g.id
+		    WHERE c.status = TRUE AND c.is_deleted = FALSE
+		)
+		SELECT DISTINCT name FROM granted ORDER BY name`, employeeID).Scan(&names).Error
+	return names, err
+}
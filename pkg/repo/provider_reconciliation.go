@@ -0,0 +1,68 @@
+package repo
+
+import (
+	"context"
+	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ProviderReconciliationRepo interface {
+	getByID[*model.ProviderReconciliation]
+	ReplaceForCutoff(ctx context.Context, cutoff time.Time, reports []*model.ProviderReconciliation) error
+	ListByCutoff(ctx context.Context, cutoff time.Time) ([]*model.ProviderReconciliation, error)
+	PaginateWithScopes(ctx context.Context, filter *dto.ListProviderReconciliationFilter) (*dto.PaginationResponse[*model.ProviderReconciliation], error)
+}
+
+type providerReconciliationRepo struct {
+	db *gorm.DB
+}
+
+func NewProviderReconciliationRepo(db *gorm.DB) ProviderReconciliationRepo {
+	return &providerReconciliationRepo{db: db}
+}
+
+func (r *providerReconciliationRepo) GetByID(ctx context.Context, id int64) (*model.ProviderReconciliation, error) {
+	report := &model.ProviderReconciliation{}
+	return report, r.db.WithContext(ctx).Preload("Items").First(&report, "id = ?", id).Error
+}
+
+// ReplaceForCutoff ghi đè kết quả đối soát của một cutoff (chạy lại job không bị trùng dữ liệu)
+func (r *providerReconciliationRepo) ReplaceForCutoff(ctx context.Context, cutoff time.Time, reports []*model.ProviderReconciliation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("cutoff_at = ?", cutoff).Delete(&model.ProviderReconciliation{}).Error; err != nil {
+			return err
+		}
+		if len(reports) == 0 {
+			return nil
+		}
+		return tx.Create(&reports).Error
+	})
+}
+
+func (r *providerReconciliationRepo) ListByCutoff(ctx context.Context, cutoff time.Time) ([]*model.ProviderReconciliation, error) {
+	var reports []*model.ProviderReconciliation
+	return reports, r.db.WithContext(ctx).
+		Preload("Items").
+		Where("cutoff_at = ?", cutoff).
+		Order("provider, currency").
+		Find(&reports).Error
+}
+
+func (r *providerReconciliationRepo) PaginateWithScopes(ctx context.Context, fields *dto.ListProviderReconciliationFilter) (*dto.PaginationResponse[*model.ProviderReconciliation], error) {
+	params := BuildParamsFromFilter(fields)
+
+	var items []*model.ProviderReconciliation
+	limit := int64(25)
+	page := int64(1)
+	if fields.Limit != nil {
+		limit = *fields.Limit
+	}
+	if fields.Page != nil {
+		page = *fields.Page
+	}
+
+	return CustomPaginate(r.db.WithContext(ctx).Model(&model.ProviderReconciliation{}).Order("cutoff_at DESC, id ASC"), params, page, limit, &items)
+}
@@ -18,6 +18,7 @@ type RuleValueRepo interface {
 	Upsert(accounts []*model.RuleValue, updateColumns []string) error
 	List(ctx context.Context, filter *dto.FilterRuleValueRequest) ([]*model.RuleValue, error)
 	DeleteByIDs(ctx context.Context, ids []uint) error
+	FindByCategoryCode(ctx context.Context, categoryCode, value string) (*model.RuleValue, error)
 }
 
 type ruleValueRepo struct {
@@ -37,6 +38,16 @@ func (c *ruleValueRepo) List(ctx context.Context, filter *dto.FilterRuleValueReq
 	}
 	return accounts, nil
 }
+
+// FindByCategoryCode tìm rule value còn hiệu lực theo mã category (VD: KINDS_OF_REVENUE) và value
+func (c *ruleValueRepo) FindByCategoryCode(ctx context.Context, categoryCode, value string) (*model.RuleValue, error) {
+	ruleValue := &model.RuleValue{}
+	return ruleValue, c.db.WithContext(ctx).
+		Joins("JOIN rule_categories rc ON rc.id = rule_values.category_id").
+		Where("rc.code = ? AND rule_values.value = ? AND rule_values.is_delete = false", categoryCode, value).
+		First(ruleValue).Error
+}
+
 func (c *ruleValueRepo) Save(customer *model.RuleValue) error {
 	return c.db.Create(&customer).Error
 }
@@ -1,6 +1,7 @@
 package repo
 
 import (
+	model "core-ledger/model/core-ledger"
 	"core-ledger/model/dto"
 	"errors"
 	"fmt"
@@ -17,24 +18,13 @@ type Scoper interface{}
 // ApplyFilterScopeDynamic áp dụng các filter scope từ map,
 // includeSort=true → áp dụng cả ScopeSort
 // includeSort=false → bỏ ScopeSort (dùng cho COUNT)
+// Key không có ScopeXxx thì tra whitelist FilterFields nếu model là model.Filterable (filter DSL: amount_gte, meta.provider...),
+// key không hợp lệ gắn lỗi model.ErrInvalidFilter vào query thay vì bị bỏ qua
 func ApplyFilterScopeDynamic[T any](db *gorm.DB, filters map[string]any, includeSort bool) *gorm.DB {
-	var t T
-	tType := reflect.TypeOf(t)
-
-	var model reflect.Value
-	if tType.Kind() == reflect.Pointer {
-		model = reflect.New(tType.Elem()) // *CoaAccount
-	} else {
-		model = reflect.New(tType) // CoaAccount → &CoaAccount{}
-	}
-
-	modelPtr := model
-	if modelPtr.Kind() != reflect.Pointer {
-		modelPtr = modelPtr.Addr()
-	}
+	modelPtr := newModelPtr[T]()
 
 	for key, rawParam := range filters {
-		if key == "page" || key == "limit" || key == "offset" {
+		if isPaginationKey(key) {
 			continue
 		}
 
@@ -43,32 +33,99 @@ func ApplyFilterScopeDynamic[T any](db *gorm.DB, filters map[string]any, include
 			continue // bỏ sort khi includeSort=false
 		}
 
-		log.Printf("[DynamicScope] Checking method: %s with param %+v", scopeName, rawParam)
-
-		method, ok := modelPtr.Type().MethodByName(scopeName)
-		if !ok {
-			log.Printf("[DynamicScope] Method %s not found", scopeName)
+		scopeFn, err := dynamicScope(modelPtr, key, rawParam)
+		if err != nil {
+			log.Printf("[DynamicScope] %v", err)
+			db = db.Scopes(func(tx *gorm.DB) *gorm.DB {
+				_ = tx.AddError(err)
+				return tx
+			})
 			continue
 		}
+		if scopeFn != nil {
+			db = db.Scopes(scopeFn)
+			log.Println("[DynamicScope] Scope applied")
+		}
+	}
 
-		converted, err := convertDynamicValue(rawParam, method.Type.In(1))
-		if err != nil {
-			log.Printf("[DynamicScope] Cannot convert param for %s: %v", scopeName, err)
+	return db
+}
+
+// ValidateFilters kiểm tra filters theo ScopeXxx/whitelist của model mà không chạy query, lỗi là model.ErrInvalidFilter
+func ValidateFilters[T any](filters map[string]any) error {
+	modelPtr := newModelPtr[T]()
+	for key, rawParam := range filters {
+		if isPaginationKey(key) {
 			continue
 		}
+		if _, err := dynamicScope(modelPtr, key, rawParam); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		out := method.Func.Call([]reflect.Value{modelPtr, converted})
-		if len(out) == 1 {
-			if scopeFn, ok := out[0].Interface().(func(*gorm.DB) *gorm.DB); ok {
-				db = db.Scopes(scopeFn)
-				log.Println("[DynamicScope] Scope applied")
-			} else {
-				log.Printf("[DynamicScope] Method %s return not func(*gorm.DB)*gorm.DB", scopeName)
-			}
+func isPaginationKey(key string) bool {
+	switch key {
+	case "page", "limit", "offset", "cursor", "with_total":
+		return true
+	}
+	return false
+}
+
+// isBaseQueryKey field của dto.BasePaginationQuery không phải filter DSL: model không có ScopeXxx thì bỏ qua như trước,
+// không báo filter lạ (ids, start_date, end_date đã có trong whitelist chung)
+func isBaseQueryKey(key string) bool {
+	switch key {
+	case "order", "is_deleted", "keyword", "string_ids":
+		return true
+	}
+	return false
+}
+
+// newModelPtr *Model từ T (T có thể là Model hoặc *Model)
+func newModelPtr[T any]() reflect.Value {
+	tType := reflect.TypeOf((*T)(nil)).Elem()
+	if tType.Kind() == reflect.Pointer {
+		return reflect.New(tType.Elem()) // *CoaAccount
+	}
+	return reflect.New(tType) // CoaAccount → &CoaAccount{}
+}
+
+// dynamicScope scope cho một key: ScopeXxx của model trước, sau đó whitelist FilterFields.
+// Model chưa khai báo whitelist giữ cách cũ: key lạ chỉ log rồi bỏ qua
+func dynamicScope(modelPtr reflect.Value, key string, rawParam any) (func(*gorm.DB) *gorm.DB, error) {
+	filterable, strict := modelPtr.Interface().(model.Filterable)
+	scopeName := jsonKeyToScopeName(key)
+
+	log.Printf("[DynamicScope] Checking method: %s with param %+v", scopeName, rawParam)
+
+	method, ok := modelPtr.Type().MethodByName(scopeName)
+	if !ok {
+		if strict && !isBaseQueryKey(key) {
+			return filterable.FilterFields().Scope(key, rawParam)
 		}
+		log.Printf("[DynamicScope] Method %s not found", scopeName)
+		return nil, nil
 	}
 
-	return db
+	converted, err := convertDynamicValue(rawParam, method.Type.In(1))
+	if err != nil {
+		if strict {
+			return nil, fmt.Errorf("%w: %s: %v", model.ErrInvalidFilter, key, err)
+		}
+		log.Printf("[DynamicScope] Cannot convert param for %s: %v", scopeName, err)
+		return nil, nil
+	}
+
+	out := method.Func.Call([]reflect.Value{modelPtr, converted})
+	if len(out) == 1 {
+		if scopeFn, ok := out[0].Interface().(func(*gorm.DB) *gorm.DB); ok {
+			return scopeFn, nil
+		}
+	}
+	log.Printf("[DynamicScope] Method %s return not func(*gorm.DB)*gorm.DB", scopeName)
+	return nil, nil
 }
 
 // convertDynamicValue: convert interface{} sang reflect.Value theo target type
@@ -87,7 +144,12 @@ func convertDynamicValue(val any, targetType reflect.Type) (reflect.Value, error
 		sliceVal := reflect.MakeSlice(targetType, 0, 0)
 		arr, ok := val.([]any)
 		if !ok {
-			return reflect.Value{}, ErrInvalidType
+			// status=ACTIVE từ query string → []string{"ACTIVE"}
+			str, isStr := val.(string)
+			if !isStr {
+				return reflect.Value{}, ErrInvalidType
+			}
+			arr = []any{str}
 		}
 
 		for _, item := range arr {
@@ -184,6 +246,15 @@ func BuildParamsFromFilter(f interface{}) map[string]any {
 			}
 		case reflect.Bool:
 			result[key] = field.Bool()
+		case reflect.Map:
+			// filter DSL (BasePaginationQuery.Filters), field của struct được ưu tiên khi trùng key
+			iter := field.MapRange()
+			for iter.Next() {
+				k := iter.Key().String()
+				if _, exists := result[k]; !exists {
+					result[k] = iter.Value().Interface()
+				}
+			}
 		}
 	}
 
@@ -240,3 +311,24 @@ func CustomPaginate[T any](db *gorm.DB, filters map[string]any, page, limit int6
 		PrevPage:  prevPage,
 	}, nil
 }
+
+// cursorParams cursor/limit/with_total của filter cho CursorPaginate
+func cursorParams(q dto.BasePaginationQuery) (cursor string, limit int64, withTotal bool) {
+	_, limit = pageParams(q)
+	if q.Cursor != nil {
+		cursor = *q.Cursor
+	}
+	return cursor, limit, q.WithTotal != nil && *q.WithTotal
+}
+
+// pageParams page/limit của filter, mặc định trang 1, 25 bản ghi
+func pageParams(q dto.BasePaginationQuery) (page, limit int64) {
+	page, limit = 1, 25
+	if q.Page != nil {
+		page = *q.Page
+	}
+	if q.Limit != nil {
+		limit = *q.Limit
+	}
+	return page, limit
+}
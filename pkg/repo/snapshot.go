@@ -3,6 +3,8 @@ package repo
 import (
 	"context"
 	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -16,6 +18,13 @@ type SnapshotRepo interface {
 	Save(customer *model.Snapshot) error
 	Upsert(accounts []*model.Snapshot, updateColumns []string) error
 	GetByAccount(ctx context.Context, account int64) ([]model.Snapshot, error)
+	// WithTx trả về repo chạy trên transaction của caller
+	WithTx(tx *gorm.DB) SnapshotRepo
+	ListByDate(ctx context.Context, asOf time.Time) ([]*model.Snapshot, error)
+	// CreateIgnoreConflict bỏ qua snapshot đã tồn tại (as_of_date, account_id), trả về số bản ghi đã tạo
+	CreateIgnoreConflict(ctx context.Context, snapshots []*model.Snapshot) (int64, error)
+	// Paginate snapshot tăng dần theo id
+	Paginate(ctx context.Context, filter *dto.ListSnapshotFilter) (*dto.PaginationResponse[*model.Snapshot], error)
 }
 
 type snapShotRepo struct {
@@ -68,3 +77,34 @@ func (c *snapShotRepo) GetByAccount(context context.Context, id int64) ([]model.
 	snapshots := []model.Snapshot{}
 	return snapshots, c.db.WithContext(context).Where("account_id = ?", id).Find(&snapshots).Error
 }
+
+func (c *snapShotRepo) WithTx(tx *gorm.DB) SnapshotRepo {
+	return &snapShotRepo{db: tx}
+}
+
+func (c *snapShotRepo) ListByDate(ctx context.Context, asOf time.Time) ([]*model.Snapshot, error) {
+	snapshots := []*model.Snapshot{}
+	return snapshots, c.db.WithContext(ctx).
+		Where("as_of_date = ?", asOf.Format("2006-01-02")).
+		Order("account_id").
+		Find(&snapshots).Error
+}
+
+func (c *snapShotRepo) CreateIgnoreConflict(ctx context.Context, snapshots []*model.Snapshot) (int64, error) {
+	if len(snapshots) == 0 {
+		return 0, nil
+	}
+	res := c.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "as_of_date"}, {Name: "account_id"}},
+		DoNothing: true,
+	}).CreateInBatches(snapshots, 500)
+	return res.RowsAffected, res.Error
+}
+
+func (c *snapShotRepo) Paginate(ctx context.Context, fields *dto.ListSnapshotFilter) (*dto.PaginationResponse[*model.Snapshot], error) {
+	// field của filter và filter DSL đều đi qua whitelist FilterFields của model
+	var items []*model.Snapshot
+	page, limit := pageParams(fields.BasePaginationQuery)
+	query := c.db.WithContext(ctx).Model(&model.Snapshot{}).Order("id")
+	return CustomPaginate(query, BuildParamsFromFilter(fields), page, limit, &items)
+}
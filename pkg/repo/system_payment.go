@@ -0,0 +1,55 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	model "core-ledger/model/wealify"
+
+	"gorm.io/gorm"
+)
+
+// SystemPaymentBalanceView là số dư tại nhà cung cấp kèm thông tin system payment để map sang CoA
+type SystemPaymentBalanceView struct {
+	model.SystemPaymentBalance
+	Provider    string `gorm:"column:provider" json:"provider"`
+	PaymentType string `gorm:"column:payment_type" json:"payment_type"`
+}
+
+type SystemPaymentRepo interface {
+	ListBalances(ctx context.Context) ([]*SystemPaymentBalanceView, error)
+	ListTransactions(ctx context.Context, systemPaymentID, currency string, from, to time.Time) ([]*model.SystemPaymentTransaction, error)
+}
+
+type systemPaymentRepo struct {
+	db *gorm.DB
+}
+
+func NewSystemPaymentRepo(db *gorm.DB) SystemPaymentRepo {
+	return &systemPaymentRepo{db: db}
+}
+
+func (r *systemPaymentRepo) ListBalances(ctx context.Context) ([]*SystemPaymentBalanceView, error) {
+	var balances []*SystemPaymentBalanceView
+	err := r.db.WithContext(ctx).
+		Model(&model.SystemPaymentBalance{}).
+		Select("system_payment_balances.*, sp.provider, sp.payment_type").
+		Joins("JOIN " + model.TableNameSystemPayment + " sp ON sp.id = system_payment_balances.system_payment_id").
+		Where("system_payment_balances.deleted_at IS NULL AND sp.is_deleted = 0").
+		Order("sp.provider, system_payment_balances.currency_symbol").
+		Scan(&balances).Error
+	return balances, err
+}
+
+// ListTransactions lấy giao dịch của system payment trong khoảng [from, to)
+func (r *systemPaymentRepo) ListTransactions(ctx context.Context, systemPaymentID, currency string, from, to time.Time) ([]*model.SystemPaymentTransaction, error) {
+	var transactions []*model.SystemPaymentTransaction
+	query := r.db.WithContext(ctx).
+		Model(&model.SystemPaymentTransaction{}).
+		Where("system_payment_id = ? AND currency_symbol = ?", systemPaymentID, currency).
+		Where("created_at < ?", to)
+	if !from.IsZero() {
+		query = query.Where("created_at >= ?", from)
+	}
+	return transactions, query.Order("created_at ASC").Find(&transactions).Error
+}
@@ -3,6 +3,8 @@ package repo
 import (
 	"context"
 	model "core-ledger/model/core-ledger"
+	"core-ledger/model/dto"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -15,6 +17,23 @@ type TransactionLogRepo interface {
 	updater[*model.TransactionLog]
 	Save(customer *model.TransactionLog) error
 	Upsert(accounts []*model.TransactionLog, updateColumns []string) error
+	WithTx(tx *gorm.DB) TransactionLogRepo
+	// ClaimPending khoá (FOR UPDATE SKIP LOCKED) tối đa limit event PENDING đến hạn relay, chạy trong transaction
+	ClaimPending(ctx context.Context, now time.Time, limit int) ([]*model.TransactionLog, error)
+	MarkPublished(ctx context.Context, id uint64, now time.Time) error
+	// MarkRetry ghi nhận một lần relay lỗi, status = DEAD khi hết số lần thử
+	MarkRetry(ctx context.Context, id uint64, status string, nextAttemptAt, now time.Time, errMsg string) error
+	// Backlog số event chưa PUBLISHED và thời điểm tạo của event cũ nhất, theo status
+	Backlog(ctx context.Context) ([]OutboxBacklog, error)
+	// Paginate event outbox tăng dần theo id
+	Paginate(ctx context.Context, filter *dto.ListTransactionLogFilter) (*dto.PaginationResponse[*model.TransactionLog], error)
+}
+
+// OutboxBacklog số event outbox chưa publish của một status
+type OutboxBacklog struct {
+	Status   string
+	Total    int64
+	OldestAt time.Time
 }
 
 type transactionLogRepo struct {
@@ -26,6 +45,10 @@ func NewTransactionLogRepo(db *gorm.DB) TransactionLogRepo {
 		db: db,
 	}
 }
+func (c *transactionLogRepo) WithTx(tx *gorm.DB) TransactionLogRepo {
+	return &transactionLogRepo{db: tx}
+}
+
 func (c *transactionLogRepo) Save(customer *model.TransactionLog) error {
 	return c.db.Create(&customer).Error
 }
@@ -62,3 +85,54 @@ func (c *transactionLogRepo) Upsert(accounts []*model.TransactionLog, updateColu
 		DoUpdates: clause.AssignmentColumns(updateColumns),
 	}).Create(&accounts).Error
 }
+
+func (c *transactionLogRepo) ClaimPending(ctx context.Context, now time.Time, limit int) ([]*model.TransactionLog, error) {
+	var logs []*model.TransactionLog
+	err := c.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", model.TransactionLogStatusPending, now).
+		Order("id").
+		Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+func (c *transactionLogRepo) MarkPublished(ctx context.Context, id uint64, now time.Time) error {
+	return c.db.WithContext(ctx).Model(&model.TransactionLog{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          model.TransactionLogStatusPublished,
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_attempt_at": now,
+		"published_at":    now,
+		"error_last":      nil,
+	}).Error
+}
+
+func (c *transactionLogRepo) MarkRetry(ctx context.Context, id uint64, status string, nextAttemptAt, now time.Time, errMsg string) error {
+	return c.db.WithContext(ctx).Model(&model.TransactionLog{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          status,
+		"attempts":        gorm.Expr("attempts + 1"),
+		"last_attempt_at": now,
+		"next_attempt_at": nextAttemptAt,
+		"error_last":      errMsg,
+	}).Error
+}
+
+func (c *transactionLogRepo) Backlog(ctx context.Context) ([]OutboxBacklog, error) {
+	var rows []OutboxBacklog
+	err := c.db.WithContext(ctx).
+		Model(&model.TransactionLog{}).
+		Select("status, COUNT(*) AS total, MIN(created_at) AS oldest_at").
+		Where("status <> ?", model.TransactionLogStatusPublished).
+		Group("status").
+		Order("status").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (c *transactionLogRepo) Paginate(ctx context.Context, fields *dto.ListTransactionLogFilter) (*dto.PaginationResponse[*model.TransactionLog], error) {
+	// field của filter và filter DSL đều đi qua whitelist FilterFields của model
+	var items []*model.TransactionLog
+	page, limit := pageParams(fields.BasePaginationQuery)
+	query := c.db.WithContext(ctx).Model(&model.TransactionLog{}).Order("id")
+	return CustomPaginate(query, BuildParamsFromFilter(fields), page, limit, &items)
+}
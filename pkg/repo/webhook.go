@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+	"core-ledger/model/dto"
+	wealify "core-ledger/model/wealify"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookRepo đọc/ghi cấu hình webhook, event đăng ký và lịch sử gửi (bảng dùng chung với wealify)
+type WebhookRepo interface {
+	WithTx(tx *gorm.DB) WebhookRepo
+	CreateConfig(ctx context.Context, config *wealify.WebhookConfiguration) error
+	GetConfig(ctx context.Context, id string) (*wealify.WebhookConfiguration, error)
+	ListConfigs(ctx context.Context, ownerID int64) ([]*wealify.WebhookConfiguration, error)
+	UpdateConfig(ctx context.Context, id string, fields map[string]interface{}) error
+	// ListSubscribers webhook đang bật của owner có đăng ký eventName
+	ListSubscribers(ctx context.Context, ownerID int64, eventName string) ([]*wealify.WebhookConfiguration, error)
+	ListEvents(ctx context.Context, webhookIDs []string) ([]*wealify.WebhookRegisterEvent, error)
+	// ReplaceEvents thay toàn bộ event đăng ký của webhook
+	ReplaceEvents(ctx context.Context, webhookID string, events []string, now time.Time) error
+	CreateLog(ctx context.Context, log *wealify.WebhookLog) error
+	GetLog(ctx context.Context, id string) (*wealify.WebhookLog, error)
+	PaginateLogs(ctx context.Context, webhookIDs []string, filter *dto.ListWebhookDeliveryFilter) (*dto.PaginationResponse[*wealify.WebhookLog], error)
+}
+
+type webhookRepo struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepo(db *gorm.DB) WebhookRepo {
+	return &webhookRepo{db: db}
+}
+
+func (r *webhookRepo) WithTx(tx *gorm.DB) WebhookRepo {
+	return &webhookRepo{db: tx}
+}
+
+func (r *webhookRepo) CreateConfig(ctx context.Context, config *wealify.WebhookConfiguration) error {
+	return r.db.WithContext(ctx).Create(config).Error
+}
+
+func (r *webhookRepo) GetConfig(ctx context.Context, id string) (*wealify.WebhookConfiguration, error) {
+	config := &wealify.WebhookConfiguration{}
+	return config, r.db.WithContext(ctx).First(config, "id = ?", id).Error
+}
+
+func (r *webhookRepo) ListConfigs(ctx context.Context, ownerID int64) ([]*wealify.WebhookConfiguration, error) {
+	var configs []*wealify.WebhookConfiguration
+	err := r.db.WithContext(ctx).Where("owner_id = ?", ownerID).Order("created_at DESC").Find(&configs).Error
+	return configs, err
+}
+
+func (r *webhookRepo) UpdateConfig(ctx context.Context, id string, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&wealify.WebhookConfiguration{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *webhookRepo) ListSubscribers(ctx context.Context, ownerID int64, eventName string) ([]*wealify.WebhookConfiguration, error) {
+	var configs []*wealify.WebhookConfiguration
+	err := r.db.WithContext(ctx).
+		Joins("JOIN webhook_register_events wre ON wre.webhook_id = webhook_configuration.id").
+		Where("webhook_configuration.owner_id = ? AND webhook_configuration.is_active = ? AND wre.event_name = ?", ownerID, true, eventName).
+		Find(&configs).Error
+	return configs, err
+}
+
+func (r *webhookRepo) ListEvents(ctx context.Context, webhookIDs []string) ([]*wealify.WebhookRegisterEvent, error) {
+	var events []*wealify.WebhookRegisterEvent
+	if len(webhookIDs) == 0 {
+		return events, nil
+	}
+	err := r.db.WithContext(ctx).Where("webhook_id IN ?", webhookIDs).Order("event_name").Find(&events).Error
+	return events, err
+}
+
+func (r *webhookRepo) ReplaceEvents(ctx context.Context, webhookID string, events []string, now time.Time) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Where("webhook_id = ? AND event_name NOT IN ?", webhookID, events).Delete(&wealify.WebhookRegisterEvent{}).Error; err != nil {
+		return err
+	}
+	rows := make([]*wealify.WebhookRegisterEvent, 0, len(events))
+	for _, e := range events {
+		rows = append(rows, &wealify.WebhookRegisterEvent{WebhookID: webhookID, EventName: e, SubscribedAt: now})
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func (r *webhookRepo) CreateLog(ctx context.Context, log *wealify.WebhookLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+func (r *webhookRepo) GetLog(ctx context.Context, id string) (*wealify.WebhookLog, error) {
+	log := &wealify.WebhookLog{}
+	return log, r.db.WithContext(ctx).First(log, "id = ?", id).Error
+}
+
+// PaginateLogs lịch sử gửi của các webhook trong webhookIDs, mới nhất trước
+func (r *webhookRepo) PaginateLogs(ctx context.Context, webhookIDs []string, fields *dto.ListWebhookDeliveryFilter) (*dto.PaginationResponse[*wealify.WebhookLog], error) {
+	query := r.db.WithContext(ctx).Model(&wealify.WebhookLog{}).Where("webhook_config_id IN ?", webhookIDs)
+	if fields.WebhookConfigID != nil {
+		query = query.Where("webhook_config_id = ?", *fields.WebhookConfigID)
+	}
+	if fields.EventName != nil {
+		query = query.Where("event_name = ?", *fields.EventName)
+	}
+	if fields.Status != nil {
+		query = query.Where("status = ?", *fields.Status)
+	}
+	if fields.MessageID != nil {
+		query = query.Where("message_id = ?", *fields.MessageID)
+	}
+
+	var items []*wealify.WebhookLog
+	limit := int64(25)
+	page := int64(1)
+	if fields.Limit != nil {
+		limit = *fields.Limit
+	}
+	if fields.Page != nil {
+		page = *fields.Page
+	}
+	return CustomPaginate(query.Order("created_at DESC"), nil, page, limit, &items)
+}
@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// LocalStore lưu file dưới một thư mục trên disk, URL tải ký HMAC và được phục vụ bởi ServeHTTP
+type LocalStore struct {
+	dir        string
+	publicURL  string
+	signingKey []byte
+	now        func() time.Time
+}
+
+// NewLocalStore tạo thư mục gốc nếu chưa có. publicURL là prefix nơi mount ServeHTTP
+func NewLocalStore(dir, publicURL string, signingKey []byte) (*LocalStore, error) {
+	if len(signingKey) == 0 {
+		return nil, errors.New("storage: signing key is empty")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &LocalStore{dir: dir, publicURL: publicURL, signingKey: signingKey, now: time.Now}, nil
+}
+
+func (s *LocalStore) path(key string) (string, string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", "", err
+	}
+	return key, filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// Put ghi vào file tạm rồi rename để người đọc không thấy file ghi dở
+func (s *LocalStore) Put(_ context.Context, key string, r io.Reader, opts PutOptions) (*Object, error) {
+	key, p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o750); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return nil, err
+	}
+	contentType := opts.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(key))
+	}
+	return &Object{Key: key, Size: size, ContentType: contentType, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+func (s *LocalStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	_, p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	_, p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL {publicURL}/{key}?expires=<unix>&name=<downloadName>&signature=<hmac>
+func (s *LocalStore) SignedURL(_ context.Context, key string, ttl time.Duration, downloadName string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", expires)
+	if downloadName != "" {
+		q.Set("name", downloadName)
+	}
+	q.Set("signature", s.sign(key, expires, downloadName))
+	return fmt.Sprintf("%s/%s?%s", s.publicURL, (&url.URL{Path: key}).EscapedPath(), q.Encode()), nil
+}
+
+func (s *LocalStore) sign(key, expires, downloadName string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key + "\n" + expires + "\n" + downloadName))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ServeHTTP phục vụ URL do SignedURL tạo, mount với http.StripPrefix(publicURL) để r.URL.Path là key
+func (s *LocalStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key, err := cleanKey(r.URL.Path)
+	if err != nil {
+		http.Error(w, "invalid file key", http.StatusBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	expires, downloadName := q.Get("expires"), q.Get("name")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || !hmac.Equal([]byte(s.sign(key, expires, downloadName)), []byte(q.Get("signature"))) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+	if s.now().Unix() > unix {
+		http.Error(w, "link expired", http.StatusGone)
+		return
+	}
+	f, err := os.Open(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if err != nil {
+		http.Error(w, "file not found", http.StatusNotFound)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if downloadName == "" {
+		downloadName = path.Base(key)
+	}
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": downloadName}))
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.ServeContent(w, r, downloadName, info.ModTime(), f)
+}
+
+// PublicPath path của publicURL (bỏ scheme/host), dùng làm prefix khi mount ServeHTTP trên router
+func (s *LocalStore) PublicPath() string {
+	u, err := url.Parse(s.publicURL)
+	if err != nil {
+		return s.publicURL
+	}
+	return u.Path
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStoreSignedURL(t *testing.T) {
+	s, err := NewLocalStore(t.TempDir(), "/files", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	obj, err := s.Put(ctx, "exports/a b.csv", strings.NewReader("id\n1\n"), PutOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.Size != 5 || obj.ContentType == "" {
+		t.Fatalf("object = %+v", obj)
+	}
+
+	signed, err := s.SignedURL(ctx, obj.Key, time.Minute, "report.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := http.StripPrefix("/files", s)
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	w := get(signed)
+	if w.Code != http.StatusOK || w.Body.String() != "id\n1\n" || !strings.Contains(w.Header().Get("Content-Disposition"), "report.csv") {
+		t.Fatalf("download: %d %q %q", w.Code, w.Body.String(), w.Header().Get("Content-Disposition"))
+	}
+
+	// đổi tên file tải về làm sai chữ ký
+	u, _ := url.Parse(signed)
+	q := u.Query()
+	q.Set("name", "other.csv")
+	u.RawQuery = q.Encode()
+	if w := get(u.String()); w.Code != http.StatusForbidden {
+		t.Fatalf("tampered: status = %d", w.Code)
+	}
+
+	// link hết hạn
+	s.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if w := get(signed); w.Code != http.StatusGone {
+		t.Fatalf("expired: status = %d", w.Code)
+	}
+}
+
+func TestLocalStoreRejectsTraversal(t *testing.T) {
+	s, err := NewLocalStore(t.TempDir(), "/files", []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Put(context.Background(), "../escape.txt", strings.NewReader("x"), PutOptions{}); err != ErrInvalidKey {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := s.Get(context.Background(), "missing/file.txt"); err != ErrNotFound {
+		t.Fatalf("err = %v", err)
+	}
+	r, err := s.Put(context.Background(), "ok/file.txt", strings.NewReader("x"), PutOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := s.Get(context.Background(), r.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if b, _ := io.ReadAll(rc); string(b) != "x" {
+		t.Fatalf("content = %q", b)
+	}
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// metaSHA256 metadata (x-amz-meta-sha256) lưu SHA-256 của nội dung
+const metaSHA256 = "sha256"
+
+// S3Store lưu file trên S3 hoặc dịch vụ tương thích (MinIO), URL tải là presigned GET
+type S3Store struct {
+	client  *s3.Client
+	presign *s3.PresignClient
+	bucket  string
+}
+
+// NewS3Store tạo client theo cấu hình, không gọi mạng khi khởi tạo
+func NewS3Store(cfg config.S3StorageConfig) *S3Store {
+	opts := s3.Options{
+		Region:       cfg.Region,
+		UsePathStyle: cfg.UsePathStyle,
+		// chỉ gửi checksum khi API bắt buộc, tránh aws-chunked trailer mà một số dịch vụ tương thích S3 chưa hỗ trợ
+		RequestChecksumCalculation: aws.RequestChecksumCalculationWhenRequired,
+		ResponseChecksumValidation: aws.ResponseChecksumValidationWhenRequired,
+	}
+	if cfg.AccessKey != "" {
+		opts.Credentials = credentials.NewStaticCredentialsProvider(cfg.AccessKey, cfg.SecretKey, "")
+	}
+	if cfg.Endpoint != "" {
+		opts.BaseEndpoint = aws.String(cfg.Endpoint)
+	}
+	client := s3.New(opts)
+	return &S3Store{client: client, presign: s3.NewPresignClient(client), bucket: cfg.Bucket}
+}
+
+// Put ghi r ra file tạm để biết kích thước và SHA-256 trước khi upload (PutObject cần body seek được)
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, opts PutOptions) (*Object, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp("", "s3-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	contentType := opts.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(key))
+	}
+	input := &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		Body:          tmp,
+		ContentLength: aws.Int64(size),
+		Metadata:      map[string]string{metaSHA256: sum},
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+	if _, err := s.client.PutObject(ctx, input); err != nil {
+		return nil, err
+	}
+	return &Object{Key: key, Size: size, ContentType: contentType, SHA256: sum}, nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+// Delete xoá object, S3 trả thành công cả khi key không tồn tại
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil && isS3NotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// SignedURL presigned GET, downloadName đặt qua response-content-disposition
+func (s *S3Store) SignedURL(ctx context.Context, key string, ttl time.Duration, downloadName string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}
+	if downloadName != "" {
+		input.ResponseContentDisposition = aws.String(mime.FormatMediaType("attachment", map[string]string{"filename": downloadName}))
+	}
+	req, err := s.presign.PresignGetObject(ctx, input, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+func isS3NotFound(err error) bool {
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return true
+	}
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return true
+	}
+	var respErr *smithyhttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == 404
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeS3 bản thu nhỏ của MinIO: path-style /<bucket>/<key>, chỉ PUT/GET/HEAD/DELETE
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	meta    map[string]http.Header
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+		f.meta[key] = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		body, ok := f.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+			return
+		}
+		w.Header().Set("Content-Type", f.meta[key].Get("Content-Type"))
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}, meta: map[string]http.Header{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	s := NewS3Store(config.S3StorageConfig{
+		Endpoint:     srv.URL,
+		Region:       "us-east-1",
+		Bucket:       "ledger",
+		AccessKey:    "minio",
+		SecretKey:    "minio123",
+		UsePathStyle: true,
+	})
+	ctx := context.Background()
+	content := "id\n1\n"
+	obj, err := s.Put(ctx, "exports/report.csv", strings.NewReader(content), PutOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(content))
+	if obj.Size != 5 || obj.SHA256 != hex.EncodeToString(sum[:]) || obj.ContentType == "" {
+		t.Fatalf("object = %+v", obj)
+	}
+	if got := fake.meta["ledger/exports/report.csv"].Get("X-Amz-Meta-Sha256"); got != obj.SHA256 {
+		t.Fatalf("sha256 metadata = %q", got)
+	}
+
+	rc, err := s.Get(ctx, obj.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(body) != content {
+		t.Fatalf("body = %q", body)
+	}
+
+	signed, err := s.SignedURL(ctx, obj.Key, time.Minute, "report.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(signed, srv.URL+"/ledger/exports/report.csv?") || !strings.Contains(signed, "X-Amz-Signature=") || !strings.Contains(signed, "response-content-disposition=") {
+		t.Fatalf("signed url = %s", signed)
+	}
+
+	if err := s.Delete(ctx, obj.Key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, obj.Key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get after delete: err = %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// Driver lưu file, chọn bằng STORAGE_DRIVER
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	// ErrNotFound key không tồn tại trong store
+	ErrNotFound = errors.New("storage: object not found")
+	// ErrInvalidKey key rỗng, tuyệt đối hoặc chứa ".."
+	ErrInvalidKey = errors.New("storage: invalid key")
+)
+
+// Object thông tin file sau khi Put, SHA256 là hex SHA-256 của nội dung
+type Object struct {
+	Key         string
+	Size        int64
+	ContentType string
+	SHA256      string
+}
+
+// PutOptions metadata đi kèm file
+type PutOptions struct {
+	ContentType string
+}
+
+// Store nơi lưu file dùng chung cho API và worker (export, upload)
+type Store interface {
+	// Put ghi nội dung r vào key (ghi đè nếu đã có), trả về kích thước và SHA-256
+	Put(ctx context.Context, key string, r io.Reader, opts PutOptions) (*Object, error)
+	// Get mở file để đọc, trả ErrNotFound khi key không tồn tại; caller phải Close
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete xoá file, key không tồn tại không phải lỗi
+	Delete(ctx context.Context, key string) error
+	// SignedURL URL tải file có hạn ttl, downloadName là tên file trình duyệt lưu (rỗng thì lấy theo key)
+	SignedURL(ctx context.Context, key string, ttl time.Duration, downloadName string) (string, error)
+}
+
+// NewFromConfig tạo Store theo StorageConfig
+func NewFromConfig(cfg *config.StorageConfig) (Store, error) {
+	switch cfg.Driver {
+	case DriverLocal, "":
+		if cfg.SigningKey == "" {
+			return nil, config.ErrStorageSigningKeyMissing
+		}
+		return NewLocalStore(cfg.LocalDir, cfg.PublicURL, []byte(cfg.SigningKey))
+	case DriverS3:
+		if cfg.S3.Bucket == "" {
+			return nil, config.ErrStorageS3BucketMissing
+		}
+		return NewS3Store(cfg.S3), nil
+	default:
+		return nil, fmt.Errorf("storage: unsupported driver %q", cfg.Driver)
+	}
+}
+
+// cleanKey chuẩn hoá key dạng "exports/2025/12/abc.xlsx", chặn thoát khỏi thư mục gốc
+func cleanKey(key string) (string, error) {
+	key = strings.TrimPrefix(key, "/")
+	if key == "" || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", ErrInvalidKey
+		}
+	}
+	return path.Clean(key), nil
+}
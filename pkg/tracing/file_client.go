@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// fileClient otlptrace.Client ghi mỗi lần export thành một dòng OTLP JSON (ExportTraceServiceRequest),
+// đọc được bằng receiver otlpjsonfile của OpenTelemetry Collector hoặc jq khi chạy local
+type fileClient struct {
+	mu sync.Mutex
+	w  io.WriteCloser
+}
+
+func newFileClient(w io.WriteCloser) *fileClient {
+	return &fileClient{w: w}
+}
+
+func (c *fileClient) Start(context.Context) error {
+	return nil
+}
+
+func (c *fileClient) Stop(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.w.Close()
+}
+
+func (c *fileClient) UploadTraces(_ context.Context, spans []*tracepb.ResourceSpans) error {
+	line, err := protojson.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: spans})
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err = c.w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// gormSpanKey key lưu span của câu lệnh đang chạy trong gorm.Statement
+const gormSpanKey = "otel:span"
+
+// gormPlugin tạo span cho mỗi câu lệnh GORM qua callback before/after.
+// Chỉ tạo span khi context đã có span cha (request HTTP, job) để query nền (scrape metrics, scheduler) không sinh trace rời.
+// Span ghi câu SQL dạng tham số (không kèm giá trị) để không lộ dữ liệu.
+type gormPlugin struct{}
+
+// GormPlugin plugin tracing cho GORM, đăng ký bằng db.Use(tracing.GormPlugin())
+func GormPlugin() gorm.Plugin {
+	return gormPlugin{}
+}
+
+func (gormPlugin) Name() string {
+	return "otel-tracing"
+}
+
+func (p gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("otel:before_create", p.before("create")),
+		cb.Create().After("gorm:create").Register("otel:after_create", p.after),
+		cb.Query().Before("gorm:query").Register("otel:before_query", p.before("query")),
+		cb.Query().After("gorm:query").Register("otel:after_query", p.after),
+		cb.Update().Before("gorm:update").Register("otel:before_update", p.before("update")),
+		cb.Update().After("gorm:update").Register("otel:after_update", p.after),
+		cb.Delete().Before("gorm:delete").Register("otel:before_delete", p.before("delete")),
+		cb.Delete().After("gorm:delete").Register("otel:after_delete", p.after),
+		cb.Row().Before("gorm:row").Register("otel:before_row", p.before("row")),
+		cb.Row().After("gorm:row").Register("otel:after_row", p.after),
+		cb.Raw().Before("gorm:raw").Register("otel:before_raw", p.before("raw")),
+		cb.Raw().After("gorm:raw").Register("otel:after_raw", p.after),
+	)
+}
+
+func (gormPlugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+			return
+		}
+		_, span := Tracer().Start(ctx, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemNamePostgreSQL, semconv.DBOperationName(operation)),
+		)
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func (gormPlugin) after(db *gorm.DB) {
+	v, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	if table := db.Statement.Table; table != "" {
+		span.SetAttributes(semconv.DBCollectionName(table))
+	}
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,138 @@
+// Package tracing khởi tạo OpenTelemetry tracing và các helper lan truyền trace context
+// (HTTP → GORM → asynq job, outbox event).
+package tracing
+
+import (
+	"context"
+	config "core-ledger/configs"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName tên tracer của các span do code ledger tạo
+const instrumentationName = "core-ledger"
+
+// Header ghi vào TransactionLog.Headers để đối chiếu event với trace
+const (
+	HeaderTraceID     = "trace_id"
+	HeaderSpanID      = "span_id"
+	HeaderTraceparent = "traceparent"
+)
+
+func init() {
+	// propagator luôn được cài để trace context từ client/job vẫn được chuyển tiếp khi tracing tắt
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+// Init cài TracerProvider toàn cục theo cấu hình, trả về hàm shutdown để flush span khi tắt ứng dụng.
+// Tracing tắt hoặc exporter = none thì giữ provider no-op mặc định.
+func Init(ctx context.Context, cfg *config.TracingConfig) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+	if !cfg.Enabled || cfg.Exporter == config.TracingExporterNone {
+		return noop, nil
+	}
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return noop, err
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+	))
+	if err != nil {
+		return noop, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, cfg *config.TracingConfig) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case config.TracingExporterStdout:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case config.TracingExporterOTLPFile:
+		file, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, err
+		}
+		return otlptrace.New(ctx, newFileClient(file))
+	case config.TracingExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OTLPEndpoint)}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+	return nil, fmt.Errorf("unsupported TRACING_EXPORTER %q", cfg.Exporter)
+}
+
+// Tracer tracer dùng chung của ledger
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Inject ghi trace context của ctx vào map (payload job, header outbox), rỗng nếu ctx không có span
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract đọc trace context từ map đã Inject
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// TraceID trace id của span trong ctx, rỗng nếu không có
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// Headers trace_id/span_id/traceparent của ctx để ghi vào TransactionLog.Headers, nil nếu ctx không có span
+func Headers(ctx context.Context) map[string]any {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	headers := map[string]any{
+		HeaderTraceID: sc.TraceID().String(),
+		HeaderSpanID:  sc.SpanID().String(),
+	}
+	if traceparent := Inject(ctx)[HeaderTraceparent]; traceparent != "" {
+		headers[HeaderTraceparent] = traceparent
+	}
+	return headers
+}
+
+// ContextFromHeaders khôi phục trace context từ TransactionLog.Headers
+func ContextFromHeaders(ctx context.Context, headers map[string]any) context.Context {
+	traceparent, _ := headers[HeaderTraceparent].(string)
+	if traceparent == "" {
+		return ctx
+	}
+	return Extract(ctx, map[string]string{HeaderTraceparent: traceparent})
+}
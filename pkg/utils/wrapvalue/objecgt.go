@@ -1,8 +1,8 @@
 package wv
 
 import (
+	"core-ledger/pkg/logging"
 	"encoding/json"
-	"github.com/sirupsen/logrus"
 )
 
 func ToObject(src, des any) error {
@@ -12,7 +12,7 @@ func ToObject(src, des any) error {
 	}
 	err = json.Unmarshal(bytes, des)
 	if err != nil {
-		logrus.Info("parse object failed")
+		logging.DefaultLogger().Infow("parse object failed", "error", err)
 		return err
 	}
 	return nil